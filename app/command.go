@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/williamchand/kuncie-cart/export"
+	_exportRepo "github.com/williamchand/kuncie-cart/export/repository"
+	_exportUcase "github.com/williamchand/kuncie-cart/export/usecase"
+	_importerUcase "github.com/williamchand/kuncie-cart/importer/usecase"
+	_itemRepo "github.com/williamchand/kuncie-cart/item/repository"
+	"github.com/williamchand/kuncie-cart/transaction"
+)
+
+// runCommand runs the subcommand given on the command line instead of starting the server
+func runCommand(dbConn *sql.DB, name string, args []string) error {
+	switch name {
+	case "export":
+		return runExport(dbConn, args)
+	case "import":
+		return runImport(dbConn, args)
+	default:
+		return fmt.Errorf("unknown command %q", name)
+	}
+}
+
+// runExport writes the orders of a date range as CSV or JSON Lines, e.g.
+//
+//	engine export -from 2022-01-01 -to 2022-01-31 -format jsonl -output orders.jsonl
+func runExport(dbConn *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	from := fs.String("from", "", "first day to export, as YYYY-MM-DD or RFC3339")
+	to := fs.String("to", "", "last day to export, as YYYY-MM-DD or an exclusive RFC3339 timestamp")
+	format := fs.String("format", export.FormatCSV, "output format, csv or jsonl")
+	output := fs.String("output", "", "file to write, standard output when empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	start, end, err := export.ParseRange(*from, *to)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	bw := bufio.NewWriter(w)
+	eu := _exportUcase.NewExportUsecase(_exportRepo.NewMysqlExportRepository(dbConn), time.Duration(viper.GetInt("export.timeout"))*time.Second)
+	if err := eu.Export(context.Background(), start, end, *format, bw); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// runImport upserts the items of a CSV or JSON file and prints the report of every row, e.g.
+//
+//	engine import -input items.csv -dry-run
+func runImport(dbConn *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	input := fs.String("input", "", "file to read, standard input when empty")
+	format := fs.String("format", "", "input format, csv or json, guessed from the file extension when empty")
+	dryRun := fs.Bool("dry-run", false, "validate and report without saving anything")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if *input != "" {
+		f, err := os.Open(*input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+		if *format == "" {
+			*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*input)), ".")
+		}
+	}
+
+	iu := _importerUcase.NewImporterUsecase(_itemRepo.NewMysqlItemRepository(dbConn), transaction.NewMysqlManager(dbConn),
+		time.Duration(viper.GetInt("import.timeout"))*time.Second)
+	report, err := iu.Import(context.Background(), *format, bufio.NewReader(r), *dryRun)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
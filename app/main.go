@@ -1,11 +1,15 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/graphql-go/graphql"
@@ -14,12 +18,57 @@ import (
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/graphql-go/handler"
 	"github.com/labstack/echo"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 
+	"github.com/williamchand/kuncie-cart/auth"
+	_graphQLCreditDelivery "github.com/williamchand/kuncie-cart/credit/delivery/graphql"
+	_creditRepo "github.com/williamchand/kuncie-cart/credit/repository"
+	_creditUcase "github.com/williamchand/kuncie-cart/credit/usecase"
+	_graphQLCustomerDelivery "github.com/williamchand/kuncie-cart/customer/delivery/graphql"
+	_customerRepo "github.com/williamchand/kuncie-cart/customer/repository"
+	_customerUcase "github.com/williamchand/kuncie-cart/customer/usecase"
+	_exportHttpDelivery "github.com/williamchand/kuncie-cart/export/delivery/http"
+	_exportRepo "github.com/williamchand/kuncie-cart/export/repository"
+	_exportUcase "github.com/williamchand/kuncie-cart/export/usecase"
+	"github.com/williamchand/kuncie-cart/health"
+	_healthHttpDelivery "github.com/williamchand/kuncie-cart/health/delivery/http"
+	_healthRepo "github.com/williamchand/kuncie-cart/health/repository"
+	_healthUcase "github.com/williamchand/kuncie-cart/health/usecase"
+	_importerHttpDelivery "github.com/williamchand/kuncie-cart/importer/delivery/http"
+	_importerUcase "github.com/williamchand/kuncie-cart/importer/usecase"
+	_invoiceHttpDelivery "github.com/williamchand/kuncie-cart/invoice/delivery/http"
+	_invoiceRepo "github.com/williamchand/kuncie-cart/invoice/repository"
+	_invoiceUcase "github.com/williamchand/kuncie-cart/invoice/usecase"
+	_graphQLItemDelivery "github.com/williamchand/kuncie-cart/item/delivery/graphql"
+	_itemRepo "github.com/williamchand/kuncie-cart/item/repository"
+	_itemUcase "github.com/williamchand/kuncie-cart/item/usecase"
+	"github.com/williamchand/kuncie-cart/metrics"
 	"github.com/williamchand/kuncie-cart/middleware"
 	_graphQLOrderDelivery "github.com/williamchand/kuncie-cart/order/delivery/graphql"
 	_orderRepo "github.com/williamchand/kuncie-cart/order/repository"
 	_orderUcase "github.com/williamchand/kuncie-cart/order/usecase"
+	"github.com/williamchand/kuncie-cart/payment"
+	_graphQLPaymentDelivery "github.com/williamchand/kuncie-cart/payment/delivery/graphql"
+	_paymentHttpDelivery "github.com/williamchand/kuncie-cart/payment/delivery/http"
+	_fakePaymentProvider "github.com/williamchand/kuncie-cart/payment/provider/fake"
+	_paymentRepo "github.com/williamchand/kuncie-cart/payment/repository"
+	_paymentUcase "github.com/williamchand/kuncie-cart/payment/usecase"
+	"github.com/williamchand/kuncie-cart/ratelimit"
+	_memoryRateLimitStore "github.com/williamchand/kuncie-cart/ratelimit/store/memory"
+	_graphQLRefundDelivery "github.com/williamchand/kuncie-cart/refund/delivery/graphql"
+	_refundRepo "github.com/williamchand/kuncie-cart/refund/repository"
+	_refundUcase "github.com/williamchand/kuncie-cart/refund/usecase"
+	_graphQLReportDelivery "github.com/williamchand/kuncie-cart/report/delivery/graphql"
+	_reportRepo "github.com/williamchand/kuncie-cart/report/repository"
+	_reportUcase "github.com/williamchand/kuncie-cart/report/usecase"
+	_graphQLSearchDelivery "github.com/williamchand/kuncie-cart/search/delivery/graphql"
+	_fulltextSearchIndex "github.com/williamchand/kuncie-cart/search/index/fulltext"
+	_searchUcase "github.com/williamchand/kuncie-cart/search/usecase"
+	"github.com/williamchand/kuncie-cart/tracing"
+	"github.com/williamchand/kuncie-cart/transaction"
 )
 
 func init() {
@@ -29,8 +78,10 @@ func init() {
 		panic(err)
 	}
 
+	logrus.SetFormatter(&logrus.JSONFormatter{})
 	if viper.GetBool(`debug`) {
-		fmt.Println("Service RUN on DEBUG mode")
+		logrus.SetLevel(logrus.DebugLevel)
+		fmt.Fprintln(os.Stderr, "Service RUN on DEBUG mode")
 	}
 }
 
@@ -62,18 +113,113 @@ func main() {
 		}
 	}()
 
+	if len(os.Args) > 1 {
+		if err := runCommand(dbConn, os.Args[1], os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	authSecret := ""
+	if viper.GetString("auth.algorithm") == auth.AlgHS256 && viper.GetString("auth.jwks_file") == "" {
+		// the other keys are read from files, only HS256 tokens are signed with the secret
+		authSecret = secret("auth.secret", "AUTH_SECRET")
+	}
+	verifier, err := auth.NewJWTVerifier(auth.Config{
+		Algorithm:     viper.GetString("auth.algorithm"),
+		Secret:        authSecret,
+		PublicKeyFile: viper.GetString("auth.public_key_file"),
+		JWKSFile:      viper.GetString("auth.jwks_file"),
+		Issuer:        viper.GetString("auth.issuer"),
+		Audience:      viper.GetString("auth.audience"),
+		Leeway:        time.Duration(viper.GetInt("auth.leeway")) * time.Second,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sampleRatio := 1.0
+	if viper.IsSet("tracing.sample_ratio") {
+		sampleRatio = viper.GetFloat64("tracing.sample_ratio")
+	}
+	tracerProvider := tracing.Setup(newTraceExporter(), sampleRatio)
+
 	e := echo.New()
 	middL := middleware.InitMiddleware()
+	middL.TrustedProxies, err = middleware.ParseTrustedProxies(viper.GetStringSlice("server.trusted_proxies"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	e.Use(middL.RequestLogger)
+	e.Use(middL.Metrics)
+	e.Use(middL.Tracing)
+	if viper.IsSet("cors") {
+		middL.CORSConfig = middleware.CORSConfig{
+			AllowOrigins:     viper.GetStringSlice("cors.allow_origins"),
+			AllowMethods:     viper.GetStringSlice("cors.allow_methods"),
+			AllowHeaders:     viper.GetStringSlice("cors.allow_headers"),
+			ExposeHeaders:    viper.GetStringSlice("cors.expose_headers"),
+			AllowCredentials: viper.GetBool("cors.allow_credentials"),
+			MaxAge:           viper.GetInt("cors.max_age"),
+		}
+	}
 	e.Use(middL.CORS)
+	e.Use(middL.JWT(verifier))
+	e.Use(middL.Session)
+	e.Use(middL.RateLimit(_memoryRateLimitStore.NewMemoryStore(), rateLimitConfig()))
+	tm := transaction.NewMysqlManager(dbConn)
 	or := _orderRepo.NewMysqlOrderRepository(dbConn)
+	rr := _refundRepo.NewMysqlRefundRepository(dbConn)
+	ir := _invoiceRepo.NewMysqlInvoiceRepository(dbConn)
+	er := _exportRepo.NewMysqlExportRepository(dbConn)
+	rpr := _reportRepo.NewMysqlReportRepository(dbConn)
+	pr := _paymentRepo.NewMysqlPaymentRepository(dbConn)
+	cr := _creditRepo.NewMysqlCreditRepository(dbConn)
+	itr := _itemRepo.NewMysqlItemRepository(dbConn)
+	sx := _fulltextSearchIndex.NewFulltextIndex(dbConn)
+	csr := _customerRepo.NewMysqlCustomerRepository(dbConn)
 
 	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
-	ou := _orderUcase.NewOrderUsecase(or, timeoutContext)
+	cu := _creditUcase.NewCreditUsecase(cr, tm, timeoutContext)
+	csu := _customerUcase.NewCustomerUsecase(csr, tm, timeoutContext)
+	itu := _itemUcase.NewItemUsecase(itr, tm, timeoutContext)
+	su := _searchUcase.NewSearchUsecase(sx, itu, timeoutContext)
+	imu := _importerUcase.NewImporterUsecase(itr, tm, time.Duration(viper.GetInt("import.timeout"))*time.Second)
+	ou := _orderUcase.NewOrderUsecase(or, cu, csu, tm, timeoutContext)
+	iu := _invoiceUcase.NewInvoiceUsecase(ir, or, tm, timeoutContext)
+	rpu := _reportUcase.NewReportUsecase(rpr, timeoutContext)
+	pu := _paymentUcase.NewPaymentUsecase(pr, or, newPaymentProvider(), tm, timeoutContext,
+		time.Duration(viper.GetInt("payment.timeout"))*time.Second)
+	ru := _refundUcase.NewRefundUsecase(rr, or, cu, pu, tm, timeoutContext)
+	eu := _exportUcase.NewExportUsecase(er, time.Duration(viper.GetInt("export.timeout"))*time.Second)
 
 	schema := _graphQLOrderDelivery.NewSchema(_graphQLOrderDelivery.NewResolver(ou))
+	refundSchema := _graphQLRefundDelivery.NewSchema(_graphQLRefundDelivery.NewResolver(ru))
+	reportSchema := _graphQLReportDelivery.NewSchema(_graphQLReportDelivery.NewResolver(rpu))
+	paymentSchema := _graphQLPaymentDelivery.NewSchema(_graphQLPaymentDelivery.NewResolver(pu))
+	creditSchema := _graphQLCreditDelivery.NewSchema(_graphQLCreditDelivery.NewResolver(cu))
+	itemSchema := _graphQLItemDelivery.NewSchema(_graphQLItemDelivery.NewResolver(itu))
+	searchSchema := _graphQLSearchDelivery.NewSchema(_graphQLSearchDelivery.NewResolver(su))
+	customerSchema := _graphQLCustomerDelivery.NewSchema(_graphQLCustomerDelivery.NewResolver(csu))
+
+	query := schema.Query()
+	mutation := schema.Mutation()
+	for _, fields := range []graphql.Fields{refundSchema.QueryFields(), reportSchema.QueryFields(), paymentSchema.QueryFields(),
+		creditSchema.QueryFields(), itemSchema.QueryFields(), searchSchema.QueryFields(), customerSchema.QueryFields()} {
+		for name, field := range fields {
+			query.AddFieldConfig(name, field)
+		}
+	}
+	for _, fields := range []graphql.Fields{refundSchema.MutationFields(), paymentSchema.MutationFields(), creditSchema.MutationFields(),
+		itemSchema.MutationFields(), customerSchema.MutationFields()} {
+		for name, field := range fields {
+			mutation.AddFieldConfig(name, field)
+		}
+	}
+
 	graphqlSchema, err := graphql.NewSchema(graphql.SchemaConfig{
-		Query:    schema.Query(),
-		Mutation: schema.Mutation(),
+		Query:    query,
+		Mutation: mutation,
 	})
 	if err != nil {
 		logrus.Fatal(err)
@@ -85,8 +231,133 @@ func main() {
 		Pretty:   true,
 	})
 
-	e.GET("/graphql", echo.WrapHandler(graphQLHandler))
-	e.POST("/graphql", echo.WrapHandler(graphQLHandler))
+	_invoiceHttpDelivery.NewInvoiceHandler(e, iu, middL.Require(auth.PermManageOrders))
+	_exportHttpDelivery.NewExportHandler(e, eu, middL.Require(auth.PermManageOrders))
+	_importerHttpDelivery.NewImportHandler(e, imu, middL.Require(auth.PermManageCatalog))
+	_paymentHttpDelivery.NewWebhookHandler(e, pu, secret("payment.webhook.secret", "PAYMENT_WEBHOOK_SECRET"),
+		time.Duration(viper.GetInt("payment.webhook.tolerance"))*time.Second)
 
-	log.Fatal(e.Start(viper.GetString("server.address")))
+	operations := make([]string, 0)
+	for _, fields := range []graphql.FieldDefinitionMap{query.Fields(), mutation.Fields()} {
+		for name, field := range fields {
+			operations = append(operations, name)
+			if field.Resolve != nil {
+				field.Resolve = tracing.Resolve(name, field.Resolve)
+			}
+		}
+	}
+	graphQLMetrics := middL.GraphQLMetrics(operations)
+	e.GET("/graphql", echo.WrapHandler(graphQLHandler), graphQLMetrics)
+	e.POST("/graphql", echo.WrapHandler(graphQLHandler), graphQLMetrics)
+
+	// the metrics include the revenue, they are served on their own listener which must stay internal
+	metrics.Default.MustRegister(collectors.NewDBStatsCollector(dbConn, viper.GetString("database.name")))
+	metricsServer := &http.Server{Addr: viper.GetString("metrics.address"), Handler: metrics.Handler()}
+
+	hu := _healthUcase.NewHealthUsecase(_healthRepo.NewMysqlHealthRepository(dbConn), health.SchemaVersion,
+		time.Duration(viper.GetInt("health.timeout"))*time.Second)
+	_healthHttpDelivery.NewHealthHandler(e, hu)
+
+	go func() {
+		if err := e.Start(viper.GetString("server.address")); err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+	go func() {
+		if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	// on SIGINT or SIGTERM the service reports itself as not ready, leaves the load balancer the drain delay to
+	// stop sending it requests, then lets the requests in flight finish
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+	hu.Drain()
+	time.Sleep(time.Duration(viper.GetInt("shutdown.drain")) * time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(viper.GetInt("shutdown.timeout"))*time.Second)
+	defer cancel()
+	if err := e.Shutdown(ctx); err != nil {
+		logrus.Error(err)
+	}
+	if err := metricsServer.Shutdown(ctx); err != nil {
+		logrus.Error(err)
+	}
+	if err := tracerProvider.Shutdown(ctx); err != nil {
+		logrus.Error(err)
+	}
+}
+
+// rateLimitConfig reads the rate_limit section of the config, viper lowercases the operation names
+// which are compared without case anyway
+func rateLimitConfig() middleware.RateLimitConfig {
+	limit := func(key string) ratelimit.Limit {
+		return ratelimit.Limit{
+			Rate:  viper.GetFloat64(key + ".rate"),
+			Burst: viper.GetInt(key + ".burst"),
+		}
+	}
+	cfg := middleware.RateLimitConfig{
+		Default:    limit("rate_limit.default"),
+		Operations: make(map[string]ratelimit.Limit),
+		APIKeys:    viper.GetStringSlice("rate_limit.api_keys"),
+	}
+	for name := range viper.GetStringMap("rate_limit.operations") {
+		cfg.Operations[name] = limit("rate_limit.operations." + name)
+	}
+	return cfg
+}
+
+// placeholderSecret is the value config.json ships with in place of the secrets of a deployment
+const placeholderSecret = "change-me"
+
+// secret returns the secret at key of the config, the environment variable env overrides it.
+// The service refuses to start while the secret is empty or still the placeholder of config.json.
+func secret(key string, env string) string {
+	if err := viper.BindEnv(key, env); err != nil {
+		log.Fatal(err)
+	}
+	s := viper.GetString(key)
+	if s == "" || s == placeholderSecret {
+		log.Fatalf("%s is not set, replace it in config.json or set %s", key, env)
+	}
+	return s
+}
+
+// newTraceExporter initializes the span exporter selected in the config, none when it is empty
+func newTraceExporter() sdktrace.SpanExporter {
+	switch name := viper.GetString("tracing.exporter"); name {
+	case "":
+		return nil
+	case "stdout":
+		w := os.Stdout
+		if path := viper.GetString("tracing.file"); path != "" {
+			f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+			if err != nil {
+				log.Fatal(err)
+			}
+			w = f
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(w))
+		if err != nil {
+			log.Fatal(err)
+		}
+		return exporter
+	default:
+		log.Fatalf("unknown trace exporter %q", name)
+		return nil
+	}
+}
+
+// newPaymentProvider initializes the payment provider selected in the config
+func newPaymentProvider() payment.Provider {
+	switch name := viper.GetString("payment.provider"); name {
+	case _fakePaymentProvider.Name:
+		return _fakePaymentProvider.NewFakeProvider(_fakePaymentProvider.Mode(viper.GetString("payment.fake.mode")))
+	default:
+		log.Fatalf("unknown payment provider %q", name)
+		return nil
+	}
 }
@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// Verifier represent the contract to authenticate the bearer token of a request
+type Verifier interface {
+	Verify(token string) (*models.Principal, error)
+}
+
+type principalKey struct{}
+
+// NewContext returns a copy of ctx carrying the authenticated principal
+func NewContext(ctx context.Context, p *models.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal bound to the context, false for anonymous requests
+func FromContext(ctx context.Context) (*models.Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*models.Principal)
+	return p, ok && p != nil
+}
+
+// CustomerID returns the customer authenticated in the context, models.ErrUnauthorized when there is none
+func CustomerID(ctx context.Context) (int64, error) {
+	p, ok := FromContext(ctx)
+	if !ok || p.CustomerID == 0 {
+		return 0, models.ErrUnauthorized
+	}
+	return p.CustomerID, nil
+}
+
+// NewCustomerContext returns a copy of ctx signed in as the customer, as with a token issued to them
+func NewCustomerContext(ctx context.Context, customerID int64) context.Context {
+	return NewContext(ctx, &models.Principal{Subject: strconv.FormatInt(customerID, 10), CustomerID: customerID})
+}
@@ -0,0 +1,304 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+const (
+	// AlgHS256 signs tokens with an HMAC-SHA256 shared secret
+	AlgHS256 = "HS256"
+	// AlgRS256 signs tokens with an RSA private key, verified with its public key
+	AlgRS256 = "RS256"
+)
+
+// ErrInvalidToken will throw if the token is malformed, badly signed or not valid at this time
+var ErrInvalidToken = errors.New("invalid token")
+
+// Config represent how the tokens are verified.
+// The keys come from the JWKS file when given, otherwise from Secret for HS256 or PublicKeyFile for RS256.
+type Config struct {
+	Algorithm     string
+	Secret        string
+	PublicKeyFile string
+	JWKSFile      string
+	// Issuer and Audience are checked against the iss and aud claims when not empty
+	Issuer   string
+	Audience string
+	// Leeway is the clock skew tolerated on exp and nbf
+	Leeway time.Duration
+}
+
+type jwtKey struct {
+	alg string
+	key interface{}
+}
+
+type jwtVerifier struct {
+	// keys are indexed by kid, the key of a single key config has an empty kid
+	keys     map[string]jwtKey
+	issuer   string
+	audience string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type jwtClaims struct {
+	Subject   string      `json:"sub"`
+	Issuer    string      `json:"iss"`
+	Audience  interface{} `json:"aud"`
+	ExpiresAt *int64      `json:"exp"`
+	NotBefore *int64      `json:"nbf"`
+	Roles     []string    `json:"roles"`
+}
+
+// NewJWTVerifier will create an object that represent the Verifier interface for JWT bearer tokens
+func NewJWTVerifier(cfg Config) (Verifier, error) {
+	v := &jwtVerifier{
+		keys:     make(map[string]jwtKey),
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		leeway:   cfg.Leeway,
+		now:      time.Now,
+	}
+
+	switch {
+	case cfg.JWKSFile != "":
+		if err := v.loadJWKS(cfg.JWKSFile, cfg.Algorithm); err != nil {
+			return nil, err
+		}
+	case cfg.Algorithm == AlgHS256:
+		if cfg.Secret == "" {
+			return nil, fmt.Errorf("auth: HS256 needs a secret")
+		}
+		v.keys[""] = jwtKey{alg: AlgHS256, key: []byte(cfg.Secret)}
+	case cfg.Algorithm == AlgRS256:
+		key, err := loadPublicKey(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		v.keys[""] = jwtKey{alg: AlgRS256, key: key}
+	default:
+		return nil, fmt.Errorf("auth: unsupported algorithm %q", cfg.Algorithm)
+	}
+
+	return v, nil
+}
+
+// Verify checks the signature and the registered claims of a compact JWT and returns its principal
+func (v *jwtVerifier) Verify(token string) (*models.Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	header := jwtHeader{}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, ErrInvalidToken
+	}
+	key, ok := v.keys[header.Kid]
+	if !ok && len(v.keys) == 1 {
+		key, ok = v.keys[""]
+	}
+	// the algorithm of the key wins over the header so a RSA public key is never used as a HMAC secret
+	if !ok || header.Alg != key.alg {
+		return nil, ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if !verifySignature(key, parts[0]+"."+parts[1], signature) {
+		return nil, ErrInvalidToken
+	}
+
+	claims := jwtClaims{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if err := v.validate(&claims); err != nil {
+		return nil, err
+	}
+
+	p := &models.Principal{Subject: claims.Subject, Roles: claims.Roles}
+	if id, err := strconv.ParseInt(claims.Subject, 10, 64); err == nil && id > 0 {
+		p.CustomerID = id
+	}
+	return p, nil
+}
+
+func (v *jwtVerifier) validate(c *jwtClaims) error {
+	now := v.now()
+	if c.ExpiresAt == nil || now.After(time.Unix(*c.ExpiresAt, 0).Add(v.leeway)) {
+		return ErrInvalidToken
+	}
+	if c.NotBefore != nil && now.Before(time.Unix(*c.NotBefore, 0).Add(-v.leeway)) {
+		return ErrInvalidToken
+	}
+	if v.issuer != "" && c.Issuer != v.issuer {
+		return ErrInvalidToken
+	}
+	if v.audience != "" && !hasAudience(c.Audience, v.audience) {
+		return ErrInvalidToken
+	}
+	if c.Subject == "" {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// hasAudience tells whether the aud claim, a string or a list of strings, contains audience
+func hasAudience(aud interface{}, audience string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for i := range aud {
+			if s, ok := aud[i].(string); ok && s == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func verifySignature(k jwtKey, signed string, signature []byte) bool {
+	switch k.alg {
+	case AlgHS256:
+		mac := hmac.New(sha256.New, k.key.([]byte))
+		mac.Write([]byte(signed))
+		return hmac.Equal(mac.Sum(nil), signature)
+	case AlgRS256:
+		sum := sha256.Sum256([]byte(signed))
+		return rsa.VerifyPKCS1v15(k.key.(*rsa.PublicKey), crypto.SHA256, sum[:], signature) == nil
+	}
+	return false
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// loadPublicKey reads a PEM encoded RSA public key or certificate
+func loadPublicKey(path string) (*rsa.PublicKey, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("auth: %s is not PEM encoded", path)
+	}
+
+	var key interface{}
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key = cert.PublicKey
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("auth: %s is not a RSA public key", path)
+	}
+	return rsaKey, nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+// loadJWKS reads the RSA and symmetric signing keys of a local JWK Set file, keys without alg default to alg
+func (v *jwtVerifier) loadJWKS(path string, alg string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	set := struct {
+		Keys []jwk `json:"keys"`
+	}{}
+	if err := json.Unmarshal(b, &set); err != nil {
+		return fmt.Errorf("auth: %s: %v", path, err)
+	}
+
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key := jwtKey{alg: k.Alg}
+		switch k.Kty {
+		case "RSA":
+			if key.alg == "" {
+				key.alg = AlgRS256
+			}
+			n, errN := base64.RawURLEncoding.DecodeString(k.N)
+			e, errE := base64.RawURLEncoding.DecodeString(k.E)
+			if errN != nil || errE != nil {
+				return fmt.Errorf("auth: %s: key %q is not valid", path, k.Kid)
+			}
+			key.key = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case "oct":
+			if key.alg == "" {
+				key.alg = alg
+			}
+			secret, err := base64.RawURLEncoding.DecodeString(k.K)
+			if err != nil {
+				return fmt.Errorf("auth: %s: key %q is not valid", path, k.Kid)
+			}
+			key.key = secret
+		default:
+			continue
+		}
+		if key.alg != AlgHS256 && key.alg != AlgRS256 {
+			continue
+		}
+		if (key.alg == AlgHS256) != (k.Kty == "oct") {
+			return fmt.Errorf("auth: %s: key %q does not match %s", path, k.Kid, key.alg)
+		}
+		v.keys[k.Kid] = key
+	}
+	if len(v.keys) == 0 {
+		return fmt.Errorf("auth: %s has no signing key", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package auth_test
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/williamchand/kuncie-cart/auth"
+)
+
+func segment(t *testing.T, v interface{}) string {
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func signHS256(t *testing.T, secret string, header map[string]interface{}, claims map[string]interface{}) string {
+	signed := segment(t, header) + "." + segment(t, claims)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, header map[string]interface{}, claims map[string]interface{}) string {
+	signed := segment(t, header) + "." + segment(t, claims)
+	sum := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	require.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestVerifyHS256(t *testing.T) {
+	v, err := auth.NewJWTVerifier(auth.Config{Algorithm: auth.AlgHS256, Secret: "secret", Issuer: "kuncie-cart"})
+	require.NoError(t, err)
+	header := map[string]interface{}{"alg": "HS256", "typ": "JWT"}
+	claims := func(exp time.Time) map[string]interface{} {
+		return map[string]interface{}{"sub": "5", "iss": "kuncie-cart", "exp": exp.Unix()}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		p, err := v.Verify(signHS256(t, "secret", header, claims(time.Now().Add(time.Hour))))
+
+		require.NoError(t, err)
+		assert.Equal(t, "5", p.Subject)
+		assert.Equal(t, int64(5), p.CustomerID)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		_, err := v.Verify(signHS256(t, "secret", header, claims(time.Now().Add(-time.Hour))))
+
+		assert.Equal(t, auth.ErrInvalidToken, err)
+	})
+
+	t.Run("wrong-secret", func(t *testing.T) {
+		_, err := v.Verify(signHS256(t, "other", header, claims(time.Now().Add(time.Hour))))
+
+		assert.Equal(t, auth.ErrInvalidToken, err)
+	})
+
+	t.Run("wrong-issuer", func(t *testing.T) {
+		c := claims(time.Now().Add(time.Hour))
+		c["iss"] = "someone-else"
+		_, err := v.Verify(signHS256(t, "secret", header, c))
+
+		assert.Equal(t, auth.ErrInvalidToken, err)
+	})
+
+	t.Run("alg-none", func(t *testing.T) {
+		token := segment(t, map[string]interface{}{"alg": "none"}) + "." + segment(t, claims(time.Now().Add(time.Hour))) + "."
+		_, err := v.Verify(token)
+
+		assert.Equal(t, auth.ErrInvalidToken, err)
+	})
+}
+
+func TestVerifyRS256WithJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	dir, err := ioutil.TempDir("", "jwks")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "jwks.json")
+	jwks := map[string]interface{}{"keys": []map[string]interface{}{{
+		"kty": "RSA",
+		"kid": "key-1",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+	b, err := json.Marshal(jwks)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(path, b, 0600))
+
+	v, err := auth.NewJWTVerifier(auth.Config{JWKSFile: path, Audience: "shop"})
+	require.NoError(t, err)
+	claims := map[string]interface{}{"sub": "admin@kuncie", "aud": []string{"shop"}, "exp": time.Now().Add(time.Hour).Unix()}
+
+	t.Run("success", func(t *testing.T) {
+		p, err := v.Verify(signRS256(t, key, map[string]interface{}{"alg": "RS256", "kid": "key-1"}, claims))
+
+		require.NoError(t, err)
+		assert.Equal(t, "admin@kuncie", p.Subject)
+		assert.Equal(t, int64(0), p.CustomerID)
+	})
+
+	t.Run("unknown-kid", func(t *testing.T) {
+		_, err := v.Verify(signRS256(t, key, map[string]interface{}{"alg": "RS256", "kid": "key-2"}, claims))
+
+		assert.Equal(t, auth.ErrInvalidToken, err)
+	})
+
+	t.Run("hs256-with-public-key", func(t *testing.T) {
+		token := signHS256(t, string(key.N.Bytes()), map[string]interface{}{"alg": "HS256", "kid": "key-1"}, claims)
+		_, err := v.Verify(token)
+
+		assert.Equal(t, auth.ErrInvalidToken, err)
+	})
+}
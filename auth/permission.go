@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// Permission represent an operation reserved to some roles
+type Permission string
+
+const (
+	// PermManageCatalog allows to change items, products, categories, kits, promotions and stock
+	PermManageCatalog Permission = "catalog:manage"
+	// PermManageOrders allows to settle the returns of any order and to export orders
+	PermManageOrders Permission = "orders:manage"
+	// PermManageCustomers allows to read and change the account and address book of any customer
+	PermManageCustomers Permission = "customers:manage"
+	// PermManageCredit allows to issue gift cards, and to read and add to the store credit of any customer
+	PermManageCredit Permission = "credit:manage"
+	// PermReadReports allows to read the sales reports
+	PermReadReports Permission = "reports:read"
+)
+
+const (
+	// RoleShopper is the role of customers, it has no permission beyond their own account
+	RoleShopper = "shopper"
+	// RoleSupport is the role of the customer service
+	RoleSupport = "support"
+	// RoleAdmin is the role of the store operators, it has every permission
+	RoleAdmin = "admin"
+)
+
+var rolePermissions = map[string][]Permission{
+	RoleShopper: {},
+	RoleSupport: {PermManageOrders, PermManageCustomers, PermManageCredit},
+	RoleAdmin:   {PermManageCatalog, PermManageOrders, PermManageCustomers, PermManageCredit, PermReadReports},
+}
+
+// HasPermission tells whether one of the roles of the principal grants perm, unknown roles grant nothing
+func HasPermission(p *models.Principal, perm Permission) bool {
+	if p == nil {
+		return false
+	}
+	for _, role := range p.Roles {
+		for _, granted := range rolePermissions[role] {
+			if granted == perm {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Authorize returns models.ErrUnauthorized for anonymous requests and models.ErrForbidden when the principal
+// in the context lacks perm
+func Authorize(ctx context.Context, perm Permission) error {
+	p, ok := FromContext(ctx)
+	if !ok {
+		return models.ErrUnauthorized
+	}
+	if !HasPermission(p, perm) {
+		return models.ErrForbidden
+	}
+	return nil
+}
+
+// AuthorizeCustomer lets a customer act on their own account and the principals with perm act on any
+func AuthorizeCustomer(ctx context.Context, customerID int64, perm Permission) error {
+	p, ok := FromContext(ctx)
+	if !ok {
+		return models.ErrUnauthorized
+	}
+	if (p.CustomerID != 0 && p.CustomerID == customerID) || HasPermission(p, perm) {
+		return nil
+	}
+	return models.ErrForbidden
+}
+
+// AuthorizeOrder lets a customer act on their own orders and the principals with PermManageOrders act on any.
+// Orders placed without an account belong to nobody but the staff.
+func AuthorizeOrder(ctx context.Context, o *models.Order) error {
+	return AuthorizeCustomer(ctx, o.CustomerID, PermManageOrders)
+}
+
+// Require wraps the resolver of a graphql field so it only runs for principals with perm
+func Require(perm Permission, resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		if err := Authorize(params.Context, perm); err != nil {
+			return nil, err
+		}
+		return resolve(params)
+	}
+}
+
+// RequireCustomer wraps the resolver of a graphql field so it only runs for the customer given in the arg
+// argument or for principals with perm
+func RequireCustomer(arg string, perm Permission, resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		customerID, _ := params.Args[arg].(int)
+		if err := AuthorizeCustomer(params.Context, int64(customerID), perm); err != nil {
+			return nil, err
+		}
+		return resolve(params)
+	}
+}
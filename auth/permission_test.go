@@ -0,0 +1,98 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/williamchand/kuncie-cart/auth"
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+func TestRequire(t *testing.T) {
+	resolve := auth.Require(auth.PermManageCatalog, func(params graphql.ResolveParams) (interface{}, error) {
+		return "done", nil
+	})
+	tests := []struct {
+		name      string
+		principal *models.Principal
+		err       error
+	}{
+		{name: "anonymous", err: models.ErrUnauthorized},
+		{name: "shopper", principal: &models.Principal{Subject: "5", CustomerID: 5, Roles: []string{auth.RoleShopper}}, err: models.ErrForbidden},
+		{name: "support", principal: &models.Principal{Subject: "agent", Roles: []string{auth.RoleSupport}}, err: models.ErrForbidden},
+		{name: "unknown-role", principal: &models.Principal{Subject: "bot", Roles: []string{"root"}}, err: models.ErrForbidden},
+		{name: "admin", principal: &models.Principal{Subject: "ops", Roles: []string{auth.RoleAdmin}}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.TODO()
+			if tc.principal != nil {
+				ctx = auth.NewContext(ctx, tc.principal)
+			}
+			res, err := resolve(graphql.ResolveParams{Context: ctx})
+
+			assert.Equal(t, tc.err, err)
+			if tc.err == nil {
+				assert.Equal(t, "done", res)
+			}
+		})
+	}
+}
+
+func TestRequireCustomer(t *testing.T) {
+	resolve := auth.RequireCustomer("customer_id", auth.PermManageCustomers, func(params graphql.ResolveParams) (interface{}, error) {
+		return "done", nil
+	})
+	tests := []struct {
+		name      string
+		principal *models.Principal
+		err       error
+	}{
+		{name: "owner", principal: &models.Principal{Subject: "5", CustomerID: 5}},
+		{name: "other-customer", principal: &models.Principal{Subject: "6", CustomerID: 6}, err: models.ErrForbidden},
+		{name: "support", principal: &models.Principal{Subject: "agent", Roles: []string{auth.RoleSupport}}},
+		{name: "anonymous", err: models.ErrUnauthorized},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.TODO()
+			if tc.principal != nil {
+				ctx = auth.NewContext(ctx, tc.principal)
+			}
+			_, err := resolve(graphql.ResolveParams{Context: ctx, Args: map[string]interface{}{"customer_id": 5}})
+
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestAuthorizeOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		order     *models.Order
+		principal *models.Principal
+		err       error
+	}{
+		{name: "owner", order: &models.Order{ID: 7, CustomerID: 5}, principal: &models.Principal{Subject: "5", CustomerID: 5}},
+		{name: "other-customer", order: &models.Order{ID: 7, CustomerID: 5}, principal: &models.Principal{Subject: "6", CustomerID: 6}, err: models.ErrForbidden},
+		{name: "guest-order", order: &models.Order{ID: 7}, principal: &models.Principal{Subject: "6", CustomerID: 6}, err: models.ErrForbidden},
+		{name: "support", order: &models.Order{ID: 7, CustomerID: 5}, principal: &models.Principal{Subject: "agent", Roles: []string{auth.RoleSupport}}},
+		{name: "anonymous", order: &models.Order{ID: 7}, err: models.ErrUnauthorized},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.TODO()
+			if tc.principal != nil {
+				ctx = auth.NewContext(ctx, tc.principal)
+			}
+
+			assert.Equal(t, tc.err, auth.AuthorizeOrder(ctx, tc.order))
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+)
+
+// HeaderSessionToken carries the token of the anonymous session owning a guest cart
+const HeaderSessionToken = "X-Session-Token"
+
+var sessionTokenPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{16,128}$`)
+
+type sessionKey struct{}
+
+// NewSessionToken returns a random session token
+func NewSessionToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ValidSessionToken tells whether a token sent by a client can be used as a session token
+func ValidSessionToken(token string) bool {
+	return sessionTokenPattern.MatchString(token)
+}
+
+// NewSessionContext returns a copy of ctx carrying the session token
+func NewSessionContext(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, sessionKey{}, token)
+}
+
+// SessionFromContext returns the session token bound to the context, empty when there is none
+func SessionFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(sessionKey{}).(string)
+	return token
+}
@@ -0,0 +1,85 @@
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/williamchand/kuncie-cart/credit"
+)
+
+type Resolver interface {
+	GiftCard(params graphql.ResolveParams) (interface{}, error)
+	StoreCredit(params graphql.ResolveParams) (interface{}, error)
+	IssueGiftCard(params graphql.ResolveParams) (interface{}, error)
+	IssueStoreCredit(params graphql.ResolveParams) (interface{}, error)
+}
+
+type resolver struct {
+	creditService credit.Usecase
+}
+
+func (r resolver) GiftCard(params graphql.ResolveParams) (interface{}, error) {
+	code, ok := params.Args["code"].(string)
+	if !ok || code == "" {
+		return nil, fmt.Errorf("code is empty or not string")
+	}
+
+	res, err := r.creditService.GetGiftCard(params.Context, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func (r resolver) StoreCredit(params graphql.ResolveParams) (interface{}, error) {
+	customerID, ok := params.Args["customer_id"].(int)
+	if !ok || customerID == 0 {
+		return nil, fmt.Errorf("customer_id is not integer or zero")
+	}
+
+	res, err := r.creditService.GetStoreCredit(params.Context, int64(customerID))
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func (r resolver) IssueGiftCard(params graphql.ResolveParams) (interface{}, error) {
+	amount, ok := params.Args["amount"].(float64)
+	if !ok || amount <= 0 {
+		return nil, fmt.Errorf("amount is not a positive number")
+	}
+
+	res, err := r.creditService.IssueGiftCard(params.Context, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func (r resolver) IssueStoreCredit(params graphql.ResolveParams) (interface{}, error) {
+	customerID, ok := params.Args["customer_id"].(int)
+	if !ok || customerID == 0 {
+		return nil, fmt.Errorf("customer_id is not integer or zero")
+	}
+	amount, ok := params.Args["amount"].(float64)
+	if !ok || amount <= 0 {
+		return nil, fmt.Errorf("amount is not a positive number")
+	}
+
+	res, err := r.creditService.IssueStoreCredit(params.Context, int64(customerID), 0, 0, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func NewResolver(creditService credit.Usecase) Resolver {
+	return &resolver{
+		creditService: creditService,
+	}
+}
@@ -0,0 +1,149 @@
+package graphql
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/williamchand/kuncie-cart/auth"
+)
+
+// GiftCardGraphQL holds gift card information with graphql object
+var GiftCardGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "GiftCard",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"code": &graphql.Field{
+				Type: graphql.String,
+			},
+			"initial_balance": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"balance": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"updated_at": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+			"created_at": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+		},
+	},
+)
+
+// CreditEntryGraphQL holds a ledger entry with graphql object
+var CreditEntryGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "CreditEntry",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"order_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"refund_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"amount": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"balance_after": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"created_at": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+		},
+	},
+)
+
+// StoreCreditGraphQL holds the wallet of a customer with graphql object
+var StoreCreditGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "StoreCredit",
+		Fields: graphql.Fields{
+			"customer_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"balance": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"entries": &graphql.Field{
+				Type: graphql.NewList(CreditEntryGraphQL),
+			},
+			"updated_at": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+		},
+	},
+)
+
+// Schema is struct which has method for Query and Mutation fields. Please init this struct using constructor function.
+type Schema struct {
+	creditResolver Resolver
+}
+
+// QueryFields initializes the gift card and store credit fields of the graphql query.
+func (s Schema) QueryFields() graphql.Fields {
+	return graphql.Fields{
+		"GiftCard": &graphql.Field{
+			Type:        GiftCardGraphQL,
+			Description: "Get the balance of a gift card",
+			Args: graphql.FieldConfigArgument{
+				"code": &graphql.ArgumentConfig{
+					Type: graphql.String,
+				},
+			},
+			Resolve: s.creditResolver.GiftCard,
+		},
+		"StoreCredit": &graphql.Field{
+			Type:        StoreCreditGraphQL,
+			Description: "Get the store credit of a customer and its ledger",
+			Args: graphql.FieldConfigArgument{
+				"customer_id": &graphql.ArgumentConfig{
+					Type: graphql.Int,
+				},
+			},
+			Resolve: auth.RequireCustomer("customer_id", auth.PermManageCredit, s.creditResolver.StoreCredit),
+		},
+	}
+}
+
+// MutationFields initializes the gift card fields of the graphql mutation.
+func (s Schema) MutationFields() graphql.Fields {
+	return graphql.Fields{
+		"IssueGiftCard": &graphql.Field{
+			Type:        GiftCardGraphQL,
+			Description: "Issue a gift card with a new code",
+			Args: graphql.FieldConfigArgument{
+				"amount": &graphql.ArgumentConfig{
+					Type: graphql.Float,
+				},
+			},
+			Resolve: auth.Require(auth.PermManageCredit, s.creditResolver.IssueGiftCard),
+		},
+		"IssueStoreCredit": &graphql.Field{
+			Type:        StoreCreditGraphQL,
+			Description: "Add to the store credit of a customer",
+			Args: graphql.FieldConfigArgument{
+				"customer_id": &graphql.ArgumentConfig{
+					Type: graphql.Int,
+				},
+				"amount": &graphql.ArgumentConfig{
+					Type: graphql.Float,
+				},
+			},
+			Resolve: auth.Require(auth.PermManageCredit, s.creditResolver.IssueStoreCredit),
+		},
+	}
+}
+
+// NewSchema initializes Schema struct which takes resolver as the argument.
+func NewSchema(creditResolver Resolver) Schema {
+	return Schema{
+		creditResolver: creditResolver,
+	}
+}
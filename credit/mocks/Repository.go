@@ -0,0 +1,155 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	models "github.com/williamchand/kuncie-cart/models"
+)
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+// FetchEntries provides a mock function with given fields: ctx, account, accountID
+func (_m *Repository) FetchEntries(ctx context.Context, account string, accountID int64) ([]*models.CreditEntry, error) {
+	ret := _m.Called(ctx, account, accountID)
+
+	var r0 []*models.CreditEntry
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []*models.CreditEntry); ok {
+		r0 = rf(ctx, account, accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.CreditEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, account, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchEntriesByOrder provides a mock function with given fields: ctx, orderID
+func (_m *Repository) FetchEntriesByOrder(ctx context.Context, orderID int64) ([]*models.CreditEntry, error) {
+	ret := _m.Called(ctx, orderID)
+
+	var r0 []*models.CreditEntry
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*models.CreditEntry); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.CreditEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetGiftCard provides a mock function with given fields: ctx, code
+func (_m *Repository) GetGiftCard(ctx context.Context, code string) (*models.GiftCard, error) {
+	ret := _m.Called(ctx, code)
+
+	var r0 *models.GiftCard
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.GiftCard); ok {
+		r0 = rf(ctx, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GiftCard)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStoreCredit provides a mock function with given fields: ctx, customerID
+func (_m *Repository) GetStoreCredit(ctx context.Context, customerID int64) (*models.StoreCredit, error) {
+	ret := _m.Called(ctx, customerID)
+
+	var r0 *models.StoreCredit
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.StoreCredit); ok {
+		r0 = rf(ctx, customerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.StoreCredit)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, customerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StoreEntry provides a mock function with given fields: ctx, a
+func (_m *Repository) StoreEntry(ctx context.Context, a *models.CreditEntry) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.CreditEntry) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreGiftCard provides a mock function with given fields: ctx, a
+func (_m *Repository) StoreGiftCard(ctx context.Context, a *models.GiftCard) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.GiftCard) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateBalance provides a mock function with given fields: ctx, account, accountID, amount
+func (_m *Repository) UpdateBalance(ctx context.Context, account string, accountID int64, amount float64) (float64, error) {
+	ret := _m.Called(ctx, account, accountID, amount)
+
+	var r0 float64
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, float64) float64); ok {
+		r0 = rf(ctx, account, accountID, amount)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, float64) error); ok {
+		r1 = rf(ctx, account, accountID, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,148 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	models "github.com/williamchand/kuncie-cart/models"
+)
+
+// Usecase is an autogenerated mock type for the Usecase type
+type Usecase struct {
+	mock.Mock
+}
+
+// GetGiftCard provides a mock function with given fields: ctx, code
+func (_m *Usecase) GetGiftCard(ctx context.Context, code string) (*models.GiftCard, error) {
+	ret := _m.Called(ctx, code)
+
+	var r0 *models.GiftCard
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.GiftCard); ok {
+		r0 = rf(ctx, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GiftCard)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStoreCredit provides a mock function with given fields: ctx, customerID
+func (_m *Usecase) GetStoreCredit(ctx context.Context, customerID int64) (*models.StoreCredit, error) {
+	ret := _m.Called(ctx, customerID)
+
+	var r0 *models.StoreCredit
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.StoreCredit); ok {
+		r0 = rf(ctx, customerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.StoreCredit)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, customerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IssueGiftCard provides a mock function with given fields: ctx, amount
+func (_m *Usecase) IssueGiftCard(ctx context.Context, amount float64) (*models.GiftCard, error) {
+	ret := _m.Called(ctx, amount)
+
+	var r0 *models.GiftCard
+	if rf, ok := ret.Get(0).(func(context.Context, float64) *models.GiftCard); ok {
+		r0 = rf(ctx, amount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GiftCard)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, float64) error); ok {
+		r1 = rf(ctx, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IssueStoreCredit provides a mock function with given fields: ctx, customerID, orderID, refundID, amount
+func (_m *Usecase) IssueStoreCredit(ctx context.Context, customerID int64, orderID int64, refundID int64, amount float64) (*models.StoreCredit, error) {
+	ret := _m.Called(ctx, customerID, orderID, refundID, amount)
+
+	var r0 *models.StoreCredit
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64, float64) *models.StoreCredit); ok {
+		r0 = rf(ctx, customerID, orderID, refundID, amount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.StoreCredit)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, int64, float64) error); ok {
+		r1 = rf(ctx, customerID, orderID, refundID, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Redeem provides a mock function with given fields: ctx, orderID, tenders, amount
+func (_m *Usecase) Redeem(ctx context.Context, orderID int64, tenders []*models.Tender, amount float64) (float64, error) {
+	ret := _m.Called(ctx, orderID, tenders, amount)
+
+	var r0 float64
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []*models.Tender, float64) float64); ok {
+		r0 = rf(ctx, orderID, tenders, amount)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []*models.Tender, float64) error); ok {
+		r1 = rf(ctx, orderID, tenders, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, orderID, refundID, amount
+func (_m *Usecase) Restore(ctx context.Context, orderID int64, refundID int64, amount float64) (float64, error) {
+	ret := _m.Called(ctx, orderID, refundID, amount)
+
+	var r0 float64
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, float64) float64); ok {
+		r0 = rf(ctx, orderID, refundID, amount)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, float64) error); ok {
+		r1 = rf(ctx, orderID, refundID, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
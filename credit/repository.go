@@ -0,0 +1,20 @@
+package credit
+
+import (
+	"context"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// Repository represent the gift card and store credit's repository contract
+type Repository interface {
+	GetGiftCard(ctx context.Context, code string) (*models.GiftCard, error)
+	StoreGiftCard(ctx context.Context, a *models.GiftCard) error
+	GetStoreCredit(ctx context.Context, customerID int64) (*models.StoreCredit, error)
+	// UpdateBalance adds amount, negative for a debit, to the balance of the account and returns the new balance.
+	// It fails with models.ErrInsufficientBalance when the balance would become negative.
+	UpdateBalance(ctx context.Context, account string, accountID int64, amount float64) (float64, error)
+	StoreEntry(ctx context.Context, a *models.CreditEntry) error
+	FetchEntries(ctx context.Context, account string, accountID int64) ([]*models.CreditEntry, error)
+	FetchEntriesByOrder(ctx context.Context, orderID int64) ([]*models.CreditEntry, error)
+}
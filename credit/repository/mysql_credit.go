@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/williamchand/kuncie-cart/credit"
+	"github.com/williamchand/kuncie-cart/logging"
+	"github.com/williamchand/kuncie-cart/metrics"
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/transaction"
+)
+
+// errDuplicateEntry is the mysql error number of a unique key violation
+const errDuplicateEntry = 1062
+
+type mysqlCreditRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlCreditRepository will create an object that represent the credit.Repository interface
+func NewMysqlCreditRepository(Conn *sql.DB) credit.Repository {
+	return &mysqlCreditRepository{Conn}
+}
+
+func (m *mysqlCreditRepository) GetGiftCard(ctx context.Context, code string) (*models.GiftCard, error) {
+	defer metrics.ObserveQuery("credit", "GetGiftCard", time.Now())
+	query := `SELECT id, code, initial_balance, balance, updated_at, created_at FROM gift_cards WHERE code = ?`
+
+	res := new(models.GiftCard)
+	err := transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, query, code).Scan(
+		&res.ID,
+		&res.Code,
+		&res.InitialBalance,
+		&res.Balance,
+		&res.UpdatedAt,
+		&res.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (m *mysqlCreditRepository) StoreGiftCard(ctx context.Context, a *models.GiftCard) error {
+	defer metrics.ObserveQuery("credit", "StoreGiftCard", time.Now())
+	query := `INSERT gift_cards SET code=?, initial_balance=?, balance=?, updated_at=?, created_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, a.Code, a.InitialBalance, a.Balance, a.UpdatedAt, a.CreatedAt)
+	if err != nil {
+		if me, ok := err.(*mysql.MySQLError); ok && me.Number == errDuplicateEntry {
+			return models.ErrConflict
+		}
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	a.ID = lastID
+	return nil
+}
+
+func (m *mysqlCreditRepository) GetStoreCredit(ctx context.Context, customerID int64) (*models.StoreCredit, error) {
+	defer metrics.ObserveQuery("credit", "GetStoreCredit", time.Now())
+	query := `SELECT customer_id, balance, updated_at FROM store_credits WHERE customer_id = ?`
+
+	res := new(models.StoreCredit)
+	err := transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, query, customerID).Scan(
+		&res.CustomerID,
+		&res.Balance,
+		&res.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (m *mysqlCreditRepository) UpdateBalance(ctx context.Context, account string, accountID int64, amount float64) (float64, error) {
+	defer metrics.ObserveQuery("credit", "UpdateBalance", time.Now())
+	var (
+		update string
+		check  string
+	)
+	switch account {
+	case models.CreditAccountGiftCard:
+		update = `UPDATE gift_cards SET balance = balance + ?, updated_at = ? WHERE id = ? AND balance + ? >= 0`
+		check = `SELECT balance FROM gift_cards WHERE id = ?`
+	case models.CreditAccountStoreCredit:
+		if amount >= 0 {
+			update = `INSERT INTO store_credits (balance, updated_at, customer_id) VALUES (?, ?, ?)
+						ON DUPLICATE KEY UPDATE balance = balance + VALUES(balance), updated_at = VALUES(updated_at)`
+		} else {
+			update = `UPDATE store_credits SET balance = balance + ?, updated_at = ? WHERE customer_id = ? AND balance + ? >= 0`
+		}
+		check = `SELECT balance FROM store_credits WHERE customer_id = ?`
+	default:
+		return 0, fmt.Errorf("unknown credit account %q", account)
+	}
+
+	conn := transaction.Conn(ctx, m.Conn)
+	args := []interface{}{amount, time.Now(), accountID}
+	if amount < 0 || account == models.CreditAccountGiftCard {
+		args = append(args, amount)
+	}
+	res, err := conn.ExecContext(ctx, update, args...)
+	if err != nil {
+		return 0, err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	var balance float64
+	err = conn.QueryRowContext(ctx, check, accountID).Scan(&balance)
+	if err == sql.ErrNoRows {
+		return 0, models.ErrNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	if affect == 0 {
+		return 0, models.ErrInsufficientBalance
+	}
+
+	return balance, nil
+}
+
+func (m *mysqlCreditRepository) StoreEntry(ctx context.Context, a *models.CreditEntry) error {
+	defer metrics.ObserveQuery("credit", "StoreEntry", time.Now())
+	query := `INSERT credit_ledger SET account=?, account_id=?, order_id=?, refund_id=?, amount=?, balance_after=?, created_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, a.Account, a.AccountID, a.OrderID, a.RefundID, a.Amount, a.BalanceAfter, a.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	a.ID = lastID
+	return nil
+}
+
+func (m *mysqlCreditRepository) fetchEntries(ctx context.Context, query string, args ...interface{}) ([]*models.CreditEntry, error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	result := make([]*models.CreditEntry, 0)
+	for rows.Next() {
+		t := new(models.CreditEntry)
+		err = rows.Scan(
+			&t.ID,
+			&t.Account,
+			&t.AccountID,
+			&t.OrderID,
+			&t.RefundID,
+			&t.Amount,
+			&t.BalanceAfter,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+func (m *mysqlCreditRepository) FetchEntries(ctx context.Context, account string, accountID int64) ([]*models.CreditEntry, error) {
+	defer metrics.ObserveQuery("credit", "FetchEntries", time.Now())
+	query := `SELECT id, account, account_id, order_id, refund_id, amount, balance_after, created_at
+  						FROM credit_ledger WHERE account = ? AND account_id = ? ORDER BY id`
+
+	return m.fetchEntries(ctx, query, account, accountID)
+}
+
+func (m *mysqlCreditRepository) FetchEntriesByOrder(ctx context.Context, orderID int64) ([]*models.CreditEntry, error) {
+	defer metrics.ObserveQuery("credit", "FetchEntriesByOrder", time.Now())
+	query := `SELECT id, account, account_id, order_id, refund_id, amount, balance_after, created_at
+  						FROM credit_ledger WHERE order_id = ? ORDER BY id`
+
+	return m.fetchEntries(ctx, query, orderID)
+}
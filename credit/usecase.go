@@ -0,0 +1,20 @@
+package credit
+
+import (
+	"context"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// Usecase represent the gift card and store credit's usecases
+type Usecase interface {
+	GetGiftCard(ctx context.Context, code string) (*models.GiftCard, error)
+	IssueGiftCard(ctx context.Context, amount float64) (*models.GiftCard, error)
+	GetStoreCredit(ctx context.Context, customerID int64) (*models.StoreCredit, error)
+	// IssueStoreCredit adds amount to the wallet of the customer, orderID and refundID are zero when it is not a refund
+	IssueStoreCredit(ctx context.Context, customerID int64, orderID int64, refundID int64, amount float64) (*models.StoreCredit, error)
+	// Redeem debits the tenders, in the given order, to pay up to amount of the order and returns the amount paid
+	Redeem(ctx context.Context, orderID int64, tenders []*models.Tender, amount float64) (float64, error)
+	// Restore credits back, up to amount, what the order was paid with and returns the amount restored
+	Restore(ctx context.Context, orderID int64, refundID int64, amount float64) (float64, error)
+}
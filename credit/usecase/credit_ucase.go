@@ -0,0 +1,301 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"math"
+	"time"
+
+	"github.com/williamchand/kuncie-cart/auth"
+	"github.com/williamchand/kuncie-cart/credit"
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/transaction"
+)
+
+// codeAlphabet leaves out the characters that are easily confused when a code is typed
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+type creditUsecase struct {
+	creditRepo     credit.Repository
+	txManager      transaction.Manager
+	contextTimeout time.Duration
+}
+
+// NewCreditUsecase will create new a creditUsecase object representation of credit.Usecase interface
+func NewCreditUsecase(c credit.Repository, tx transaction.Manager, timeout time.Duration) credit.Usecase {
+	return &creditUsecase{
+		creditRepo:     c,
+		txManager:      tx,
+		contextTimeout: timeout,
+	}
+}
+
+func (a *creditUsecase) GetGiftCard(c context.Context, code string) (*models.GiftCard, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.creditRepo.GetGiftCard(ctx, code)
+}
+
+// IssueGiftCard creates a gift card with a random code, retrying when the code is already taken
+func (a *creditUsecase) IssueGiftCard(c context.Context, amount float64) (*models.GiftCard, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	amount = round(amount)
+	if amount <= 0 {
+		return nil, models.ErrBadParamInput
+	}
+
+	var res *models.GiftCard
+	err := models.ErrConflict
+	for attempt := 0; attempt < 3 && err == models.ErrConflict; attempt++ {
+		code, cErr := newCode()
+		if cErr != nil {
+			return nil, cErr
+		}
+		now := time.Now()
+		res = &models.GiftCard{
+			Code:           code,
+			InitialBalance: amount,
+			Balance:        amount,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		err = a.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := a.creditRepo.StoreGiftCard(ctx, res); err != nil {
+				return err
+			}
+			return a.creditRepo.StoreEntry(ctx, &models.CreditEntry{
+				Account:      models.CreditAccountGiftCard,
+				AccountID:    res.ID,
+				Amount:       amount,
+				BalanceAfter: amount,
+				CreatedAt:    now,
+			})
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetStoreCredit returns the wallet of the customer with its ledger, a customer without credit has an empty wallet
+func (a *creditUsecase) GetStoreCredit(c context.Context, customerID int64) (*models.StoreCredit, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, err := a.creditRepo.GetStoreCredit(ctx, customerID)
+	if err == models.ErrNotFound {
+		res, err = &models.StoreCredit{CustomerID: customerID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res.Entries, err = a.creditRepo.FetchEntries(ctx, models.CreditAccountStoreCredit, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// IssueStoreCredit credits the wallet of the customer within the transaction of the context and records it in the
+// ledger, the wallet is created by its first credit
+func (a *creditUsecase) IssueStoreCredit(c context.Context, customerID int64, orderID int64, refundID int64, amount float64) (*models.StoreCredit, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	amount = round(amount)
+	if customerID == 0 || amount <= 0 {
+		return nil, models.ErrBadParamInput
+	}
+
+	res := &models.StoreCredit{CustomerID: customerID, UpdatedAt: time.Now()}
+	err := a.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		balanceAfter, err := a.creditRepo.UpdateBalance(ctx, models.CreditAccountStoreCredit, customerID, amount)
+		if err != nil {
+			return err
+		}
+		res.Balance = balanceAfter
+		return a.creditRepo.StoreEntry(ctx, &models.CreditEntry{
+			Account:      models.CreditAccountStoreCredit,
+			AccountID:    customerID,
+			OrderID:      orderID,
+			RefundID:     refundID,
+			Amount:       amount,
+			BalanceAfter: balanceAfter,
+			CreatedAt:    res.UpdatedAt,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Redeem debits the tenders within the transaction of the context, so the debits are rolled back
+// together with the order they paid for. Store credit is taken from the wallet of the signed in customer.
+func (a *creditUsecase) Redeem(c context.Context, orderID int64, tenders []*models.Tender, amount float64) (float64, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	paid := 0.0
+	err := a.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		for _, t := range tenders {
+			remaining := round(amount - paid)
+			if remaining <= 0 {
+				break
+			}
+
+			var (
+				accountID int64
+				balance   float64
+			)
+			switch t.Account {
+			case models.CreditAccountGiftCard:
+				card, err := a.creditRepo.GetGiftCard(ctx, t.Code)
+				if err != nil {
+					return err
+				}
+				accountID, balance = card.ID, card.Balance
+			case models.CreditAccountStoreCredit:
+				// only the signed in customer can spend their own store credit
+				customerID, err := auth.CustomerID(ctx)
+				if err != nil {
+					return err
+				}
+				if t.CustomerID != 0 && t.CustomerID != customerID {
+					return models.ErrForbidden
+				}
+				wallet, err := a.creditRepo.GetStoreCredit(ctx, customerID)
+				if err != nil && err != models.ErrNotFound {
+					return err
+				}
+				accountID = customerID
+				if wallet != nil {
+					balance = wallet.Balance
+				}
+			default:
+				return models.ErrBadParamInput
+			}
+
+			debit := math.Min(balance, remaining)
+			if t.Amount > 0 {
+				debit = math.Min(round(t.Amount), remaining)
+			}
+			if debit <= 0 {
+				continue
+			}
+
+			balanceAfter, err := a.creditRepo.UpdateBalance(ctx, t.Account, accountID, -debit)
+			if err != nil {
+				return err
+			}
+			err = a.creditRepo.StoreEntry(ctx, &models.CreditEntry{
+				Account:      t.Account,
+				AccountID:    accountID,
+				OrderID:      orderID,
+				Amount:       -debit,
+				BalanceAfter: balanceAfter,
+				CreatedAt:    time.Now(),
+			})
+			if err != nil {
+				return err
+			}
+			paid = round(paid + debit)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return paid, nil
+}
+
+// Restore gives back to the gift cards and store credit that paid for the order, latest debit first,
+// what they have not been given back yet by previous refunds.
+func (a *creditUsecase) Restore(c context.Context, orderID int64, refundID int64, amount float64) (float64, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	type account struct {
+		name string
+		id   int64
+	}
+
+	restored := 0.0
+	err := a.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		entries, err := a.creditRepo.FetchEntriesByOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		accounts := make([]account, 0)
+		restorable := make(map[account]float64)
+		for _, e := range entries {
+			k := account{e.Account, e.AccountID}
+			if _, ok := restorable[k]; !ok {
+				accounts = append(accounts, k)
+			}
+			restorable[k] -= e.Amount
+		}
+
+		for i := len(accounts) - 1; i >= 0; i-- {
+			k := accounts[i]
+			back := round(math.Min(restorable[k], amount-restored))
+			if back <= 0 {
+				continue
+			}
+
+			balanceAfter, err := a.creditRepo.UpdateBalance(ctx, k.name, k.id, back)
+			if err != nil {
+				return err
+			}
+			err = a.creditRepo.StoreEntry(ctx, &models.CreditEntry{
+				Account:      k.name,
+				AccountID:    k.id,
+				OrderID:      orderID,
+				RefundID:     refundID,
+				Amount:       back,
+				BalanceAfter: balanceAfter,
+				CreatedAt:    time.Now(),
+			})
+			if err != nil {
+				return err
+			}
+			restored = round(restored + back)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return restored, nil
+}
+
+func newCode() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	code := make([]byte, 0, 19)
+	for i, c := range b {
+		if i > 0 && i%4 == 0 {
+			code = append(code, '-')
+		}
+		code = append(code, codeAlphabet[int(c)%len(codeAlphabet)])
+	}
+	return string(code), nil
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
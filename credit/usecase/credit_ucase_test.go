@@ -0,0 +1,166 @@
+package usecase_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/williamchand/kuncie-cart/auth"
+	"github.com/williamchand/kuncie-cart/credit/mocks"
+	ucase "github.com/williamchand/kuncie-cart/credit/usecase"
+	"github.com/williamchand/kuncie-cart/models"
+	_txMocks "github.com/williamchand/kuncie-cart/transaction/mocks"
+)
+
+func TestIssueGiftCard(t *testing.T) {
+	mockCreditRepo := new(mocks.Repository)
+	mockCreditRepo.On("StoreGiftCard", mock.Anything, mock.AnythingOfType("*models.GiftCard")).Return(models.ErrConflict).Once()
+	mockCreditRepo.On("StoreGiftCard", mock.Anything, mock.AnythingOfType("*models.GiftCard")).Return(nil).Once()
+	mockCreditRepo.On("StoreEntry", mock.Anything, mock.MatchedBy(func(e *models.CreditEntry) bool {
+		return e.Account == models.CreditAccountGiftCard && e.Amount == 50
+	})).Return(nil).Once()
+
+	u := ucase.NewCreditUsecase(mockCreditRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+	res, err := u.IssueGiftCard(context.TODO(), 50)
+
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[A-Z2-9]{4}(-[A-Z2-9]{4}){3}$`), res.Code)
+	assert.Equal(t, 50.0, res.Balance)
+	mockCreditRepo.AssertExpectations(t)
+}
+
+func TestIssueStoreCredit(t *testing.T) {
+	t.Run("refund-then-checkout", func(t *testing.T) {
+		mockCreditRepo := new(mocks.Repository)
+		mockCreditRepo.On("UpdateBalance", mock.Anything, models.CreditAccountStoreCredit, int64(5), 29.99).Return(29.99, nil).Once()
+		mockCreditRepo.On("StoreEntry", mock.Anything, mock.MatchedBy(func(e *models.CreditEntry) bool {
+			return e.Account == models.CreditAccountStoreCredit && e.AccountID == 5 && e.RefundID == 9 && e.Amount == 29.99
+		})).Return(nil).Once()
+		// the wallet funded by the refund pays for the next order
+		mockCreditRepo.On("GetStoreCredit", mock.Anything, int64(5)).
+			Return(&models.StoreCredit{CustomerID: 5, Balance: 29.99}, nil).Once()
+		mockCreditRepo.On("UpdateBalance", mock.Anything, models.CreditAccountStoreCredit, int64(5), -29.99).Return(0.0, nil).Once()
+		mockCreditRepo.On("StoreEntry", mock.Anything, mock.MatchedBy(func(e *models.CreditEntry) bool {
+			return e.OrderID == 13 && e.Amount == -29.99
+		})).Return(nil).Once()
+
+		u := ucase.NewCreditUsecase(mockCreditRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		wallet, err := u.IssueStoreCredit(context.TODO(), 5, 12, 9, 29.99)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 29.99, wallet.Balance)
+
+		paid, err := u.Redeem(auth.NewCustomerContext(context.TODO(), 5), 13, []*models.Tender{
+			{Account: models.CreditAccountStoreCredit},
+		}, 49.98)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 29.99, paid)
+		mockCreditRepo.AssertExpectations(t)
+	})
+
+	t.Run("guest", func(t *testing.T) {
+		mockCreditRepo := new(mocks.Repository)
+
+		u := ucase.NewCreditUsecase(mockCreditRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		res, err := u.IssueStoreCredit(context.TODO(), 0, 0, 0, 10)
+
+		assert.Equal(t, models.ErrBadParamInput, err)
+		assert.Nil(t, res)
+		mockCreditRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRedeem(t *testing.T) {
+	t.Run("gift-card-then-store-credit", func(t *testing.T) {
+		mockCreditRepo := new(mocks.Repository)
+		mockCreditRepo.On("GetGiftCard", mock.Anything, "ABCD-EFGH-JKLM-NPQR").
+			Return(&models.GiftCard{ID: 3, Code: "ABCD-EFGH-JKLM-NPQR", Balance: 25}, nil).Once()
+		mockCreditRepo.On("UpdateBalance", mock.Anything, models.CreditAccountGiftCard, int64(3), -25.0).Return(0.0, nil).Once()
+		mockCreditRepo.On("GetStoreCredit", mock.Anything, int64(5)).
+			Return(&models.StoreCredit{CustomerID: 5, Balance: 100}, nil).Once()
+		mockCreditRepo.On("UpdateBalance", mock.Anything, models.CreditAccountStoreCredit, int64(5), -24.98).Return(75.02, nil).Once()
+		mockCreditRepo.On("StoreEntry", mock.Anything, mock.MatchedBy(func(e *models.CreditEntry) bool {
+			return e.OrderID == 12 && e.Amount < 0
+		})).Return(nil).Twice()
+
+		u := ucase.NewCreditUsecase(mockCreditRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		paid, err := u.Redeem(auth.NewCustomerContext(context.TODO(), 5), 12, []*models.Tender{
+			{Account: models.CreditAccountGiftCard, Code: "ABCD-EFGH-JKLM-NPQR"},
+			{Account: models.CreditAccountStoreCredit},
+		}, 49.98)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 49.98, paid)
+		mockCreditRepo.AssertExpectations(t)
+	})
+
+	t.Run("store-credit-of-another-customer", func(t *testing.T) {
+		mockCreditRepo := new(mocks.Repository)
+
+		u := ucase.NewCreditUsecase(mockCreditRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		paid, err := u.Redeem(auth.NewCustomerContext(context.TODO(), 5), 12, []*models.Tender{
+			{Account: models.CreditAccountStoreCredit, CustomerID: 6},
+		}, 49.98)
+
+		assert.Equal(t, models.ErrForbidden, err)
+		assert.Zero(t, paid)
+		mockCreditRepo.AssertNotCalled(t, "GetStoreCredit", mock.Anything, mock.Anything)
+		mockCreditRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("store-credit-of-a-guest", func(t *testing.T) {
+		mockCreditRepo := new(mocks.Repository)
+
+		u := ucase.NewCreditUsecase(mockCreditRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		paid, err := u.Redeem(context.TODO(), 12, []*models.Tender{
+			{Account: models.CreditAccountStoreCredit, CustomerID: 6},
+		}, 49.98)
+
+		assert.Equal(t, models.ErrUnauthorized, err)
+		assert.Zero(t, paid)
+		mockCreditRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("insufficient-balance", func(t *testing.T) {
+		mockCreditRepo := new(mocks.Repository)
+		mockCreditRepo.On("GetGiftCard", mock.Anything, "ABCD-EFGH-JKLM-NPQR").
+			Return(&models.GiftCard{ID: 3, Code: "ABCD-EFGH-JKLM-NPQR", Balance: 25}, nil).Once()
+		mockCreditRepo.On("UpdateBalance", mock.Anything, models.CreditAccountGiftCard, int64(3), -40.0).
+			Return(0.0, models.ErrInsufficientBalance).Once()
+
+		u := ucase.NewCreditUsecase(mockCreditRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		paid, err := u.Redeem(context.TODO(), 12, []*models.Tender{
+			{Account: models.CreditAccountGiftCard, Code: "ABCD-EFGH-JKLM-NPQR", Amount: 40},
+		}, 49.98)
+
+		assert.Equal(t, models.ErrInsufficientBalance, err)
+		assert.Zero(t, paid)
+		mockCreditRepo.AssertNotCalled(t, "StoreEntry", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRestore(t *testing.T) {
+	mockCreditRepo := new(mocks.Repository)
+	mockCreditRepo.On("FetchEntriesByOrder", mock.Anything, int64(12)).Return([]*models.CreditEntry{
+		{Account: models.CreditAccountGiftCard, AccountID: 3, OrderID: 12, Amount: -25},
+		{Account: models.CreditAccountStoreCredit, AccountID: 5, OrderID: 12, Amount: -24.98},
+		{Account: models.CreditAccountStoreCredit, AccountID: 5, OrderID: 12, RefundID: 1, Amount: 10},
+	}, nil).Once()
+	mockCreditRepo.On("UpdateBalance", mock.Anything, models.CreditAccountStoreCredit, int64(5), 14.98).Return(100.0, nil).Once()
+	mockCreditRepo.On("UpdateBalance", mock.Anything, models.CreditAccountGiftCard, int64(3), 15.02).Return(15.02, nil).Once()
+	mockCreditRepo.On("StoreEntry", mock.Anything, mock.MatchedBy(func(e *models.CreditEntry) bool {
+		return e.RefundID == 2 && e.OrderID == 12 && e.Amount > 0
+	})).Return(nil).Twice()
+
+	u := ucase.NewCreditUsecase(mockCreditRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+	restored, err := u.Restore(context.TODO(), 12, 2, 30)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, restored)
+	mockCreditRepo.AssertExpectations(t)
+}
@@ -0,0 +1,140 @@
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/williamchand/kuncie-cart/customer"
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+type Resolver interface {
+	Customer(params graphql.ResolveParams) (interface{}, error)
+	CreateCustomer(params graphql.ResolveParams) (interface{}, error)
+	UpdateCustomer(params graphql.ResolveParams) (interface{}, error)
+	AddAddress(params graphql.ResolveParams) (interface{}, error)
+	UpdateAddress(params graphql.ResolveParams) (interface{}, error)
+	DeleteAddress(params graphql.ResolveParams) (interface{}, error)
+}
+
+type resolver struct {
+	customerService customer.Usecase
+}
+
+func (r resolver) Customer(params graphql.ResolveParams) (interface{}, error) {
+	id, ok := params.Args["id"].(int)
+	if !ok || id == 0 {
+		return nil, fmt.Errorf("id is not integer or zero")
+	}
+
+	res, err := r.customerService.GetByID(params.Context, int64(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func customerArgs(args map[string]interface{}) *models.Customer {
+	res := &models.Customer{}
+	res.Email, _ = args["email"].(string)
+	res.Name, _ = args["name"].(string)
+	res.Phone, _ = args["phone"].(string)
+	return res
+}
+
+func (r resolver) CreateCustomer(params graphql.ResolveParams) (interface{}, error) {
+	m := customerArgs(params.Args)
+	if err := r.customerService.Store(params.Context, m); err != nil {
+		return nil, err
+	}
+
+	return *m, nil
+}
+
+func (r resolver) UpdateCustomer(params graphql.ResolveParams) (interface{}, error) {
+	id, ok := params.Args["id"].(int)
+	if !ok || id == 0 {
+		return nil, fmt.Errorf("id is not integer or zero")
+	}
+
+	m := customerArgs(params.Args)
+	m.ID = int64(id)
+	if err := r.customerService.Update(params.Context, m); err != nil {
+		return nil, err
+	}
+
+	return *m, nil
+}
+
+func addressArgs(args map[string]interface{}) (*models.Address, error) {
+	customerID, ok := args["customer_id"].(int)
+	if !ok || customerID == 0 {
+		return nil, fmt.Errorf("customer_id is not integer or zero")
+	}
+	res := &models.Address{CustomerID: int64(customerID)}
+	res.Kind, _ = args["kind"].(string)
+	res.Recipient, _ = args["recipient"].(string)
+	res.Line1, _ = args["line1"].(string)
+	res.Line2, _ = args["line2"].(string)
+	res.City, _ = args["city"].(string)
+	res.PostalCode, _ = args["postal_code"].(string)
+	res.Country, _ = args["country"].(string)
+	res.Phone, _ = args["phone"].(string)
+	res.IsDefault, _ = args["is_default"].(bool)
+	return res, nil
+}
+
+func (r resolver) AddAddress(params graphql.ResolveParams) (interface{}, error) {
+	m, err := addressArgs(params.Args)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.customerService.StoreAddress(params.Context, m); err != nil {
+		return nil, err
+	}
+
+	return *m, nil
+}
+
+func (r resolver) UpdateAddress(params graphql.ResolveParams) (interface{}, error) {
+	id, ok := params.Args["id"].(int)
+	if !ok || id == 0 {
+		return nil, fmt.Errorf("id is not integer or zero")
+	}
+
+	m, err := addressArgs(params.Args)
+	if err != nil {
+		return nil, err
+	}
+	m.ID = int64(id)
+	if err := r.customerService.UpdateAddress(params.Context, m); err != nil {
+		return nil, err
+	}
+
+	return *m, nil
+}
+
+func (r resolver) DeleteAddress(params graphql.ResolveParams) (interface{}, error) {
+	id, ok := params.Args["id"].(int)
+	if !ok || id == 0 {
+		return nil, fmt.Errorf("id is not integer or zero")
+	}
+	customerID, ok := params.Args["customer_id"].(int)
+	if !ok || customerID == 0 {
+		return nil, fmt.Errorf("customer_id is not integer or zero")
+	}
+
+	if err := r.customerService.DeleteAddress(params.Context, int64(customerID), int64(id)); err != nil {
+		return nil, err
+	}
+
+	return true, nil
+}
+
+func NewResolver(customerService customer.Usecase) Resolver {
+	return &resolver{
+		customerService: customerService,
+	}
+}
@@ -0,0 +1,211 @@
+package graphql
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/williamchand/kuncie-cart/auth"
+)
+
+// AddressGraphQL holds a saved address with graphql object
+var AddressGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Address",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"customer_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"kind": &graphql.Field{
+				Type: graphql.String,
+			},
+			"recipient": &graphql.Field{
+				Type: graphql.String,
+			},
+			"line1": &graphql.Field{
+				Type: graphql.String,
+			},
+			"line2": &graphql.Field{
+				Type: graphql.String,
+			},
+			"city": &graphql.Field{
+				Type: graphql.String,
+			},
+			"postal_code": &graphql.Field{
+				Type: graphql.String,
+			},
+			"country": &graphql.Field{
+				Type: graphql.String,
+			},
+			"phone": &graphql.Field{
+				Type: graphql.String,
+			},
+			"is_default": &graphql.Field{
+				Type: graphql.Boolean,
+			},
+			"updated_at": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+			"created_at": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+		},
+	},
+)
+
+// CustomerGraphQL holds customer information with its address book with graphql object
+var CustomerGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Customer",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"email": &graphql.Field{
+				Type: graphql.String,
+			},
+			"name": &graphql.Field{
+				Type: graphql.String,
+			},
+			"phone": &graphql.Field{
+				Type: graphql.String,
+			},
+			"addresses": &graphql.Field{
+				Type: graphql.NewList(AddressGraphQL),
+			},
+			"updated_at": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+			"created_at": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+		},
+	},
+)
+
+// Schema is struct which has method for Query and Mutation. Please init this struct using constructor function.
+type Schema struct {
+	customerResolver Resolver
+}
+
+// QueryFields initializes the customer fields of the graphql query.
+func (s Schema) QueryFields() graphql.Fields {
+	return graphql.Fields{
+		"Customer": &graphql.Field{
+			Type:        CustomerGraphQL,
+			Description: "Get a customer with its address book",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+			},
+			Resolve: auth.RequireCustomer("id", auth.PermManageCustomers, s.customerResolver.Customer),
+		},
+	}
+}
+
+// MutationFields initializes the customer and address book fields of the graphql mutation.
+func (s Schema) MutationFields() graphql.Fields {
+	customerArgs := graphql.FieldConfigArgument{
+		"email": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"name": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"phone": &graphql.ArgumentConfig{
+			Type: graphql.String,
+		},
+	}
+	addressArgs := graphql.FieldConfigArgument{
+		"customer_id": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.Int),
+		},
+		"kind": &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "shipping or billing",
+		},
+		"recipient": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"line1": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"line2": &graphql.ArgumentConfig{
+			Type: graphql.String,
+		},
+		"city": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"postal_code": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"country": &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "ISO 3166-1 alpha-2 code",
+		},
+		"phone": &graphql.ArgumentConfig{
+			Type: graphql.String,
+		},
+		"is_default": &graphql.ArgumentConfig{
+			Type:         graphql.Boolean,
+			DefaultValue: false,
+		},
+	}
+	withArg := func(args graphql.FieldConfigArgument, name string) graphql.FieldConfigArgument {
+		res := graphql.FieldConfigArgument{
+			name: &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+		}
+		for k, v := range args {
+			res[k] = v
+		}
+		return res
+	}
+
+	return graphql.Fields{
+		"CreateCustomer": &graphql.Field{
+			Type:        CustomerGraphQL,
+			Description: "Register a customer",
+			Args:        customerArgs,
+			Resolve:     s.customerResolver.CreateCustomer,
+		},
+		"UpdateCustomer": &graphql.Field{
+			Type:        CustomerGraphQL,
+			Description: "Replace the details of a customer",
+			Args:        withArg(customerArgs, "id"),
+			Resolve:     auth.RequireCustomer("id", auth.PermManageCustomers, s.customerResolver.UpdateCustomer),
+		},
+		"AddAddress": &graphql.Field{
+			Type:        AddressGraphQL,
+			Description: "Save an address in the address book of a customer, the first one of a kind is the default",
+			Args:        addressArgs,
+			Resolve:     auth.RequireCustomer("customer_id", auth.PermManageCustomers, s.customerResolver.AddAddress),
+		},
+		"UpdateAddress": &graphql.Field{
+			Type:        AddressGraphQL,
+			Description: "Replace an address of the address book of a customer",
+			Args:        withArg(addressArgs, "id"),
+			Resolve:     auth.RequireCustomer("customer_id", auth.PermManageCustomers, s.customerResolver.UpdateAddress),
+		},
+		"DeleteAddress": &graphql.Field{
+			Type:        graphql.Boolean,
+			Description: "Remove an address from the address book of a customer",
+			Args: withArg(graphql.FieldConfigArgument{
+				"customer_id": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+			}, "id"),
+			Resolve: auth.RequireCustomer("customer_id", auth.PermManageCustomers, s.customerResolver.DeleteAddress),
+		},
+	}
+}
+
+// NewSchema initializes Schema struct which takes resolver as the argument.
+func NewSchema(customerResolver Resolver) Schema {
+	return Schema{
+		customerResolver: customerResolver,
+	}
+}
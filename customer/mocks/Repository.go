@@ -0,0 +1,167 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	models "github.com/williamchand/kuncie-cart/models"
+)
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+// ClearDefaultAddress provides a mock function with given fields: ctx, customerID, kind
+func (_m *Repository) ClearDefaultAddress(ctx context.Context, customerID int64, kind string) error {
+	ret := _m.Called(ctx, customerID, kind)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, customerID, kind)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteAddress provides a mock function with given fields: ctx, id
+func (_m *Repository) DeleteAddress(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchAddresses provides a mock function with given fields: ctx, customerID
+func (_m *Repository) FetchAddresses(ctx context.Context, customerID int64) ([]*models.Address, error) {
+	ret := _m.Called(ctx, customerID)
+
+	var r0 []*models.Address
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*models.Address); ok {
+		r0 = rf(ctx, customerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Address)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, customerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAddress provides a mock function with given fields: ctx, id
+func (_m *Repository) GetAddress(ctx context.Context, id int64) (*models.Address, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.Address
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.Address); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Address)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *Repository) GetByID(ctx context.Context, id int64) (*models.Customer, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.Customer
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.Customer); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Customer)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store provides a mock function with given fields: ctx, a
+func (_m *Repository) Store(ctx context.Context, a *models.Customer) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Customer) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreAddress provides a mock function with given fields: ctx, a
+func (_m *Repository) StoreAddress(ctx context.Context, a *models.Address) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Address) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, a
+func (_m *Repository) Update(ctx context.Context, a *models.Customer) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Customer) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateAddress provides a mock function with given fields: ctx, a
+func (_m *Repository) UpdateAddress(ctx context.Context, a *models.Address) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Address) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
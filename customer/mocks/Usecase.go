@@ -0,0 +1,130 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	models "github.com/williamchand/kuncie-cart/models"
+)
+
+// Usecase is an autogenerated mock type for the Usecase type
+type Usecase struct {
+	mock.Mock
+}
+
+// DeleteAddress provides a mock function with given fields: ctx, customerID, id
+func (_m *Usecase) DeleteAddress(ctx context.Context, customerID int64, id int64) error {
+	ret := _m.Called(ctx, customerID, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, customerID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAddress provides a mock function with given fields: ctx, customerID, id
+func (_m *Usecase) GetAddress(ctx context.Context, customerID int64, id int64) (*models.Address, error) {
+	ret := _m.Called(ctx, customerID, id)
+
+	var r0 *models.Address
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) *models.Address); ok {
+		r0 = rf(ctx, customerID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Address)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, customerID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *Usecase) GetByID(ctx context.Context, id int64) (*models.Customer, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.Customer
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.Customer); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Customer)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store provides a mock function with given fields: ctx, a
+func (_m *Usecase) Store(ctx context.Context, a *models.Customer) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Customer) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreAddress provides a mock function with given fields: ctx, a
+func (_m *Usecase) StoreAddress(ctx context.Context, a *models.Address) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Address) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, a
+func (_m *Usecase) Update(ctx context.Context, a *models.Customer) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Customer) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateAddress provides a mock function with given fields: ctx, a
+func (_m *Usecase) UpdateAddress(ctx context.Context, a *models.Address) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Address) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,20 @@
+package customer
+
+import (
+	"context"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// Repository represent the customer's repository contract
+type Repository interface {
+	GetByID(ctx context.Context, id int64) (*models.Customer, error)
+	Store(ctx context.Context, a *models.Customer) error
+	Update(ctx context.Context, a *models.Customer) error
+	FetchAddresses(ctx context.Context, customerID int64) (res []*models.Address, err error)
+	GetAddress(ctx context.Context, id int64) (*models.Address, error)
+	StoreAddress(ctx context.Context, a *models.Address) error
+	UpdateAddress(ctx context.Context, a *models.Address) error
+	DeleteAddress(ctx context.Context, id int64) error
+	ClearDefaultAddress(ctx context.Context, customerID int64, kind string) error
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/williamchand/kuncie-cart/customer"
+	"github.com/williamchand/kuncie-cart/logging"
+	"github.com/williamchand/kuncie-cart/metrics"
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/transaction"
+)
+
+// errDuplicateEntry is the mysql error number of a unique key violation
+const errDuplicateEntry = 1062
+
+type mysqlCustomerRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlCustomerRepository will create an object that represent the customer.Repository interface
+func NewMysqlCustomerRepository(Conn *sql.DB) customer.Repository {
+	return &mysqlCustomerRepository{Conn}
+}
+
+func (m *mysqlCustomerRepository) GetByID(ctx context.Context, id int64) (*models.Customer, error) {
+	defer metrics.ObserveQuery("customer", "GetByID", time.Now())
+	query := `SELECT id, email, name, phone, updated_at, created_at FROM customers WHERE id = ?`
+	res := new(models.Customer)
+	err := transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, query, id).Scan(
+		&res.ID,
+		&res.Email,
+		&res.Name,
+		&res.Phone,
+		&res.UpdatedAt,
+		&res.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Store adds a customer, an email already in use fails with models.ErrConflict
+func (m *mysqlCustomerRepository) Store(ctx context.Context, a *models.Customer) error {
+	defer metrics.ObserveQuery("customer", "Store", time.Now())
+	query := `INSERT customers SET email=?, name=?, phone=?, updated_at=?, created_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, a.Email, a.Name, a.Phone, a.UpdatedAt, a.CreatedAt)
+	if err != nil {
+		if me, ok := err.(*mysql.MySQLError); ok && me.Number == errDuplicateEntry {
+			return models.ErrConflict
+		}
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	a.ID = lastID
+	return nil
+}
+
+// exec runs an update of a single row and tells models.ErrNotFound apart from an update that changed nothing
+func (m *mysqlCustomerRepository) exec(ctx context.Context, table string, id int64, query string, args ...interface{}) error {
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		if me, ok := err.(*mysql.MySQLError); ok && me.Number == errDuplicateEntry {
+			return models.ErrConflict
+		}
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect > 0 {
+		return nil
+	}
+
+	var found int64
+	err = transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table+" WHERE id = ?", id).Scan(&found)
+	if err != nil {
+		return err
+	}
+	if found == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+func (m *mysqlCustomerRepository) Update(ctx context.Context, a *models.Customer) error {
+	defer metrics.ObserveQuery("customer", "Update", time.Now())
+	query := `UPDATE customers set email=?, name=?, phone=?, updated_at=? WHERE id = ?`
+
+	return m.exec(ctx, "customers", a.ID, query, a.Email, a.Name, a.Phone, a.UpdatedAt, a.ID)
+}
+
+func (m *mysqlCustomerRepository) fetchAddresses(ctx context.Context, query string, args ...interface{}) ([]*models.Address, error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	result := make([]*models.Address, 0)
+	for rows.Next() {
+		t := new(models.Address)
+		err = rows.Scan(
+			&t.ID,
+			&t.CustomerID,
+			&t.Kind,
+			&t.Recipient,
+			&t.Line1,
+			&t.Line2,
+			&t.City,
+			&t.PostalCode,
+			&t.Country,
+			&t.Phone,
+			&t.IsDefault,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+// FetchAddresses returns the address book of a customer, the default addresses first
+func (m *mysqlCustomerRepository) FetchAddresses(ctx context.Context, customerID int64) ([]*models.Address, error) {
+	defer metrics.ObserveQuery("customer", "FetchAddresses", time.Now())
+	query := `SELECT id, customer_id, kind, recipient, line1, line2, city, postal_code, country, phone, is_default, updated_at, created_at
+  						FROM addresses WHERE customer_id = ? AND deleted = 0 ORDER BY kind, is_default DESC, id`
+
+	return m.fetchAddresses(ctx, query, customerID)
+}
+
+func (m *mysqlCustomerRepository) GetAddress(ctx context.Context, id int64) (*models.Address, error) {
+	defer metrics.ObserveQuery("customer", "GetAddress", time.Now())
+	query := `SELECT id, customer_id, kind, recipient, line1, line2, city, postal_code, country, phone, is_default, updated_at, created_at
+  						FROM addresses WHERE id = ? AND deleted = 0`
+
+	list, err := m.fetchAddresses(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, models.ErrNotFound
+	}
+
+	return list[0], nil
+}
+
+func (m *mysqlCustomerRepository) StoreAddress(ctx context.Context, a *models.Address) error {
+	defer metrics.ObserveQuery("customer", "StoreAddress", time.Now())
+	query := `INSERT addresses SET customer_id=?, kind=?, recipient=?, line1=?, line2=?, city=?, postal_code=?, country=?,
+  						phone=?, is_default=?, updated_at=?, created_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, a.CustomerID, a.Kind, a.Recipient, a.Line1, a.Line2, a.City, a.PostalCode, a.Country,
+		a.Phone, a.IsDefault, a.UpdatedAt, a.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	a.ID = lastID
+	return nil
+}
+
+func (m *mysqlCustomerRepository) UpdateAddress(ctx context.Context, a *models.Address) error {
+	defer metrics.ObserveQuery("customer", "UpdateAddress", time.Now())
+	query := `UPDATE addresses set kind=?, recipient=?, line1=?, line2=?, city=?, postal_code=?, country=?, phone=?,
+  						is_default=?, updated_at=? WHERE id = ? AND deleted = 0`
+
+	return m.exec(ctx, "addresses", a.ID, query, a.Kind, a.Recipient, a.Line1, a.Line2, a.City, a.PostalCode, a.Country,
+		a.Phone, a.IsDefault, a.UpdatedAt, a.ID)
+}
+
+// DeleteAddress removes an address from the address book, the row is kept for the orders sent to it
+func (m *mysqlCustomerRepository) DeleteAddress(ctx context.Context, id int64) error {
+	defer metrics.ObserveQuery("customer", "DeleteAddress", time.Now())
+	query := `UPDATE addresses set deleted=1, is_default=0 WHERE id = ? AND deleted = 0`
+
+	return m.exec(ctx, "addresses", id, query, id)
+}
+
+// ClearDefaultAddress makes none of the addresses of a kind the default one of the customer
+func (m *mysqlCustomerRepository) ClearDefaultAddress(ctx context.Context, customerID int64, kind string) error {
+	defer metrics.ObserveQuery("customer", "ClearDefaultAddress", time.Now())
+	query := `UPDATE addresses set is_default=0 WHERE customer_id = ? AND kind = ? AND is_default = 1`
+
+	_, err := transaction.Conn(ctx, m.Conn).ExecContext(ctx, query, customerID, kind)
+	return err
+}
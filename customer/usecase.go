@@ -0,0 +1,18 @@
+package customer
+
+import (
+	"context"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// Usecase represent the customer's usecases
+type Usecase interface {
+	GetByID(ctx context.Context, id int64) (*models.Customer, error)
+	Store(ctx context.Context, a *models.Customer) error
+	Update(ctx context.Context, a *models.Customer) error
+	GetAddress(ctx context.Context, customerID int64, id int64) (*models.Address, error)
+	StoreAddress(ctx context.Context, a *models.Address) error
+	UpdateAddress(ctx context.Context, a *models.Address) error
+	DeleteAddress(ctx context.Context, customerID int64, id int64) error
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"github.com/williamchand/kuncie-cart/customer"
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/transaction"
+)
+
+type customerUsecase struct {
+	customerRepo   customer.Repository
+	txManager      transaction.Manager
+	contextTimeout time.Duration
+}
+
+// NewCustomerUsecase will create new a customerUsecase object representation of customer.Usecase interface
+func NewCustomerUsecase(a customer.Repository, tx transaction.Manager, timeout time.Duration) customer.Usecase {
+	return &customerUsecase{
+		customerRepo:   a,
+		txManager:      tx,
+		contextTimeout: timeout,
+	}
+}
+
+// GetByID returns the customer with its address book
+func (a *customerUsecase) GetByID(c context.Context, id int64) (*models.Customer, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, err := a.customerRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	res.Addresses, err = a.customerRepo.FetchAddresses(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Store registers a customer, emails are compared without case and one already in use fails with models.ErrConflict
+func (a *customerUsecase) Store(c context.Context, m *models.Customer) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	m.Email = strings.ToLower(strings.TrimSpace(m.Email))
+	if err := validator.New().Struct(m); err != nil {
+		return err
+	}
+
+	m.CreatedAt = time.Now()
+	m.UpdatedAt = m.CreatedAt
+	if err := a.customerRepo.Store(ctx, m); err != nil {
+		return err
+	}
+	m.Addresses = make([]*models.Address, 0)
+	return nil
+}
+
+func (a *customerUsecase) Update(c context.Context, m *models.Customer) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	m.Email = strings.ToLower(strings.TrimSpace(m.Email))
+	if err := validator.New().Struct(m); err != nil {
+		return err
+	}
+
+	m.UpdatedAt = time.Now()
+	if err := a.customerRepo.Update(ctx, m); err != nil {
+		return err
+	}
+	res, err := a.customerRepo.GetByID(ctx, m.ID)
+	if err != nil {
+		return err
+	}
+	m.CreatedAt = res.CreatedAt
+	m.Addresses, err = a.customerRepo.FetchAddresses(ctx, m.ID)
+	return err
+}
+
+// GetAddress returns an address of the customer, the addresses of other customers are not found
+func (a *customerUsecase) GetAddress(c context.Context, customerID int64, id int64) (*models.Address, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.address(ctx, customerID, id)
+}
+
+func (a *customerUsecase) address(ctx context.Context, customerID int64, id int64) (*models.Address, error) {
+	res, err := a.customerRepo.GetAddress(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if res.CustomerID != customerID {
+		return nil, models.ErrNotFound
+	}
+
+	return res, nil
+}
+
+// saveAddress makes the address the default one of its kind when asked or when the customer has none yet
+func (a *customerUsecase) saveAddress(ctx context.Context, m *models.Address, save func(ctx context.Context, m *models.Address) error) error {
+	list, err := a.customerRepo.FetchAddresses(ctx, m.CustomerID)
+	if err != nil {
+		return err
+	}
+	hasDefault := false
+	for _, other := range list {
+		if other.ID != m.ID && other.Kind == m.Kind && other.IsDefault {
+			hasDefault = true
+		}
+	}
+	if !hasDefault {
+		m.IsDefault = true
+	}
+
+	return a.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if m.IsDefault && hasDefault {
+			if err := a.customerRepo.ClearDefaultAddress(ctx, m.CustomerID, m.Kind); err != nil {
+				return err
+			}
+		}
+		return save(ctx, m)
+	})
+}
+
+// StoreAddress adds a shipping or billing address to the address book of a customer
+func (a *customerUsecase) StoreAddress(c context.Context, m *models.Address) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if err := validator.New().Struct(m); err != nil {
+		return err
+	}
+	if _, err := a.customerRepo.GetByID(ctx, m.CustomerID); err != nil {
+		return err
+	}
+
+	m.CreatedAt = time.Now()
+	m.UpdatedAt = m.CreatedAt
+	return a.saveAddress(ctx, m, a.customerRepo.StoreAddress)
+}
+
+// UpdateAddress replaces an address of the customer, the addresses of other customers are not found
+func (a *customerUsecase) UpdateAddress(c context.Context, m *models.Address) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if err := validator.New().Struct(m); err != nil {
+		return err
+	}
+	current, err := a.address(ctx, m.CustomerID, m.ID)
+	if err != nil {
+		return err
+	}
+
+	m.CreatedAt = current.CreatedAt
+	m.UpdatedAt = time.Now()
+	return a.saveAddress(ctx, m, a.customerRepo.UpdateAddress)
+}
+
+// DeleteAddress removes an address from the address book of the customer
+func (a *customerUsecase) DeleteAddress(c context.Context, customerID int64, id int64) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if _, err := a.address(ctx, customerID, id); err != nil {
+		return err
+	}
+
+	return a.customerRepo.DeleteAddress(ctx, id)
+}
@@ -0,0 +1,107 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/williamchand/kuncie-cart/customer/mocks"
+	ucase "github.com/williamchand/kuncie-cart/customer/usecase"
+	"github.com/williamchand/kuncie-cart/models"
+	_txMocks "github.com/williamchand/kuncie-cart/transaction/mocks"
+)
+
+func TestStore(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockCustomerRepo := new(mocks.Repository)
+		mockCustomerRepo.On("Store", mock.Anything, mock.MatchedBy(func(c *models.Customer) bool {
+			return c.Email == "jane@example.com"
+		})).Return(nil).Once()
+
+		u := ucase.NewCustomerUsecase(mockCustomerRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		m := &models.Customer{Email: " Jane@Example.com", Name: "Jane"}
+		err := u.Store(context.TODO(), m)
+
+		assert.NoError(t, err)
+		assert.Empty(t, m.Addresses)
+		mockCustomerRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid-email", func(t *testing.T) {
+		mockCustomerRepo := new(mocks.Repository)
+
+		u := ucase.NewCustomerUsecase(mockCustomerRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.Store(context.TODO(), &models.Customer{Email: "jane", Name: "Jane"})
+
+		assert.Error(t, err)
+		mockCustomerRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
+
+func TestStoreAddress(t *testing.T) {
+	address := func() *models.Address {
+		return &models.Address{CustomerID: 5, Kind: models.AddressKindShipping, Recipient: "Jane", Line1: "Jl. Sudirman 1",
+			City: "Jakarta", PostalCode: "10220", Country: "ID"}
+	}
+
+	t.Run("first-is-default", func(t *testing.T) {
+		mockCustomerRepo := new(mocks.Repository)
+		mockCustomerRepo.On("GetByID", mock.Anything, int64(5)).Return(&models.Customer{ID: 5}, nil).Once()
+		mockCustomerRepo.On("FetchAddresses", mock.Anything, int64(5)).Return([]*models.Address{
+			{ID: 1, CustomerID: 5, Kind: models.AddressKindBilling, IsDefault: true},
+		}, nil).Once()
+		mockCustomerRepo.On("StoreAddress", mock.Anything, mock.MatchedBy(func(a *models.Address) bool {
+			return a.IsDefault
+		})).Return(nil).Once()
+
+		u := ucase.NewCustomerUsecase(mockCustomerRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.StoreAddress(context.TODO(), address())
+
+		assert.NoError(t, err)
+		mockCustomerRepo.AssertExpectations(t)
+		mockCustomerRepo.AssertNotCalled(t, "ClearDefaultAddress", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("new-default", func(t *testing.T) {
+		mockCustomerRepo := new(mocks.Repository)
+		mockCustomerRepo.On("GetByID", mock.Anything, int64(5)).Return(&models.Customer{ID: 5}, nil).Once()
+		mockCustomerRepo.On("FetchAddresses", mock.Anything, int64(5)).Return([]*models.Address{
+			{ID: 2, CustomerID: 5, Kind: models.AddressKindShipping, IsDefault: true},
+		}, nil).Once()
+		mockCustomerRepo.On("ClearDefaultAddress", mock.Anything, int64(5), models.AddressKindShipping).Return(nil).Once()
+		mockCustomerRepo.On("StoreAddress", mock.Anything, mock.AnythingOfType("*models.Address")).Return(nil).Once()
+
+		u := ucase.NewCustomerUsecase(mockCustomerRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		m := address()
+		m.IsDefault = true
+		err := u.StoreAddress(context.TODO(), m)
+
+		assert.NoError(t, err)
+		mockCustomerRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown-customer", func(t *testing.T) {
+		mockCustomerRepo := new(mocks.Repository)
+		mockCustomerRepo.On("GetByID", mock.Anything, int64(5)).Return(nil, models.ErrNotFound).Once()
+
+		u := ucase.NewCustomerUsecase(mockCustomerRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.StoreAddress(context.TODO(), address())
+
+		assert.Equal(t, models.ErrNotFound, err)
+		mockCustomerRepo.AssertNotCalled(t, "StoreAddress", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeleteAddressOfAnotherCustomer(t *testing.T) {
+	mockCustomerRepo := new(mocks.Repository)
+	mockCustomerRepo.On("GetAddress", mock.Anything, int64(9)).Return(&models.Address{ID: 9, CustomerID: 6}, nil).Once()
+
+	u := ucase.NewCustomerUsecase(mockCustomerRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+	err := u.DeleteAddress(context.TODO(), 5, 9)
+
+	assert.Equal(t, models.ErrNotFound, err)
+	mockCustomerRepo.AssertNotCalled(t, "DeleteAddress", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,30 @@
+USE `kuncie-cart`;
+
+--
+-- Table structure for table `invoice_sequence`
+--
+
+DROP TABLE IF EXISTS `invoice_sequence`;
+CREATE TABLE `invoice_sequence` (
+  `name` varchar(20) COLLATE utf8_unicode_ci NOT NULL,
+  `last_number` int(11) unsigned NOT NULL DEFAULT '0',
+  PRIMARY KEY (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+
+INSERT INTO `invoice_sequence` VALUES ('invoice', 0);
+
+--
+-- Table structure for table `invoices`
+--
+
+DROP TABLE IF EXISTS `invoices`;
+CREATE TABLE `invoices` (
+  `id` int(11) unsigned NOT NULL AUTO_INCREMENT,
+  `order_id` int(11) NOT NULL,
+  `number` varchar(20) COLLATE utf8_unicode_ci NOT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `invoices_order_id` (`order_id`),
+  UNIQUE KEY `invoices_number` (`number`)
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
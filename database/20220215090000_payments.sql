@@ -0,0 +1,24 @@
+USE `kuncie-cart`;
+
+ALTER TABLE `order` ADD COLUMN `status` varchar(20) COLLATE utf8_unicode_ci NOT NULL DEFAULT 'pending' AFTER `total_price`;
+
+--
+-- Table structure for table `payment_intents`
+--
+
+DROP TABLE IF EXISTS `payment_intents`;
+CREATE TABLE `payment_intents` (
+  `id` int(11) unsigned NOT NULL AUTO_INCREMENT,
+  `order_id` int(11) NOT NULL,
+  `provider` varchar(20) COLLATE utf8_unicode_ci NOT NULL,
+  `provider_ref` varchar(100) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `amount` FLOAT DEFAULT '0.00',
+  `refunded_amount` FLOAT DEFAULT '0.00',
+  `status` varchar(20) COLLATE utf8_unicode_ci NOT NULL,
+  `last_error` varchar(255) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `payment_intents_order_id` (`order_id`),
+  KEY `payment_intents_provider_ref` (`provider`, `provider_ref`)
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
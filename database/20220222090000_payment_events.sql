@@ -0,0 +1,18 @@
+USE `kuncie-cart`;
+
+--
+-- Table structure for table `payment_events`
+--
+
+DROP TABLE IF EXISTS `payment_events`;
+CREATE TABLE `payment_events` (
+  `id` int(11) unsigned NOT NULL AUTO_INCREMENT,
+  `provider` varchar(20) COLLATE utf8_unicode_ci NOT NULL,
+  `event_id` varchar(100) COLLATE utf8_unicode_ci NOT NULL,
+  `type` varchar(50) COLLATE utf8_unicode_ci NOT NULL,
+  `provider_ref` varchar(100) COLLATE utf8_unicode_ci NOT NULL,
+  `amount` FLOAT DEFAULT '0.00',
+  `created_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `payment_events_provider_event_id` (`provider`, `event_id`)
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
@@ -0,0 +1,52 @@
+USE `kuncie-cart`;
+
+ALTER TABLE `order` ADD COLUMN `credit_amount` FLOAT NOT NULL DEFAULT '0.00' AFTER `total_price`;
+
+--
+-- Table structure for table `gift_cards`
+--
+
+DROP TABLE IF EXISTS `gift_cards`;
+CREATE TABLE `gift_cards` (
+  `id` int(11) unsigned NOT NULL AUTO_INCREMENT,
+  `code` varchar(19) COLLATE utf8_unicode_ci NOT NULL,
+  `initial_balance` DECIMAL(10,2) NOT NULL,
+  `balance` DECIMAL(10,2) NOT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `gift_cards_code` (`code`)
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+
+--
+-- Table structure for table `store_credits`
+--
+
+DROP TABLE IF EXISTS `store_credits`;
+CREATE TABLE `store_credits` (
+  `customer_id` int(11) NOT NULL,
+  `balance` DECIMAL(10,2) NOT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`customer_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+
+--
+-- Table structure for table `credit_ledger`
+--
+-- balances are DECIMAL so that a balance spent to the last cent compares equal to zero
+--
+
+DROP TABLE IF EXISTS `credit_ledger`;
+CREATE TABLE `credit_ledger` (
+  `id` int(11) unsigned NOT NULL AUTO_INCREMENT,
+  `account` varchar(20) COLLATE utf8_unicode_ci NOT NULL,
+  `account_id` int(11) NOT NULL,
+  `order_id` int(11) NOT NULL DEFAULT '0',
+  `refund_id` int(11) NOT NULL DEFAULT '0',
+  `amount` DECIMAL(10,2) NOT NULL,
+  `balance_after` DECIMAL(10,2) NOT NULL,
+  `created_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `credit_ledger_account` (`account`, `account_id`),
+  KEY `credit_ledger_order_id` (`order_id`)
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
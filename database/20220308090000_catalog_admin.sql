@@ -0,0 +1,9 @@
+USE `kuncie-cart`;
+
+ALTER TABLE `items`
+  ADD COLUMN `archived` tinyint(1) NOT NULL DEFAULT '0' AFTER `inventory_quantity`,
+  ADD UNIQUE KEY `items_sku` (`sku`);
+
+ALTER TABLE `promotions`
+  ADD COLUMN `active` tinyint(1) NOT NULL DEFAULT '1' AFTER `quantity_requirement`,
+  ADD KEY `promotions_items_id` (`items_id`, `active`);
@@ -0,0 +1,26 @@
+USE `kuncie-cart`;
+
+DROP TABLE IF EXISTS `products`;
+CREATE TABLE `products` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(100) COLLATE utf8_unicode_ci NOT NULL,
+  `description` text COLLATE utf8_unicode_ci,
+  `updated_at` datetime DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+
+-- items without a product are sold standalone, the others are variants of their product
+ALTER TABLE `items`
+  ADD COLUMN `product_id` int(11) NOT NULL DEFAULT '0' AFTER `id`,
+  ADD COLUMN `attributes` text COLLATE utf8_unicode_ci AFTER `inventory_quantity`,
+  ADD KEY `items_product_id` (`product_id`);
+
+-- a promotion targets either one item or every variant of a product
+ALTER TABLE `promotions`
+  MODIFY COLUMN `items_id` int(11) NOT NULL DEFAULT '0',
+  ADD COLUMN `product_id` int(11) NOT NULL DEFAULT '0' AFTER `items_id`,
+  ADD KEY `promotions_product_id` (`product_id`, `active`);
+
+ALTER TABLE `order_details`
+  ADD COLUMN `items_id` int(11) NOT NULL DEFAULT '0' AFTER `order_id`;
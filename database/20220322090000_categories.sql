@@ -0,0 +1,36 @@
+USE `kuncie-cart`;
+
+-- path lists the ids from the root down to the category, such as /1/4/9/, so a subtree is a prefix match
+DROP TABLE IF EXISTS `categories`;
+CREATE TABLE `categories` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `parent_id` int(11) NOT NULL DEFAULT '0',
+  `name` varchar(100) COLLATE utf8_unicode_ci NOT NULL,
+  `path` varchar(255) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `updated_at` datetime DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `categories_path` (`path`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+
+DROP TABLE IF EXISTS `item_categories`;
+CREATE TABLE `item_categories` (
+  `items_id` int(11) NOT NULL,
+  `category_id` int(11) NOT NULL,
+  PRIMARY KEY (`items_id`, `category_id`),
+  KEY `item_categories_category_id` (`category_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+
+DROP TABLE IF EXISTS `item_tags`;
+CREATE TABLE `item_tags` (
+  `items_id` int(11) NOT NULL,
+  `tag` varchar(50) COLLATE utf8_unicode_ci NOT NULL,
+  PRIMARY KEY (`items_id`, `tag`),
+  KEY `item_tags_tag` (`tag`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+
+ALTER TABLE `promotions`
+  ADD COLUMN `category_id` int(11) NOT NULL DEFAULT '0' AFTER `product_id`,
+  ADD COLUMN `tag` varchar(50) COLLATE utf8_unicode_ci NOT NULL DEFAULT '' AFTER `category_id`,
+  ADD KEY `promotions_category_id` (`category_id`, `active`),
+  ADD KEY `promotions_tag` (`tag`, `active`);
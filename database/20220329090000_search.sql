@@ -0,0 +1,9 @@
+USE `kuncie-cart`;
+
+-- words shorter than innodb_ft_min_token_size (3 by default) are not indexed, the search falls back to
+-- ranking the catalog in process for them
+ALTER TABLE `items`
+  ADD FULLTEXT KEY `items_search` (`name`, `sku`);
+
+ALTER TABLE `item_tags`
+  ADD FULLTEXT KEY `item_tags_search` (`tag`);
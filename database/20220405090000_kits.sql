@@ -0,0 +1,16 @@
+USE `kuncie-cart`;
+
+-- a kit is an item with components, its stock is the one of its components
+DROP TABLE IF EXISTS `kit_components`;
+CREATE TABLE `kit_components` (
+  `kit_items_id` int(11) NOT NULL,
+  `items_id` int(11) NOT NULL,
+  `quantity` int(11) NOT NULL DEFAULT '1',
+  PRIMARY KEY (`kit_items_id`, `items_id`),
+  KEY `kit_components_items_id` (`items_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+
+-- the component lines of a kit line point to it
+ALTER TABLE `order_details`
+  ADD COLUMN `parent_id` int(11) NOT NULL DEFAULT '0' AFTER `order_id`,
+  ADD KEY `order_details_parent_id` (`parent_id`);
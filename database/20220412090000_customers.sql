@@ -0,0 +1,40 @@
+USE `kuncie-cart`;
+
+DROP TABLE IF EXISTS `customers`;
+CREATE TABLE `customers` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `email` varchar(255) COLLATE utf8_unicode_ci NOT NULL,
+  `name` varchar(100) COLLATE utf8_unicode_ci NOT NULL,
+  `phone` varchar(20) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `updated_at` datetime DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `customers_email` (`email`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+
+-- deleted addresses are kept for the orders sent to them
+DROP TABLE IF EXISTS `addresses`;
+CREATE TABLE `addresses` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `customer_id` int(11) NOT NULL,
+  `kind` varchar(10) COLLATE utf8_unicode_ci NOT NULL,
+  `recipient` varchar(100) COLLATE utf8_unicode_ci NOT NULL,
+  `line1` varchar(255) COLLATE utf8_unicode_ci NOT NULL,
+  `line2` varchar(255) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `city` varchar(100) COLLATE utf8_unicode_ci NOT NULL,
+  `postal_code` varchar(20) COLLATE utf8_unicode_ci NOT NULL,
+  `country` char(2) COLLATE utf8_unicode_ci NOT NULL,
+  `phone` varchar(20) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `is_default` tinyint(1) NOT NULL DEFAULT '0',
+  `deleted` tinyint(1) NOT NULL DEFAULT '0',
+  `updated_at` datetime DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `addresses_customer_id` (`customer_id`, `kind`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+
+ALTER TABLE `order`
+  ADD COLUMN `customer_id` int(11) NOT NULL DEFAULT '0' AFTER `id`,
+  ADD COLUMN `shipping_address_id` int(11) NOT NULL DEFAULT '0' AFTER `customer_id`,
+  ADD COLUMN `billing_address_id` int(11) NOT NULL DEFAULT '0' AFTER `shipping_address_id`,
+  ADD KEY `order_customer_id` (`customer_id`, `id`);
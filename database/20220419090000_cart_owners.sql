@@ -0,0 +1,10 @@
+USE `kuncie-cart`;
+
+-- the single shared cart cannot be attributed to anyone
+DELETE FROM `cart`;
+
+ALTER TABLE `cart`
+  ADD COLUMN `customer_id` int(11) NOT NULL DEFAULT '0' AFTER `id`,
+  ADD COLUMN `session_token` varchar(128) COLLATE utf8_unicode_ci NOT NULL DEFAULT '' AFTER `customer_id`,
+  ADD KEY `cart_customer_id` (`customer_id`, `items_id`),
+  ADD KEY `cart_session_token` (`session_token`, `items_id`);
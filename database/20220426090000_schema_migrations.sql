@@ -0,0 +1,24 @@
+USE `kuncie-cart`;
+
+-- every migration from now on ends by recording its version, the service checks it is ready for the schema
+CREATE TABLE IF NOT EXISTS `schema_migrations` (
+  `version` bigint(20) NOT NULL,
+  `applied_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+
+INSERT IGNORE INTO `schema_migrations` (`version`) VALUES
+  (20220121081800),
+  (20220201090000),
+  (20220208090000),
+  (20220215090000),
+  (20220222090000),
+  (20220301090000),
+  (20220308090000),
+  (20220315090000),
+  (20220322090000),
+  (20220329090000),
+  (20220405090000),
+  (20220412090000),
+  (20220419090000),
+  (20220426090000);
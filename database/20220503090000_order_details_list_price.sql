@@ -0,0 +1,9 @@
+USE `kuncie-cart`;
+
+-- the catalog price of the units of a line when the order was placed, the discount of the line is what was
+-- charged below it. The lines placed before have no record of it and stay NULL, today's prices would give them
+-- discounts they never had.
+ALTER TABLE `order_details`
+  ADD COLUMN `list_price` FLOAT NULL DEFAULT NULL AFTER `price`;
+
+INSERT IGNORE INTO `schema_migrations` (`version`) VALUES (20220503090000);
@@ -0,0 +1,24 @@
+USE `kuncie-cart`;
+
+--
+-- Table structure for table `payment_refunds`
+--
+
+DROP TABLE IF EXISTS `payment_refunds`;
+CREATE TABLE `payment_refunds` (
+  `id` int(11) unsigned NOT NULL AUTO_INCREMENT,
+  `intent_id` int(11) NOT NULL,
+  `provider_refund_id` varchar(100) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `amount` FLOAT DEFAULT '0.00',
+  `status` varchar(20) COLLATE utf8_unicode_ci NOT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `payment_refunds_intent_id` (`intent_id`)
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+
+-- the refund notifications tell which refund of the intent they are about
+ALTER TABLE `payment_events`
+  ADD COLUMN `refund_id` varchar(100) COLLATE utf8_unicode_ci NOT NULL DEFAULT '' AFTER `provider_ref`;
+
+INSERT IGNORE INTO `schema_migrations` (`version`) VALUES (20220510090000);
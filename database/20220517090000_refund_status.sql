@@ -0,0 +1,23 @@
+USE `kuncie-cart`;
+
+--
+-- a refund records how it was split between the credit and the payment provider, and whether the provider
+-- gave its part back yet: the approval is committed before the provider is called
+--
+
+ALTER TABLE `refunds`
+  ADD COLUMN `credit_amount` FLOAT DEFAULT '0.00' AFTER `amount`,
+  ADD COLUMN `payment_amount` FLOAT DEFAULT '0.00' AFTER `credit_amount`,
+  ADD COLUMN `status` varchar(20) COLLATE utf8_unicode_ci NOT NULL DEFAULT 'completed' AFTER `payment_amount`;
+
+-- the refunds approved so far were given back in full with the approval, their credit part is in the ledger
+UPDATE `refunds` r SET
+  r.`credit_amount` = (SELECT IFNULL(SUM(l.`amount`), 0) FROM `credit_ledger` l WHERE l.`refund_id` = r.`id`),
+  r.`payment_amount` = r.`amount` - r.`credit_amount`;
+
+-- the key a refund is sent to the provider with, a refund sent again with the same key is given back once
+ALTER TABLE `payment_refunds`
+  ADD COLUMN `refund_key` varchar(100) COLLATE utf8_unicode_ci DEFAULT NULL AFTER `intent_id`,
+  ADD UNIQUE KEY `payment_refunds_refund_key` (`intent_id`, `refund_key`);
+
+INSERT IGNORE INTO `schema_migrations` (`version`) VALUES (20220517090000);
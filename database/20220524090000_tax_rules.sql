@@ -0,0 +1,19 @@
+USE `kuncie-cart`;
+
+-- a tax rule targets exactly one of an item, a product, a category and its subcategories or a tag,
+-- the most specific active rule matching an item applies as for promotions
+DROP TABLE IF EXISTS `tax_rules`;
+CREATE TABLE `tax_rules` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(100) COLLATE utf8_unicode_ci NOT NULL,
+  `rate` DECIMAL(5,4) NOT NULL DEFAULT '0.0000',
+  `items_id` int(11) NOT NULL DEFAULT '0',
+  `product_id` int(11) NOT NULL DEFAULT '0',
+  `category_id` int(11) NOT NULL DEFAULT '0',
+  `tag` varchar(50) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `active` tinyint(1) NOT NULL DEFAULT '1',
+  PRIMARY KEY (`id`),
+  KEY `tax_rules_active` (`active`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+
+INSERT IGNORE INTO `schema_migrations` (`version`) VALUES (20220524090000);
@@ -0,0 +1,10 @@
+USE `kuncie-cart`;
+
+-- the tax charged on a line at the rate of the tax rule applied to its item when the order was placed, and
+-- the tax of the whole order, which is part of its total_price. Orders placed before were not taxed.
+ALTER TABLE `order_details`
+  ADD COLUMN `tax_amount` FLOAT NOT NULL DEFAULT '0.00' AFTER `list_price`;
+ALTER TABLE `order`
+  ADD COLUMN `tax_amount` FLOAT NOT NULL DEFAULT '0.00' AFTER `total_price`;
+
+INSERT IGNORE INTO `schema_migrations` (`version`) VALUES (20220531090000);
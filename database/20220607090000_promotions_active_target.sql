@@ -0,0 +1,18 @@
+USE `kuncie-cart`;
+
+-- a target has at most one active promotion. Promotions stored concurrently on the same target before are
+-- deactivated, the oldest one stays active.
+UPDATE `promotions` p
+  JOIN `promotions` o ON o.`active` = 1 AND o.`id` < p.`id`
+    AND o.`items_id` = p.`items_id` AND o.`product_id` = p.`product_id`
+    AND o.`category_id` = p.`category_id` AND o.`tag` = p.`tag`
+SET p.`active` = 0
+WHERE p.`active` = 1;
+
+-- active_target is NULL for inactive promotions, which the unique key does not compare
+ALTER TABLE `promotions`
+  ADD COLUMN `active_target` varchar(100) GENERATED ALWAYS AS
+    (IF(`active` = 1, CONCAT_WS('/', `items_id`, `product_id`, `category_id`, `tag`), NULL)) VIRTUAL,
+  ADD UNIQUE KEY `promotions_active_target` (`active_target`);
+
+INSERT IGNORE INTO `schema_migrations` (`version`) VALUES (20220607090000);
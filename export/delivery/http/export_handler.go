@@ -0,0 +1,92 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+
+	"github.com/williamchand/kuncie-cart/export"
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// ResponseError represent the reseponse error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// ExportHandler  represent the httphandler for export
+type ExportHandler struct {
+	EUsecase export.Usecase
+}
+
+var contentTypes = map[string]string{
+	export.FormatCSV:   "text/csv; charset=UTF-8",
+	export.FormatJSONL: "application/x-ndjson",
+}
+
+// NewExportHandler will initialize the admin/orders/export resources endpoint behind the given middlewares
+func NewExportHandler(e *echo.Echo, us export.Usecase, m ...echo.MiddlewareFunc) {
+	handler := &ExportHandler{
+		EUsecase: us,
+	}
+	e.GET("/admin/orders/export", handler.ExportOrders, m...)
+}
+
+// ExportOrders will stream the orders created between the from and to query parameters
+func (h *ExportHandler) ExportOrders(c echo.Context) error {
+	from, to, err := export.ParseRange(c.QueryParam("from"), c.QueryParam("to"))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	format := c.QueryParam("format")
+	if format == "" {
+		format = export.FormatCSV
+	}
+	contentType, ok := contentTypes[format]
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: models.ErrBadParamInput.Error()})
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, contentType)
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q",
+		fmt.Sprintf("orders-%s-%s.%s", from.Format("20060102"), to.Format("20060102"), format)))
+
+	ctx := c.Request().Context()
+	err = h.EUsecase.Export(ctx, from, to, format, res)
+	if err != nil {
+		if res.Committed {
+			// the status line is gone already, the client sees a truncated file
+			logrus.Error(err)
+			return nil
+		}
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	if !res.Committed {
+		res.WriteHeader(http.StatusOK)
+	}
+
+	return nil
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case models.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case models.ErrNotFound:
+		return http.StatusNotFound
+	case models.ErrConflict:
+		return http.StatusConflict
+	case models.ErrBadParamInput:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,29 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+	export "github.com/williamchand/kuncie-cart/export"
+)
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+// StreamOrders provides a mock function with given fields: ctx, from, to, fn
+func (_m *Repository) StreamOrders(ctx context.Context, from time.Time, to time.Time, fn export.OrderFn) error {
+	ret := _m.Called(ctx, from, to, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, export.OrderFn) error); ok {
+		r0 = rf(ctx, from, to, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
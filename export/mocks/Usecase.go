@@ -0,0 +1,29 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import (
+	context "context"
+	io "io"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Usecase is an autogenerated mock type for the Usecase type
+type Usecase struct {
+	mock.Mock
+}
+
+// Export provides a mock function with given fields: ctx, from, to, format, w
+func (_m *Usecase) Export(ctx context.Context, from time.Time, to time.Time, format string, w io.Writer) error {
+	ret := _m.Called(ctx, from, to, format, w)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, string, io.Writer) error); ok {
+		r0 = rf(ctx, from, to, format, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,42 @@
+package export
+
+import (
+	"time"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+const dateFormat = "2006-01-02"
+
+// ParseRange parses the bounds of an export given as dates or RFC3339 timestamps, dates are days in UTC
+// whatever the time zone of the server. from is inclusive and to is exclusive, a date given as to includes the whole day.
+func ParseRange(from, to string) (time.Time, time.Time, error) {
+	start, _, err := parseTime(from)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, dateOnly, err := parseTime(to)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if dateOnly {
+		end = end.AddDate(0, 0, 1)
+	}
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, models.ErrBadParamInput
+	}
+
+	return start, end, nil
+}
+
+func parseTime(v string) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation(dateFormat, v, time.UTC); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, false, models.ErrBadParamInput
+	}
+
+	return t, false, nil
+}
@@ -0,0 +1,42 @@
+package export_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/williamchand/kuncie-cart/export"
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+func TestParseRange(t *testing.T) {
+	local := time.Local
+	// dates do not depend on the time zone of the server
+	time.Local = time.FixedZone("WIB", 7*60*60)
+	defer func() { time.Local = local }()
+
+	t.Run("dates", func(t *testing.T) {
+		from, to, err := export.ParseRange("2022-01-01", "2022-01-31")
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), from)
+		assert.Equal(t, time.UTC, from.Location())
+		// the whole last day is included
+		assert.Equal(t, time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC), to)
+	})
+
+	t.Run("timestamps", func(t *testing.T) {
+		from, to, err := export.ParseRange("2022-01-01T07:00:00+07:00", "2022-01-02T00:00:00Z")
+
+		assert.NoError(t, err)
+		assert.True(t, from.Equal(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)))
+		assert.True(t, to.Equal(time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("empty-range", func(t *testing.T) {
+		_, _, err := export.ParseRange("2022-01-31", "2022-01-01")
+
+		assert.Equal(t, models.ErrBadParamInput, err)
+	})
+}
@@ -0,0 +1,16 @@
+package export
+
+import (
+	"context"
+	"time"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// OrderFn receives every exported order together with its details
+type OrderFn func(o *models.Order, details []*models.OrderDetails) error
+
+// Repository represent the export's repository contract
+type Repository interface {
+	StreamOrders(ctx context.Context, from time.Time, to time.Time, fn OrderFn) error
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/williamchand/kuncie-cart/export"
+	"github.com/williamchand/kuncie-cart/logging"
+	"github.com/williamchand/kuncie-cart/metrics"
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+type mysqlExportRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlExportRepository will create an object that represent the export.Repository interface
+func NewMysqlExportRepository(Conn *sql.DB) export.Repository {
+	return &mysqlExportRepository{Conn}
+}
+
+// StreamOrders reads the orders created in [from, to) with a single query and hands them to fn one at a time,
+// so only the order being read is kept in memory.
+func (m *mysqlExportRepository) StreamOrders(ctx context.Context, from time.Time, to time.Time, fn export.OrderFn) error {
+	defer metrics.ObserveQuery("export", "StreamOrders", time.Now())
+	query := "SELECT o.id, o.customer_id, o.total_price, o.tax_amount, o.credit_amount, o.status, o.updated_at, o.created_at, " +
+		"od.id, od.parent_id, od.sku, od.name, od.price, od.list_price, od.tax_amount, od.quantity, od.promo_type, " +
+		"od.updated_at, od.created_at " +
+		"FROM `order` o LEFT JOIN order_details od ON od.order_id = o.id " +
+		"WHERE o.created_at >= ? AND o.created_at < ? ORDER BY o.id, od.id"
+	rows, err := m.Conn.QueryContext(ctx, query, from, to)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	var (
+		current *models.Order
+		details []*models.OrderDetails
+	)
+	for rows.Next() {
+		o := new(models.Order)
+		var (
+			detailsID, parentID                sql.NullInt64
+			sku, name, promoType               sql.NullString
+			price, listPrice, taxAmount        sql.NullFloat64
+			quantity                           sql.NullInt64
+			detailsUpdatedAt, detailsCreatedAt mysql.NullTime
+		)
+		err = rows.Scan(
+			&o.ID,
+			&o.CustomerID,
+			&o.TotalPrice,
+			&o.TaxAmount,
+			&o.CreditAmount,
+			&o.Status,
+			&o.UpdatedAt,
+			&o.CreatedAt,
+			&detailsID,
+			&parentID,
+			&sku,
+			&name,
+			&price,
+			&listPrice,
+			&taxAmount,
+			&quantity,
+			&promoType,
+			&detailsUpdatedAt,
+			&detailsCreatedAt,
+		)
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return err
+		}
+
+		if current == nil || current.ID != o.ID {
+			if current != nil {
+				if err = fn(current, details); err != nil {
+					return err
+				}
+			}
+			current = o
+			details = make([]*models.OrderDetails, 0)
+		}
+		if detailsID.Valid {
+			d := &models.OrderDetails{
+				ID:        detailsID.Int64,
+				OrderID:   o.ID,
+				ParentID:  parentID.Int64,
+				SKU:       sku.String,
+				Name:      name.String,
+				Price:     price.Float64,
+				TaxAmount: taxAmount.Float64,
+				Quantity:  quantity.Int64,
+				PromoType: promoType.String,
+				UpdatedAt: detailsUpdatedAt.Time,
+				CreatedAt: detailsCreatedAt.Time,
+			}
+			if listPrice.Valid {
+				d.ListPrice = &listPrice.Float64
+			}
+			details = append(details, d)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	if current != nil {
+		return fn(current, details)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	exportRepo "github.com/williamchand/kuncie-cart/export/repository"
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+func TestStreamOrders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "customer_id", "total_price", "tax_amount", "credit_amount", "status", "updated_at", "created_at",
+		"id", "parent_id", "sku", "name", "price", "list_price", "tax_amount", "quantity", "promo_type", "updated_at", "created_at"}).
+		AddRow(1, 5, 5549.96, 0, 100, "paid", now, now, 1, 0, "120P90", "Google Home", 149.97, 199.96, 0, 4, "bonus_price", now, now).
+		AddRow(1, 5, 5549.96, 0, 100, "paid", now, now, 2, 0, "43N23P", "Macbook Pro", 5399.99, nil, 0, 1, "", now, now).
+		AddRow(2, 0, 0, 0, 0, "pending", now, now, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	query := "SELECT o.id, o.customer_id, o.total_price, (.+) FROM `order` o LEFT JOIN order_details od ON od.order_id = o.id WHERE o.created_at >= \\? AND o.created_at < \\? ORDER BY o.id, od.id"
+	mock.ExpectQuery(query).WillReturnRows(rows)
+
+	a := exportRepo.NewMysqlExportRepository(db)
+	orders := make([]*models.Order, 0)
+	details := make([][]*models.OrderDetails, 0)
+	err = a.StreamOrders(context.TODO(), now.Add(-time.Hour), now, func(o *models.Order, d []*models.OrderDetails) error {
+		orders = append(orders, o)
+		details = append(details, d)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, orders, 2)
+	assert.Len(t, details[0], 2)
+	assert.Equal(t, int64(5), orders[0].CustomerID)
+	assert.Equal(t, 100.0, orders[0].CreditAmount)
+	if assert.NotNil(t, details[0][0].ListPrice) {
+		assert.Equal(t, 199.96, *details[0][0].ListPrice)
+	}
+	assert.Equal(t, "43N23P", details[0][1].SKU)
+	assert.Nil(t, details[0][1].ListPrice)
+	assert.Len(t, details[1], 0)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,19 @@
+package export
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+const (
+	// FormatCSV writes one row per order line
+	FormatCSV = "csv"
+	// FormatJSONL writes one JSON document per order
+	FormatJSONL = "jsonl"
+)
+
+// Usecase represent the export's usecases
+type Usecase interface {
+	Export(ctx context.Context, from time.Time, to time.Time, format string, w io.Writer) error
+}
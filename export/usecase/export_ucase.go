@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/williamchand/kuncie-cart/export"
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+var csvHeader = []string{
+	"order_id", "order_created_at", "customer_id", "order_total_price", "order_tax_amount", "order_credit_amount", "order_status",
+	"order_details_id", "parent_id", "sku", "name", "quantity", "price", "list_price", "tax_amount", "promo_type",
+}
+
+// orderColumns is the number of columns of csvHeader describing the order, the others describe the line
+const orderColumns = 7
+
+type exportUsecase struct {
+	exportRepo     export.Repository
+	contextTimeout time.Duration
+}
+
+// NewExportUsecase will create new an exportUsecase object representation of export.Usecase interface
+func NewExportUsecase(a export.Repository, timeout time.Duration) export.Usecase {
+	return &exportUsecase{
+		exportRepo:     a,
+		contextTimeout: timeout,
+	}
+}
+
+func (a *exportUsecase) Export(c context.Context, from time.Time, to time.Time, format string, w io.Writer) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	switch format {
+	case export.FormatCSV:
+		return a.exportCSV(ctx, from, to, w)
+	case export.FormatJSONL:
+		return a.exportJSONL(ctx, from, to, w)
+	default:
+		return models.ErrBadParamInput
+	}
+}
+
+func (a *exportUsecase) exportCSV(ctx context.Context, from time.Time, to time.Time, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	err := a.exportRepo.StreamOrders(ctx, from, to, func(o *models.Order, details []*models.OrderDetails) error {
+		order := []string{
+			strconv.FormatInt(o.ID, 10),
+			o.CreatedAt.UTC().Format(time.RFC3339),
+			strconv.FormatInt(o.CustomerID, 10),
+			formatPrice(o.TotalPrice),
+			formatPrice(o.TaxAmount),
+			formatPrice(o.CreditAmount),
+			o.Status,
+		}
+		if len(details) == 0 {
+			if err := cw.Write(append(order, make([]string, len(csvHeader)-orderColumns)...)); err != nil {
+				return err
+			}
+		}
+		for _, d := range details {
+			// lines placed before list prices were recorded have none
+			listPrice := ""
+			if d.ListPrice != nil {
+				listPrice = formatPrice(*d.ListPrice)
+			}
+			record := append(order[:orderColumns:orderColumns],
+				strconv.FormatInt(d.ID, 10),
+				strconv.FormatInt(d.ParentID, 10),
+				d.SKU,
+				d.Name,
+				strconv.FormatInt(d.Quantity, 10),
+				formatPrice(d.Price),
+				listPrice,
+				formatPrice(d.TaxAmount),
+				d.PromoType,
+			)
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// orderDocument is the JSON Lines representation of an order with its details
+type orderDocument struct {
+	*models.Order
+	Details []*models.OrderDetails `json:"details"`
+}
+
+func (a *exportUsecase) exportJSONL(ctx context.Context, from time.Time, to time.Time, w io.Writer) error {
+	enc := json.NewEncoder(w)
+
+	return a.exportRepo.StreamOrders(ctx, from, to, func(o *models.Order, details []*models.OrderDetails) error {
+		return enc.Encode(orderDocument{Order: o, Details: details})
+	})
+}
+
+func formatPrice(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
@@ -0,0 +1,83 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/williamchand/kuncie-cart/export"
+	"github.com/williamchand/kuncie-cart/export/mocks"
+	ucase "github.com/williamchand/kuncie-cart/export/usecase"
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+func TestExport(t *testing.T) {
+	created := time.Date(2022, 1, 21, 18, 0, 0, 0, time.UTC)
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+	stream := func(ctx context.Context, from time.Time, to time.Time, fn export.OrderFn) error {
+		listPrice := 199.96
+		o := &models.Order{ID: 1, CustomerID: 5, TotalPrice: 266.44, TaxAmount: 16.49, CreditAmount: 20, Status: models.OrderStatusPaid, CreatedAt: created}
+		if err := fn(o, []*models.OrderDetails{
+			{ID: 1, OrderID: 1, SKU: "120P90", Name: "Google Home", Price: 149.97, ListPrice: &listPrice, TaxAmount: 16.49, Quantity: 4, PromoType: "bonus_price"},
+			{ID: 2, OrderID: 1, SKU: "KIT001", Name: "Speaker Kit", Price: 99.98, Quantity: 1},
+			{ID: 3, OrderID: 1, ParentID: 2, SKU: "A304SD", Name: "Alexa Speaker", Price: 0, Quantity: 2},
+		}); err != nil {
+			return err
+		}
+		return fn(&models.Order{ID: 2, TotalPrice: 0, Status: models.OrderStatusPending, CreatedAt: created}, []*models.OrderDetails{})
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		mockExportRepo := new(mocks.Repository)
+		mockExportRepo.On("StreamOrders", mock.Anything, from, to, mock.AnythingOfType("export.OrderFn")).Return(stream).Once()
+
+		u := ucase.NewExportUsecase(mockExportRepo, time.Second*2)
+		var buf bytes.Buffer
+		err := u.Export(context.TODO(), from, to, export.FormatCSV, &buf)
+
+		assert.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Len(t, lines, 5)
+		assert.Equal(t, "order_id,order_created_at,customer_id,order_total_price,order_tax_amount,order_credit_amount,order_status,"+
+			"order_details_id,parent_id,sku,name,quantity,price,list_price,tax_amount,promo_type", lines[0])
+		assert.Equal(t, "1,2022-01-21T18:00:00Z,5,266.44,16.49,20.00,paid,1,0,120P90,Google Home,4,149.97,199.96,16.49,bonus_price", lines[1])
+		// no list price was recorded for the kit, its component points to it
+		assert.Equal(t, "1,2022-01-21T18:00:00Z,5,266.44,16.49,20.00,paid,2,0,KIT001,Speaker Kit,1,99.98,,0.00,", lines[2])
+		assert.Equal(t, "1,2022-01-21T18:00:00Z,5,266.44,16.49,20.00,paid,3,2,A304SD,Alexa Speaker,2,0.00,,0.00,", lines[3])
+		assert.Equal(t, "2,2022-01-21T18:00:00Z,0,0.00,0.00,0.00,pending,,,,,,,,,", lines[4])
+		mockExportRepo.AssertExpectations(t)
+	})
+
+	t.Run("jsonl", func(t *testing.T) {
+		mockExportRepo := new(mocks.Repository)
+		mockExportRepo.On("StreamOrders", mock.Anything, from, to, mock.AnythingOfType("export.OrderFn")).Return(stream).Once()
+
+		u := ucase.NewExportUsecase(mockExportRepo, time.Second*2)
+		var buf bytes.Buffer
+		err := u.Export(context.TODO(), from, to, export.FormatJSONL, &buf)
+
+		assert.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Len(t, lines, 2)
+		assert.Contains(t, lines[0], `"id":1`)
+		assert.Contains(t, lines[0], `"sku":"120P90"`)
+		assert.Contains(t, lines[1], `"details":[]`)
+		mockExportRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown-format", func(t *testing.T) {
+		mockExportRepo := new(mocks.Repository)
+
+		u := ucase.NewExportUsecase(mockExportRepo, time.Second*2)
+		err := u.Export(context.TODO(), from, to, "xlsx", &bytes.Buffer{})
+
+		assert.Equal(t, models.ErrBadParamInput, err)
+		mockExportRepo.AssertExpectations(t)
+	})
+}
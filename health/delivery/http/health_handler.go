@@ -0,0 +1,38 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+
+	"github.com/williamchand/kuncie-cart/health"
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// HealthHandler  represent the httphandler for the health probes
+type HealthHandler struct {
+	HUsecase health.Usecase
+}
+
+// NewHealthHandler will initialize the /healthz and /readyz endpoints
+func NewHealthHandler(e *echo.Echo, us health.Usecase) {
+	handler := &HealthHandler{
+		HUsecase: us,
+	}
+	e.GET("/healthz", handler.Live)
+	e.GET("/readyz", handler.Ready)
+}
+
+// Live answers as long as the process serves requests
+func (h *HealthHandler) Live(c echo.Context) error {
+	return c.JSON(http.StatusOK, models.Readiness{Status: models.HealthStatusOK, Checks: []*models.HealthCheck{}})
+}
+
+// Ready answers 503 when the service cannot take traffic, with the checks that failed
+func (h *HealthHandler) Ready(c echo.Context) error {
+	res := h.HUsecase.Ready(c.Request().Context())
+	if !res.Ready() {
+		return c.JSON(http.StatusServiceUnavailable, res)
+	}
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,48 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	healthHttp "github.com/williamchand/kuncie-cart/health/delivery/http"
+	"github.com/williamchand/kuncie-cart/health/mocks"
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+func TestHealth(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		readiness *models.Readiness
+		status    int
+		body      string
+	}{
+		{name: "live", path: "/healthz", status: http.StatusOK, body: `{"status":"ok","checks":[]}`},
+		{name: "ready", path: "/readyz", status: http.StatusOK,
+			readiness: &models.Readiness{Status: models.HealthStatusOK, Checks: []*models.HealthCheck{{Name: "database", OK: true}}},
+			body:      `{"status":"ok","checks":[{"name":"database","ok":true}]}`},
+		{name: "not-ready", path: "/readyz", status: http.StatusServiceUnavailable,
+			readiness: &models.Readiness{Status: models.HealthStatusUnavailable, Checks: []*models.HealthCheck{{Name: "shutdown", Error: "shutting down"}}},
+			body:      `{"status":"unavailable","checks":[{"name":"shutdown","ok":false,"error":"shutting down"}]}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockHealthUcase := new(mocks.Usecase)
+			mockHealthUcase.On("Ready", mock.Anything).Return(tc.readiness)
+
+			e := echo.New()
+			healthHttp.NewHealthHandler(e, mockHealthUcase)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(echo.GET, tc.path, nil))
+
+			assert.Equal(t, tc.status, rec.Code)
+			assert.JSONEq(t, tc.body, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,35 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	models "github.com/williamchand/kuncie-cart/models"
+)
+
+// Usecase is an autogenerated mock type for the Usecase type
+type Usecase struct {
+	mock.Mock
+}
+
+// Drain provides a mock function with given fields:
+func (_m *Usecase) Drain() {
+	_m.Called()
+}
+
+// Ready provides a mock function with given fields: ctx
+func (_m *Usecase) Ready(ctx context.Context) *models.Readiness {
+	ret := _m.Called(ctx)
+
+	var r0 *models.Readiness
+	if rf, ok := ret.Get(0).(func(context.Context) *models.Readiness); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Readiness)
+		}
+	}
+
+	return r0
+}
@@ -0,0 +1,9 @@
+package health
+
+import "context"
+
+// Repository represent the health's repository contract
+type Repository interface {
+	Ping(ctx context.Context) error
+	SchemaVersion(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/williamchand/kuncie-cart/health"
+	"github.com/williamchand/kuncie-cart/logging"
+	"github.com/williamchand/kuncie-cart/metrics"
+)
+
+type mysqlHealthRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlHealthRepository will create an object that represent the health.Repository interface
+func NewMysqlHealthRepository(Conn *sql.DB) health.Repository {
+	return &mysqlHealthRepository{Conn}
+}
+
+func (m *mysqlHealthRepository) Ping(ctx context.Context) error {
+	defer metrics.ObserveQuery("health", "Ping", time.Now())
+	err := m.Conn.PingContext(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+	}
+	return err
+}
+
+// SchemaVersion returns the version of the latest migration applied, zero when there is none
+func (m *mysqlHealthRepository) SchemaVersion(ctx context.Context) (int64, error) {
+	defer metrics.ObserveQuery("health", "SchemaVersion", time.Now())
+	var version int64
+	err := m.Conn.QueryRowContext(ctx, "SELECT IFNULL(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return 0, err
+	}
+	return version, nil
+}
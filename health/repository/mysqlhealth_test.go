@@ -0,0 +1,33 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	healthRepo "github.com/williamchand/kuncie-cart/health/repository"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "SELECT IFNULL\\(MAX\\(version\\), 0\\) FROM schema_migrations"
+	mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(20220426090000))
+	mock.ExpectQuery(query).WillReturnError(errors.New("Table 'kuncie-cart.schema_migrations' doesn't exist"))
+
+	h := healthRepo.NewMysqlHealthRepository(db)
+	version, err := h.SchemaVersion(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20220426090000), version)
+
+	_, err = h.SchemaVersion(context.TODO())
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,18 @@
+package health
+
+import (
+	"context"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// SchemaVersion is the version of the latest migration of database/ the service relies on.
+// Bump it with every migration, which must record its version in schema_migrations.
+const SchemaVersion int64 = 20220607090000
+
+// Usecase represent the health's usecases
+type Usecase interface {
+	Ready(ctx context.Context) *models.Readiness
+	// Drain makes the service report itself as not ready, before it shuts down
+	Drain()
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/williamchand/kuncie-cart/health"
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+type healthUsecase struct {
+	healthRepo     health.Repository
+	schemaVersion  int64
+	contextTimeout time.Duration
+	draining       int32
+}
+
+// NewHealthUsecase will create new an healthUsecase object representation of health.Usecase interface.
+// The service is ready while the database answers within timeout and is migrated to schemaVersion or later,
+// migrations being applied before the new version of the service is rolled out.
+func NewHealthUsecase(a health.Repository, schemaVersion int64, timeout time.Duration) health.Usecase {
+	return &healthUsecase{
+		healthRepo:     a,
+		schemaVersion:  schemaVersion,
+		contextTimeout: timeout,
+	}
+}
+
+func (a *healthUsecase) Ready(c context.Context) *models.Readiness {
+	res := &models.Readiness{Status: models.HealthStatusOK, Checks: make([]*models.HealthCheck, 0, 3)}
+	check := func(name string, err error) {
+		hc := &models.HealthCheck{Name: name, OK: err == nil}
+		if err != nil {
+			hc.Error = err.Error()
+			res.Status = models.HealthStatusUnavailable
+		}
+		res.Checks = append(res.Checks, hc)
+	}
+
+	if atomic.LoadInt32(&a.draining) == 1 {
+		check("shutdown", fmt.Errorf("shutting down"))
+		return res
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if err := a.healthRepo.Ping(ctx); err != nil {
+		check("database", err)
+		return res
+	}
+	check("database", nil)
+
+	version, err := a.healthRepo.SchemaVersion(ctx)
+	if err == nil && version < a.schemaVersion {
+		err = fmt.Errorf("schema version is %d, expected %d or later", version, a.schemaVersion)
+	}
+	check("migrations", err)
+	return res
+}
+
+func (a *healthUsecase) Drain() {
+	atomic.StoreInt32(&a.draining, 1)
+}
@@ -0,0 +1,66 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/williamchand/kuncie-cart/health/mocks"
+	ucase "github.com/williamchand/kuncie-cart/health/usecase"
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+func TestReady(t *testing.T) {
+	tests := []struct {
+		name    string
+		pingErr error
+		version int64
+		drain   bool
+		status  string
+		checks  []*models.HealthCheck
+	}{
+		{name: "ready", version: 20220426090000, status: models.HealthStatusOK, checks: []*models.HealthCheck{
+			{Name: "database", OK: true},
+			{Name: "migrations", OK: true},
+		}},
+		{name: "database-down", pingErr: errors.New("connection refused"), status: models.HealthStatusUnavailable, checks: []*models.HealthCheck{
+			{Name: "database", Error: "connection refused"},
+		}},
+		{name: "migrations-behind", version: 20220419090000, status: models.HealthStatusUnavailable, checks: []*models.HealthCheck{
+			{Name: "database", OK: true},
+			{Name: "migrations", Error: "schema version is 20220419090000, expected 20220426090000 or later"},
+		}},
+		// the migrations of the next release are applied while this one still serves
+		{name: "migrations-ahead", version: 20220503090000, status: models.HealthStatusOK, checks: []*models.HealthCheck{
+			{Name: "database", OK: true},
+			{Name: "migrations", OK: true},
+		}},
+		{name: "draining", drain: true, status: models.HealthStatusUnavailable, checks: []*models.HealthCheck{
+			{Name: "shutdown", Error: "shutting down"},
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockHealthRepo := new(mocks.Repository)
+			mockHealthRepo.On("Ping", mock.Anything).Return(tc.pingErr)
+			mockHealthRepo.On("SchemaVersion", mock.Anything).Return(tc.version, nil)
+
+			u := ucase.NewHealthUsecase(mockHealthRepo, 20220426090000, time.Second)
+			if tc.drain {
+				u.Drain()
+			}
+			res := u.Ready(context.TODO())
+
+			assert.Equal(t, tc.status, res.Status)
+			assert.Equal(t, tc.checks, res.Checks)
+			if tc.drain {
+				mockHealthRepo.AssertNotCalled(t, "Ping", mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+
+	"github.com/williamchand/kuncie-cart/importer"
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// ResponseError represent the reseponse error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// ImportHandler  represent the httphandler for catalog import
+type ImportHandler struct {
+	IUsecase importer.Usecase
+}
+
+// NewImportHandler will initialize the admin/items/import resources endpoint behind the given middlewares
+func NewImportHandler(e *echo.Echo, us importer.Usecase, m ...echo.MiddlewareFunc) {
+	handler := &ImportHandler{
+		IUsecase: us,
+	}
+	e.POST("/admin/items/import", handler.ImportItems, m...)
+}
+
+// ImportItems will import the file uploaded in the file form field, as told by the format
+// query parameter or else the file extension. With dry_run=true nothing is saved.
+func (h *ImportHandler) ImportItems(c echo.Context) error {
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+	}
+	format := c.QueryParam("format")
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(file.Filename)), ".")
+	}
+	dryRun := false
+	if v := c.QueryParam("dry_run"); v != "" {
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			return c.JSON(http.StatusBadRequest, ResponseError{Message: models.ErrBadParamInput.Error()})
+		}
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+	}
+	defer src.Close()
+
+	ctx := c.Request().Context()
+	report, err := h.IUsecase.Import(ctx, format, src, dryRun)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case models.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case models.ErrNotFound:
+		return http.StatusNotFound
+	case models.ErrConflict:
+		return http.StatusConflict
+	case models.ErrBadParamInput:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,38 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import (
+	context "context"
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+	models "github.com/williamchand/kuncie-cart/models"
+)
+
+// Usecase is an autogenerated mock type for the Usecase type
+type Usecase struct {
+	mock.Mock
+}
+
+// Import provides a mock function with given fields: ctx, format, r, dryRun
+func (_m *Usecase) Import(ctx context.Context, format string, r io.Reader, dryRun bool) (*models.ImportReport, error) {
+	ret := _m.Called(ctx, format, r, dryRun)
+
+	var r0 *models.ImportReport
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader, bool) *models.ImportReport); ok {
+		r0 = rf(ctx, format, r, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ImportReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.Reader, bool) error); ok {
+		r1 = rf(ctx, format, r, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,20 @@
+package importer
+
+import (
+	"context"
+	"io"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+const (
+	// FormatCSV reads a header line followed by one item per line
+	FormatCSV = "csv"
+	// FormatJSON reads an array of items
+	FormatJSON = "json"
+)
+
+// Usecase represent the catalog import's usecases
+type Usecase interface {
+	Import(ctx context.Context, format string, r io.Reader, dryRun bool) (*models.ImportReport, error)
+}
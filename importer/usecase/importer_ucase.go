@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"github.com/williamchand/kuncie-cart/importer"
+	"github.com/williamchand/kuncie-cart/item"
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/transaction"
+)
+
+// errDryRun rolls back the transaction of a dry run once every row has been applied
+var errDryRun = errors.New("dry run")
+
+type importerUsecase struct {
+	itemRepo       item.Repository
+	txManager      transaction.Manager
+	contextTimeout time.Duration
+}
+
+// NewImporterUsecase will create new an importerUsecase object representation of importer.Usecase interface
+func NewImporterUsecase(i item.Repository, tx transaction.Manager, timeout time.Duration) importer.Usecase {
+	return &importerUsecase{
+		itemRepo:       i,
+		txManager:      tx,
+		contextTimeout: timeout,
+	}
+}
+
+// Import upserts the items of the file by SKU, with their promotion when the row has one.
+// Rows of archived items are skipped, rows that fail validation are rejected and both are reported, the others
+// are saved in a single transaction which a dry run rolls back, so a dry run reports exactly what an import would do.
+func (a *importerUsecase) Import(c context.Context, format string, r io.Reader, dryRun bool) (*models.ImportReport, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	var (
+		records []*record
+		err     error
+	)
+	switch format {
+	case importer.FormatCSV:
+		records, err = parseCSV(r)
+	case importer.FormatJSON:
+		records, err = parseJSON(r)
+	default:
+		return nil, models.ErrBadParamInput
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var res *models.ImportReport
+	err = a.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		res = &models.ImportReport{DryRun: dryRun, Rows: make([]*models.ImportRow, 0, len(records))}
+		if err := a.apply(ctx, records, res); err != nil {
+			return err
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && err != errDryRun {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (a *importerUsecase) apply(ctx context.Context, records []*record, res *models.ImportReport) error {
+	skus := make([]string, 0, len(records))
+	for _, rec := range records {
+		if rec.err == nil {
+			skus = append(skus, rec.item.SKU)
+		}
+	}
+	items, err := a.itemRepo.FetchBySKU(ctx, skus)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]*models.Items, len(items))
+	for _, it := range items {
+		existing[it.SKU] = it
+	}
+
+	validate := validator.New()
+	seen := make(map[string]int, len(records))
+	for _, rec := range records {
+		row := &models.ImportRow{Line: rec.line}
+		res.Rows = append(res.Rows, row)
+		if rec.item != nil {
+			row.SKU = rec.item.SKU
+		}
+
+		if rec.err == nil {
+			if first, ok := seen[rec.item.SKU]; ok {
+				rec.err = fmt.Errorf("sku already imported on line %d", first)
+			}
+		}
+		if rec.err == nil {
+			rec.err = validate.Struct(rec.item)
+		}
+		if rec.err == nil && rec.promotion != nil {
+			rec.err = validate.Struct(rec.promotion)
+		}
+		if rec.err != nil {
+			row.Status = models.ImportRowRejected
+			row.Error = rec.err.Error()
+			res.Rejected++
+			continue
+		}
+		seen[rec.item.SKU] = rec.line
+
+		if it, ok := existing[rec.item.SKU]; ok && it.Archived {
+			// archiving is a decision of the staff, a file listing the SKU again does not undo it
+			row.Status = models.ImportRowSkipped
+			row.Error = "item is archived"
+			res.Skipped++
+			continue
+		}
+
+		now := time.Now()
+		rec.item.UpdatedAt = now
+		if it, ok := existing[rec.item.SKU]; ok {
+			// the file does not describe variants, an updated item stays under its product
+			rec.item.ID = it.ID
+			rec.item.ProductID = it.ProductID
+			rec.item.Attributes = it.Attributes
+			err = a.itemRepo.Update(ctx, rec.item)
+			row.Status = models.ImportRowUpdated
+		} else {
+			rec.item.CreatedAt = now
+			err = a.itemRepo.Store(ctx, rec.item)
+			row.Status = models.ImportRowCreated
+		}
+		if err == nil && rec.promotion != nil {
+			err = a.applyPromotion(ctx, rec.item.ID, rec.promotion)
+		}
+		if err != nil {
+			return err
+		}
+
+		if row.Status == models.ImportRowCreated {
+			res.Created++
+		} else {
+			res.Updated++
+		}
+	}
+
+	return nil
+}
+
+// applyPromotion changes the terms of the active promotion of the item, or starts one
+func (a *importerUsecase) applyPromotion(ctx context.Context, itemsID int64, p *models.Promotions) error {
+	active, err := a.itemRepo.FetchPromotions(ctx, []int64{itemsID})
+	if err != nil {
+		return err
+	}
+
+	p.ItemsID = itemsID
+	p.Active = true
+	if len(active) > 0 {
+		p.ID = active[0].ID
+		return a.itemRepo.UpdatePromotion(ctx, p)
+	}
+	return a.itemRepo.StorePromotion(ctx, p)
+}
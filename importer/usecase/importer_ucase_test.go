@@ -0,0 +1,109 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/williamchand/kuncie-cart/importer"
+	ucase "github.com/williamchand/kuncie-cart/importer/usecase"
+	_itemMocks "github.com/williamchand/kuncie-cart/item/mocks"
+	"github.com/williamchand/kuncie-cart/models"
+	_txMocks "github.com/williamchand/kuncie-cart/transaction/mocks"
+)
+
+const itemsCSV = `sku,name,price,inventory_quantity,promo_type,promo,quantity_requirement
+120P90,Google Home,49.99,12,bonus_price,99.98,3
+P4B8GB,Raspberry Pi 4,75,20,,,
+BADPRC,Broken,abc,1,,,
+120P90,Google Home again,49.99,12,,,
+NONAME,,10,1,,,
+PROMO1,Bad promotion,10,1,half_price,0.5,1
+`
+
+func TestImportCSV(t *testing.T) {
+	mockItemRepo := new(_itemMocks.Repository)
+	mockItemRepo.On("FetchBySKU", mock.Anything, []string{"120P90", "P4B8GB", "120P90", "NONAME", "PROMO1"}).
+		Return([]*models.Items{{ID: 1, SKU: "120P90"}}, nil).Once()
+	mockItemRepo.On("Update", mock.Anything, mock.MatchedBy(func(it *models.Items) bool {
+		return it.ID == 1 && it.InventoryQuantity == 12
+	})).Return(nil).Once()
+	mockItemRepo.On("FetchPromotions", mock.Anything, []int64{1}).
+		Return([]*models.Promotions{{ID: 2, ItemsID: 1, PromoType: "bonus_price", Active: true}}, nil).Once()
+	mockItemRepo.On("UpdatePromotion", mock.Anything, mock.MatchedBy(func(p *models.Promotions) bool {
+		return p.ID == 2 && p.Promo == "99.98" && p.Active
+	})).Return(nil).Once()
+	mockItemRepo.On("Store", mock.Anything, mock.MatchedBy(func(it *models.Items) bool {
+		return it.SKU == "P4B8GB"
+	})).Return(nil).Once()
+
+	u := ucase.NewImporterUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+	report, err := u.Import(context.TODO(), importer.FormatCSV, strings.NewReader(itemsCSV), false)
+
+	assert.NoError(t, err)
+	assert.False(t, report.DryRun)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 4, report.Rejected)
+	statuses := make([]string, len(report.Rows))
+	for i, row := range report.Rows {
+		statuses[i] = row.Status
+	}
+	assert.Equal(t, []string{"updated", "created", "rejected", "rejected", "rejected", "rejected"}, statuses)
+	assert.Equal(t, 4, report.Rows[2].Line)
+	assert.Contains(t, report.Rows[3].Error, "line 2")
+	mockItemRepo.AssertExpectations(t)
+}
+
+func TestImportJSONDryRun(t *testing.T) {
+	mockItemRepo := new(_itemMocks.Repository)
+	mockItemRepo.On("FetchBySKU", mock.Anything, []string{"P4B8GB"}).Return([]*models.Items{}, nil).Once()
+	mockItemRepo.On("Store", mock.Anything, mock.AnythingOfType("*models.Items")).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Items).ID = 5
+	}).Once()
+	mockItemRepo.On("FetchPromotions", mock.Anything, []int64{5}).Return([]*models.Promotions{}, nil).Once()
+	mockItemRepo.On("StorePromotion", mock.Anything, mock.MatchedBy(func(p *models.Promotions) bool {
+		return p.ItemsID == 5 && p.Promo == "0.1"
+	})).Return(nil).Once()
+
+	body := `[{"sku": "P4B8GB", "name": "Raspberry Pi 4", "price": 75, "inventory_quantity": 20,
+		"promo_type": "discount_items", "promo": 0.1, "quantity_requirement": 2}]`
+	u := ucase.NewImporterUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+	report, err := u.Import(context.TODO(), importer.FormatJSON, strings.NewReader(body), true)
+
+	assert.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.Created)
+	mockItemRepo.AssertExpectations(t)
+}
+
+func TestImportArchived(t *testing.T) {
+	mockItemRepo := new(_itemMocks.Repository)
+	mockItemRepo.On("FetchBySKU", mock.Anything, []string{"120P90"}).
+		Return([]*models.Items{{ID: 1, SKU: "120P90", Archived: true}}, nil).Once()
+
+	body := "sku,name,price,inventory_quantity\n120P90,Google Home,49.99,12\n"
+	u := ucase.NewImporterUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+	report, err := u.Import(context.TODO(), importer.FormatCSV, strings.NewReader(body), false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, report.Updated)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, models.ImportRowSkipped, report.Rows[0].Status)
+	mockItemRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockItemRepo.AssertExpectations(t)
+}
+
+func TestImportMissingColumn(t *testing.T) {
+	mockItemRepo := new(_itemMocks.Repository)
+
+	u := ucase.NewImporterUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+	report, err := u.Import(context.TODO(), importer.FormatCSV, strings.NewReader("sku,name\n120P90,Google Home\n"), false)
+
+	assert.Equal(t, models.ErrBadParamInput, err)
+	assert.Nil(t, report)
+}
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// record is an item read from an import file, with the promotion to apply to it if any
+type record struct {
+	line      int
+	item      *models.Items
+	promotion *models.Promotions
+	err       error
+}
+
+// row is the shape of an item in a JSON import, and the column names of a CSV import
+type row struct {
+	SKU                 string      `json:"sku"`
+	Name                string      `json:"name"`
+	Price               float64     `json:"price"`
+	InventoryQuantity   int64       `json:"inventory_quantity"`
+	PromoType           string      `json:"promo_type"`
+	Promo               json.Number `json:"promo"`
+	QuantityRequirement int64       `json:"quantity_requirement"`
+}
+
+func (r *row) record(line int) *record {
+	res := &record{
+		line: line,
+		item: &models.Items{
+			SKU:               strings.TrimSpace(r.SKU),
+			Name:              strings.TrimSpace(r.Name),
+			Price:             r.Price,
+			InventoryQuantity: r.InventoryQuantity,
+		},
+	}
+	if r.PromoType != "" {
+		res.promotion = &models.Promotions{
+			PromoType:           strings.TrimSpace(r.PromoType),
+			Promo:               strings.TrimSpace(r.Promo.String()),
+			QuantityRequirement: r.QuantityRequirement,
+		}
+	}
+	return res
+}
+
+var requiredColumns = []string{"sku", "name", "price", "inventory_quantity"}
+
+func parseCSV(r io.Reader) ([]*record, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, models.ErrBadParamInput
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, models.ErrBadParamInput
+		}
+	}
+
+	res := make([]*record, 0)
+	for line := 2; ; line++ {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if pe, ok := err.(*csv.ParseError); ok && pe.Err == csv.ErrFieldCount {
+				res = append(res, &record{line: line, err: pe.Err})
+				continue
+			}
+			return nil, models.ErrBadParamInput
+		}
+
+		value := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(fields[i])
+			}
+			return ""
+		}
+		rec := &row{
+			SKU:       value("sku"),
+			Name:      value("name"),
+			PromoType: value("promo_type"),
+			Promo:     json.Number(value("promo")),
+		}
+		if rec.Price, err = strconv.ParseFloat(value("price"), 64); err != nil {
+			res = append(res, &record{line: line, item: &models.Items{SKU: rec.SKU}, err: fmt.Errorf("price is not a number")})
+			continue
+		}
+		if rec.InventoryQuantity, err = strconv.ParseInt(value("inventory_quantity"), 10, 64); err != nil {
+			res = append(res, &record{line: line, item: &models.Items{SKU: rec.SKU}, err: fmt.Errorf("inventory_quantity is not an integer")})
+			continue
+		}
+		if rec.PromoType != "" {
+			if rec.QuantityRequirement, err = strconv.ParseInt(value("quantity_requirement"), 10, 64); err != nil {
+				res = append(res, &record{line: line, item: &models.Items{SKU: rec.SKU}, err: fmt.Errorf("quantity_requirement is not an integer")})
+				continue
+			}
+		}
+		res = append(res, rec.record(line))
+	}
+
+	return res, nil
+}
+
+// parseJSON reads an array of items, the line of a record is its position in the array
+func parseJSON(r io.Reader) ([]*record, error) {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, models.ErrBadParamInput
+	}
+
+	res := make([]*record, 0, len(raw))
+	for i := range raw {
+		rec := new(row)
+		if err := json.Unmarshal(raw[i], rec); err != nil {
+			res = append(res, &record{line: i + 1, item: &models.Items{SKU: rec.SKU}, err: err})
+			continue
+		}
+		res = append(res, rec.record(i+1))
+	}
+
+	return res, nil
+}
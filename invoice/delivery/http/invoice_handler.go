@@ -0,0 +1,111 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+
+	"github.com/williamchand/kuncie-cart/invoice"
+	"github.com/williamchand/kuncie-cart/invoice/renderer"
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// ResponseError represent the reseponse error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// InvoiceHandler  represent the httphandler for invoice
+type InvoiceHandler struct {
+	IUsecase  invoice.Usecase
+	Renderers map[string]invoice.Renderer
+}
+
+// extensions maps the supported formats to the file extension of the downloaded document
+var extensions = map[string]string{
+	"html": "html",
+	"text": "txt",
+}
+
+// NewInvoiceHandler will initialize the orders/:id/invoice resources endpoint, issuing an invoice goes through
+// the staff middlewares
+func NewInvoiceHandler(e *echo.Echo, us invoice.Usecase, staff ...echo.MiddlewareFunc) {
+	handler := &InvoiceHandler{
+		IUsecase: us,
+		Renderers: map[string]invoice.Renderer{
+			"html": renderer.NewHTMLRenderer(),
+			"text": renderer.NewTextRenderer(),
+		},
+	}
+	e.GET("/orders/:id/invoice", handler.GetByOrder)
+	e.POST("/orders/:id/invoice", handler.Generate, staff...)
+}
+
+// GetByOrder will download the invoice of the given order as HTML or plain text, issuing it once the order is paid
+func (i *InvoiceHandler) GetByOrder(c echo.Context) error {
+	return i.download(c, i.IUsecase.GetByOrder)
+}
+
+// Generate will issue the invoice of the given order and download it as HTML or plain text
+func (i *InvoiceHandler) Generate(c echo.Context) error {
+	return i.download(c, i.IUsecase.Generate)
+}
+
+func (i *InvoiceHandler) download(c echo.Context, fetch func(ctx context.Context, orderID int64) (*models.Invoice, error)) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, ResponseError{Message: models.ErrNotFound.Error()})
+	}
+
+	format := c.QueryParam("format")
+	if format == "" {
+		format = "html"
+	}
+	r, ok := i.Renderers[format]
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: models.ErrBadParamInput.Error()})
+	}
+
+	ctx := c.Request().Context()
+	inv, err := fetch(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	var buf bytes.Buffer
+	if err = r.Render(&buf, inv); err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", inv.Number+"."+extensions[format]))
+	return c.Blob(http.StatusOK, r.ContentType(), buf.Bytes())
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case models.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case models.ErrNotFound:
+		return http.StatusNotFound
+	case models.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case models.ErrForbidden:
+		return http.StatusForbidden
+	case models.ErrConflict:
+		return http.StatusConflict
+	case models.ErrBadParamInput:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
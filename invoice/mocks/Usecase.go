@@ -0,0 +1,60 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	models "github.com/williamchand/kuncie-cart/models"
+)
+
+// Usecase is an autogenerated mock type for the Usecase type
+type Usecase struct {
+	mock.Mock
+}
+
+// Generate provides a mock function with given fields: ctx, orderID
+func (_m *Usecase) Generate(ctx context.Context, orderID int64) (*models.Invoice, error) {
+	ret := _m.Called(ctx, orderID)
+
+	var r0 *models.Invoice
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.Invoice); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Invoice)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByOrder provides a mock function with given fields: ctx, orderID
+func (_m *Usecase) GetByOrder(ctx context.Context, orderID int64) (*models.Invoice, error) {
+	ret := _m.Called(ctx, orderID)
+
+	var r0 *models.Invoice
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.Invoice); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Invoice)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
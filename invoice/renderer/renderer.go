@@ -0,0 +1,64 @@
+package renderer
+
+import (
+	htmlTemplate "html/template"
+	"io"
+	"strconv"
+	textTemplate "text/template"
+
+	"github.com/williamchand/kuncie-cart/invoice"
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+var promotionLabels = map[string]string{
+	"free_items":     "Free item",
+	"bonus_price":    "Bundle price",
+	"discount_items": "Discount",
+}
+
+var funcs = map[string]interface{}{
+	"money": func(v float64) string {
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	},
+	"promotion": func(promoType string) string {
+		if label, ok := promotionLabels[promoType]; ok {
+			return label
+		}
+		return "-"
+	},
+}
+
+var (
+	htmlInvoice = htmlTemplate.Must(htmlTemplate.New("invoice").Funcs(funcs).Parse(htmlLayout))
+	textInvoice = textTemplate.Must(textTemplate.New("invoice").Funcs(funcs).Parse(textLayout))
+)
+
+type htmlRenderer struct{}
+
+// NewHTMLRenderer will create an object that represent the invoice.Renderer interface writing HTML documents
+func NewHTMLRenderer() invoice.Renderer {
+	return &htmlRenderer{}
+}
+
+func (r *htmlRenderer) ContentType() string {
+	return "text/html; charset=UTF-8"
+}
+
+func (r *htmlRenderer) Render(w io.Writer, a *models.Invoice) error {
+	return htmlInvoice.Execute(w, a)
+}
+
+type textRenderer struct{}
+
+// NewTextRenderer will create an object that represent the invoice.Renderer interface writing plain text documents
+func NewTextRenderer() invoice.Renderer {
+	return &textRenderer{}
+}
+
+func (r *textRenderer) ContentType() string {
+	return "text/plain; charset=UTF-8"
+}
+
+func (r *textRenderer) Render(w io.Writer, a *models.Invoice) error {
+	return textInvoice.Execute(w, a)
+}
@@ -0,0 +1,89 @@
+package renderer_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/williamchand/kuncie-cart/invoice/renderer"
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+func TestRender(t *testing.T) {
+	inv := &models.Invoice{
+		OrderID: 7,
+		Number:  "INV-000042",
+		Order:   &models.Order{ID: 7, TotalPrice: 5549.96, CreditAmount: 100},
+		Details: []*models.OrderDetails{
+			{SKU: "120P90", Name: "Google Home", Price: 149.97, Quantity: 4, PromoType: "bonus_price"},
+			{SKU: "43N23P", Name: "Macbook <Pro>", Price: 5399.99, Quantity: 1},
+		},
+		CreatedAt: time.Date(2022, 2, 8, 9, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("html", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := renderer.NewHTMLRenderer().Render(&buf, inv)
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), "Invoice INV-000042")
+		assert.Contains(t, buf.String(), "Macbook &lt;Pro&gt;")
+		assert.Contains(t, buf.String(), "Bundle price")
+		assert.Contains(t, buf.String(), "5549.96")
+		assert.Contains(t, buf.String(), `<th colspan="4">Gift cards and store credit</th><th class="amount">-100.00</th>`)
+		assert.Contains(t, buf.String(), `<th colspan="4">Amount due</th><th class="amount">5449.96</th>`)
+	})
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := renderer.NewTextRenderer().Render(&buf, inv)
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), "INVOICE INV-000042")
+		assert.Contains(t, buf.String(), "Macbook <Pro>")
+		assert.Contains(t, buf.String(), "2022-02-08 09:00")
+		assert.Contains(t, buf.String(), "5549.96")
+		assert.Regexp(t, `\nGIFT CARDS AND STORE CREDIT +-100\.00\nAMOUNT DUE +5449\.96\n$`, buf.String())
+	})
+
+	t.Run("without-credit", func(t *testing.T) {
+		pending := *inv
+		pending.Order = &models.Order{ID: 7, TotalPrice: 5549.96, Status: models.OrderStatusPending}
+		var buf bytes.Buffer
+		err := renderer.NewTextRenderer().Render(&buf, &pending)
+		require.NoError(t, err)
+		assert.NotContains(t, buf.String(), "GIFT CARDS")
+		assert.Regexp(t, `\nTOTAL +5549\.96\nAMOUNT DUE +5549\.96\n$`, buf.String())
+	})
+
+	t.Run("paid", func(t *testing.T) {
+		paid := *inv
+		paid.Order = &models.Order{ID: 7, TotalPrice: 5549.96, CreditAmount: 100, Status: models.OrderStatusPaid}
+		var buf bytes.Buffer
+		err := renderer.NewTextRenderer().Render(&buf, &paid)
+		require.NoError(t, err)
+		assert.NotContains(t, buf.String(), "AMOUNT DUE")
+		assert.Regexp(t, `\nGIFT CARDS AND STORE CREDIT +-100\.00\nPAID +5449\.96\n$`, buf.String())
+
+		buf.Reset()
+		err = renderer.NewHTMLRenderer().Render(&buf, &paid)
+		require.NoError(t, err)
+		assert.NotContains(t, buf.String(), "Amount due")
+		assert.Contains(t, buf.String(), `<th colspan="4">Paid</th><th class="amount">5449.96</th>`)
+	})
+
+	t.Run("taxed", func(t *testing.T) {
+		taxed := *inv
+		taxed.Order = &models.Order{ID: 7, TotalPrice: 6160.46, TaxAmount: 610.50}
+		var buf bytes.Buffer
+		err := renderer.NewTextRenderer().Render(&buf, &taxed)
+		require.NoError(t, err)
+		assert.Regexp(t, `\nTAX +610\.50\nTOTAL +6160\.46\n`, buf.String())
+
+		buf.Reset()
+		err = renderer.NewHTMLRenderer().Render(&buf, &taxed)
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), `<th colspan="4">Tax</th><th class="amount">610.50</th>`)
+	})
+}
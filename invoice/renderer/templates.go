@@ -0,0 +1,65 @@
+package renderer
+
+const htmlLayout = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Invoice {{.Number}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #ddd; padding: 4px 8px; text-align: left; }
+td.amount, th.amount { text-align: right; }
+</style>
+</head>
+<body>
+<h1>Invoice {{.Number}}</h1>
+<p>Order #{{.OrderID}}<br>Issued {{.CreatedAt.Format "2006-01-02 15:04"}}</p>
+<table>
+<thead>
+<tr><th>SKU</th><th>Item</th><th>Quantity</th><th>Promotion</th><th class="amount">Amount</th></tr>
+</thead>
+<tbody>
+{{- range .Details}}
+<tr><td>{{.SKU}}</td><td>{{.Name}}</td><td>{{.Quantity}}</td><td>{{promotion .PromoType}}</td><td class="amount">{{money .Price}}</td></tr>
+{{- end}}
+</tbody>
+<tfoot>
+{{- if gt .Order.TaxAmount 0.0}}
+<tr><th colspan="4">Tax</th><th class="amount">{{money .Order.TaxAmount}}</th></tr>
+{{- end}}
+<tr><th colspan="4">Total</th><th class="amount">{{money .Order.TotalPrice}}</th></tr>
+{{- if gt .Order.CreditAmount 0.0}}
+<tr><th colspan="4">Gift cards and store credit</th><th class="amount">-{{money .Order.CreditAmount}}</th></tr>
+{{- end}}
+{{- if .Order.Paid}}
+<tr><th colspan="4">Paid</th><th class="amount">{{money .Order.AmountDue}}</th></tr>
+{{- else}}
+<tr><th colspan="4">Amount due</th><th class="amount">{{money .Order.AmountDue}}</th></tr>
+{{- end}}
+</tfoot>
+</table>
+</body>
+</html>
+`
+
+const textLayout = `INVOICE {{.Number}}
+Order #{{.OrderID}}
+Issued {{.CreatedAt.Format "2006-01-02 15:04"}}
+
+{{range .Details -}}
+{{printf "%-10s %-30s %5d %-16s %12s" .SKU .Name .Quantity (promotion .PromoType) (money .Price)}}
+{{end}}
+{{if gt .Order.TaxAmount 0.0 -}}
+{{printf "%-63s %12s" "TAX" (money .Order.TaxAmount)}}
+{{end -}}
+{{printf "%-63s %12s" "TOTAL" (money .Order.TotalPrice)}}
+{{if gt .Order.CreditAmount 0.0 -}}
+{{printf "%-63s %12s" "GIFT CARDS AND STORE CREDIT" (printf "-%s" (money .Order.CreditAmount))}}
+{{end -}}
+{{if .Order.Paid -}}
+{{printf "%-63s %12s" "PAID" (money .Order.AmountDue)}}
+{{else -}}
+{{printf "%-63s %12s" "AMOUNT DUE" (money .Order.AmountDue)}}
+{{end -}}
+`
@@ -0,0 +1,14 @@
+package invoice
+
+import (
+	"context"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// Repository represent the invoice's repository contract
+type Repository interface {
+	GetByOrder(ctx context.Context, orderID int64) (*models.Invoice, error)
+	NextNumber(ctx context.Context) (int64, error)
+	Store(ctx context.Context, a *models.Invoice) error
+}
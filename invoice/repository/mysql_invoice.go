@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/williamchand/kuncie-cart/invoice"
+	"github.com/williamchand/kuncie-cart/logging"
+	"github.com/williamchand/kuncie-cart/metrics"
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/transaction"
+)
+
+// errDuplicateEntry is the mysql error number of a unique key violation
+const errDuplicateEntry = 1062
+
+type mysqlInvoiceRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlInvoiceRepository will create an object that represent the invoice.Repository interface
+func NewMysqlInvoiceRepository(Conn *sql.DB) invoice.Repository {
+	return &mysqlInvoiceRepository{Conn}
+}
+
+func (m *mysqlInvoiceRepository) GetByOrder(ctx context.Context, orderID int64) (*models.Invoice, error) {
+	defer metrics.ObserveQuery("invoice", "GetByOrder", time.Now())
+	query := `SELECT id, order_id, number, updated_at, created_at FROM invoices WHERE order_id = ?`
+	res := new(models.Invoice)
+	err := transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, query, orderID).Scan(
+		&res.ID,
+		&res.OrderID,
+		&res.Number,
+		&res.UpdatedAt,
+		&res.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// NextNumber allocates the next invoice sequence value. LAST_INSERT_ID(expr) makes the increment
+// and the read a single atomic statement, so concurrent callers never receive the same value.
+func (m *mysqlInvoiceRepository) NextNumber(ctx context.Context) (int64, error) {
+	defer metrics.ObserveQuery("invoice", "NextNumber", time.Now())
+	query := `UPDATE invoice_sequence SET last_number = LAST_INSERT_ID(last_number + 1) WHERE name = 'invoice'`
+	res, err := transaction.Conn(ctx, m.Conn).ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if affect != 1 {
+		return 0, models.ErrInternalServerError
+	}
+
+	return res.LastInsertId()
+}
+
+func (m *mysqlInvoiceRepository) Store(ctx context.Context, a *models.Invoice) error {
+	defer metrics.ObserveQuery("invoice", "Store", time.Now())
+	query := `INSERT invoices SET order_id=?, number=?, updated_at=?, created_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, a.OrderID, a.Number, a.UpdatedAt, a.CreatedAt)
+	if err != nil {
+		if me, ok := err.(*mysql.MySQLError); ok && me.Number == errDuplicateEntry {
+			return models.ErrConflict
+		}
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	a.ID = lastID
+	return nil
+}
@@ -0,0 +1,20 @@
+package invoice
+
+import (
+	"context"
+	"io"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// Usecase represent the invoice's usecases
+type Usecase interface {
+	GetByOrder(ctx context.Context, orderID int64) (*models.Invoice, error)
+	Generate(ctx context.Context, orderID int64) (*models.Invoice, error)
+}
+
+// Renderer represent the contract to write an invoice document
+type Renderer interface {
+	ContentType() string
+	Render(w io.Writer, a *models.Invoice) error
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/williamchand/kuncie-cart/auth"
+	"github.com/williamchand/kuncie-cart/invoice"
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/order"
+	"github.com/williamchand/kuncie-cart/transaction"
+)
+
+// numberFormat is the layout of the human readable invoice number
+const numberFormat = "INV-%06d"
+
+type invoiceUsecase struct {
+	invoiceRepo    invoice.Repository
+	orderRepo      order.Repository
+	txManager      transaction.Manager
+	contextTimeout time.Duration
+}
+
+// NewInvoiceUsecase will create new an invoiceUsecase object representation of invoice.Usecase interface
+func NewInvoiceUsecase(i invoice.Repository, o order.Repository, tx transaction.Manager, timeout time.Duration) invoice.Usecase {
+	return &invoiceUsecase{
+		invoiceRepo:    i,
+		orderRepo:      o,
+		txManager:      tx,
+		contextTimeout: timeout,
+	}
+}
+
+// GetByOrder returns the invoice of an order to its customer or the staff. The invoice of a paid order is issued
+// the first time it is asked for, models.ErrNotFound is returned for an order not paid yet until the staff issue it.
+func (a *invoiceUsecase) GetByOrder(c context.Context, orderID int64) (*models.Invoice, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	o, err := a.orderRepo.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err = auth.AuthorizeOrder(ctx, o); err != nil {
+		return nil, err
+	}
+	res, err := a.invoiceRepo.GetByOrder(ctx, orderID)
+	if err == models.ErrNotFound && o.Paid() {
+		res, err = a.issue(ctx, orderID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return a.withOrder(ctx, res, o)
+}
+
+// Generate issues the invoice of an order, allocating its number the first time. Only the staff issue the
+// invoice of an order not paid yet.
+func (a *invoiceUsecase) Generate(c context.Context, orderID int64) (*models.Invoice, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if err := auth.Authorize(ctx, auth.PermManageOrders); err != nil {
+		return nil, err
+	}
+	o, err := a.orderRepo.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := a.issue(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.withOrder(ctx, res, o)
+}
+
+// issue returns the invoice of the order, allocating the next number when it has none yet
+func (a *invoiceUsecase) issue(ctx context.Context, orderID int64) (*models.Invoice, error) {
+	var res *models.Invoice
+	err := a.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		existing, err := a.invoiceRepo.GetByOrder(ctx, orderID)
+		if err == nil {
+			res = existing
+			return nil
+		}
+		if err != models.ErrNotFound {
+			return err
+		}
+
+		number, err := a.invoiceRepo.NextNumber(ctx)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		res = &models.Invoice{
+			OrderID:   orderID,
+			Number:    fmt.Sprintf(numberFormat, number),
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		return a.invoiceRepo.Store(ctx, res)
+	})
+	if err == models.ErrConflict {
+		// another request issued the invoice first, its number wins
+		res, err = a.invoiceRepo.GetByOrder(ctx, orderID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// withOrder attaches the order and its lines to the invoice
+func (a *invoiceUsecase) withOrder(ctx context.Context, res *models.Invoice, o *models.Order) (*models.Invoice, error) {
+	details, err := a.orderRepo.GetOrderDetails(ctx, o.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Order = o
+	res.Details = details
+	return res, nil
+}
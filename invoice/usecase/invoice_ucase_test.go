@@ -0,0 +1,172 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/williamchand/kuncie-cart/auth"
+	"github.com/williamchand/kuncie-cart/invoice/mocks"
+	ucase "github.com/williamchand/kuncie-cart/invoice/usecase"
+	"github.com/williamchand/kuncie-cart/models"
+	_orderMocks "github.com/williamchand/kuncie-cart/order/mocks"
+	_txMocks "github.com/williamchand/kuncie-cart/transaction/mocks"
+)
+
+var (
+	staff = auth.NewContext(context.TODO(), &models.Principal{Subject: "agent", Roles: []string{auth.RoleSupport}})
+	owner = auth.NewContext(context.TODO(), &models.Principal{Subject: "5", CustomerID: 5})
+)
+
+func TestGetByOrder(t *testing.T) {
+	mockOrder := &models.Order{ID: 7, CustomerID: 5, TotalPrice: 149.97, Status: models.OrderStatusPending}
+	mockDetails := []*models.OrderDetails{
+		{ID: 1, OrderID: 7, SKU: "120P90", Name: "Google Home", Price: 149.97, Quantity: 4, PromoType: "bonus_price"},
+	}
+
+	t.Run("owner", func(t *testing.T) {
+		mockInvoiceRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).Return(mockOrder, nil).Once()
+		mockOrderRepo.On("GetOrderDetails", mock.Anything, int64(7)).Return(mockDetails, nil).Once()
+		mockInvoiceRepo.On("GetByOrder", mock.Anything, int64(7)).
+			Return(&models.Invoice{ID: 1, OrderID: 7, Number: "INV-000003"}, nil).Once()
+
+		u := ucase.NewInvoiceUsecase(mockInvoiceRepo, mockOrderRepo, new(_txMocks.Manager), time.Second*2)
+		inv, err := u.GetByOrder(owner, 7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "INV-000003", inv.Number)
+		assert.Equal(t, mockOrder, inv.Order)
+		assert.Len(t, inv.Details, 1)
+		mockInvoiceRepo.AssertExpectations(t)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("not-issued", func(t *testing.T) {
+		mockInvoiceRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).Return(mockOrder, nil).Once()
+		mockInvoiceRepo.On("GetByOrder", mock.Anything, int64(7)).Return(nil, models.ErrNotFound).Once()
+
+		u := ucase.NewInvoiceUsecase(mockInvoiceRepo, mockOrderRepo, new(_txMocks.Manager), time.Second*2)
+		inv, err := u.GetByOrder(owner, 7)
+
+		assert.Equal(t, models.ErrNotFound, err)
+		assert.Nil(t, inv)
+		mockInvoiceRepo.AssertNotCalled(t, "NextNumber", mock.Anything)
+	})
+
+	t.Run("issued-on-first-request-of-a-paid-order", func(t *testing.T) {
+		paidOrder := &models.Order{ID: 7, CustomerID: 5, TotalPrice: 149.97, Status: models.OrderStatusPaid}
+		mockInvoiceRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).Return(paidOrder, nil).Once()
+		mockOrderRepo.On("GetOrderDetails", mock.Anything, int64(7)).Return(mockDetails, nil).Once()
+		mockInvoiceRepo.On("GetByOrder", mock.Anything, int64(7)).Return(nil, models.ErrNotFound).Twice()
+		mockInvoiceRepo.On("NextNumber", mock.Anything).Return(int64(42), nil).Once()
+		mockInvoiceRepo.On("Store", mock.Anything, mock.AnythingOfType("*models.Invoice")).Return(nil).Once()
+
+		u := ucase.NewInvoiceUsecase(mockInvoiceRepo, mockOrderRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		inv, err := u.GetByOrder(owner, 7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "INV-000042", inv.Number)
+		assert.Equal(t, paidOrder, inv.Order)
+		mockInvoiceRepo.AssertExpectations(t)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("order-of-another-customer", func(t *testing.T) {
+		mockInvoiceRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).Return(mockOrder, nil).Once()
+
+		u := ucase.NewInvoiceUsecase(mockInvoiceRepo, mockOrderRepo, new(_txMocks.Manager), time.Second*2)
+		ctx := auth.NewContext(context.TODO(), &models.Principal{Subject: "6", CustomerID: 6})
+		inv, err := u.GetByOrder(ctx, 7)
+
+		assert.Equal(t, models.ErrForbidden, err)
+		assert.Nil(t, inv)
+		mockInvoiceRepo.AssertNotCalled(t, "GetByOrder", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGenerate(t *testing.T) {
+	mockOrder := &models.Order{ID: 7, CustomerID: 5, TotalPrice: 149.97}
+	mockDetails := []*models.OrderDetails{
+		{ID: 1, OrderID: 7, SKU: "120P90", Name: "Google Home", Price: 149.97, Quantity: 4, PromoType: "bonus_price"},
+	}
+	mockTx := new(_txMocks.Manager)
+	mockTx.On("WithTransaction", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+
+	t.Run("new-invoice", func(t *testing.T) {
+		mockInvoiceRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).Return(mockOrder, nil).Once()
+		mockOrderRepo.On("GetOrderDetails", mock.Anything, int64(7)).Return(mockDetails, nil).Once()
+		mockInvoiceRepo.On("GetByOrder", mock.Anything, int64(7)).Return(nil, models.ErrNotFound).Once()
+		mockInvoiceRepo.On("NextNumber", mock.Anything).Return(int64(42), nil).Once()
+		mockInvoiceRepo.On("Store", mock.Anything, mock.AnythingOfType("*models.Invoice")).Return(nil).Once()
+
+		u := ucase.NewInvoiceUsecase(mockInvoiceRepo, mockOrderRepo, mockTx, time.Second*2)
+		inv, err := u.Generate(staff, 7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "INV-000042", inv.Number)
+		assert.Equal(t, mockOrder, inv.Order)
+		assert.Len(t, inv.Details, 1)
+		mockInvoiceRepo.AssertExpectations(t)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("existing-invoice", func(t *testing.T) {
+		mockInvoiceRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).Return(mockOrder, nil).Once()
+		mockOrderRepo.On("GetOrderDetails", mock.Anything, int64(7)).Return(mockDetails, nil).Once()
+		mockInvoiceRepo.On("GetByOrder", mock.Anything, int64(7)).
+			Return(&models.Invoice{ID: 1, OrderID: 7, Number: "INV-000003"}, nil).Once()
+
+		u := ucase.NewInvoiceUsecase(mockInvoiceRepo, mockOrderRepo, mockTx, time.Second*2)
+		inv, err := u.Generate(staff, 7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "INV-000003", inv.Number)
+		mockInvoiceRepo.AssertNotCalled(t, "NextNumber", mock.Anything)
+		mockInvoiceRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-happens-in-db", func(t *testing.T) {
+		mockInvoiceRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).Return(mockOrder, nil).Once()
+		mockInvoiceRepo.On("GetByOrder", mock.Anything, int64(7)).Return(nil, models.ErrNotFound).Once()
+		mockInvoiceRepo.On("NextNumber", mock.Anything).Return(int64(0), errors.New("Unexpected Error")).Once()
+
+		u := ucase.NewInvoiceUsecase(mockInvoiceRepo, mockOrderRepo, mockTx, time.Second*2)
+		inv, err := u.Generate(staff, 7)
+
+		assert.Error(t, err)
+		assert.Nil(t, inv)
+		mockInvoiceRepo.AssertExpectations(t)
+	})
+	t.Run("customer", func(t *testing.T) {
+		mockInvoiceRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+
+		u := ucase.NewInvoiceUsecase(mockInvoiceRepo, mockOrderRepo, mockTx, time.Second*2)
+		inv, err := u.Generate(owner, 7)
+
+		assert.Equal(t, models.ErrForbidden, err)
+		assert.Nil(t, inv)
+		mockInvoiceRepo.AssertNotCalled(t, "NextNumber", mock.Anything)
+	})
+}
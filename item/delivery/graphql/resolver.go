@@ -0,0 +1,410 @@
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/williamchand/kuncie-cart/item"
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+type Resolver interface {
+	Items(params graphql.ResolveParams) (interface{}, error)
+	Item(params graphql.ResolveParams) (interface{}, error)
+	Categories(params graphql.ResolveParams) (interface{}, error)
+	Products(params graphql.ResolveParams) (interface{}, error)
+	Product(params graphql.ResolveParams) (interface{}, error)
+	CreateItem(params graphql.ResolveParams) (interface{}, error)
+	UpdateItem(params graphql.ResolveParams) (interface{}, error)
+	ArchiveItem(params graphql.ResolveParams) (interface{}, error)
+	CreateCategory(params graphql.ResolveParams) (interface{}, error)
+	UpdateCategory(params graphql.ResolveParams) (interface{}, error)
+	SetItemCategories(params graphql.ResolveParams) (interface{}, error)
+	SetItemTags(params graphql.ResolveParams) (interface{}, error)
+	SetKitComponents(params graphql.ResolveParams) (interface{}, error)
+	CreateProduct(params graphql.ResolveParams) (interface{}, error)
+	UpdateProduct(params graphql.ResolveParams) (interface{}, error)
+	CreatePromotion(params graphql.ResolveParams) (interface{}, error)
+	UpdatePromotion(params graphql.ResolveParams) (interface{}, error)
+	DisablePromotion(params graphql.ResolveParams) (interface{}, error)
+	TaxRules(params graphql.ResolveParams) (interface{}, error)
+	CreateTaxRule(params graphql.ResolveParams) (interface{}, error)
+	UpdateTaxRule(params graphql.ResolveParams) (interface{}, error)
+	DisableTaxRule(params graphql.ResolveParams) (interface{}, error)
+}
+
+type resolver struct {
+	itemService item.Usecase
+}
+
+func (r resolver) Items(params graphql.ResolveParams) (interface{}, error) {
+	cursor, _ := params.Args["cursor"].(string)
+	limit, _ := params.Args["limit"].(int)
+	filter := models.ItemFilter{}
+	categoryID, _ := params.Args["category_id"].(int)
+	filter.CategoryID = int64(categoryID)
+	filter.Tag, _ = params.Args["tag"].(string)
+
+	res, err := r.itemService.Fetch(params.Context, filter, cursor, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func (r resolver) Item(params graphql.ResolveParams) (interface{}, error) {
+	var (
+		res *models.CatalogItem
+		err error
+	)
+
+	id, _ := params.Args["id"].(int)
+	sku, _ := params.Args["sku"].(string)
+	switch {
+	case id != 0:
+		res, err = r.itemService.GetByID(params.Context, int64(id))
+	case sku != "":
+		res, err = r.itemService.GetBySKU(params.Context, sku)
+	default:
+		return nil, fmt.Errorf("id or sku is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func (r resolver) Categories(params graphql.ResolveParams) (interface{}, error) {
+	return r.itemService.FetchCategories(params.Context)
+}
+
+func (r resolver) Products(params graphql.ResolveParams) (interface{}, error) {
+	cursor, _ := params.Args["cursor"].(string)
+	limit, _ := params.Args["limit"].(int)
+
+	res, err := r.itemService.FetchProducts(params.Context, cursor, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func (r resolver) Product(params graphql.ResolveParams) (interface{}, error) {
+	id, ok := params.Args["id"].(int)
+	if !ok || id == 0 {
+		return nil, fmt.Errorf("id is not integer or zero")
+	}
+
+	res, err := r.itemService.GetProduct(params.Context, int64(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func itemArgs(args map[string]interface{}) *models.Items {
+	res := &models.Items{}
+	res.SKU, _ = args["sku"].(string)
+	res.Name, _ = args["name"].(string)
+	res.Price, _ = args["price"].(float64)
+	quantity, _ := args["inventory_quantity"].(int)
+	res.InventoryQuantity = int64(quantity)
+	productID, _ := args["product_id"].(int)
+	res.ProductID = int64(productID)
+	list, _ := args["attributes"].([]interface{})
+	res.Attributes = make([]*models.Attribute, 0, len(list))
+	for _, v := range list {
+		attribute, _ := v.(map[string]interface{})
+		name, _ := attribute["name"].(string)
+		value, _ := attribute["value"].(string)
+		res.Attributes = append(res.Attributes, &models.Attribute{Name: name, Value: value})
+	}
+	return res
+}
+
+func productArgs(args map[string]interface{}) *models.Product {
+	res := &models.Product{}
+	res.Name, _ = args["name"].(string)
+	res.Description, _ = args["description"].(string)
+	return res
+}
+
+func promotionArgs(args map[string]interface{}) *models.Promotions {
+	res := &models.Promotions{}
+	res.PromoType, _ = args["promo_type"].(string)
+	res.Promo, _ = args["promo"].(string)
+	quantity, _ := args["quantity_requirement"].(int)
+	res.QuantityRequirement = int64(quantity)
+	return res
+}
+
+func taxRuleArgs(args map[string]interface{}) *models.TaxRule {
+	res := &models.TaxRule{}
+	res.Name, _ = args["name"].(string)
+	res.Rate, _ = args["rate"].(float64)
+	return res
+}
+
+func (r resolver) CreateItem(params graphql.ResolveParams) (interface{}, error) {
+	it := itemArgs(params.Args)
+	if err := r.itemService.Store(params.Context, it); err != nil {
+		return nil, err
+	}
+
+	return *it, nil
+}
+
+func (r resolver) UpdateItem(params graphql.ResolveParams) (interface{}, error) {
+	id, ok := params.Args["id"].(int)
+	if !ok || id == 0 {
+		return nil, fmt.Errorf("id is not integer or zero")
+	}
+
+	it := itemArgs(params.Args)
+	it.ID = int64(id)
+	if err := r.itemService.Update(params.Context, it); err != nil {
+		return nil, err
+	}
+
+	return *it, nil
+}
+
+func (r resolver) ArchiveItem(params graphql.ResolveParams) (interface{}, error) {
+	id, ok := params.Args["id"].(int)
+	if !ok || id == 0 {
+		return nil, fmt.Errorf("id is not integer or zero")
+	}
+
+	if err := r.itemService.Archive(params.Context, int64(id)); err != nil {
+		return nil, err
+	}
+
+	return true, nil
+}
+
+func categoryArgs(args map[string]interface{}) *models.Category {
+	res := &models.Category{}
+	res.Name, _ = args["name"].(string)
+	parentID, _ := args["parent_id"].(int)
+	res.ParentID = int64(parentID)
+	return res
+}
+
+func (r resolver) CreateCategory(params graphql.ResolveParams) (interface{}, error) {
+	c := categoryArgs(params.Args)
+	if err := r.itemService.StoreCategory(params.Context, c); err != nil {
+		return nil, err
+	}
+
+	return *c, nil
+}
+
+func (r resolver) UpdateCategory(params graphql.ResolveParams) (interface{}, error) {
+	id, ok := params.Args["id"].(int)
+	if !ok || id == 0 {
+		return nil, fmt.Errorf("id is not integer or zero")
+	}
+
+	c := categoryArgs(params.Args)
+	c.ID = int64(id)
+	if err := r.itemService.UpdateCategory(params.Context, c); err != nil {
+		return nil, err
+	}
+
+	return *c, nil
+}
+
+func (r resolver) SetItemCategories(params graphql.ResolveParams) (interface{}, error) {
+	itemsID, ok := params.Args["items_id"].(int)
+	if !ok || itemsID == 0 {
+		return nil, fmt.Errorf("items_id is not integer or zero")
+	}
+	list, _ := params.Args["category_ids"].([]interface{})
+	categoryID := make([]int64, 0, len(list))
+	for _, v := range list {
+		id, _ := v.(int)
+		categoryID = append(categoryID, int64(id))
+	}
+
+	res, err := r.itemService.SetCategories(params.Context, int64(itemsID), categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func (r resolver) SetItemTags(params graphql.ResolveParams) (interface{}, error) {
+	itemsID, ok := params.Args["items_id"].(int)
+	if !ok || itemsID == 0 {
+		return nil, fmt.Errorf("items_id is not integer or zero")
+	}
+	list, _ := params.Args["tags"].([]interface{})
+	tags := make([]string, 0, len(list))
+	for _, v := range list {
+		tag, _ := v.(string)
+		tags = append(tags, tag)
+	}
+
+	res, err := r.itemService.SetTags(params.Context, int64(itemsID), tags)
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func (r resolver) SetKitComponents(params graphql.ResolveParams) (interface{}, error) {
+	itemsID, ok := params.Args["items_id"].(int)
+	if !ok || itemsID == 0 {
+		return nil, fmt.Errorf("items_id is not integer or zero")
+	}
+	list, _ := params.Args["components"].([]interface{})
+	components := make([]*models.KitComponent, 0, len(list))
+	for _, v := range list {
+		component, _ := v.(map[string]interface{})
+		componentID, _ := component["items_id"].(int)
+		quantity, _ := component["quantity"].(int)
+		components = append(components, &models.KitComponent{
+			ItemsID:  int64(componentID),
+			Quantity: int64(quantity),
+		})
+	}
+
+	res, err := r.itemService.SetKitComponents(params.Context, int64(itemsID), components)
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func (r resolver) CreateProduct(params graphql.ResolveParams) (interface{}, error) {
+	p := productArgs(params.Args)
+	if err := r.itemService.StoreProduct(params.Context, p); err != nil {
+		return nil, err
+	}
+
+	return *p, nil
+}
+
+func (r resolver) UpdateProduct(params graphql.ResolveParams) (interface{}, error) {
+	id, ok := params.Args["id"].(int)
+	if !ok || id == 0 {
+		return nil, fmt.Errorf("id is not integer or zero")
+	}
+
+	p := productArgs(params.Args)
+	p.ID = int64(id)
+	if err := r.itemService.UpdateProduct(params.Context, p); err != nil {
+		return nil, err
+	}
+
+	return *p, nil
+}
+
+func (r resolver) CreatePromotion(params graphql.ResolveParams) (interface{}, error) {
+	itemsID, _ := params.Args["items_id"].(int)
+	productID, _ := params.Args["product_id"].(int)
+	categoryID, _ := params.Args["category_id"].(int)
+
+	p := promotionArgs(params.Args)
+	p.ItemsID = int64(itemsID)
+	p.ProductID = int64(productID)
+	p.CategoryID = int64(categoryID)
+	p.Tag, _ = params.Args["tag"].(string)
+	if err := r.itemService.StorePromotion(params.Context, p); err != nil {
+		return nil, err
+	}
+
+	return *p, nil
+}
+
+func (r resolver) UpdatePromotion(params graphql.ResolveParams) (interface{}, error) {
+	id, ok := params.Args["id"].(int)
+	if !ok || id == 0 {
+		return nil, fmt.Errorf("id is not integer or zero")
+	}
+
+	p := promotionArgs(params.Args)
+	p.ID = int64(id)
+	if err := r.itemService.UpdatePromotion(params.Context, p); err != nil {
+		return nil, err
+	}
+
+	return *p, nil
+}
+
+func (r resolver) DisablePromotion(params graphql.ResolveParams) (interface{}, error) {
+	id, ok := params.Args["id"].(int)
+	if !ok || id == 0 {
+		return nil, fmt.Errorf("id is not integer or zero")
+	}
+
+	res, err := r.itemService.DisablePromotion(params.Context, int64(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func (r resolver) TaxRules(params graphql.ResolveParams) (interface{}, error) {
+	return r.itemService.FetchTaxRules(params.Context)
+}
+
+func (r resolver) CreateTaxRule(params graphql.ResolveParams) (interface{}, error) {
+	itemsID, _ := params.Args["items_id"].(int)
+	productID, _ := params.Args["product_id"].(int)
+	categoryID, _ := params.Args["category_id"].(int)
+
+	t := taxRuleArgs(params.Args)
+	t.ItemsID = int64(itemsID)
+	t.ProductID = int64(productID)
+	t.CategoryID = int64(categoryID)
+	t.Tag, _ = params.Args["tag"].(string)
+	if err := r.itemService.StoreTaxRule(params.Context, t); err != nil {
+		return nil, err
+	}
+
+	return *t, nil
+}
+
+func (r resolver) UpdateTaxRule(params graphql.ResolveParams) (interface{}, error) {
+	id, ok := params.Args["id"].(int)
+	if !ok || id == 0 {
+		return nil, fmt.Errorf("id is not integer or zero")
+	}
+
+	t := taxRuleArgs(params.Args)
+	t.ID = int64(id)
+	if err := r.itemService.UpdateTaxRule(params.Context, t); err != nil {
+		return nil, err
+	}
+
+	return *t, nil
+}
+
+func (r resolver) DisableTaxRule(params graphql.ResolveParams) (interface{}, error) {
+	id, ok := params.Args["id"].(int)
+	if !ok || id == 0 {
+		return nil, fmt.Errorf("id is not integer or zero")
+	}
+
+	res, err := r.itemService.DisableTaxRule(params.Context, int64(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func NewResolver(itemService item.Usecase) Resolver {
+	return &resolver{
+		itemService: itemService,
+	}
+}
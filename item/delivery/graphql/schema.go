@@ -0,0 +1,626 @@
+package graphql
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/williamchand/kuncie-cart/auth"
+)
+
+// PromotionGraphQL holds promotion information with graphql object
+var PromotionGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Promotion",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"items_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"product_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"category_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"tag": &graphql.Field{
+				Type: graphql.String,
+			},
+			"promo_type": &graphql.Field{
+				Type: graphql.String,
+			},
+			"promo": &graphql.Field{
+				Type: graphql.String,
+			},
+			"quantity_requirement": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"active": &graphql.Field{
+				Type: graphql.Boolean,
+			},
+		},
+	},
+)
+
+// TaxRuleGraphQL holds tax rule information with graphql object
+var TaxRuleGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "TaxRule",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"name": &graphql.Field{
+				Type: graphql.String,
+			},
+			"rate": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"items_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"product_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"category_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"tag": &graphql.Field{
+				Type: graphql.String,
+			},
+			"active": &graphql.Field{
+				Type: graphql.Boolean,
+			},
+		},
+	},
+)
+
+// AttributeGraphQL holds a variant attribute with graphql object
+var AttributeGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Attribute",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{
+				Type: graphql.String,
+			},
+			"value": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+	},
+)
+
+// AttributeInput holds a variant attribute as a graphql input object
+var AttributeInput = graphql.NewInputObject(
+	graphql.InputObjectConfig{
+		Name: "AttributeInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name": &graphql.InputObjectFieldConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"value": &graphql.InputObjectFieldConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+	},
+)
+
+// KitComponentGraphQL holds a kit component with graphql object
+var KitComponentGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "KitComponent",
+		Fields: graphql.Fields{
+			"items_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"sku": &graphql.Field{
+				Type: graphql.String,
+			},
+			"name": &graphql.Field{
+				Type: graphql.String,
+			},
+			"quantity": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"inventory_quantity": &graphql.Field{
+				Type: graphql.Int,
+			},
+		},
+	},
+)
+
+// KitComponentInput holds a kit component as a graphql input object
+var KitComponentInput = graphql.NewInputObject(
+	graphql.InputObjectConfig{
+		Name: "KitComponentInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"items_id": &graphql.InputObjectFieldConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+			"quantity": &graphql.InputObjectFieldConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+		},
+	},
+)
+
+// CategoryGraphQL holds category information with graphql object
+var CategoryGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Category",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"parent_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"name": &graphql.Field{
+				Type: graphql.String,
+			},
+			"path": &graphql.Field{
+				Type: graphql.String,
+			},
+			"updated_at": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+			"created_at": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+		},
+	},
+)
+
+// ItemGraphQL holds catalog item information with graphql object
+var ItemGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Item",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"product_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"sku": &graphql.Field{
+				Type: graphql.String,
+			},
+			"name": &graphql.Field{
+				Type: graphql.String,
+			},
+			"price": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"inventory_quantity": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"attributes": &graphql.Field{
+				Type: graphql.NewList(AttributeGraphQL),
+			},
+			"categories": &graphql.Field{
+				Type: graphql.NewList(CategoryGraphQL),
+			},
+			"tags": &graphql.Field{
+				Type: graphql.NewList(graphql.String),
+			},
+			"components": &graphql.Field{
+				Type: graphql.NewList(KitComponentGraphQL),
+			},
+			"available": &graphql.Field{
+				Type: graphql.Boolean,
+			},
+			"promotion": &graphql.Field{
+				Type: PromotionGraphQL,
+			},
+			"tax_rule": &graphql.Field{
+				Type: TaxRuleGraphQL,
+			},
+			"updated_at": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+			"created_at": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+		},
+	},
+)
+
+// AdminItemGraphQL holds item information as stored, archived or not, with graphql object
+var AdminItemGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "AdminItem",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"product_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"sku": &graphql.Field{
+				Type: graphql.String,
+			},
+			"name": &graphql.Field{
+				Type: graphql.String,
+			},
+			"price": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"inventory_quantity": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"attributes": &graphql.Field{
+				Type: graphql.NewList(AttributeGraphQL),
+			},
+			"archived": &graphql.Field{
+				Type: graphql.Boolean,
+			},
+			"updated_at": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+			"created_at": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+		},
+	},
+)
+
+// ItemPageGraphQL holds a page of the catalog with graphql object
+var ItemPageGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "ItemPage",
+		Fields: graphql.Fields{
+			"items": &graphql.Field{
+				Type: graphql.NewList(ItemGraphQL),
+			},
+			"next_cursor": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+	},
+)
+
+// ProductGraphQL holds product information with its variants with graphql object
+var ProductGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Product",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"name": &graphql.Field{
+				Type: graphql.String,
+			},
+			"description": &graphql.Field{
+				Type: graphql.String,
+			},
+			"promotion": &graphql.Field{
+				Type: PromotionGraphQL,
+			},
+			"variants": &graphql.Field{
+				Type: graphql.NewList(ItemGraphQL),
+			},
+			"updated_at": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+			"created_at": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+		},
+	},
+)
+
+// ProductPageGraphQL holds a page of products with graphql object
+var ProductPageGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "ProductPage",
+		Fields: graphql.Fields{
+			"products": &graphql.Field{
+				Type: graphql.NewList(ProductGraphQL),
+			},
+			"next_cursor": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+	},
+)
+
+// Schema is struct which has method for Query and Mutation fields. Please init this struct using constructor function.
+type Schema struct {
+	itemResolver Resolver
+}
+
+// QueryFields initializes the catalog fields of the graphql query.
+func (s Schema) QueryFields() graphql.Fields {
+	return graphql.Fields{
+		"Items": &graphql.Field{
+			Type:        ItemPageGraphQL,
+			Description: "Browse the catalog, pass the next_cursor of a page to get the following one",
+			Args: graphql.FieldConfigArgument{
+				"cursor": &graphql.ArgumentConfig{
+					Type: graphql.String,
+				},
+				"category_id": &graphql.ArgumentConfig{
+					Type:        graphql.Int,
+					Description: "Only the items of this category and its subcategories",
+				},
+				"tag": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "Only the items with this tag",
+				},
+				"limit": &graphql.ArgumentConfig{
+					Type:         graphql.Int,
+					DefaultValue: 10,
+				},
+			},
+			Resolve: s.itemResolver.Items,
+		},
+		"Item": &graphql.Field{
+			Type:        ItemGraphQL,
+			Description: "Get an item by id or sku",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type: graphql.Int,
+				},
+				"sku": &graphql.ArgumentConfig{
+					Type: graphql.String,
+				},
+			},
+			Resolve: s.itemResolver.Item,
+		},
+		"Categories": &graphql.Field{
+			Type:        graphql.NewList(CategoryGraphQL),
+			Description: "Get the category tree, every category following its parent",
+			Resolve:     s.itemResolver.Categories,
+		},
+		"Products": &graphql.Field{
+			Type:        ProductPageGraphQL,
+			Description: "Browse the products with their variants, pass the next_cursor of a page to get the following one",
+			Args: graphql.FieldConfigArgument{
+				"cursor": &graphql.ArgumentConfig{
+					Type: graphql.String,
+				},
+				"limit": &graphql.ArgumentConfig{
+					Type:         graphql.Int,
+					DefaultValue: 10,
+				},
+			},
+			Resolve: s.itemResolver.Products,
+		},
+		"Product": &graphql.Field{
+			Type:        ProductGraphQL,
+			Description: "Get a product with its variants by id",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+			},
+			Resolve: s.itemResolver.Product,
+		},
+		"TaxRules": &graphql.Field{
+			Type:        graphql.NewList(TaxRuleGraphQL),
+			Description: "List the active tax rules",
+			Resolve:     auth.Require(auth.PermManageCatalog, s.itemResolver.TaxRules),
+		},
+	}
+}
+
+// MutationFields initializes the item, promotion and tax rule administration fields of the graphql mutation.
+func (s Schema) MutationFields() graphql.Fields {
+	itemArgs := graphql.FieldConfigArgument{
+		"sku": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"name": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"price": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.Float),
+		},
+		"inventory_quantity": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.Int),
+		},
+		"product_id": &graphql.ArgumentConfig{
+			Type: graphql.Int,
+		},
+		"attributes": &graphql.ArgumentConfig{
+			Type: graphql.NewList(AttributeInput),
+		},
+	}
+	productArgs := graphql.FieldConfigArgument{
+		"name": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"description": &graphql.ArgumentConfig{
+			Type: graphql.String,
+		},
+	}
+	categoryArgs := graphql.FieldConfigArgument{
+		"name": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"parent_id": &graphql.ArgumentConfig{
+			Type:         graphql.Int,
+			DefaultValue: 0,
+		},
+	}
+	promotionArgs := graphql.FieldConfigArgument{
+		"promo_type": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"promo": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"quantity_requirement": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.Int),
+		},
+	}
+	taxRuleArgs := graphql.FieldConfigArgument{
+		"name": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"rate": &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.Float),
+			Description: "Fraction of the price, 0.11 for 11%",
+		},
+	}
+	withArg := func(args graphql.FieldConfigArgument, name string) graphql.FieldConfigArgument {
+		res := graphql.FieldConfigArgument{
+			name: &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+		}
+		for k, v := range args {
+			res[k] = v
+		}
+		return res
+	}
+
+	return graphql.Fields{
+		"CreateItem": &graphql.Field{
+			Type:        AdminItemGraphQL,
+			Description: "Add an item to the catalog",
+			Args:        itemArgs,
+			Resolve:     auth.Require(auth.PermManageCatalog, s.itemResolver.CreateItem),
+		},
+		"UpdateItem": &graphql.Field{
+			Type:        AdminItemGraphQL,
+			Description: "Replace the details of an item",
+			Args:        withArg(itemArgs, "id"),
+			Resolve:     auth.Require(auth.PermManageCatalog, s.itemResolver.UpdateItem),
+		},
+		"ArchiveItem": &graphql.Field{
+			Type:        graphql.Boolean,
+			Description: "Remove an item from the catalog",
+			Args:        withArg(graphql.FieldConfigArgument{}, "id"),
+			Resolve:     auth.Require(auth.PermManageCatalog, s.itemResolver.ArchiveItem),
+		},
+		"CreateProduct": &graphql.Field{
+			Type:        ProductGraphQL,
+			Description: "Add a product, its variants are items created with its product_id",
+			Args:        productArgs,
+			Resolve:     auth.Require(auth.PermManageCatalog, s.itemResolver.CreateProduct),
+		},
+		"UpdateProduct": &graphql.Field{
+			Type:        ProductGraphQL,
+			Description: "Replace the details of a product",
+			Args:        withArg(productArgs, "id"),
+			Resolve:     auth.Require(auth.PermManageCatalog, s.itemResolver.UpdateProduct),
+		},
+		"CreateCategory": &graphql.Field{
+			Type:        CategoryGraphQL,
+			Description: "Add a category under parent_id, or at the root when it is omitted",
+			Args:        categoryArgs,
+			Resolve:     auth.Require(auth.PermManageCatalog, s.itemResolver.CreateCategory),
+		},
+		"UpdateCategory": &graphql.Field{
+			Type:        CategoryGraphQL,
+			Description: "Rename a category or move it with its subcategories",
+			Args:        withArg(categoryArgs, "id"),
+			Resolve:     auth.Require(auth.PermManageCatalog, s.itemResolver.UpdateCategory),
+		},
+		"SetItemCategories": &graphql.Field{
+			Type:        ItemGraphQL,
+			Description: "Replace the categories of an item",
+			Args: withArg(graphql.FieldConfigArgument{
+				"category_ids": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.Int))),
+				},
+			}, "items_id"),
+			Resolve: auth.Require(auth.PermManageCatalog, s.itemResolver.SetItemCategories),
+		},
+		"SetItemTags": &graphql.Field{
+			Type:        ItemGraphQL,
+			Description: "Replace the tags of an item",
+			Args: withArg(graphql.FieldConfigArgument{
+				"tags": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+				},
+			}, "items_id"),
+			Resolve: auth.Require(auth.PermManageCatalog, s.itemResolver.SetItemTags),
+		},
+		"SetKitComponents": &graphql.Field{
+			Type:        ItemGraphQL,
+			Description: "Make an item a kit of the given components, none makes it a plain item again",
+			Args: withArg(graphql.FieldConfigArgument{
+				"components": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(KitComponentInput))),
+				},
+			}, "items_id"),
+			Resolve: auth.Require(auth.PermManageCatalog, s.itemResolver.SetKitComponents),
+		},
+		"CreatePromotion": &graphql.Field{
+			Type:        PromotionGraphQL,
+			Description: "Start a promotion on an item, a product, a category or a tag without an active one",
+			Args: graphql.FieldConfigArgument{
+				"items_id": &graphql.ArgumentConfig{
+					Type: graphql.Int,
+				},
+				"product_id": &graphql.ArgumentConfig{
+					Type: graphql.Int,
+				},
+				"category_id": &graphql.ArgumentConfig{
+					Type: graphql.Int,
+				},
+				"tag": &graphql.ArgumentConfig{
+					Type: graphql.String,
+				},
+				"promo_type":           promotionArgs["promo_type"],
+				"promo":                promotionArgs["promo"],
+				"quantity_requirement": promotionArgs["quantity_requirement"],
+			},
+			Resolve: auth.Require(auth.PermManageCatalog, s.itemResolver.CreatePromotion),
+		},
+		"UpdatePromotion": &graphql.Field{
+			Type:        PromotionGraphQL,
+			Description: "Change the terms of a promotion",
+			Args:        withArg(promotionArgs, "id"),
+			Resolve:     auth.Require(auth.PermManageCatalog, s.itemResolver.UpdatePromotion),
+		},
+		"DisablePromotion": &graphql.Field{
+			Type:        PromotionGraphQL,
+			Description: "Stop applying a promotion",
+			Args:        withArg(graphql.FieldConfigArgument{}, "id"),
+			Resolve:     auth.Require(auth.PermManageCatalog, s.itemResolver.DisablePromotion),
+		},
+		"CreateTaxRule": &graphql.Field{
+			Type:        TaxRuleGraphQL,
+			Description: "Tax an item, a product, a category or a tag without an active tax rule",
+			Args: graphql.FieldConfigArgument{
+				"items_id": &graphql.ArgumentConfig{
+					Type: graphql.Int,
+				},
+				"product_id": &graphql.ArgumentConfig{
+					Type: graphql.Int,
+				},
+				"category_id": &graphql.ArgumentConfig{
+					Type: graphql.Int,
+				},
+				"tag": &graphql.ArgumentConfig{
+					Type: graphql.String,
+				},
+				"name": taxRuleArgs["name"],
+				"rate": taxRuleArgs["rate"],
+			},
+			Resolve: auth.Require(auth.PermManageCatalog, s.itemResolver.CreateTaxRule),
+		},
+		"UpdateTaxRule": &graphql.Field{
+			Type:        TaxRuleGraphQL,
+			Description: "Change the name and the rate of a tax rule",
+			Args:        withArg(taxRuleArgs, "id"),
+			Resolve:     auth.Require(auth.PermManageCatalog, s.itemResolver.UpdateTaxRule),
+		},
+		"DisableTaxRule": &graphql.Field{
+			Type:        TaxRuleGraphQL,
+			Description: "Stop applying a tax rule",
+			Args:        withArg(graphql.FieldConfigArgument{}, "id"),
+			Resolve:     auth.Require(auth.PermManageCatalog, s.itemResolver.DisableTaxRule),
+		},
+	}
+}
+
+// NewSchema initializes Schema struct which takes resolver as the argument.
+func NewSchema(itemResolver Resolver) Schema {
+	return Schema{
+		itemResolver: itemResolver,
+	}
+}
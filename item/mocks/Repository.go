@@ -0,0 +1,719 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	models "github.com/williamchand/kuncie-cart/models"
+)
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+// Archive provides a mock function with given fields: ctx, id
+func (_m *Repository) Archive(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CountKitsUsing provides a mock function with given fields: ctx, itemsID
+func (_m *Repository) CountKitsUsing(ctx context.Context, itemsID int64) (int64, error) {
+	ret := _m.Called(ctx, itemsID)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, int64) int64); ok {
+		r0 = rf(ctx, itemsID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, itemsID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Fetch provides a mock function with given fields: ctx, filter, cursor, num
+func (_m *Repository) Fetch(ctx context.Context, filter models.ItemFilter, cursor string, num int64) ([]*models.Items, string, error) {
+	ret := _m.Called(ctx, filter, cursor, num)
+
+	var r0 []*models.Items
+	if rf, ok := ret.Get(0).(func(context.Context, models.ItemFilter, string, int64) []*models.Items); ok {
+		r0 = rf(ctx, filter, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Items)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, models.ItemFilter, string, int64) string); ok {
+		r1 = rf(ctx, filter, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, models.ItemFilter, string, int64) error); ok {
+		r2 = rf(ctx, filter, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchByID provides a mock function with given fields: ctx, id
+func (_m *Repository) FetchByID(ctx context.Context, id []int64) ([]*models.Items, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []*models.Items
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []*models.Items); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Items)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchBySKU provides a mock function with given fields: ctx, sku
+func (_m *Repository) FetchBySKU(ctx context.Context, sku []string) ([]*models.Items, error) {
+	ret := _m.Called(ctx, sku)
+
+	var r0 []*models.Items
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*models.Items); ok {
+		r0 = rf(ctx, sku)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Items)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, sku)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchCategories provides a mock function with given fields: ctx
+func (_m *Repository) FetchCategories(ctx context.Context) ([]*models.Category, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*models.Category
+	if rf, ok := ret.Get(0).(func(context.Context) []*models.Category); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Category)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchCategoryPromotions provides a mock function with given fields: ctx, categoryID
+func (_m *Repository) FetchCategoryPromotions(ctx context.Context, categoryID []int64) ([]*models.Promotions, error) {
+	ret := _m.Called(ctx, categoryID)
+
+	var r0 []*models.Promotions
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []*models.Promotions); ok {
+		r0 = rf(ctx, categoryID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Promotions)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, categoryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchItemCategories provides a mock function with given fields: ctx, itemsID
+func (_m *Repository) FetchItemCategories(ctx context.Context, itemsID []int64) (map[int64][]*models.Category, error) {
+	ret := _m.Called(ctx, itemsID)
+
+	var r0 map[int64][]*models.Category
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) map[int64][]*models.Category); ok {
+		r0 = rf(ctx, itemsID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int64][]*models.Category)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, itemsID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchItemTags provides a mock function with given fields: ctx, itemsID
+func (_m *Repository) FetchItemTags(ctx context.Context, itemsID []int64) (map[int64][]string, error) {
+	ret := _m.Called(ctx, itemsID)
+
+	var r0 map[int64][]string
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) map[int64][]string); ok {
+		r0 = rf(ctx, itemsID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int64][]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, itemsID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchKitComponents provides a mock function with given fields: ctx, kitID
+func (_m *Repository) FetchKitComponents(ctx context.Context, kitID []int64) (map[int64][]*models.KitComponent, error) {
+	ret := _m.Called(ctx, kitID)
+
+	var r0 map[int64][]*models.KitComponent
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) map[int64][]*models.KitComponent); ok {
+		r0 = rf(ctx, kitID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int64][]*models.KitComponent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, kitID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchProductPromotions provides a mock function with given fields: ctx, productID
+func (_m *Repository) FetchProductPromotions(ctx context.Context, productID []int64) ([]*models.Promotions, error) {
+	ret := _m.Called(ctx, productID)
+
+	var r0 []*models.Promotions
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []*models.Promotions); ok {
+		r0 = rf(ctx, productID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Promotions)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, productID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchProducts provides a mock function with given fields: ctx, cursor, num
+func (_m *Repository) FetchProducts(ctx context.Context, cursor string, num int64) ([]*models.Product, string, error) {
+	ret := _m.Called(ctx, cursor, num)
+
+	var r0 []*models.Product
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []*models.Product); ok {
+		r0 = rf(ctx, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Product)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
+		r1 = rf(ctx, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
+		r2 = rf(ctx, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchPromotions provides a mock function with given fields: ctx, itemsID
+func (_m *Repository) FetchPromotions(ctx context.Context, itemsID []int64) ([]*models.Promotions, error) {
+	ret := _m.Called(ctx, itemsID)
+
+	var r0 []*models.Promotions
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []*models.Promotions); ok {
+		r0 = rf(ctx, itemsID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Promotions)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, itemsID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchTagPromotions provides a mock function with given fields: ctx, tags
+func (_m *Repository) FetchTagPromotions(ctx context.Context, tags []string) ([]*models.Promotions, error) {
+	ret := _m.Called(ctx, tags)
+
+	var r0 []*models.Promotions
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*models.Promotions); ok {
+		r0 = rf(ctx, tags)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Promotions)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, tags)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchTaxRules provides a mock function with given fields: ctx
+func (_m *Repository) FetchTaxRules(ctx context.Context) ([]*models.TaxRule, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*models.TaxRule
+	if rf, ok := ret.Get(0).(func(context.Context) []*models.TaxRule); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.TaxRule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchVariants provides a mock function with given fields: ctx, productID
+func (_m *Repository) FetchVariants(ctx context.Context, productID []int64) ([]*models.Items, error) {
+	ret := _m.Called(ctx, productID)
+
+	var r0 []*models.Items
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []*models.Items); ok {
+		r0 = rf(ctx, productID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Items)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, productID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *Repository) GetByID(ctx context.Context, id int64) (*models.Items, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.Items
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.Items); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Items)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBySKU provides a mock function with given fields: ctx, sku
+func (_m *Repository) GetBySKU(ctx context.Context, sku string) (*models.Items, error) {
+	ret := _m.Called(ctx, sku)
+
+	var r0 *models.Items
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Items); ok {
+		r0 = rf(ctx, sku)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Items)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, sku)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCategory provides a mock function with given fields: ctx, id
+func (_m *Repository) GetCategory(ctx context.Context, id int64) (*models.Category, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.Category
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.Category); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Category)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProduct provides a mock function with given fields: ctx, id
+func (_m *Repository) GetProduct(ctx context.Context, id int64) (*models.Product, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.Product
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.Product); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Product)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPromotion provides a mock function with given fields: ctx, id
+func (_m *Repository) GetPromotion(ctx context.Context, id int64) (*models.Promotions, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.Promotions
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.Promotions); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Promotions)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTaxRule provides a mock function with given fields: ctx, id
+func (_m *Repository) GetTaxRule(ctx context.Context, id int64) (*models.TaxRule, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.TaxRule
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.TaxRule); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TaxRule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MoveCategories provides a mock function with given fields: ctx, oldPath, newPath
+func (_m *Repository) MoveCategories(ctx context.Context, oldPath string, newPath string) error {
+	ret := _m.Called(ctx, oldPath, newPath)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, oldPath, newPath)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetItemCategories provides a mock function with given fields: ctx, itemsID, categoryID
+func (_m *Repository) SetItemCategories(ctx context.Context, itemsID int64, categoryID []int64) error {
+	ret := _m.Called(ctx, itemsID, categoryID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []int64) error); ok {
+		r0 = rf(ctx, itemsID, categoryID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetItemTags provides a mock function with given fields: ctx, itemsID, tags
+func (_m *Repository) SetItemTags(ctx context.Context, itemsID int64, tags []string) error {
+	ret := _m.Called(ctx, itemsID, tags)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []string) error); ok {
+		r0 = rf(ctx, itemsID, tags)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetKitComponents provides a mock function with given fields: ctx, kitID, components
+func (_m *Repository) SetKitComponents(ctx context.Context, kitID int64, components []*models.KitComponent) error {
+	ret := _m.Called(ctx, kitID, components)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []*models.KitComponent) error); ok {
+		r0 = rf(ctx, kitID, components)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Store provides a mock function with given fields: ctx, a
+func (_m *Repository) Store(ctx context.Context, a *models.Items) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Items) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreCategory provides a mock function with given fields: ctx, a
+func (_m *Repository) StoreCategory(ctx context.Context, a *models.Category) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Category) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreProduct provides a mock function with given fields: ctx, a
+func (_m *Repository) StoreProduct(ctx context.Context, a *models.Product) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Product) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StorePromotion provides a mock function with given fields: ctx, a
+func (_m *Repository) StorePromotion(ctx context.Context, a *models.Promotions) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Promotions) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreTaxRule provides a mock function with given fields: ctx, a
+func (_m *Repository) StoreTaxRule(ctx context.Context, a *models.TaxRule) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.TaxRule) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, a
+func (_m *Repository) Update(ctx context.Context, a *models.Items) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Items) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateCategory provides a mock function with given fields: ctx, a
+func (_m *Repository) UpdateCategory(ctx context.Context, a *models.Category) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Category) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateProduct provides a mock function with given fields: ctx, a
+func (_m *Repository) UpdateProduct(ctx context.Context, a *models.Product) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Product) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePromotion provides a mock function with given fields: ctx, a
+func (_m *Repository) UpdatePromotion(ctx context.Context, a *models.Promotions) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Promotions) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTaxRule provides a mock function with given fields: ctx, a
+func (_m *Repository) UpdateTaxRule(ctx context.Context, a *models.TaxRule) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.TaxRule) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
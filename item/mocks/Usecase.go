@@ -0,0 +1,467 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	models "github.com/williamchand/kuncie-cart/models"
+)
+
+// Usecase is an autogenerated mock type for the Usecase type
+type Usecase struct {
+	mock.Mock
+}
+
+// Archive provides a mock function with given fields: ctx, id
+func (_m *Usecase) Archive(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DisablePromotion provides a mock function with given fields: ctx, id
+func (_m *Usecase) DisablePromotion(ctx context.Context, id int64) (*models.Promotions, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.Promotions
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.Promotions); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Promotions)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DisableTaxRule provides a mock function with given fields: ctx, id
+func (_m *Usecase) DisableTaxRule(ctx context.Context, id int64) (*models.TaxRule, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.TaxRule
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.TaxRule); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TaxRule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Fetch provides a mock function with given fields: ctx, filter, cursor, num
+func (_m *Usecase) Fetch(ctx context.Context, filter models.ItemFilter, cursor string, num int64) (*models.ItemPage, error) {
+	ret := _m.Called(ctx, filter, cursor, num)
+
+	var r0 *models.ItemPage
+	if rf, ok := ret.Get(0).(func(context.Context, models.ItemFilter, string, int64) *models.ItemPage); ok {
+		r0 = rf(ctx, filter, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ItemPage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, models.ItemFilter, string, int64) error); ok {
+		r1 = rf(ctx, filter, cursor, num)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByID provides a mock function with given fields: ctx, id
+func (_m *Usecase) FetchByID(ctx context.Context, id []int64) ([]*models.CatalogItem, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []*models.CatalogItem
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []*models.CatalogItem); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.CatalogItem)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchCategories provides a mock function with given fields: ctx
+func (_m *Usecase) FetchCategories(ctx context.Context) ([]*models.Category, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*models.Category
+	if rf, ok := ret.Get(0).(func(context.Context) []*models.Category); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Category)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchProducts provides a mock function with given fields: ctx, cursor, num
+func (_m *Usecase) FetchProducts(ctx context.Context, cursor string, num int64) (*models.ProductPage, error) {
+	ret := _m.Called(ctx, cursor, num)
+
+	var r0 *models.ProductPage
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) *models.ProductPage); ok {
+		r0 = rf(ctx, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ProductPage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, cursor, num)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchTaxRules provides a mock function with given fields: ctx
+func (_m *Usecase) FetchTaxRules(ctx context.Context) ([]*models.TaxRule, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*models.TaxRule
+	if rf, ok := ret.Get(0).(func(context.Context) []*models.TaxRule); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.TaxRule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *Usecase) GetByID(ctx context.Context, id int64) (*models.CatalogItem, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.CatalogItem
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.CatalogItem); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.CatalogItem)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBySKU provides a mock function with given fields: ctx, sku
+func (_m *Usecase) GetBySKU(ctx context.Context, sku string) (*models.CatalogItem, error) {
+	ret := _m.Called(ctx, sku)
+
+	var r0 *models.CatalogItem
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.CatalogItem); ok {
+		r0 = rf(ctx, sku)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.CatalogItem)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, sku)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProduct provides a mock function with given fields: ctx, id
+func (_m *Usecase) GetProduct(ctx context.Context, id int64) (*models.Product, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.Product
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.Product); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Product)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetCategories provides a mock function with given fields: ctx, itemsID, categoryID
+func (_m *Usecase) SetCategories(ctx context.Context, itemsID int64, categoryID []int64) (*models.CatalogItem, error) {
+	ret := _m.Called(ctx, itemsID, categoryID)
+
+	var r0 *models.CatalogItem
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []int64) *models.CatalogItem); ok {
+		r0 = rf(ctx, itemsID, categoryID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.CatalogItem)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []int64) error); ok {
+		r1 = rf(ctx, itemsID, categoryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetKitComponents provides a mock function with given fields: ctx, kitID, components
+func (_m *Usecase) SetKitComponents(ctx context.Context, kitID int64, components []*models.KitComponent) (*models.CatalogItem, error) {
+	ret := _m.Called(ctx, kitID, components)
+
+	var r0 *models.CatalogItem
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []*models.KitComponent) *models.CatalogItem); ok {
+		r0 = rf(ctx, kitID, components)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.CatalogItem)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []*models.KitComponent) error); ok {
+		r1 = rf(ctx, kitID, components)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetTags provides a mock function with given fields: ctx, itemsID, tags
+func (_m *Usecase) SetTags(ctx context.Context, itemsID int64, tags []string) (*models.CatalogItem, error) {
+	ret := _m.Called(ctx, itemsID, tags)
+
+	var r0 *models.CatalogItem
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []string) *models.CatalogItem); ok {
+		r0 = rf(ctx, itemsID, tags)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.CatalogItem)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []string) error); ok {
+		r1 = rf(ctx, itemsID, tags)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store provides a mock function with given fields: ctx, a
+func (_m *Usecase) Store(ctx context.Context, a *models.Items) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Items) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreCategory provides a mock function with given fields: ctx, a
+func (_m *Usecase) StoreCategory(ctx context.Context, a *models.Category) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Category) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreProduct provides a mock function with given fields: ctx, a
+func (_m *Usecase) StoreProduct(ctx context.Context, a *models.Product) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Product) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StorePromotion provides a mock function with given fields: ctx, a
+func (_m *Usecase) StorePromotion(ctx context.Context, a *models.Promotions) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Promotions) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreTaxRule provides a mock function with given fields: ctx, a
+func (_m *Usecase) StoreTaxRule(ctx context.Context, a *models.TaxRule) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.TaxRule) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, a
+func (_m *Usecase) Update(ctx context.Context, a *models.Items) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Items) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateCategory provides a mock function with given fields: ctx, a
+func (_m *Usecase) UpdateCategory(ctx context.Context, a *models.Category) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Category) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateProduct provides a mock function with given fields: ctx, a
+func (_m *Usecase) UpdateProduct(ctx context.Context, a *models.Product) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Product) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePromotion provides a mock function with given fields: ctx, a
+func (_m *Usecase) UpdatePromotion(ctx context.Context, a *models.Promotions) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Promotions) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTaxRule provides a mock function with given fields: ctx, a
+func (_m *Usecase) UpdateTaxRule(ctx context.Context, a *models.TaxRule) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.TaxRule) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,47 @@
+package item
+
+import (
+	"context"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// Repository represent the item catalog's repository contract
+type Repository interface {
+	Fetch(ctx context.Context, filter models.ItemFilter, cursor string, num int64) (res []*models.Items, nextCursor string, err error)
+	GetByID(ctx context.Context, id int64) (*models.Items, error)
+	GetBySKU(ctx context.Context, sku string) (*models.Items, error)
+	FetchByID(ctx context.Context, id []int64) (res []*models.Items, err error)
+	FetchBySKU(ctx context.Context, sku []string) (res []*models.Items, err error)
+	FetchVariants(ctx context.Context, productID []int64) (res []*models.Items, err error)
+	FetchPromotions(ctx context.Context, itemsID []int64) (res []*models.Promotions, err error)
+	FetchProductPromotions(ctx context.Context, productID []int64) (res []*models.Promotions, err error)
+	FetchCategoryPromotions(ctx context.Context, categoryID []int64) (res []*models.Promotions, err error)
+	FetchTagPromotions(ctx context.Context, tags []string) (res []*models.Promotions, err error)
+	Store(ctx context.Context, a *models.Items) error
+	Update(ctx context.Context, a *models.Items) error
+	Archive(ctx context.Context, id int64) error
+	FetchProducts(ctx context.Context, cursor string, num int64) (res []*models.Product, nextCursor string, err error)
+	GetProduct(ctx context.Context, id int64) (*models.Product, error)
+	StoreProduct(ctx context.Context, a *models.Product) error
+	UpdateProduct(ctx context.Context, a *models.Product) error
+	FetchCategories(ctx context.Context) (res []*models.Category, err error)
+	GetCategory(ctx context.Context, id int64) (*models.Category, error)
+	StoreCategory(ctx context.Context, a *models.Category) error
+	UpdateCategory(ctx context.Context, a *models.Category) error
+	MoveCategories(ctx context.Context, oldPath string, newPath string) error
+	FetchItemCategories(ctx context.Context, itemsID []int64) (res map[int64][]*models.Category, err error)
+	SetItemCategories(ctx context.Context, itemsID int64, categoryID []int64) error
+	FetchItemTags(ctx context.Context, itemsID []int64) (res map[int64][]string, err error)
+	SetItemTags(ctx context.Context, itemsID int64, tags []string) error
+	FetchKitComponents(ctx context.Context, kitID []int64) (res map[int64][]*models.KitComponent, err error)
+	SetKitComponents(ctx context.Context, kitID int64, components []*models.KitComponent) error
+	CountKitsUsing(ctx context.Context, itemsID int64) (int64, error)
+	GetPromotion(ctx context.Context, id int64) (*models.Promotions, error)
+	StorePromotion(ctx context.Context, a *models.Promotions) error
+	UpdatePromotion(ctx context.Context, a *models.Promotions) error
+	FetchTaxRules(ctx context.Context) (res []*models.TaxRule, err error)
+	GetTaxRule(ctx context.Context, id int64) (*models.TaxRule, error)
+	StoreTaxRule(ctx context.Context, a *models.TaxRule) error
+	UpdateTaxRule(ctx context.Context, a *models.TaxRule) error
+}
@@ -0,0 +1,936 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/williamchand/kuncie-cart/item"
+	"github.com/williamchand/kuncie-cart/logging"
+	"github.com/williamchand/kuncie-cart/metrics"
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/transaction"
+)
+
+// errDuplicateEntry is the mysql error number of a unique key violation
+const errDuplicateEntry = 1062
+
+type mysqlItemRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlItemRepository will create an object that represent the item.Repository interface
+func NewMysqlItemRepository(Conn *sql.DB) item.Repository {
+	return &mysqlItemRepository{Conn}
+}
+
+func (m *mysqlItemRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]*models.Items, error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	result := make([]*models.Items, 0)
+	for rows.Next() {
+		t := new(models.Items)
+		attributes := ""
+		err = rows.Scan(
+			&t.ID,
+			&t.ProductID,
+			&t.SKU,
+			&t.Name,
+			&t.Price,
+			&t.InventoryQuantity,
+			&attributes,
+			&t.Archived,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		if t.Attributes, err = decodeAttributes(attributes); err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+// Fetch pages through the items by id, so items added while browsing do not shift the pages
+func (m *mysqlItemRepository) Fetch(ctx context.Context, filter models.ItemFilter, cursor string, num int64) ([]*models.Items, string, error) {
+	defer metrics.ObserveQuery("item", "Fetch", time.Now())
+	query := `SELECT id, product_id, sku, name, price, inventory_quantity, IFNULL(attributes, ''), archived, updated_at, created_at
+  						FROM items WHERE archived = 0 AND id > ?`
+
+	afterID, err := DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", models.ErrBadParamInput
+	}
+	args := []interface{}{afterID}
+	if filter.CategoryID != 0 {
+		query += ` AND id IN (SELECT ic.items_id FROM item_categories ic JOIN categories c ON c.id = ic.category_id
+  						JOIN categories f ON f.id = ? WHERE c.path LIKE CONCAT(f.path, '%'))`
+		args = append(args, filter.CategoryID)
+	}
+	if filter.Tag != "" {
+		query += ` AND id IN (SELECT items_id FROM item_tags WHERE tag = ?)`
+		args = append(args, filter.Tag)
+	}
+	query += ` ORDER BY id LIMIT ?`
+
+	// one more row than asked tells whether there is a next page
+	res, err := m.fetch(ctx, query, append(args, num+1)...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if int64(len(res)) > num {
+		res = res[:num]
+		nextCursor = EncodeCursor(res[len(res)-1].ID)
+	}
+
+	return res, nextCursor, nil
+}
+
+func (m *mysqlItemRepository) getOne(ctx context.Context, query string, args ...interface{}) (*models.Items, error) {
+	list, err := m.fetch(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, models.ErrNotFound
+	}
+
+	return list[0], nil
+}
+
+func (m *mysqlItemRepository) GetByID(ctx context.Context, id int64) (*models.Items, error) {
+	defer metrics.ObserveQuery("item", "GetByID", time.Now())
+	query := `SELECT id, product_id, sku, name, price, inventory_quantity, IFNULL(attributes, ''), archived, updated_at, created_at
+  						FROM items WHERE archived = 0 AND id = ?`
+
+	return m.getOne(ctx, query, id)
+}
+
+func (m *mysqlItemRepository) GetBySKU(ctx context.Context, sku string) (*models.Items, error) {
+	defer metrics.ObserveQuery("item", "GetBySKU", time.Now())
+	query := `SELECT id, product_id, sku, name, price, inventory_quantity, IFNULL(attributes, ''), archived, updated_at, created_at
+  						FROM items WHERE archived = 0 AND sku = ?`
+
+	return m.getOne(ctx, query, sku)
+}
+
+// FetchByID returns the items with the given ids still in the catalog
+func (m *mysqlItemRepository) FetchByID(ctx context.Context, id []int64) ([]*models.Items, error) {
+	defer metrics.ObserveQuery("item", "FetchByID", time.Now())
+	if len(id) == 0 {
+		return make([]*models.Items, 0), nil
+	}
+
+	query := `SELECT id, product_id, sku, name, price, inventory_quantity, IFNULL(attributes, ''), archived, updated_at, created_at
+  						FROM items WHERE archived = 0 AND id IN (?` + strings.Repeat(",?", len(id)-1) + `)`
+
+	return m.fetch(ctx, query, int64Args(id)...)
+}
+
+// FetchBySKU returns the items with the given SKUs, archived ones included
+func (m *mysqlItemRepository) FetchBySKU(ctx context.Context, sku []string) ([]*models.Items, error) {
+	defer metrics.ObserveQuery("item", "FetchBySKU", time.Now())
+	if len(sku) == 0 {
+		return make([]*models.Items, 0), nil
+	}
+
+	args := make([]interface{}, len(sku))
+	for i, s := range sku {
+		args[i] = s
+	}
+	query := `SELECT id, product_id, sku, name, price, inventory_quantity, IFNULL(attributes, ''), archived, updated_at, created_at
+  						FROM items WHERE sku IN (?` + strings.Repeat(",?", len(args)-1) + `)`
+
+	return m.fetch(ctx, query, args...)
+}
+
+// FetchPromotions returns the active promotions targeting the given items
+func (m *mysqlItemRepository) FetchPromotions(ctx context.Context, itemsID []int64) ([]*models.Promotions, error) {
+	defer metrics.ObserveQuery("item", "FetchPromotions", time.Now())
+	return m.fetchPromotions(ctx, "items_id", int64Args(itemsID))
+}
+
+// FetchProductPromotions returns the active promotions targeting every variant of the given products
+func (m *mysqlItemRepository) FetchProductPromotions(ctx context.Context, productID []int64) ([]*models.Promotions, error) {
+	defer metrics.ObserveQuery("item", "FetchProductPromotions", time.Now())
+	return m.fetchPromotions(ctx, "product_id", int64Args(productID))
+}
+
+// FetchCategoryPromotions returns the active promotions targeting the given categories
+func (m *mysqlItemRepository) FetchCategoryPromotions(ctx context.Context, categoryID []int64) ([]*models.Promotions, error) {
+	defer metrics.ObserveQuery("item", "FetchCategoryPromotions", time.Now())
+	return m.fetchPromotions(ctx, "category_id", int64Args(categoryID))
+}
+
+// FetchTagPromotions returns the active promotions targeting the given tags
+func (m *mysqlItemRepository) FetchTagPromotions(ctx context.Context, tags []string) ([]*models.Promotions, error) {
+	defer metrics.ObserveQuery("item", "FetchTagPromotions", time.Now())
+	args := make([]interface{}, len(tags))
+	for i, v := range tags {
+		args[i] = v
+	}
+	return m.fetchPromotions(ctx, "tag", args)
+}
+
+func int64Args(id []int64) []interface{} {
+	args := make([]interface{}, len(id))
+	for i, v := range id {
+		args[i] = v
+	}
+	return args
+}
+
+func (m *mysqlItemRepository) fetchPromotions(ctx context.Context, column string, args []interface{}) ([]*models.Promotions, error) {
+	result := make([]*models.Promotions, 0)
+	if len(args) == 0 {
+		return result, nil
+	}
+
+	query := `SELECT id, items_id, product_id, category_id, tag, promo_type, promo, quantity_requirement, active
+  						FROM promotions WHERE active = 1 AND ` + column + ` IN (?` + strings.Repeat(",?", len(args)-1) + `) ORDER BY id`
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	for rows.Next() {
+		t := new(models.Promotions)
+		err = rows.Scan(
+			&t.ID,
+			&t.ItemsID,
+			&t.ProductID,
+			&t.CategoryID,
+			&t.Tag,
+			&t.PromoType,
+			&t.Promo,
+			&t.QuantityRequirement,
+			&t.Active,
+		)
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+func (m *mysqlItemRepository) Store(ctx context.Context, a *models.Items) error {
+	defer metrics.ObserveQuery("item", "Store", time.Now())
+	query := `INSERT items SET product_id=?, sku=?, name=?, price=?, inventory_quantity=?, attributes=?, archived=?, updated_at=?, created_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	attributes, err := encodeAttributes(a.Attributes)
+	if err != nil {
+		return err
+	}
+	res, err := stmt.ExecContext(ctx, a.ProductID, a.SKU, a.Name, a.Price, a.InventoryQuantity, attributes, a.Archived, a.UpdatedAt, a.CreatedAt)
+	if err != nil {
+		if me, ok := err.(*mysql.MySQLError); ok && me.Number == errDuplicateEntry {
+			return models.ErrConflict
+		}
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	a.ID = lastID
+	return nil
+}
+
+// exec runs an update of a single row and tells models.ErrNotFound apart from an update that changed nothing
+func (m *mysqlItemRepository) exec(ctx context.Context, table string, id int64, query string, args ...interface{}) error {
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		if me, ok := err.(*mysql.MySQLError); ok && me.Number == errDuplicateEntry {
+			return models.ErrConflict
+		}
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect > 0 {
+		return nil
+	}
+
+	var found int64
+	err = transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table+" WHERE id = ?", id).Scan(&found)
+	if err != nil {
+		return err
+	}
+	if found == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+func (m *mysqlItemRepository) Update(ctx context.Context, a *models.Items) error {
+	defer metrics.ObserveQuery("item", "Update", time.Now())
+	query := `UPDATE items set product_id=?, sku=?, name=?, price=?, inventory_quantity=?, attributes=?, updated_at=? WHERE id = ?`
+
+	attributes, err := encodeAttributes(a.Attributes)
+	if err != nil {
+		return err
+	}
+	return m.exec(ctx, "items", a.ID, query, a.ProductID, a.SKU, a.Name, a.Price, a.InventoryQuantity, attributes, a.UpdatedAt, a.ID)
+}
+
+func (m *mysqlItemRepository) Archive(ctx context.Context, id int64) error {
+	defer metrics.ObserveQuery("item", "Archive", time.Now())
+	query := `UPDATE items set archived=1 WHERE id = ?`
+
+	return m.exec(ctx, "items", id, query, id)
+}
+
+func (m *mysqlItemRepository) GetPromotion(ctx context.Context, id int64) (*models.Promotions, error) {
+	defer metrics.ObserveQuery("item", "GetPromotion", time.Now())
+	query := `SELECT id, items_id, product_id, category_id, tag, promo_type, promo, quantity_requirement, active
+  						FROM promotions WHERE id = ?`
+
+	res := new(models.Promotions)
+	err := transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, query, id).Scan(
+		&res.ID,
+		&res.ItemsID,
+		&res.ProductID,
+		&res.CategoryID,
+		&res.Tag,
+		&res.PromoType,
+		&res.Promo,
+		&res.QuantityRequirement,
+		&res.Active,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (m *mysqlItemRepository) StorePromotion(ctx context.Context, a *models.Promotions) error {
+	defer metrics.ObserveQuery("item", "StorePromotion", time.Now())
+	query := `INSERT promotions SET items_id=?, product_id=?, category_id=?, tag=?, promo_type=?, promo=?, quantity_requirement=?, active=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, a.ItemsID, a.ProductID, a.CategoryID, a.Tag, a.PromoType, a.Promo, a.QuantityRequirement, a.Active)
+	if me, ok := err.(*mysql.MySQLError); ok && me.Number == errDuplicateEntry {
+		// another active promotion was stored on the same target meanwhile
+		return models.ErrConflict
+	}
+	if err != nil {
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	a.ID = lastID
+	return nil
+}
+
+func (m *mysqlItemRepository) UpdatePromotion(ctx context.Context, a *models.Promotions) error {
+	defer metrics.ObserveQuery("item", "UpdatePromotion", time.Now())
+	query := `UPDATE promotions set promo_type=?, promo=?, quantity_requirement=?, active=? WHERE id = ?`
+
+	return m.exec(ctx, "promotions", a.ID, query, a.PromoType, a.Promo, a.QuantityRequirement, a.Active, a.ID)
+}
+
+// FetchTaxRules returns the active tax rules, oldest first
+func (m *mysqlItemRepository) FetchTaxRules(ctx context.Context) ([]*models.TaxRule, error) {
+	defer metrics.ObserveQuery("item", "FetchTaxRules", time.Now())
+	query := `SELECT id, name, rate, items_id, product_id, category_id, tag, active
+  						FROM tax_rules WHERE active = 1 ORDER BY id`
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	result := make([]*models.TaxRule, 0)
+	for rows.Next() {
+		t := new(models.TaxRule)
+		err = rows.Scan(
+			&t.ID,
+			&t.Name,
+			&t.Rate,
+			&t.ItemsID,
+			&t.ProductID,
+			&t.CategoryID,
+			&t.Tag,
+			&t.Active,
+		)
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+func (m *mysqlItemRepository) GetTaxRule(ctx context.Context, id int64) (*models.TaxRule, error) {
+	defer metrics.ObserveQuery("item", "GetTaxRule", time.Now())
+	query := `SELECT id, name, rate, items_id, product_id, category_id, tag, active
+  						FROM tax_rules WHERE id = ?`
+
+	res := new(models.TaxRule)
+	err := transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, query, id).Scan(
+		&res.ID,
+		&res.Name,
+		&res.Rate,
+		&res.ItemsID,
+		&res.ProductID,
+		&res.CategoryID,
+		&res.Tag,
+		&res.Active,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (m *mysqlItemRepository) StoreTaxRule(ctx context.Context, a *models.TaxRule) error {
+	defer metrics.ObserveQuery("item", "StoreTaxRule", time.Now())
+	query := `INSERT tax_rules SET name=?, rate=?, items_id=?, product_id=?, category_id=?, tag=?, active=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, a.Name, a.Rate, a.ItemsID, a.ProductID, a.CategoryID, a.Tag, a.Active)
+	if err != nil {
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	a.ID = lastID
+	return nil
+}
+
+func (m *mysqlItemRepository) UpdateTaxRule(ctx context.Context, a *models.TaxRule) error {
+	defer metrics.ObserveQuery("item", "UpdateTaxRule", time.Now())
+	query := `UPDATE tax_rules set name=?, rate=?, active=? WHERE id = ?`
+
+	return m.exec(ctx, "tax_rules", a.ID, query, a.Name, a.Rate, a.Active, a.ID)
+}
+
+// FetchVariants returns the items sold under the given products
+func (m *mysqlItemRepository) FetchVariants(ctx context.Context, productID []int64) ([]*models.Items, error) {
+	defer metrics.ObserveQuery("item", "FetchVariants", time.Now())
+	if len(productID) == 0 {
+		return make([]*models.Items, 0), nil
+	}
+
+	query := `SELECT id, product_id, sku, name, price, inventory_quantity, IFNULL(attributes, ''), archived, updated_at, created_at
+  						FROM items WHERE archived = 0 AND product_id IN (?` + strings.Repeat(",?", len(productID)-1) + `) ORDER BY id`
+
+	return m.fetch(ctx, query, int64Args(productID)...)
+}
+
+func (m *mysqlItemRepository) fetchProducts(ctx context.Context, query string, args ...interface{}) ([]*models.Product, error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	result := make([]*models.Product, 0)
+	for rows.Next() {
+		t := new(models.Product)
+		err = rows.Scan(
+			&t.ID,
+			&t.Name,
+			&t.Description,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+// FetchProducts pages through the products by id like Fetch does for the items
+func (m *mysqlItemRepository) FetchProducts(ctx context.Context, cursor string, num int64) ([]*models.Product, string, error) {
+	defer metrics.ObserveQuery("item", "FetchProducts", time.Now())
+	query := `SELECT id, name, IFNULL(description, ''), updated_at, created_at
+  						FROM products WHERE id > ? ORDER BY id LIMIT ?`
+
+	afterID, err := DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", models.ErrBadParamInput
+	}
+
+	res, err := m.fetchProducts(ctx, query, afterID, num+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if int64(len(res)) > num {
+		res = res[:num]
+		nextCursor = EncodeCursor(res[len(res)-1].ID)
+	}
+
+	return res, nextCursor, nil
+}
+
+func (m *mysqlItemRepository) GetProduct(ctx context.Context, id int64) (*models.Product, error) {
+	defer metrics.ObserveQuery("item", "GetProduct", time.Now())
+	query := `SELECT id, name, IFNULL(description, ''), updated_at, created_at
+  						FROM products WHERE id = ?`
+
+	list, err := m.fetchProducts(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, models.ErrNotFound
+	}
+
+	return list[0], nil
+}
+
+func (m *mysqlItemRepository) StoreProduct(ctx context.Context, a *models.Product) error {
+	defer metrics.ObserveQuery("item", "StoreProduct", time.Now())
+	query := `INSERT products SET name=?, description=?, updated_at=?, created_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, a.Name, a.Description, a.UpdatedAt, a.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	a.ID = lastID
+	return nil
+}
+
+func (m *mysqlItemRepository) UpdateProduct(ctx context.Context, a *models.Product) error {
+	defer metrics.ObserveQuery("item", "UpdateProduct", time.Now())
+	query := `UPDATE products set name=?, description=?, updated_at=? WHERE id = ?`
+
+	return m.exec(ctx, "products", a.ID, query, a.Name, a.Description, a.UpdatedAt, a.ID)
+}
+
+func (m *mysqlItemRepository) fetchCategories(ctx context.Context, query string, args ...interface{}) ([]*models.Category, error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	result := make([]*models.Category, 0)
+	for rows.Next() {
+		t := new(models.Category)
+		err = rows.Scan(
+			&t.ID,
+			&t.ParentID,
+			&t.Name,
+			&t.Path,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+// FetchCategories returns the whole category tree, every category following its parent
+func (m *mysqlItemRepository) FetchCategories(ctx context.Context) ([]*models.Category, error) {
+	defer metrics.ObserveQuery("item", "FetchCategories", time.Now())
+	query := `SELECT id, parent_id, name, path, updated_at, created_at FROM categories ORDER BY path`
+
+	return m.fetchCategories(ctx, query)
+}
+
+func (m *mysqlItemRepository) GetCategory(ctx context.Context, id int64) (*models.Category, error) {
+	defer metrics.ObserveQuery("item", "GetCategory", time.Now())
+	query := `SELECT id, parent_id, name, path, updated_at, created_at FROM categories WHERE id = ?`
+
+	list, err := m.fetchCategories(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, models.ErrNotFound
+	}
+
+	return list[0], nil
+}
+
+func (m *mysqlItemRepository) StoreCategory(ctx context.Context, a *models.Category) error {
+	defer metrics.ObserveQuery("item", "StoreCategory", time.Now())
+	query := `INSERT categories SET parent_id=?, name=?, path=?, updated_at=?, created_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, a.ParentID, a.Name, a.Path, a.UpdatedAt, a.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	a.ID = lastID
+	return nil
+}
+
+func (m *mysqlItemRepository) UpdateCategory(ctx context.Context, a *models.Category) error {
+	defer metrics.ObserveQuery("item", "UpdateCategory", time.Now())
+	query := `UPDATE categories set parent_id=?, name=?, path=?, updated_at=? WHERE id = ?`
+
+	return m.exec(ctx, "categories", a.ID, query, a.ParentID, a.Name, a.Path, a.UpdatedAt, a.ID)
+}
+
+// MoveCategories replaces the path prefix of a category and of all its subcategories
+func (m *mysqlItemRepository) MoveCategories(ctx context.Context, oldPath string, newPath string) error {
+	defer metrics.ObserveQuery("item", "MoveCategories", time.Now())
+	query := `UPDATE categories set path = CONCAT(?, SUBSTRING(path, ?)) WHERE path LIKE ?`
+
+	_, err := transaction.Conn(ctx, m.Conn).ExecContext(ctx, query, newPath, len(oldPath)+1, oldPath+"%")
+	return err
+}
+
+// FetchItemCategories returns the categories of the given items by item id
+func (m *mysqlItemRepository) FetchItemCategories(ctx context.Context, itemsID []int64) (map[int64][]*models.Category, error) {
+	defer metrics.ObserveQuery("item", "FetchItemCategories", time.Now())
+	result := make(map[int64][]*models.Category)
+	if len(itemsID) == 0 {
+		return result, nil
+	}
+
+	query := `SELECT ic.items_id, c.id, c.parent_id, c.name, c.path, c.updated_at, c.created_at
+  						FROM item_categories ic JOIN categories c ON c.id = ic.category_id
+  						WHERE ic.items_id IN (?` + strings.Repeat(",?", len(itemsID)-1) + `) ORDER BY c.path`
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, int64Args(itemsID)...)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	for rows.Next() {
+		var id int64
+		t := new(models.Category)
+		err = rows.Scan(
+			&id,
+			&t.ID,
+			&t.ParentID,
+			&t.Name,
+			&t.Path,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		result[id] = append(result[id], t)
+	}
+
+	return result, rows.Err()
+}
+
+// SetItemCategories replaces the categories of an item
+func (m *mysqlItemRepository) SetItemCategories(ctx context.Context, itemsID int64, categoryID []int64) error {
+	defer metrics.ObserveQuery("item", "SetItemCategories", time.Now())
+	conn := transaction.Conn(ctx, m.Conn)
+	if _, err := conn.ExecContext(ctx, `DELETE FROM item_categories WHERE items_id = ?`, itemsID); err != nil {
+		return err
+	}
+	for _, id := range categoryID {
+		if _, err := conn.ExecContext(ctx, `INSERT item_categories SET items_id=?, category_id=?`, itemsID, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FetchItemTags returns the tags of the given items by item id
+func (m *mysqlItemRepository) FetchItemTags(ctx context.Context, itemsID []int64) (map[int64][]string, error) {
+	defer metrics.ObserveQuery("item", "FetchItemTags", time.Now())
+	result := make(map[int64][]string)
+	if len(itemsID) == 0 {
+		return result, nil
+	}
+
+	query := `SELECT items_id, tag FROM item_tags
+  						WHERE items_id IN (?` + strings.Repeat(",?", len(itemsID)-1) + `) ORDER BY tag`
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, int64Args(itemsID)...)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	for rows.Next() {
+		var (
+			id  int64
+			tag string
+		)
+		if err = rows.Scan(&id, &tag); err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		result[id] = append(result[id], tag)
+	}
+
+	return result, rows.Err()
+}
+
+// SetItemTags replaces the tags of an item
+func (m *mysqlItemRepository) SetItemTags(ctx context.Context, itemsID int64, tags []string) error {
+	defer metrics.ObserveQuery("item", "SetItemTags", time.Now())
+	conn := transaction.Conn(ctx, m.Conn)
+	if _, err := conn.ExecContext(ctx, `DELETE FROM item_tags WHERE items_id = ?`, itemsID); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if _, err := conn.ExecContext(ctx, `INSERT item_tags SET items_id=?, tag=?`, itemsID, tag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FetchKitComponents returns the components of the given kits by kit id, items that are not kits have none
+func (m *mysqlItemRepository) FetchKitComponents(ctx context.Context, kitID []int64) (map[int64][]*models.KitComponent, error) {
+	defer metrics.ObserveQuery("item", "FetchKitComponents", time.Now())
+	result := make(map[int64][]*models.KitComponent)
+	if len(kitID) == 0 {
+		return result, nil
+	}
+
+	query := `SELECT k.kit_items_id, k.items_id, i.sku, i.name, k.quantity, i.inventory_quantity
+  						FROM kit_components k JOIN items i ON i.id = k.items_id
+  						WHERE k.kit_items_id IN (?` + strings.Repeat(",?", len(kitID)-1) + `) ORDER BY k.kit_items_id, k.items_id`
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, int64Args(kitID)...)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	for rows.Next() {
+		t := new(models.KitComponent)
+		err = rows.Scan(
+			&t.KitID,
+			&t.ItemsID,
+			&t.SKU,
+			&t.Name,
+			&t.Quantity,
+			&t.InventoryQuantity,
+		)
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		result[t.KitID] = append(result[t.KitID], t)
+	}
+
+	return result, rows.Err()
+}
+
+// SetKitComponents replaces the components of a kit, none turns the kit back into a plain item
+func (m *mysqlItemRepository) SetKitComponents(ctx context.Context, kitID int64, components []*models.KitComponent) error {
+	defer metrics.ObserveQuery("item", "SetKitComponents", time.Now())
+	conn := transaction.Conn(ctx, m.Conn)
+	if _, err := conn.ExecContext(ctx, `DELETE FROM kit_components WHERE kit_items_id = ?`, kitID); err != nil {
+		return err
+	}
+	for _, c := range components {
+		query := `INSERT kit_components SET kit_items_id=?, items_id=?, quantity=?`
+		if _, err := conn.ExecContext(ctx, query, kitID, c.ItemsID, c.Quantity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CountKitsUsing returns the number of kits the item is a component of
+func (m *mysqlItemRepository) CountKitsUsing(ctx context.Context, itemsID int64) (int64, error) {
+	defer metrics.ObserveQuery("item", "CountKitsUsing", time.Now())
+	var res int64
+	query := `SELECT COUNT(*) FROM kit_components WHERE items_id = ?`
+	err := transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, query, itemsID).Scan(&res)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return 0, err
+	}
+
+	return res, nil
+}
+
+// encodeAttributes stores the attributes of a variant as a JSON array, NULL when there are none
+func encodeAttributes(attributes []*models.Attribute) (sql.NullString, error) {
+	if len(attributes) == 0 {
+		return sql.NullString{}, nil
+	}
+	byt, err := json.Marshal(attributes)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	return sql.NullString{String: string(byt), Valid: true}, nil
+}
+
+func decodeAttributes(s string) ([]*models.Attribute, error) {
+	res := make([]*models.Attribute, 0)
+	if s == "" {
+		return res, nil
+	}
+	if err := json.Unmarshal([]byte(s), &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// DecodeCursor will decode cursor from user for mysql, an empty cursor starts from the first item
+func DecodeCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	byt, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(string(byt), 10, 64)
+}
+
+// EncodeCursor will encode cursor from mysql to user
+func EncodeCursor(id int64) string {
+	return base64.StdEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
@@ -0,0 +1,171 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	itemRepo "github.com/williamchand/kuncie-cart/item/repository"
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+func TestFetch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "product_id", "sku", "name", "price", "inventory_quantity", "attributes", "archived", "updated_at", "created_at"}).
+		AddRow(2, 0, "43N23P", "Macbook Pro", 5399.99, 5, "", false, now, now).
+		AddRow(3, 0, "A304SD", "Alexa Speaker", 109.50, 10, "", false, now, now).
+		AddRow(4, 0, "234234", "Raspberry Pi B", 30.00, 2, "", false, now, now)
+
+	query := "SELECT id, product_id, sku, name, price, inventory_quantity, IFNULL\\(attributes, ''\\), archived, updated_at, created_at FROM items WHERE archived = 0 AND id > \\? ORDER BY id LIMIT \\?"
+	mock.ExpectQuery(query).WithArgs(int64(1), int64(3)).WillReturnRows(rows)
+
+	a := itemRepo.NewMysqlItemRepository(db)
+	list, nextCursor, err := a.Fetch(context.TODO(), models.ItemFilter{}, itemRepo.EncodeCursor(1), 2)
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, itemRepo.EncodeCursor(3), nextCursor)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchLastPage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "product_id", "sku", "name", "price", "inventory_quantity", "attributes", "archived", "updated_at", "created_at"}).
+		AddRow(4, 0, "234234", "Raspberry Pi B", 30.00, 2, "", false, now, now)
+
+	query := "SELECT id, product_id, sku, name, price, inventory_quantity, IFNULL\\(attributes, ''\\), archived, updated_at, created_at FROM items WHERE archived = 0 AND id > \\? ORDER BY id LIMIT \\?"
+	mock.ExpectQuery(query).WithArgs(int64(3), int64(3)).WillReturnRows(rows)
+
+	a := itemRepo.NewMysqlItemRepository(db)
+	list, nextCursor, err := a.Fetch(context.TODO(), models.ItemFilter{}, itemRepo.EncodeCursor(3), 2)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Empty(t, nextCursor)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchFiltered(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "product_id", "sku", "name", "price", "inventory_quantity", "attributes", "archived", "updated_at", "created_at"}).
+		AddRow(3, 0, "A304SD", "Alexa Speaker", 109.50, 10, "", false, now, now)
+
+	query := "SELECT (.+) FROM items WHERE archived = 0 AND id > \\? AND id IN \\(SELECT ic.items_id (.+) c.path LIKE CONCAT\\(f.path, '%'\\)\\) AND id IN \\(SELECT items_id FROM item_tags WHERE tag = \\?\\) ORDER BY id LIMIT \\?"
+	mock.ExpectQuery(query).WithArgs(int64(0), int64(1), "speaker", int64(11)).WillReturnRows(rows)
+
+	a := itemRepo.NewMysqlItemRepository(db)
+	list, nextCursor, err := a.Fetch(context.TODO(), models.ItemFilter{CategoryID: 1, Tag: "speaker"}, "", 10)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Empty(t, nextCursor)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchVariants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "product_id", "sku", "name", "price", "inventory_quantity", "attributes", "archived", "updated_at", "created_at"}).
+		AddRow(5, 7, "TSHIRT-M", "T-Shirt M", 12.00, 3, `[{"name":"size","value":"M"},{"name":"color","value":"red"}]`, false, now, now)
+
+	query := "SELECT id, product_id, sku, name, price, inventory_quantity, IFNULL\\(attributes, ''\\), archived, updated_at, created_at FROM items WHERE archived = 0 AND product_id IN \\(\\?\\) ORDER BY id"
+	mock.ExpectQuery(query).WithArgs(int64(7)).WillReturnRows(rows)
+
+	a := itemRepo.NewMysqlItemRepository(db)
+	list, err := a.FetchVariants(context.TODO(), []int64{7})
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, []*models.Attribute{{Name: "size", Value: "M"}, {Name: "color", Value: "red"}}, list[0].Attributes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchKitComponents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"kit_items_id", "items_id", "sku", "name", "quantity", "inventory_quantity"}).
+		AddRow(12, 1, "120P90", "Google Home", 1, 10).
+		AddRow(12, 4, "234234", "Raspberry Pi B", 2, 5)
+
+	query := "SELECT k.kit_items_id, k.items_id, i.sku, i.name, k.quantity, i.inventory_quantity FROM kit_components k JOIN items i ON (.+) WHERE k.kit_items_id IN \\(\\?,\\?\\)"
+	mock.ExpectQuery(query).WithArgs(int64(12), int64(13)).WillReturnRows(rows)
+
+	a := itemRepo.NewMysqlItemRepository(db)
+	res, err := a.FetchKitComponents(context.TODO(), []int64{12, 13})
+	assert.NoError(t, err)
+	assert.Len(t, res[12], 2)
+	assert.Empty(t, res[13])
+	assert.Equal(t, int64(2), models.KitStock(res[12]))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreDuplicateSKU(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "INSERT items SET product_id=\\?, sku=\\?, name=\\?, price=\\?, inventory_quantity=\\?, attributes=\\?, archived=\\?, updated_at=\\?, created_at=\\?"
+	mock.ExpectPrepare(query).ExpectExec().
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '120P90' for key 'items_sku'"})
+
+	a := itemRepo.NewMysqlItemRepository(db)
+	err = a.Store(context.TODO(), &models.Items{SKU: "120P90", Name: "Google Home", Price: 49.99, InventoryQuantity: 10})
+	assert.Equal(t, models.ErrConflict, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStorePromotionActiveOnTarget(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "INSERT promotions SET items_id=\\?, product_id=\\?, category_id=\\?, tag=\\?, promo_type=\\?, promo=\\?, quantity_requirement=\\?, active=\\?"
+	mock.ExpectPrepare(query).ExpectExec().
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '0/0/0/speaker' for key 'promotions_active_target'"})
+
+	a := itemRepo.NewMysqlItemRepository(db)
+	err = a.StorePromotion(context.TODO(), &models.Promotions{Tag: "speaker", PromoType: "discount_items", Promo: "0.9", QuantityRequirement: 1, Active: true})
+	assert.Equal(t, models.ErrConflict, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArchiveNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectPrepare("UPDATE items set archived=1 WHERE id = \\?").ExpectExec().
+		WithArgs(int64(42)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM items WHERE id = \\?").
+		WithArgs(int64(42)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	a := itemRepo.NewMysqlItemRepository(db)
+	err = a.Archive(context.TODO(), 42)
+	assert.Equal(t, models.ErrNotFound, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
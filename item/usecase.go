@@ -0,0 +1,35 @@
+package item
+
+import (
+	"context"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// Usecase represent the item catalog's usecases
+type Usecase interface {
+	Fetch(ctx context.Context, filter models.ItemFilter, cursor string, num int64) (*models.ItemPage, error)
+	GetByID(ctx context.Context, id int64) (*models.CatalogItem, error)
+	FetchByID(ctx context.Context, id []int64) ([]*models.CatalogItem, error)
+	GetBySKU(ctx context.Context, sku string) (*models.CatalogItem, error)
+	Store(ctx context.Context, a *models.Items) error
+	Update(ctx context.Context, a *models.Items) error
+	Archive(ctx context.Context, id int64) error
+	FetchProducts(ctx context.Context, cursor string, num int64) (*models.ProductPage, error)
+	GetProduct(ctx context.Context, id int64) (*models.Product, error)
+	StoreProduct(ctx context.Context, a *models.Product) error
+	UpdateProduct(ctx context.Context, a *models.Product) error
+	FetchCategories(ctx context.Context) ([]*models.Category, error)
+	StoreCategory(ctx context.Context, a *models.Category) error
+	UpdateCategory(ctx context.Context, a *models.Category) error
+	SetCategories(ctx context.Context, itemsID int64, categoryID []int64) (*models.CatalogItem, error)
+	SetTags(ctx context.Context, itemsID int64, tags []string) (*models.CatalogItem, error)
+	SetKitComponents(ctx context.Context, kitID int64, components []*models.KitComponent) (*models.CatalogItem, error)
+	StorePromotion(ctx context.Context, a *models.Promotions) error
+	UpdatePromotion(ctx context.Context, a *models.Promotions) error
+	DisablePromotion(ctx context.Context, id int64) (*models.Promotions, error)
+	FetchTaxRules(ctx context.Context) ([]*models.TaxRule, error)
+	StoreTaxRule(ctx context.Context, a *models.TaxRule) error
+	UpdateTaxRule(ctx context.Context, a *models.TaxRule) error
+	DisableTaxRule(ctx context.Context, id int64) (*models.TaxRule, error)
+}
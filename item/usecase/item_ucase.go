@@ -0,0 +1,726 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"github.com/williamchand/kuncie-cart/item"
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/transaction"
+)
+
+const (
+	defaultPageSize = 10
+	maxPageSize     = 100
+)
+
+type itemUsecase struct {
+	itemRepo       item.Repository
+	txManager      transaction.Manager
+	contextTimeout time.Duration
+}
+
+// NewItemUsecase will create new an itemUsecase object representation of item.Usecase interface
+func NewItemUsecase(a item.Repository, tx transaction.Manager, timeout time.Duration) item.Usecase {
+	return &itemUsecase{
+		itemRepo:       a,
+		txManager:      tx,
+		contextTimeout: timeout,
+	}
+}
+
+func toCatalog(it *models.Items, categories []*models.Category, tags []string, components []*models.KitComponent,
+	promotion *models.Promotions, taxRule *models.TaxRule) *models.CatalogItem {
+	if categories == nil {
+		categories = make([]*models.Category, 0)
+	}
+	if tags == nil {
+		tags = make([]string, 0)
+	}
+	stock := it.InventoryQuantity
+	if len(components) > 0 {
+		stock = models.KitStock(components)
+	} else {
+		components = make([]*models.KitComponent, 0)
+	}
+	return &models.CatalogItem{
+		ID:                it.ID,
+		ProductID:         it.ProductID,
+		SKU:               it.SKU,
+		Name:              it.Name,
+		Price:             it.Price,
+		InventoryQuantity: stock,
+		Attributes:        it.Attributes,
+		Categories:        categories,
+		Tags:              tags,
+		Components:        components,
+		Available:         stock > 0,
+		Promotion:         promotion,
+		TaxRule:           taxRule,
+		UpdatedAt:         it.UpdatedAt,
+		CreatedAt:         it.CreatedAt,
+	}
+}
+
+// catalogOf attaches to the items their categories, tags, kit components, availability, the promotion
+// checkout applies to them and their tax rule. The promotions of the given products are looked up as well.
+func (a *itemUsecase) catalogOf(ctx context.Context, items []*models.Items, productID []int64) ([]*models.CatalogItem, *promotionIndex, error) {
+	ids := make([]int64, len(items))
+	for i, it := range items {
+		ids[i] = it.ID
+	}
+	categories, err := a.itemRepo.FetchItemCategories(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	tags, err := a.itemRepo.FetchItemTags(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	components, err := a.itemRepo.FetchKitComponents(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	idx, err := a.promotions(ctx, items, productID, categories, tags)
+	if err != nil {
+		return nil, nil, err
+	}
+	rules, err := a.itemRepo.FetchTaxRules(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := make([]*models.CatalogItem, len(items))
+	for i, it := range items {
+		promotion := idx.of(it, categories[it.ID], tags[it.ID])
+		taxRule := taxRuleOf(rules, it, categories[it.ID], tags[it.ID])
+		res[i] = toCatalog(it, categories[it.ID], tags[it.ID], components[it.ID], promotion, taxRule)
+	}
+	return res, idx, nil
+}
+
+func (a *itemUsecase) catalog(ctx context.Context, items []*models.Items) ([]*models.CatalogItem, error) {
+	res, _, err := a.catalogOf(ctx, items, nil)
+	return res, err
+}
+
+// products attaches to the products their promotion and their variants as shown in the catalog
+func (a *itemUsecase) products(ctx context.Context, products []*models.Product) error {
+	ids := make([]int64, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+	variants, err := a.itemRepo.FetchVariants(ctx, ids)
+	if err != nil {
+		return err
+	}
+	list, idx, err := a.catalogOf(ctx, variants, ids)
+	if err != nil {
+		return err
+	}
+
+	byProduct := make(map[int64][]*models.CatalogItem, len(products))
+	for _, it := range list {
+		byProduct[it.ProductID] = append(byProduct[it.ProductID], it)
+	}
+	for _, p := range products {
+		p.Promotion = idx.byProduct[p.ID]
+		p.Variants = byProduct[p.ID]
+		if p.Variants == nil {
+			p.Variants = make([]*models.CatalogItem, 0)
+		}
+	}
+	return nil
+}
+
+// Fetch pages through the catalog, optionally restricted to a category and its subcategories or to a tag
+func (a *itemUsecase) Fetch(c context.Context, filter models.ItemFilter, cursor string, num int64) (*models.ItemPage, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if num <= 0 {
+		num = defaultPageSize
+	}
+	if num > maxPageSize {
+		num = maxPageSize
+	}
+	filter.Tag = normalizeTag(filter.Tag)
+
+	items, nextCursor, err := a.itemRepo.Fetch(ctx, filter, cursor, num)
+	if err != nil {
+		return nil, err
+	}
+	res, err := a.catalog(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ItemPage{Items: res, NextCursor: nextCursor}, nil
+}
+
+func (a *itemUsecase) getOne(ctx context.Context, it *models.Items) (*models.CatalogItem, error) {
+	res, err := a.catalog(ctx, []*models.Items{it})
+	if err != nil {
+		return nil, err
+	}
+
+	return res[0], nil
+}
+
+func (a *itemUsecase) GetByID(c context.Context, id int64) (*models.CatalogItem, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	it, err := a.itemRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.getOne(ctx, it)
+}
+
+// FetchByID returns the catalog items with the given ids in the same order, unknown and archived items are left out
+func (a *itemUsecase) FetchByID(c context.Context, id []int64) ([]*models.CatalogItem, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	items, err := a.itemRepo.FetchByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*models.Items, len(items))
+	for _, it := range items {
+		byID[it.ID] = it
+	}
+	ordered := make([]*models.Items, 0, len(items))
+	for _, v := range id {
+		if it, ok := byID[v]; ok {
+			ordered = append(ordered, it)
+			delete(byID, v)
+		}
+	}
+
+	return a.catalog(ctx, ordered)
+}
+
+func (a *itemUsecase) GetBySKU(c context.Context, sku string) (*models.CatalogItem, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	it, err := a.itemRepo.GetBySKU(ctx, sku)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.getOne(ctx, it)
+}
+
+// validate checks an item and that the product it is a variant of exists
+func (a *itemUsecase) validate(ctx context.Context, m *models.Items) error {
+	if err := validator.New().Struct(m); err != nil {
+		return err
+	}
+	if m.ProductID == 0 {
+		return nil
+	}
+	_, err := a.itemRepo.GetProduct(ctx, m.ProductID)
+	return err
+}
+
+// Store adds an item to the catalog, a SKU already in use fails with models.ErrConflict
+func (a *itemUsecase) Store(c context.Context, m *models.Items) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if err := a.validate(ctx, m); err != nil {
+		return err
+	}
+
+	m.Archived = false
+	m.CreatedAt = time.Now()
+	m.UpdatedAt = m.CreatedAt
+	return a.itemRepo.Store(ctx, m)
+}
+
+func (a *itemUsecase) Update(c context.Context, m *models.Items) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if err := a.validate(ctx, m); err != nil {
+		return err
+	}
+
+	m.UpdatedAt = time.Now()
+	return a.itemRepo.Update(ctx, m)
+}
+
+// Archive hides an item from the catalog and the cart, past orders keep their copy of it
+func (a *itemUsecase) Archive(c context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.itemRepo.Archive(ctx, id)
+}
+
+func (a *itemUsecase) FetchProducts(c context.Context, cursor string, num int64) (*models.ProductPage, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if num <= 0 {
+		num = defaultPageSize
+	}
+	if num > maxPageSize {
+		num = maxPageSize
+	}
+
+	products, nextCursor, err := a.itemRepo.FetchProducts(ctx, cursor, num)
+	if err != nil {
+		return nil, err
+	}
+	if err = a.products(ctx, products); err != nil {
+		return nil, err
+	}
+
+	return &models.ProductPage{Products: products, NextCursor: nextCursor}, nil
+}
+
+// GetProduct returns a product with its variants still in the catalog
+func (a *itemUsecase) GetProduct(c context.Context, id int64) (*models.Product, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, err := a.itemRepo.GetProduct(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err = a.products(ctx, []*models.Product{res}); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// StoreProduct adds a product, its variants are then added as items referencing it
+func (a *itemUsecase) StoreProduct(c context.Context, m *models.Product) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if err := validator.New().Struct(m); err != nil {
+		return err
+	}
+
+	m.CreatedAt = time.Now()
+	m.UpdatedAt = m.CreatedAt
+	return a.itemRepo.StoreProduct(ctx, m)
+}
+
+func (a *itemUsecase) UpdateProduct(c context.Context, m *models.Product) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if err := validator.New().Struct(m); err != nil {
+		return err
+	}
+
+	m.UpdatedAt = time.Now()
+	return a.itemRepo.UpdateProduct(ctx, m)
+}
+
+// FetchCategories returns the whole category tree, every category following its parent
+func (a *itemUsecase) FetchCategories(c context.Context) ([]*models.Category, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.itemRepo.FetchCategories(ctx)
+}
+
+// parentPath returns the path the children of the category parentID start with, the root when it is zero
+func (a *itemUsecase) parentPath(ctx context.Context, parentID int64) (string, error) {
+	if parentID == 0 {
+		return "/", nil
+	}
+	parent, err := a.itemRepo.GetCategory(ctx, parentID)
+	if err == models.ErrNotFound {
+		return "", models.ErrBadParamInput
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return parent.Path, nil
+}
+
+// StoreCategory adds a category under ParentID, or at the root of the tree when it is zero
+func (a *itemUsecase) StoreCategory(c context.Context, m *models.Category) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if err := validator.New().Struct(m); err != nil {
+		return err
+	}
+	path, err := a.parentPath(ctx, m.ParentID)
+	if err != nil {
+		return err
+	}
+
+	m.CreatedAt = time.Now()
+	m.UpdatedAt = m.CreatedAt
+	return a.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		m.Path = ""
+		if err := a.itemRepo.StoreCategory(ctx, m); err != nil {
+			return err
+		}
+		m.Path = path + strconv.FormatInt(m.ID, 10) + "/"
+		return a.itemRepo.UpdateCategory(ctx, m)
+	})
+}
+
+// UpdateCategory renames a category or moves it with its subcategories under another parent.
+// A category cannot be moved under itself or one of its subcategories.
+func (a *itemUsecase) UpdateCategory(c context.Context, m *models.Category) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if err := validator.New().Struct(m); err != nil {
+		return err
+	}
+	existing, err := a.itemRepo.GetCategory(ctx, m.ID)
+	if err != nil {
+		return err
+	}
+	path, err := a.parentPath(ctx, m.ParentID)
+	if err != nil {
+		return err
+	}
+	if strings.HasPrefix(path, existing.Path) {
+		return models.ErrBadParamInput
+	}
+
+	m.Path = path + strconv.FormatInt(m.ID, 10) + "/"
+	m.CreatedAt = existing.CreatedAt
+	m.UpdatedAt = time.Now()
+	return a.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if m.Path != existing.Path {
+			if err := a.itemRepo.MoveCategories(ctx, existing.Path, m.Path); err != nil {
+				return err
+			}
+		}
+		return a.itemRepo.UpdateCategory(ctx, m)
+	})
+}
+
+// SetCategories replaces the categories of an item, unknown categories fail with models.ErrBadParamInput
+func (a *itemUsecase) SetCategories(c context.Context, itemsID int64, categoryID []int64) (*models.CatalogItem, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	it, err := a.itemRepo.GetByID(ctx, itemsID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(categoryID))
+	seen := make(map[int64]bool, len(categoryID))
+	for _, id := range categoryID {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if _, err := a.itemRepo.GetCategory(ctx, id); err != nil {
+			if err == models.ErrNotFound {
+				return nil, models.ErrBadParamInput
+			}
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	err = a.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		return a.itemRepo.SetItemCategories(ctx, itemsID, ids)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return a.getOne(ctx, it)
+}
+
+// SetTags replaces the tags of an item. Tags are free-form, compared without case and surrounding spaces.
+func (a *itemUsecase) SetTags(c context.Context, itemsID int64, tags []string) (*models.CatalogItem, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	it, err := a.itemRepo.GetByID(ctx, itemsID)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > maxTagLength {
+			return nil, models.ErrBadParamInput
+		}
+		seen[tag] = true
+		list = append(list, tag)
+	}
+
+	err = a.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		return a.itemRepo.SetItemTags(ctx, itemsID, list)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return a.getOne(ctx, it)
+}
+
+// SetKitComponents turns an item into a kit of the given components, or back into a plain item when there are
+// none. A kit is priced on its own and sells as long as its components are in stock. Kits do not nest: unknown
+// components, kits, the kit itself and items that are components of another kit fail with models.ErrBadParamInput.
+func (a *itemUsecase) SetKitComponents(c context.Context, kitID int64, components []*models.KitComponent) (*models.CatalogItem, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	it, err := a.itemRepo.GetByID(ctx, kitID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(components))
+	seen := make(map[int64]bool, len(components))
+	for _, m := range components {
+		if err := validator.New().Struct(m); err != nil {
+			return nil, err
+		}
+		if m.ItemsID == kitID || seen[m.ItemsID] {
+			return nil, models.ErrBadParamInput
+		}
+		seen[m.ItemsID] = true
+		ids = append(ids, m.ItemsID)
+	}
+	if len(ids) > 0 {
+		kits, err := a.itemRepo.CountKitsUsing(ctx, kitID)
+		if err != nil {
+			return nil, err
+		}
+		if kits > 0 {
+			return nil, models.ErrBadParamInput
+		}
+		found, err := a.itemRepo.FetchByID(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		if len(found) != len(ids) {
+			return nil, models.ErrBadParamInput
+		}
+		nested, err := a.itemRepo.FetchKitComponents(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		if len(nested) > 0 {
+			return nil, models.ErrBadParamInput
+		}
+	}
+
+	err = a.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		return a.itemRepo.SetKitComponents(ctx, kitID, components)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return a.getOne(ctx, it)
+}
+
+// checkTarget makes sure the target of a promotion or a tax rule is exactly one existing item, product,
+// category or tag, the tag is normalized
+func (a *itemUsecase) checkTarget(ctx context.Context, t *models.Target) error {
+	t.Tag = normalizeTag(t.Tag)
+	targets := 0
+	for _, set := range []bool{t.ItemsID != 0, t.ProductID != 0, t.CategoryID != 0, t.Tag != ""} {
+		if set {
+			targets++
+		}
+	}
+	if targets != 1 || len(t.Tag) > maxTagLength {
+		return models.ErrBadParamInput
+	}
+
+	var err error
+	switch {
+	case t.ItemsID != 0:
+		_, err = a.itemRepo.GetByID(ctx, t.ItemsID)
+	case t.ProductID != 0:
+		_, err = a.itemRepo.GetProduct(ctx, t.ProductID)
+	case t.CategoryID != 0:
+		_, err = a.itemRepo.GetCategory(ctx, t.CategoryID)
+	}
+	return err
+}
+
+// StorePromotion adds an active promotion to exactly one target: an item, every variant of a product, every
+// item of a category and its subcategories or every item with a tag. A target can only have one active
+// promotion at a time. When several promotions match an item the most specific one is applied,
+// see models.SelectPromotion.
+func (a *itemUsecase) StorePromotion(c context.Context, m *models.Promotions) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if err := validator.New().Struct(m); err != nil {
+		return err
+	}
+	target := m.Target()
+	if err := a.checkTarget(ctx, &target); err != nil {
+		return err
+	}
+	m.Tag = target.Tag
+
+	var (
+		active []*models.Promotions
+		err    error
+	)
+	switch {
+	case m.ItemsID != 0:
+		active, err = a.itemRepo.FetchPromotions(ctx, []int64{m.ItemsID})
+	case m.ProductID != 0:
+		active, err = a.itemRepo.FetchProductPromotions(ctx, []int64{m.ProductID})
+	case m.CategoryID != 0:
+		active, err = a.itemRepo.FetchCategoryPromotions(ctx, []int64{m.CategoryID})
+	default:
+		active, err = a.itemRepo.FetchTagPromotions(ctx, []string{m.Tag})
+	}
+	if err != nil {
+		return err
+	}
+	if len(active) > 0 {
+		return models.ErrConflict
+	}
+
+	m.Active = true
+	return a.itemRepo.StorePromotion(ctx, m)
+}
+
+// UpdatePromotion edits the terms of a promotion, it keeps its target and its status
+func (a *itemUsecase) UpdatePromotion(c context.Context, m *models.Promotions) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	existing, err := a.itemRepo.GetPromotion(ctx, m.ID)
+	if err != nil {
+		return err
+	}
+	m.ItemsID = existing.ItemsID
+	m.ProductID = existing.ProductID
+	m.CategoryID = existing.CategoryID
+	m.Tag = existing.Tag
+	m.Active = existing.Active
+	if err := validator.New().Struct(m); err != nil {
+		return err
+	}
+
+	return a.itemRepo.UpdatePromotion(ctx, m)
+}
+
+func (a *itemUsecase) DisablePromotion(c context.Context, id int64) (*models.Promotions, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, err := a.itemRepo.GetPromotion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !res.Active {
+		return res, nil
+	}
+
+	res.Active = false
+	if err := a.itemRepo.UpdatePromotion(ctx, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (a *itemUsecase) FetchTaxRules(c context.Context) ([]*models.TaxRule, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.itemRepo.FetchTaxRules(ctx)
+}
+
+// StoreTaxRule adds an active tax rule to exactly one target, as a promotion. A target can only have one active
+// tax rule at a time. When several tax rules match an item the most specific one is applied,
+// see models.SelectTaxRule.
+func (a *itemUsecase) StoreTaxRule(c context.Context, m *models.TaxRule) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if err := validator.New().Struct(m); err != nil {
+		return err
+	}
+	target := m.Target()
+	if err := a.checkTarget(ctx, &target); err != nil {
+		return err
+	}
+	m.Tag = target.Tag
+
+	active, err := a.itemRepo.FetchTaxRules(ctx)
+	if err != nil {
+		return err
+	}
+	for _, r := range active {
+		if r.Target() == m.Target() {
+			return models.ErrConflict
+		}
+	}
+
+	m.Active = true
+	return a.itemRepo.StoreTaxRule(ctx, m)
+}
+
+// UpdateTaxRule edits the name and the rate of a tax rule, it keeps its target and its status
+func (a *itemUsecase) UpdateTaxRule(c context.Context, m *models.TaxRule) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	existing, err := a.itemRepo.GetTaxRule(ctx, m.ID)
+	if err != nil {
+		return err
+	}
+	m.ItemsID = existing.ItemsID
+	m.ProductID = existing.ProductID
+	m.CategoryID = existing.CategoryID
+	m.Tag = existing.Tag
+	m.Active = existing.Active
+	if err := validator.New().Struct(m); err != nil {
+		return err
+	}
+
+	return a.itemRepo.UpdateTaxRule(ctx, m)
+}
+
+func (a *itemUsecase) DisableTaxRule(c context.Context, id int64) (*models.TaxRule, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, err := a.itemRepo.GetTaxRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !res.Active {
+		return res, nil
+	}
+
+	res.Active = false
+	if err := a.itemRepo.UpdateTaxRule(ctx, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,486 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/williamchand/kuncie-cart/item/mocks"
+	ucase "github.com/williamchand/kuncie-cart/item/usecase"
+	"github.com/williamchand/kuncie-cart/models"
+	_txMocks "github.com/williamchand/kuncie-cart/transaction/mocks"
+)
+
+// withoutTaxonomy expects items without categories nor tags that are not kits, and no tax rules
+func withoutTaxonomy(mockItemRepo *mocks.Repository) {
+	mockItemRepo.On("FetchItemCategories", mock.Anything, mock.Anything).Return(map[int64][]*models.Category{}, nil)
+	mockItemRepo.On("FetchItemTags", mock.Anything, mock.Anything).Return(map[int64][]string{}, nil)
+	mockItemRepo.On("FetchKitComponents", mock.Anything, mock.Anything).Return(map[int64][]*models.KitComponent{}, nil)
+	mockItemRepo.On("FetchCategoryPromotions", mock.Anything, []int64{}).Return([]*models.Promotions{}, nil)
+	mockItemRepo.On("FetchTagPromotions", mock.Anything, []string{}).Return([]*models.Promotions{}, nil)
+	mockItemRepo.On("FetchTaxRules", mock.Anything).Return([]*models.TaxRule{}, nil)
+}
+
+func TestFetch(t *testing.T) {
+	mockItemRepo := new(mocks.Repository)
+	items := []*models.Items{
+		{ID: 1, SKU: "120P90", Name: "Google Home", Price: 49.99, InventoryQuantity: 10},
+		{ID: 4, SKU: "234234", Name: "Raspberry Pi B", Price: 30.00, InventoryQuantity: 0},
+		{ID: 5, ProductID: 7, SKU: "TSHIRT-M", Name: "T-Shirt M", Price: 12.00, InventoryQuantity: 3},
+	}
+	mockItemRepo.On("Fetch", mock.Anything, models.ItemFilter{}, "", int64(10)).Return(items, "NQ==", nil).Once()
+	mockItemRepo.On("FetchPromotions", mock.Anything, []int64{1, 4, 5}).Return([]*models.Promotions{
+		{ID: 2, ItemsID: 1, PromoType: "bonus_price", Promo: "2", QuantityRequirement: 3},
+	}, nil).Once()
+	mockItemRepo.On("FetchProductPromotions", mock.Anything, []int64{7}).Return([]*models.Promotions{
+		{ID: 5, ProductID: 7, PromoType: "discount_items", Promo: "0.9", QuantityRequirement: 2},
+	}, nil).Once()
+	withoutTaxonomy(mockItemRepo)
+
+	u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+	page, err := u.Fetch(context.TODO(), models.ItemFilter{}, "", 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "NQ==", page.NextCursor)
+	assert.Len(t, page.Items, 3)
+	assert.True(t, page.Items[0].Available)
+	assert.Equal(t, "bonus_price", page.Items[0].Promotion.PromoType)
+	assert.False(t, page.Items[1].Available)
+	assert.Nil(t, page.Items[1].Promotion)
+	assert.Equal(t, int64(5), page.Items[2].Promotion.ID)
+	mockItemRepo.AssertExpectations(t)
+}
+
+func TestGetProduct(t *testing.T) {
+	mockItemRepo := new(mocks.Repository)
+	mockItemRepo.On("GetProduct", mock.Anything, int64(7)).Return(&models.Product{ID: 7, Name: "T-Shirt"}, nil).Once()
+	mockItemRepo.On("FetchVariants", mock.Anything, []int64{7}).Return([]*models.Items{
+		{ID: 5, ProductID: 7, SKU: "TSHIRT-M", Name: "T-Shirt M", Price: 12.00, InventoryQuantity: 3,
+			Attributes: []*models.Attribute{{Name: "size", Value: "M"}}},
+		{ID: 6, ProductID: 7, SKU: "TSHIRT-L", Name: "T-Shirt L", Price: 14.00, InventoryQuantity: 0,
+			Attributes: []*models.Attribute{{Name: "size", Value: "L"}}},
+	}, nil).Once()
+	mockItemRepo.On("FetchPromotions", mock.Anything, []int64{5, 6}).Return([]*models.Promotions{
+		{ID: 8, ItemsID: 6, PromoType: "bonus_price", Promo: "25", QuantityRequirement: 2},
+	}, nil).Once()
+	mockItemRepo.On("FetchProductPromotions", mock.Anything, []int64{7}).Return([]*models.Promotions{
+		{ID: 5, ProductID: 7, PromoType: "discount_items", Promo: "0.9", QuantityRequirement: 2},
+	}, nil).Once()
+	withoutTaxonomy(mockItemRepo)
+
+	u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+	res, err := u.GetProduct(context.TODO(), 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), res.Promotion.ID)
+	assert.Len(t, res.Variants, 2)
+	assert.Equal(t, "M", res.Variants[0].Attributes[0].Value)
+	// a variant without its own promotion gets the one of its product
+	assert.Equal(t, int64(5), res.Variants[0].Promotion.ID)
+	assert.Equal(t, int64(8), res.Variants[1].Promotion.ID)
+	mockItemRepo.AssertExpectations(t)
+}
+
+func TestFetchByCategory(t *testing.T) {
+	mockItemRepo := new(mocks.Repository)
+	filter := models.ItemFilter{CategoryID: 1, Tag: " Sale"}
+	items := []*models.Items{
+		{ID: 3, SKU: "A304SD", Name: "Alexa Speaker", Price: 109.50, InventoryQuantity: 10},
+		{ID: 8, SKU: "SONOS1", Name: "Sonos One", Price: 199.00, InventoryQuantity: 4},
+		{ID: 9, SKU: "JBL5", Name: "JBL Flip 5", Price: 99.00, InventoryQuantity: 7},
+	}
+	speakers := &models.Category{ID: 1, Name: "Speakers", Path: "/1/"}
+	smart := &models.Category{ID: 4, ParentID: 1, Name: "Smart speakers", Path: "/1/4/"}
+	mockItemRepo.On("Fetch", mock.Anything, models.ItemFilter{CategoryID: 1, Tag: "sale"}, "", int64(10)).Return(items, "", nil).Once()
+	mockItemRepo.On("FetchItemCategories", mock.Anything, []int64{3, 8, 9}).Return(map[int64][]*models.Category{
+		3: {smart},
+		8: {speakers},
+	}, nil).Once()
+	mockItemRepo.On("FetchItemTags", mock.Anything, []int64{3, 8, 9}).Return(map[int64][]string{
+		3: {"sale"},
+		8: {"sale"},
+		9: {"sale"},
+	}, nil).Once()
+	mockItemRepo.On("FetchKitComponents", mock.Anything, []int64{3, 8, 9}).Return(map[int64][]*models.KitComponent{}, nil).Once()
+	mockItemRepo.On("FetchPromotions", mock.Anything, []int64{3, 8, 9}).Return([]*models.Promotions{}, nil).Once()
+	mockItemRepo.On("FetchProductPromotions", mock.Anything, mock.Anything).Return([]*models.Promotions{}, nil).Once()
+	mockItemRepo.On("FetchCategoryPromotions", mock.Anything, []int64{4, 1}).Return([]*models.Promotions{
+		{ID: 10, CategoryID: 1, PromoType: "discount_items", Promo: "0.9", QuantityRequirement: 1},
+		{ID: 11, CategoryID: 4, PromoType: "discount_items", Promo: "0.8", QuantityRequirement: 1},
+	}, nil).Once()
+	mockItemRepo.On("FetchTagPromotions", mock.Anything, []string{"sale"}).Return([]*models.Promotions{
+		{ID: 12, Tag: "sale", PromoType: "discount_items", Promo: "0.95", QuantityRequirement: 1},
+	}, nil).Once()
+	mockItemRepo.On("FetchTaxRules", mock.Anything).Return([]*models.TaxRule{
+		{ID: 20, Name: "Electronics", Rate: 0.11, CategoryID: 1, Active: true},
+		{ID: 21, Name: "Clearance", Rate: 0.05, Tag: "sale", Active: true},
+		{ID: 22, Name: "Imported", Rate: 0.15, ItemsID: 8, Active: true},
+	}, nil).Once()
+
+	u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+	page, err := u.Fetch(context.TODO(), filter, "", 0)
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 3)
+	// the closest category wins over its parent, a category over a tag
+	assert.Equal(t, int64(11), page.Items[0].Promotion.ID)
+	assert.Equal(t, int64(10), page.Items[1].Promotion.ID)
+	assert.Equal(t, int64(12), page.Items[2].Promotion.ID)
+	// tax rules are selected the same way: the item's own, then the category, then the tag
+	assert.Equal(t, int64(20), page.Items[0].TaxRule.ID)
+	assert.Equal(t, int64(22), page.Items[1].TaxRule.ID)
+	assert.Equal(t, int64(21), page.Items[2].TaxRule.ID)
+	assert.Equal(t, []string{"sale"}, page.Items[2].Tags)
+	assert.Empty(t, page.Items[2].Categories)
+	mockItemRepo.AssertExpectations(t)
+}
+
+func TestFetchByID(t *testing.T) {
+	mockItemRepo := new(mocks.Repository)
+	mockItemRepo.On("FetchByID", mock.Anything, []int64{4, 42, 1}).Return([]*models.Items{
+		{ID: 1, SKU: "120P90", Name: "Google Home", Price: 49.99, InventoryQuantity: 10},
+		{ID: 4, SKU: "234234", Name: "Raspberry Pi B", Price: 30.00, InventoryQuantity: 2},
+	}, nil).Once()
+	mockItemRepo.On("FetchPromotions", mock.Anything, []int64{4, 1}).Return([]*models.Promotions{}, nil).Once()
+	mockItemRepo.On("FetchProductPromotions", mock.Anything, mock.Anything).Return([]*models.Promotions{}, nil).Once()
+	withoutTaxonomy(mockItemRepo)
+
+	u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+	res, err := u.FetchByID(context.TODO(), []int64{4, 42, 1})
+
+	// the order of the ids is kept and the unknown one is left out
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.Equal(t, int64(4), res[0].ID)
+	assert.Equal(t, int64(1), res[1].ID)
+	mockItemRepo.AssertExpectations(t)
+}
+
+func TestGetBySKU(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockItemRepo := new(mocks.Repository)
+		mockItemRepo.On("GetBySKU", mock.Anything, "120P90").
+			Return(&models.Items{ID: 1, SKU: "120P90", Name: "Google Home", Price: 49.99, InventoryQuantity: 10}, nil).Once()
+		mockItemRepo.On("FetchPromotions", mock.Anything, []int64{1}).Return([]*models.Promotions{}, nil).Once()
+		mockItemRepo.On("FetchProductPromotions", mock.Anything, mock.Anything).Return([]*models.Promotions{}, nil).Once()
+		withoutTaxonomy(mockItemRepo)
+
+		u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		res, err := u.GetBySKU(context.TODO(), "120P90")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), res.ID)
+		assert.True(t, res.Available)
+		mockItemRepo.AssertExpectations(t)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mockItemRepo := new(mocks.Repository)
+		mockItemRepo.On("GetBySKU", mock.Anything, "XXXXXX").Return(nil, models.ErrNotFound).Once()
+
+		u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		res, err := u.GetBySKU(context.TODO(), "XXXXXX")
+
+		assert.Equal(t, models.ErrNotFound, err)
+		assert.Nil(t, res)
+		mockItemRepo.AssertNotCalled(t, "FetchPromotions", mock.Anything, mock.Anything)
+	})
+}
+
+func TestStore(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockItemRepo := new(mocks.Repository)
+		mockItemRepo.On("Store", mock.Anything, mock.AnythingOfType("*models.Items")).Return(nil).Once()
+
+		u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		it := &models.Items{SKU: "P4B8GB", Name: "Raspberry Pi 4", Price: 75, InventoryQuantity: 20}
+		err := u.Store(context.TODO(), it)
+
+		assert.NoError(t, err)
+		assert.False(t, it.CreatedAt.IsZero())
+		mockItemRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		mockItemRepo := new(mocks.Repository)
+
+		u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.Store(context.TODO(), &models.Items{SKU: "P4B8GB", Price: 75, InventoryQuantity: 20})
+
+		assert.Error(t, err)
+		mockItemRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown-product", func(t *testing.T) {
+		mockItemRepo := new(mocks.Repository)
+		mockItemRepo.On("GetProduct", mock.Anything, int64(9)).Return(nil, models.ErrNotFound).Once()
+
+		u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.Store(context.TODO(), &models.Items{ProductID: 9, SKU: "TSHIRT-S", Name: "T-Shirt S", Price: 12, InventoryQuantity: 5})
+
+		assert.Equal(t, models.ErrNotFound, err)
+		mockItemRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("duplicate-sku", func(t *testing.T) {
+		mockItemRepo := new(mocks.Repository)
+		mockItemRepo.On("Store", mock.Anything, mock.AnythingOfType("*models.Items")).Return(models.ErrConflict).Once()
+
+		u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.Store(context.TODO(), &models.Items{SKU: "120P90", Name: "Google Home", Price: 49.99, InventoryQuantity: 10})
+
+		assert.Equal(t, models.ErrConflict, err)
+	})
+}
+
+func TestUpdateCategory(t *testing.T) {
+	t.Run("move", func(t *testing.T) {
+		mockItemRepo := new(mocks.Repository)
+		mockItemRepo.On("GetCategory", mock.Anything, int64(4)).Return(&models.Category{ID: 4, ParentID: 1, Name: "Smart speakers", Path: "/1/4/"}, nil).Once()
+		mockItemRepo.On("GetCategory", mock.Anything, int64(2)).Return(&models.Category{ID: 2, Name: "Smart home", Path: "/2/"}, nil).Once()
+		mockItemRepo.On("MoveCategories", mock.Anything, "/1/4/", "/2/4/").Return(nil).Once()
+		mockItemRepo.On("UpdateCategory", mock.Anything, mock.MatchedBy(func(c *models.Category) bool {
+			return c.Path == "/2/4/"
+		})).Return(nil).Once()
+
+		u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.UpdateCategory(context.TODO(), &models.Category{ID: 4, ParentID: 2, Name: "Smart speakers"})
+
+		assert.NoError(t, err)
+		mockItemRepo.AssertExpectations(t)
+	})
+
+	t.Run("under-itself", func(t *testing.T) {
+		mockItemRepo := new(mocks.Repository)
+		mockItemRepo.On("GetCategory", mock.Anything, int64(1)).Return(&models.Category{ID: 1, Name: "Speakers", Path: "/1/"}, nil).Once()
+		mockItemRepo.On("GetCategory", mock.Anything, int64(4)).Return(&models.Category{ID: 4, ParentID: 1, Name: "Smart speakers", Path: "/1/4/"}, nil).Once()
+
+		u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.UpdateCategory(context.TODO(), &models.Category{ID: 1, ParentID: 4, Name: "Speakers"})
+
+		assert.Equal(t, models.ErrBadParamInput, err)
+		mockItemRepo.AssertNotCalled(t, "MoveCategories", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestSetTags(t *testing.T) {
+	mockItemRepo := new(mocks.Repository)
+	mockItemRepo.On("GetByID", mock.Anything, int64(3)).Return(&models.Items{ID: 3, SKU: "A304SD", Name: "Alexa Speaker"}, nil).Once()
+	mockItemRepo.On("SetItemTags", mock.Anything, int64(3), []string{"speaker", "alexa"}).Return(nil).Once()
+	mockItemRepo.On("FetchPromotions", mock.Anything, []int64{3}).Return([]*models.Promotions{}, nil).Once()
+	mockItemRepo.On("FetchProductPromotions", mock.Anything, mock.Anything).Return([]*models.Promotions{}, nil).Once()
+	withoutTaxonomy(mockItemRepo)
+
+	u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+	_, err := u.SetTags(context.TODO(), 3, []string{" Speaker", "alexa", "speaker ", ""})
+
+	assert.NoError(t, err)
+	mockItemRepo.AssertExpectations(t)
+}
+
+func TestSetKitComponents(t *testing.T) {
+	kit := &models.Items{ID: 12, SKU: "KIT001", Name: "Smart Home Starter Kit", Price: 69.99}
+	components := []*models.KitComponent{
+		{ItemsID: 1, Quantity: 1},
+		{ItemsID: 4, Quantity: 2},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockItemRepo := new(mocks.Repository)
+		mockItemRepo.On("GetByID", mock.Anything, int64(12)).Return(kit, nil).Once()
+		mockItemRepo.On("CountKitsUsing", mock.Anything, int64(12)).Return(int64(0), nil).Once()
+		mockItemRepo.On("FetchByID", mock.Anything, []int64{1, 4}).Return([]*models.Items{{ID: 1}, {ID: 4}}, nil).Once()
+		mockItemRepo.On("FetchKitComponents", mock.Anything, []int64{1, 4}).Return(map[int64][]*models.KitComponent{}, nil).Once()
+		mockItemRepo.On("SetKitComponents", mock.Anything, int64(12), components).Return(nil).Once()
+		mockItemRepo.On("FetchItemCategories", mock.Anything, mock.Anything).Return(map[int64][]*models.Category{}, nil).Once()
+		mockItemRepo.On("FetchItemTags", mock.Anything, mock.Anything).Return(map[int64][]string{}, nil).Once()
+		mockItemRepo.On("FetchKitComponents", mock.Anything, []int64{12}).Return(map[int64][]*models.KitComponent{
+			12: {
+				{KitID: 12, ItemsID: 1, SKU: "120P90", Name: "Google Home", Quantity: 1, InventoryQuantity: 10},
+				{KitID: 12, ItemsID: 4, SKU: "234234", Name: "Raspberry Pi B", Quantity: 2, InventoryQuantity: 5},
+			},
+		}, nil).Once()
+		mockItemRepo.On("FetchPromotions", mock.Anything, []int64{12}).Return([]*models.Promotions{}, nil).Once()
+		mockItemRepo.On("FetchProductPromotions", mock.Anything, mock.Anything).Return([]*models.Promotions{}, nil).Once()
+		mockItemRepo.On("FetchCategoryPromotions", mock.Anything, []int64{}).Return([]*models.Promotions{}, nil).Once()
+		mockItemRepo.On("FetchTagPromotions", mock.Anything, []string{}).Return([]*models.Promotions{}, nil).Once()
+		mockItemRepo.On("FetchTaxRules", mock.Anything).Return([]*models.TaxRule{}, nil).Once()
+
+		u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		res, err := u.SetKitComponents(context.TODO(), 12, components)
+
+		assert.NoError(t, err)
+		assert.Len(t, res.Components, 2)
+		// five Raspberry Pi make two kits, the kit's own stock is not used
+		assert.Equal(t, int64(2), res.InventoryQuantity)
+		assert.True(t, res.Available)
+		mockItemRepo.AssertExpectations(t)
+	})
+
+	t.Run("nested-kit", func(t *testing.T) {
+		mockItemRepo := new(mocks.Repository)
+		mockItemRepo.On("GetByID", mock.Anything, int64(12)).Return(kit, nil).Once()
+		mockItemRepo.On("CountKitsUsing", mock.Anything, int64(12)).Return(int64(0), nil).Once()
+		mockItemRepo.On("FetchByID", mock.Anything, []int64{1, 4}).Return([]*models.Items{{ID: 1}, {ID: 4}}, nil).Once()
+		mockItemRepo.On("FetchKitComponents", mock.Anything, []int64{1, 4}).Return(map[int64][]*models.KitComponent{
+			4: {{KitID: 4, ItemsID: 2, Quantity: 1}},
+		}, nil).Once()
+
+		u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		_, err := u.SetKitComponents(context.TODO(), 12, components)
+
+		assert.Equal(t, models.ErrBadParamInput, err)
+		mockItemRepo.AssertNotCalled(t, "SetKitComponents", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("itself", func(t *testing.T) {
+		mockItemRepo := new(mocks.Repository)
+		mockItemRepo.On("GetByID", mock.Anything, int64(12)).Return(kit, nil).Once()
+
+		u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		_, err := u.SetKitComponents(context.TODO(), 12, []*models.KitComponent{{ItemsID: 12, Quantity: 1}})
+
+		assert.Equal(t, models.ErrBadParamInput, err)
+		mockItemRepo.AssertExpectations(t)
+	})
+}
+
+func TestStorePromotion(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockItemRepo := new(mocks.Repository)
+		mockItemRepo.On("GetByID", mock.Anything, int64(4)).Return(&models.Items{ID: 4}, nil).Once()
+		mockItemRepo.On("FetchPromotions", mock.Anything, []int64{4}).Return([]*models.Promotions{}, nil).Once()
+		mockItemRepo.On("StorePromotion", mock.Anything, mock.MatchedBy(func(p *models.Promotions) bool {
+			return p.Active
+		})).Return(nil).Once()
+
+		u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.StorePromotion(context.TODO(), &models.Promotions{ItemsID: 4, PromoType: "discount_items", Promo: "0.2", QuantityRequirement: 2})
+
+		assert.NoError(t, err)
+		mockItemRepo.AssertExpectations(t)
+	})
+
+	t.Run("product", func(t *testing.T) {
+		mockItemRepo := new(mocks.Repository)
+		mockItemRepo.On("GetProduct", mock.Anything, int64(7)).Return(&models.Product{ID: 7}, nil).Once()
+		mockItemRepo.On("FetchProductPromotions", mock.Anything, []int64{7}).Return([]*models.Promotions{}, nil).Once()
+		mockItemRepo.On("StorePromotion", mock.Anything, mock.MatchedBy(func(p *models.Promotions) bool {
+			return p.Active && p.ProductID == 7 && p.ItemsID == 0
+		})).Return(nil).Once()
+
+		u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.StorePromotion(context.TODO(), &models.Promotions{ProductID: 7, PromoType: "discount_items", Promo: "0.9", QuantityRequirement: 2})
+
+		assert.NoError(t, err)
+		mockItemRepo.AssertExpectations(t)
+	})
+
+	t.Run("tag", func(t *testing.T) {
+		mockItemRepo := new(mocks.Repository)
+		mockItemRepo.On("FetchTagPromotions", mock.Anything, []string{"speaker"}).Return([]*models.Promotions{}, nil).Once()
+		mockItemRepo.On("StorePromotion", mock.Anything, mock.MatchedBy(func(p *models.Promotions) bool {
+			return p.Active && p.Tag == "speaker"
+		})).Return(nil).Once()
+
+		u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.StorePromotion(context.TODO(), &models.Promotions{Tag: "Speaker", PromoType: "discount_items", Promo: "0.9", QuantityRequirement: 1})
+
+		assert.NoError(t, err)
+		mockItemRepo.AssertExpectations(t)
+	})
+
+	t.Run("item-and-product", func(t *testing.T) {
+		mockItemRepo := new(mocks.Repository)
+
+		u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.StorePromotion(context.TODO(), &models.Promotions{ItemsID: 5, ProductID: 7, PromoType: "discount_items", Promo: "0.9", QuantityRequirement: 2})
+
+		assert.Equal(t, models.ErrBadParamInput, err)
+		mockItemRepo.AssertNotCalled(t, "StorePromotion", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown-type", func(t *testing.T) {
+		mockItemRepo := new(mocks.Repository)
+
+		u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.StorePromotion(context.TODO(), &models.Promotions{ItemsID: 4, PromoType: "half_price", Promo: "0.5", QuantityRequirement: 1})
+
+		assert.Error(t, err)
+		mockItemRepo.AssertNotCalled(t, "StorePromotion", mock.Anything, mock.Anything)
+	})
+
+	t.Run("already-promoted", func(t *testing.T) {
+		mockItemRepo := new(mocks.Repository)
+		mockItemRepo.On("GetByID", mock.Anything, int64(1)).Return(&models.Items{ID: 1}, nil).Once()
+		mockItemRepo.On("FetchPromotions", mock.Anything, []int64{1}).
+			Return([]*models.Promotions{{ID: 2, ItemsID: 1, PromoType: "bonus_price", Active: true}}, nil).Once()
+
+		u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.StorePromotion(context.TODO(), &models.Promotions{ItemsID: 1, PromoType: "discount_items", Promo: "0.2", QuantityRequirement: 2})
+
+		assert.Equal(t, models.ErrConflict, err)
+		mockItemRepo.AssertNotCalled(t, "StorePromotion", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDisablePromotion(t *testing.T) {
+	mockItemRepo := new(mocks.Repository)
+	mockItemRepo.On("GetPromotion", mock.Anything, int64(2)).
+		Return(&models.Promotions{ID: 2, ItemsID: 1, PromoType: "bonus_price", Promo: "99.98", QuantityRequirement: 3, Active: true}, nil).Once()
+	mockItemRepo.On("UpdatePromotion", mock.Anything, mock.MatchedBy(func(p *models.Promotions) bool {
+		return p.ID == 2 && !p.Active
+	})).Return(nil).Once()
+
+	u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+	res, err := u.DisablePromotion(context.TODO(), 2)
+
+	assert.NoError(t, err)
+	assert.False(t, res.Active)
+	mockItemRepo.AssertExpectations(t)
+}
+
+func TestStoreTaxRule(t *testing.T) {
+	t.Run("category", func(t *testing.T) {
+		mockItemRepo := new(mocks.Repository)
+		mockItemRepo.On("GetCategory", mock.Anything, int64(1)).Return(&models.Category{ID: 1, Path: "/1/"}, nil).Once()
+		mockItemRepo.On("FetchTaxRules", mock.Anything).Return([]*models.TaxRule{
+			{ID: 21, Name: "Clearance", Rate: 0.05, Tag: "sale", Active: true},
+		}, nil).Once()
+		mockItemRepo.On("StoreTaxRule", mock.Anything, mock.MatchedBy(func(r *models.TaxRule) bool {
+			return r.Active && r.CategoryID == 1
+		})).Return(nil).Once()
+
+		u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.StoreTaxRule(context.TODO(), &models.TaxRule{Name: "Electronics", Rate: 0.11, CategoryID: 1})
+
+		assert.NoError(t, err)
+		mockItemRepo.AssertExpectations(t)
+	})
+
+	t.Run("tag-already-taxed", func(t *testing.T) {
+		mockItemRepo := new(mocks.Repository)
+		mockItemRepo.On("FetchTaxRules", mock.Anything).Return([]*models.TaxRule{
+			{ID: 21, Name: "Clearance", Rate: 0.05, Tag: "sale", Active: true},
+		}, nil).Once()
+
+		u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.StoreTaxRule(context.TODO(), &models.TaxRule{Name: "Sale", Rate: 0.1, Tag: " Sale"})
+
+		assert.Equal(t, models.ErrConflict, err)
+		mockItemRepo.AssertNotCalled(t, "StoreTaxRule", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rate-out-of-range", func(t *testing.T) {
+		mockItemRepo := new(mocks.Repository)
+
+		u := ucase.NewItemUsecase(mockItemRepo, _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.StoreTaxRule(context.TODO(), &models.TaxRule{Name: "Electronics", Rate: 11, CategoryID: 1})
+
+		assert.Error(t, err)
+		mockItemRepo.AssertNotCalled(t, "StoreTaxRule", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+const maxTagLength = 50
+
+// promotionIndex holds the active promotions that may apply to a set of items, by target
+type promotionIndex struct {
+	byItem     map[int64]*models.Promotions
+	byProduct  map[int64]*models.Promotions
+	byCategory map[int64]*models.Promotions
+	byTag      map[string]*models.Promotions
+}
+
+// of returns the promotion checkout applies to the item among those matching it, see models.SelectPromotion
+func (idx *promotionIndex) of(it *models.Items, categories []*models.Category, tags []string) *models.Promotions {
+	matching := make([]*models.Promotions, 0)
+	if p, ok := idx.byItem[it.ID]; ok {
+		matching = append(matching, p)
+	}
+	if p, ok := idx.byProduct[it.ProductID]; ok && it.ProductID != 0 {
+		matching = append(matching, p)
+	}
+	depth := models.CategoryDepths(categories)
+	for id := range depth {
+		if p, ok := idx.byCategory[id]; ok {
+			matching = append(matching, p)
+		}
+	}
+	for _, tag := range tags {
+		if p, ok := idx.byTag[tag]; ok {
+			matching = append(matching, p)
+		}
+	}
+	return models.SelectPromotion(matching, depth)
+}
+
+// taxRuleOf returns the tax rule applied to the item among the active rules, see models.SelectTaxRule
+func taxRuleOf(rules []*models.TaxRule, it *models.Items, categories []*models.Category, tags []string) *models.TaxRule {
+	depth := models.CategoryDepths(categories)
+	tagged := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tagged[tag] = true
+	}
+	matching := make([]*models.TaxRule, 0)
+	for _, r := range rules {
+		_, inCategory := depth[r.CategoryID]
+		if (r.ItemsID != 0 && r.ItemsID == it.ID) || (r.ProductID != 0 && r.ProductID == it.ProductID) ||
+			(r.CategoryID != 0 && inCategory) || (r.Tag != "" && tagged[r.Tag]) {
+			matching = append(matching, r)
+		}
+	}
+	return models.SelectTaxRule(matching, depth)
+}
+
+func (a *itemUsecase) promotions(ctx context.Context, items []*models.Items, productID []int64,
+	categories map[int64][]*models.Category, tags map[int64][]string) (*promotionIndex, error) {
+	ids := make([]int64, len(items))
+	seenProduct := make(map[int64]bool, len(productID))
+	for _, id := range productID {
+		seenProduct[id] = true
+	}
+	categoryID := make([]int64, 0)
+	seenCategory := make(map[int64]bool)
+	tagList := make([]string, 0)
+	seenTag := make(map[string]bool)
+	for i, it := range items {
+		ids[i] = it.ID
+		if it.ProductID != 0 && !seenProduct[it.ProductID] {
+			seenProduct[it.ProductID] = true
+			productID = append(productID, it.ProductID)
+		}
+		for _, c := range categories[it.ID] {
+			for _, id := range c.Lineage() {
+				if !seenCategory[id] {
+					seenCategory[id] = true
+					categoryID = append(categoryID, id)
+				}
+			}
+		}
+		for _, tag := range tags[it.ID] {
+			if !seenTag[tag] {
+				seenTag[tag] = true
+				tagList = append(tagList, tag)
+			}
+		}
+	}
+
+	byItem, err := a.itemRepo.FetchPromotions(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byProduct, err := a.itemRepo.FetchProductPromotions(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	byCategory, err := a.itemRepo.FetchCategoryPromotions(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	byTag, err := a.itemRepo.FetchTagPromotions(ctx, tagList)
+	if err != nil {
+		return nil, err
+	}
+
+	// promotions come ordered by id, the first one of a target is kept
+	idx := &promotionIndex{
+		byItem:     make(map[int64]*models.Promotions, len(byItem)),
+		byProduct:  make(map[int64]*models.Promotions, len(byProduct)),
+		byCategory: make(map[int64]*models.Promotions, len(byCategory)),
+		byTag:      make(map[string]*models.Promotions, len(byTag)),
+	}
+	for _, p := range byItem {
+		if _, ok := idx.byItem[p.ItemsID]; !ok {
+			idx.byItem[p.ItemsID] = p
+		}
+	}
+	for _, p := range byProduct {
+		if _, ok := idx.byProduct[p.ProductID]; !ok {
+			idx.byProduct[p.ProductID] = p
+		}
+	}
+	for _, p := range byCategory {
+		if _, ok := idx.byCategory[p.CategoryID]; !ok {
+			idx.byCategory[p.CategoryID] = p
+		}
+	}
+	for _, p := range byTag {
+		if _, ok := idx.byTag[p.Tag]; !ok {
+			idx.byTag[p.Tag] = p
+		}
+	}
+	return idx, nil
+}
+
+// normalizeTag makes tags case insensitive and ignores surrounding spaces
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
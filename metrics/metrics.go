@@ -0,0 +1,27 @@
+// Package metrics exposes the metrics of the service to Prometheus
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DefBuckets are the default histogram buckets, in seconds, suited to request and query durations
+var DefBuckets = prometheus.DefBuckets
+
+// Default is the registry of the service metrics, exposed by Handler. It starts with the Go runtime and process
+// collectors.
+var Default = prometheus.NewRegistry()
+
+func init() {
+	Default.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+}
+
+// Handler serves the metrics of the Default registry to the Prometheus scrapes, it must not be reachable by the
+// clients of the service
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Default, promhttp.HandlerOpts{})
+}
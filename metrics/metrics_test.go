@@ -0,0 +1,30 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/williamchand/kuncie-cart/metrics"
+)
+
+func TestHandler(t *testing.T) {
+	metrics.ObserveQuery("order", "GetCart", time.Now().Add(-50*time.Millisecond))
+	metrics.OutOfStockRejections.WithLabelValues(`Add"Cart`).Inc()
+
+	res := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Header().Get("Content-Type"), "text/plain; version=0.0.4")
+	out := res.Body.String()
+	assert.Contains(t, out, "# TYPE kuncie_cart_repository_query_duration_seconds histogram\n")
+	assert.Contains(t, out, `kuncie_cart_repository_query_duration_seconds_bucket{method="GetCart",repository="order",le="0.025"} 0`)
+	assert.Contains(t, out, `kuncie_cart_repository_query_duration_seconds_bucket{method="GetCart",repository="order",le="0.1"} 1`)
+	assert.Contains(t, out, `kuncie_cart_out_of_stock_rejections_total{operation="Add\"Cart"} 1`)
+	assert.Contains(t, out, "kuncie_cart_orders_created_total 0\n")
+	assert.Contains(t, out, "# TYPE go_goroutines gauge\n")
+}
@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// namespace prefixes the names of the service metrics
+const namespace = "kuncie_cart"
+
+var (
+	// HTTPRequests counts the HTTP requests served by method, route and status code
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests served by method, route and status code.",
+	}, []string{"method", "route", "status"})
+	// HTTPRequestDuration observes the time taken to serve the HTTP requests by method and route
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time taken to serve the HTTP requests by method and route.",
+		Buckets:   DefBuckets,
+	}, []string{"method", "route"})
+	// GraphQLOperationDuration observes the time taken by the GraphQL requests by root field, such as AddCart
+	GraphQLOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "graphql_operation_duration_seconds",
+		Help:      "Time taken by the GraphQL requests by root field.",
+		Buckets:   DefBuckets,
+	}, []string{"operation"})
+	// RepositoryQueryDuration observes the time taken by the repository methods
+	RepositoryQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "repository_query_duration_seconds",
+		Help:      "Time taken by the repository methods.",
+		Buckets:   DefBuckets,
+	}, []string{"repository", "method"})
+
+	// OrdersCreated counts the orders placed
+	OrdersCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orders_created_total",
+		Help:      "Orders placed.",
+	})
+	// OrderRevenue sums the total price of the orders placed
+	OrderRevenue = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "order_revenue_total",
+		Help:      "Sum of the total price of the orders placed.",
+	})
+	// CartsCreated counts the cart lines created by adding an item or reordering
+	CartsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "carts_created_total",
+		Help:      "Cart lines created by adding an item or reordering.",
+	})
+	// OutOfStockRejections counts the cart lines refused for lack of stock by operation
+	OutOfStockRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "out_of_stock_rejections_total",
+		Help:      "Cart lines refused for lack of stock by operation.",
+	}, []string{"operation"})
+)
+
+func init() {
+	Default.MustRegister(HTTPRequests, HTTPRequestDuration, GraphQLOperationDuration, RepositoryQueryDuration,
+		OrdersCreated, OrderRevenue, CartsCreated, OutOfStockRejections)
+}
+
+// ObserveQuery records the time taken by a repository method since start, it is meant to be deferred:
+//
+//	defer metrics.ObserveQuery("order", "GetCart", time.Now())
+func ObserveQuery(repository, method string, start time.Time) {
+	RepositoryQueryDuration.WithLabelValues(repository, method).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo"
+
+	"github.com/williamchand/kuncie-cart/auth"
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// ResponseError represent the reseponse error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// JWT authenticates the bearer token of the request and binds its principal to the request context.
+// Requests without an Authorization header go through anonymously, an invalid token is refused.
+func (m *GoMiddleware) JWT(v auth.Verifier) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			if header == "" {
+				return next(c)
+			}
+
+			const prefix = "Bearer "
+			var p *models.Principal
+			err := auth.ErrInvalidToken
+			if len(header) > len(prefix) && strings.EqualFold(header[:len(prefix)], prefix) {
+				p, err = v.Verify(header[len(prefix):])
+			}
+			if err != nil {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+				return c.JSON(http.StatusUnauthorized, ResponseError{Message: err.Error()})
+			}
+
+			req := c.Request()
+			c.SetRequest(req.WithContext(auth.NewContext(req.Context(), p)))
+			return next(c)
+		}
+	}
+}
+
+// Require refuses the requests whose principal lacks perm, with 401 when anonymous and 403 otherwise
+func (m *GoMiddleware) Require(perm auth.Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			switch err := auth.Authorize(c.Request().Context(), perm); err {
+			case nil:
+				return next(c)
+			case models.ErrUnauthorized:
+				return c.JSON(http.StatusUnauthorized, ResponseError{Message: err.Error()})
+			default:
+				return c.JSON(http.StatusForbidden, ResponseError{Message: err.Error()})
+			}
+		}
+	}
+}
+
+// Session binds the anonymous session of the X-Session-Token header to the request context.
+// A new session is started when the header is missing or not valid and its token is sent back in the same header.
+func (m *GoMiddleware) Session(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := c.Request().Header.Get(auth.HeaderSessionToken)
+		if !auth.ValidSessionToken(token) {
+			var err error
+			if token, err = auth.NewSessionToken(); err != nil {
+				return err
+			}
+		}
+		c.Response().Header().Set(auth.HeaderSessionToken, token)
+
+		req := c.Request()
+		c.SetRequest(req.WithContext(auth.NewSessionContext(req.Context(), token)))
+		return next(c)
+	}
+}
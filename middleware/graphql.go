@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/labstack/echo"
+)
+
+const (
+	// maxGraphQLBody is the size of the bodies read to find the GraphQL operation of a request
+	maxGraphQLBody = 1 << 20
+	graphQLKey     = "graphql_request"
+)
+
+var operationNamePattern = regexp.MustCompile(`^\s*(?:query|mutation|subscription)\s+([_A-Za-z][_0-9A-Za-z]*)`)
+
+// graphQLRequest represent the GraphQL query of a request and the name of the operation to run
+type graphQLRequest struct {
+	Query         string `json:"query"`
+	OperationName string `json:"operationName"`
+}
+
+// GraphQLOperation returns the name of the GraphQL operation of a request to /graphql, empty when it has none
+func GraphQLOperation(c echo.Context) string {
+	r := graphQLParams(c)
+	if r.OperationName != "" {
+		return r.OperationName
+	}
+	if match := operationNamePattern.FindStringSubmatch(r.Query); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// GraphQLFields returns the root fields selected by the GraphQL operation of a request to /graphql, such as
+// AddCart, once per selection. Unlike the operation name they cannot be chosen freely by the client.
+func GraphQLFields(c echo.Context) []string {
+	r := graphQLParams(c)
+	if r.Query == "" {
+		return nil
+	}
+	doc, err := parser.Parse(parser.ParseParams{Source: r.Query})
+	if err != nil {
+		return nil
+	}
+
+	var operation *ast.OperationDefinition
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.OperationDefinition:
+			name := ""
+			if def.Name != nil {
+				name = def.Name.Value
+			}
+			if operation == nil && (r.OperationName == "" || r.OperationName == name) {
+				operation = def
+			}
+		case *ast.FragmentDefinition:
+			fragments[def.Name.Value] = def
+		}
+	}
+	if operation == nil {
+		return nil
+	}
+
+	fields := make([]string, 0)
+	seen := make(map[string]bool)
+	var collect func(set *ast.SelectionSet)
+	collect = func(set *ast.SelectionSet) {
+		if set == nil {
+			return
+		}
+		for _, sel := range set.Selections {
+			switch sel := sel.(type) {
+			case *ast.Field:
+				fields = append(fields, sel.Name.Value)
+			case *ast.InlineFragment:
+				collect(sel.SelectionSet)
+			case *ast.FragmentSpread:
+				// a fragment spread twice at the root is only followed once, so cycles stop
+				if f, ok := fragments[sel.Name.Value]; ok && !seen[sel.Name.Value] {
+					seen[sel.Name.Value] = true
+					collect(f.SelectionSet)
+				}
+			}
+		}
+	}
+	collect(operation.SelectionSet)
+	return fields
+}
+
+// graphQLParams reads the GraphQL query of the request once and keeps it in the echo context.
+// The body is put back for the handler.
+func graphQLParams(c echo.Context) graphQLRequest {
+	if r, ok := c.Get(graphQLKey).(graphQLRequest); ok {
+		return r
+	}
+	r := readGraphQLRequest(c.Request())
+	c.Set(graphQLKey, r)
+	return r
+}
+
+func readGraphQLRequest(req *http.Request) graphQLRequest {
+	if !strings.HasSuffix(req.URL.Path, "/graphql") {
+		return graphQLRequest{}
+	}
+
+	r := graphQLRequest{
+		Query:         req.URL.Query().Get("query"),
+		OperationName: req.URL.Query().Get("operationName"),
+	}
+	if req.Method == echo.POST && req.Body != nil {
+		body, err := ioutil.ReadAll(io.LimitReader(req.Body, maxGraphQLBody+1))
+		req.Body = readCloser{io.MultiReader(bytes.NewReader(body), req.Body), req.Body}
+		if err != nil || len(body) > maxGraphQLBody {
+			return graphQLRequest{}
+		}
+		if strings.HasPrefix(req.Header.Get(echo.HeaderContentType), "application/graphql") {
+			r.Query = string(body)
+		} else {
+			_ = json.Unmarshal(body, &r)
+		}
+	}
+	return r
+}
+
+// readCloser reads the body again from what was already read and closes the original body
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
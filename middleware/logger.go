@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+
+	"github.com/williamchand/kuncie-cart/logging"
+)
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestLogger propagates the X-Request-ID of the request, or a new one when it has none, binds a logger
+// carrying it to the request context and logs every request once it is served.
+func (m *GoMiddleware) RequestLogger(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+		req := c.Request()
+
+		id := req.Header.Get(echo.HeaderXRequestID)
+		if !requestIDPattern.MatchString(id) {
+			id = newRequestID()
+		}
+		c.Response().Header().Set(echo.HeaderXRequestID, id)
+
+		log := logrus.StandardLogger()
+		if m.Logger != nil {
+			log = m.Logger
+		}
+		entry := log.WithField("request_id", id)
+		c.SetRequest(req.WithContext(logging.NewContext(req.Context(), entry)))
+		operation := GraphQLOperation(c)
+
+		err := next(c)
+		if err != nil {
+			c.Error(err)
+		}
+
+		fields := logrus.Fields{
+			"method":     req.Method,
+			"path":       req.URL.Path,
+			"status":     c.Response().Status,
+			"latency_ms": float64(time.Since(start)) / float64(time.Millisecond),
+			"remote_ip":  m.RealIP(c),
+		}
+		if operation != "" {
+			fields["operation"] = operation
+		}
+		// the inner middlewares may have added fields, such as the trace_id, to the logger of the request
+		logging.FromContext(c.Request().Context()).WithFields(fields).Info("request served")
+		return nil
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return hex.EncodeToString([]byte(time.Now().Format(time.RFC3339Nano)))
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo"
+
+	"github.com/williamchand/kuncie-cart/metrics"
+)
+
+// otherOperation labels the GraphQL root fields missing from the schema so clients cannot add label values
+const otherOperation = "other"
+
+// Metrics counts the requests by method, route and status code and observes the time taken to serve them.
+// Requests matching no route are labelled with an empty route.
+func (m *GoMiddleware) Metrics(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+
+		err := next(c)
+		if err != nil {
+			c.Error(err)
+		}
+
+		method := c.Request().Method
+		route := c.Path()
+		// echo answers the requests matching no route with ErrNotFound and leaves the path of the request itself in
+		// c.Path(), which would add a label value per URL
+		if err == echo.ErrNotFound {
+			route = ""
+		}
+		metrics.HTTPRequests.WithLabelValues(method, route, strconv.Itoa(c.Response().Status)).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+		return nil
+	}
+}
+
+// GraphQLMetrics observes the time taken by the GraphQL requests once for each of their root fields, such as
+// AddCart. Fields which are not in operations, the root fields of the schema, are labelled other.
+func (m *GoMiddleware) GraphQLMetrics(operations []string) echo.MiddlewareFunc {
+	known := make(map[string]bool, len(operations))
+	for _, name := range operations {
+		known[name] = true
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			fields := GraphQLFields(c)
+
+			err := next(c)
+
+			elapsed := time.Since(start).Seconds()
+			seen := make(map[string]bool, len(fields))
+			for _, field := range fields {
+				if !known[field] {
+					field = otherOperation
+				}
+				if !seen[field] {
+					seen[field] = true
+					metrics.GraphQLOperationDuration.WithLabelValues(field).Observe(elapsed)
+				}
+			}
+			return err
+		}
+	}
+}
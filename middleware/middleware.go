@@ -1,21 +1,140 @@
 package middleware
 
-import "github.com/labstack/echo"
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+
+	"github.com/williamchand/kuncie-cart/auth"
+)
+
+// CORSConfig represent the cross-origin resource sharing policy
+type CORSConfig struct {
+	// AllowOrigins are full origins such as https://shop.example.com, * for any origin or
+	// https://*.example.com for the subdomains of example.com
+	AllowOrigins     []string
+	AllowMethods     []string
+	AllowHeaders     []string
+	ExposeHeaders    []string
+	AllowCredentials bool
+	// MaxAge is how many seconds the browser may cache a preflight response, none when zero
+	MaxAge int
+}
+
+// DefaultCORSConfig allows any origin to call the API without credentials
+var DefaultCORSConfig = CORSConfig{
+	AllowOrigins:  []string{"*"},
+	AllowMethods:  []string{echo.GET, echo.POST, echo.OPTIONS},
+	AllowHeaders:  []string{echo.HeaderAuthorization, echo.HeaderContentType, auth.HeaderSessionToken},
+	ExposeHeaders: []string{auth.HeaderSessionToken},
+}
 
 // GoMiddleware represent the data-struct for middleware
 type GoMiddleware struct {
-	// another stuff , may be needed by middleware
+	// CORSConfig is the policy applied by CORS
+	CORSConfig CORSConfig
+	// Logger receives the request logs, the logrus standard logger when nil
+	Logger *logrus.Logger
+	// TrustedProxies are the proxies whose forwarding headers are believed by RealIP, none when empty
+	TrustedProxies []*net.IPNet
 }
 
-// CORS will handle the CORS middleware
+// CORS will handle the CORS middleware, preflight requests are answered without reaching the handlers
 func (m *GoMiddleware) CORS(next echo.HandlerFunc) echo.HandlerFunc {
+	cfg := m.CORSConfig
+	allowMethods := strings.Join(cfg.AllowMethods, ",")
+	allowHeaders := strings.Join(cfg.AllowHeaders, ",")
+	exposeHeaders := strings.Join(cfg.ExposeHeaders, ",")
+
 	return func(c echo.Context) error {
-		c.Response().Header().Set("Access-Control-Allow-Origin", "*")
-		return next(c)
+		req := c.Request()
+		header := c.Response().Header()
+		origin := req.Header.Get(echo.HeaderOrigin)
+		preflight := req.Method == echo.OPTIONS && req.Header.Get(echo.HeaderAccessControlRequestMethod) != ""
+
+		header.Add(echo.HeaderVary, echo.HeaderOrigin)
+		if preflight {
+			header.Add(echo.HeaderVary, echo.HeaderAccessControlRequestMethod)
+			header.Add(echo.HeaderVary, echo.HeaderAccessControlRequestHeaders)
+		}
+		if origin == "" {
+			return next(c)
+		}
+
+		allowed, wildcard := m.allowOrigin(origin)
+		if !allowed {
+			if preflight {
+				return c.NoContent(http.StatusForbidden)
+			}
+			return next(c)
+		}
+		// credentials are only shared with the listed origins, an origin matching * gets neither its echo nor them
+		if wildcard {
+			header.Set(echo.HeaderAccessControlAllowOrigin, "*")
+		} else {
+			header.Set(echo.HeaderAccessControlAllowOrigin, origin)
+			if cfg.AllowCredentials {
+				header.Set(echo.HeaderAccessControlAllowCredentials, "true")
+			}
+		}
+
+		if !preflight {
+			if exposeHeaders != "" {
+				header.Set(echo.HeaderAccessControlExposeHeaders, exposeHeaders)
+			}
+			return next(c)
+		}
+
+		header.Set(echo.HeaderAccessControlAllowMethods, allowMethods)
+		if allowHeaders != "" {
+			header.Set(echo.HeaderAccessControlAllowHeaders, allowHeaders)
+		}
+		if cfg.MaxAge > 0 {
+			header.Set(echo.HeaderAccessControlMaxAge, strconv.Itoa(cfg.MaxAge))
+		}
+		return c.NoContent(http.StatusNoContent)
 	}
 }
 
-// InitMiddleware intialize the middleware
+// allowOrigin tells whether the origin is allowed and whether it is through the * wildcard
+func (m *GoMiddleware) allowOrigin(origin string) (allowed bool, wildcard bool) {
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return false, false
+	}
+
+	// the listed origins win over *, wherever it is in the list, so they keep their credentials
+	for _, o := range m.CORSConfig.AllowOrigins {
+		if o == "*" {
+			wildcard = true
+			continue
+		}
+		if strings.EqualFold(o, origin) {
+			return true, false
+		}
+
+		// https://*.example.com matches the subdomains of example.com on the same scheme and port
+		i := strings.Index(o, "://*.")
+		if i < 0 || !strings.EqualFold(o[:i], u.Scheme) {
+			continue
+		}
+		suffix := strings.ToLower(o[i+len("://*"):])
+		host := strings.ToLower(u.Host)
+		if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+			return true, false
+		}
+	}
+	return wildcard, wildcard
+}
+
+// InitMiddleware intialize the middleware with the DefaultCORSConfig policy
 func InitMiddleware() *GoMiddleware {
-	return &GoMiddleware{}
+	return &GoMiddleware{
+		CORSConfig: DefaultCORSConfig,
+	}
 }
@@ -1,29 +1,471 @@
 package middleware_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
 	"net/http"
 	test "net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/williamchand/kuncie-cart/auth"
+	"github.com/williamchand/kuncie-cart/logging"
+	"github.com/williamchand/kuncie-cart/metrics"
 	"github.com/williamchand/kuncie-cart/middleware"
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/ratelimit"
+	"github.com/williamchand/kuncie-cart/ratelimit/store/memory"
+	"github.com/williamchand/kuncie-cart/tracing"
 )
 
 func TestCORS(t *testing.T) {
+	restricted := middleware.CORSConfig{
+		AllowOrigins:     []string{"https://shop.example.com", "https://*.kuncie.com"},
+		AllowMethods:     []string{echo.GET, echo.POST},
+		AllowHeaders:     []string{echo.HeaderAuthorization, echo.HeaderContentType},
+		ExposeHeaders:    []string{"X-Session-Token"},
+		AllowCredentials: true,
+		MaxAge:           600,
+	}
+	tests := []struct {
+		name          string
+		config        middleware.CORSConfig
+		method        string
+		origin        string
+		requestMethod string
+		status        int
+		allowOrigin   string
+		headers       map[string]string
+	}{
+		{name: "default-any-origin", config: middleware.DefaultCORSConfig, method: echo.GET, origin: "http://localhost:3000",
+			status: http.StatusOK, allowOrigin: "*", headers: map[string]string{echo.HeaderAccessControlExposeHeaders: "X-Session-Token"}},
+		{name: "no-origin", config: restricted, method: echo.GET, status: http.StatusOK},
+		{name: "exact-origin", config: restricted, method: echo.POST, origin: "https://shop.example.com",
+			status: http.StatusOK, allowOrigin: "https://shop.example.com",
+			headers: map[string]string{echo.HeaderAccessControlAllowCredentials: "true"}},
+		{name: "wildcard-subdomain", config: restricted, method: echo.GET, origin: "https://admin.kuncie.com",
+			status: http.StatusOK, allowOrigin: "https://admin.kuncie.com"},
+		{name: "wildcard-needs-subdomain", config: restricted, method: echo.GET, origin: "https://kuncie.com", status: http.StatusOK},
+		{name: "wildcard-other-scheme", config: restricted, method: echo.GET, origin: "http://admin.kuncie.com", status: http.StatusOK},
+		{name: "lookalike-domain", config: restricted, method: echo.GET, origin: "https://evilkuncie.com", status: http.StatusOK},
+		{name: "origin-not-allowed", config: restricted, method: echo.GET, origin: "https://evil.com", status: http.StatusOK},
+		{name: "preflight", config: restricted, method: echo.OPTIONS, origin: "https://shop.example.com", requestMethod: echo.POST,
+			status: http.StatusNoContent, allowOrigin: "https://shop.example.com", headers: map[string]string{
+				echo.HeaderAccessControlAllowMethods:     "GET,POST",
+				echo.HeaderAccessControlAllowHeaders:     "Authorization,Content-Type",
+				echo.HeaderAccessControlMaxAge:           "600",
+				echo.HeaderAccessControlAllowCredentials: "true",
+			}},
+		{name: "preflight-origin-not-allowed", config: restricted, method: echo.OPTIONS, origin: "https://evil.com",
+			requestMethod: echo.POST, status: http.StatusForbidden},
+		{name: "credentials-with-any-origin", config: middleware.CORSConfig{AllowOrigins: []string{"*"}, AllowCredentials: true},
+			method: echo.GET, origin: "https://shop.example.com", status: http.StatusOK, allowOrigin: "*",
+			headers: map[string]string{echo.HeaderAccessControlAllowCredentials: ""}},
+		{name: "credentials-with-listed-and-any-origin", config: middleware.CORSConfig{
+			AllowOrigins: []string{"*", "https://shop.example.com"}, AllowCredentials: true},
+			method: echo.GET, origin: "https://shop.example.com", status: http.StatusOK, allowOrigin: "https://shop.example.com",
+			headers: map[string]string{echo.HeaderAccessControlAllowCredentials: "true"}},
+		{name: "credentials-with-unlisted-origin", config: middleware.CORSConfig{
+			AllowOrigins: []string{"https://shop.example.com", "*"}, AllowCredentials: true},
+			method: echo.GET, origin: "https://evil.com", status: http.StatusOK, allowOrigin: "*",
+			headers: map[string]string{echo.HeaderAccessControlAllowCredentials: ""}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := test.NewRequest(tc.method, "/graphql", nil)
+			if tc.origin != "" {
+				req.Header.Set(echo.HeaderOrigin, tc.origin)
+			}
+			if tc.requestMethod != "" {
+				req.Header.Set(echo.HeaderAccessControlRequestMethod, tc.requestMethod)
+			}
+			res := test.NewRecorder()
+			c := e.NewContext(req, res)
+			m := middleware.InitMiddleware()
+			m.CORSConfig = tc.config
+
+			h := m.CORS(echo.HandlerFunc(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}))
+
+			err := h(c)
+			require.NoError(t, err)
+			assert.Equal(t, tc.status, res.Code)
+			assert.Equal(t, tc.allowOrigin, res.Header().Get(echo.HeaderAccessControlAllowOrigin))
+			assert.Contains(t, res.Header()[echo.HeaderVary], echo.HeaderOrigin)
+			for k, v := range tc.headers {
+				assert.Equal(t, v, res.Header().Get(k), k)
+			}
+		})
+	}
+}
+
+type verifierFunc func(token string) (*models.Principal, error)
+
+func (f verifierFunc) Verify(token string) (*models.Principal, error) {
+	return f(token)
+}
+
+func TestJWT(t *testing.T) {
+	v := verifierFunc(func(token string) (*models.Principal, error) {
+		if token != "valid" {
+			return nil, auth.ErrInvalidToken
+		}
+		return &models.Principal{Subject: "5", CustomerID: 5}, nil
+	})
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+		customerID    int64
+	}{
+		{name: "anonymous", status: http.StatusOK},
+		{name: "valid", authorization: "Bearer valid", status: http.StatusOK, customerID: 5},
+		{name: "invalid", authorization: "Bearer forged", status: http.StatusUnauthorized},
+		{name: "not-bearer", authorization: "Basic dXNlcjpwYXNz", status: http.StatusUnauthorized},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := test.NewRequest(echo.POST, "/graphql", nil)
+			if tc.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tc.authorization)
+			}
+			res := test.NewRecorder()
+			c := e.NewContext(req, res)
+			m := middleware.InitMiddleware()
+
+			customerID := int64(0)
+			h := m.JWT(v)(func(c echo.Context) error {
+				customerID, _ = auth.CustomerID(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			})
+
+			require.NoError(t, h(c))
+			assert.Equal(t, tc.status, res.Code)
+			assert.Equal(t, tc.customerID, customerID)
+		})
+	}
+}
+
+func TestRequire(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *models.Principal
+		status    int
+	}{
+		{name: "anonymous", status: http.StatusUnauthorized},
+		{name: "shopper", principal: &models.Principal{Subject: "5", CustomerID: 5}, status: http.StatusForbidden},
+		{name: "admin", principal: &models.Principal{Subject: "ops", Roles: []string{auth.RoleAdmin}}, status: http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := test.NewRequest(echo.GET, "/admin/orders/export", nil)
+			if tc.principal != nil {
+				req = req.WithContext(auth.NewContext(req.Context(), tc.principal))
+			}
+			res := test.NewRecorder()
+			c := e.NewContext(req, res)
+			m := middleware.InitMiddleware()
+
+			h := m.Require(auth.PermManageOrders)(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+
+			require.NoError(t, h(c))
+			assert.Equal(t, tc.status, res.Code)
+		})
+	}
+}
+
+func TestSession(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		kept  bool
+	}{
+		{name: "new-session"},
+		{name: "existing-session", token: "3f9c2a7e5b1d4c8a9e0f", kept: true},
+		{name: "invalid-token", token: "short"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := test.NewRequest(echo.POST, "/graphql", nil)
+			if tc.token != "" {
+				req.Header.Set(auth.HeaderSessionToken, tc.token)
+			}
+			res := test.NewRecorder()
+			c := e.NewContext(req, res)
+			m := middleware.InitMiddleware()
+
+			session := ""
+			h := m.Session(func(c echo.Context) error {
+				session = auth.SessionFromContext(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			})
+
+			require.NoError(t, h(c))
+			assert.True(t, auth.ValidSessionToken(session))
+			assert.Equal(t, session, res.Header().Get(auth.HeaderSessionToken))
+			assert.Equal(t, tc.kept, session == tc.token)
+		})
+	}
+}
+
+func TestRequestLogger(t *testing.T) {
+	tests := []struct {
+		name        string
+		requestID   string
+		contentType string
+		body        string
+		operation   string
+		generated   bool
+	}{
+		{name: "propagated-id", requestID: "abc-123", contentType: echo.MIMEApplicationJSON,
+			body: `{"query":"mutation AddCart { AddCart(sku: \"120P90\", quantity: 1) { id } }"}`, operation: "AddCart"},
+		{name: "operation-name-field", contentType: echo.MIMEApplicationJSON,
+			body: `{"query":"query A { Placeholder } query B { Placeholder }","operationName":"B"}`, operation: "B", generated: true},
+		{name: "graphql-body", requestID: "bad id with spaces", contentType: "application/graphql",
+			body: `query Items { Items { next_cursor } }`, operation: "Items", generated: true},
+		{name: "anonymous-operation", contentType: echo.MIMEApplicationJSON, body: `{"query":"{ Placeholder }"}`, generated: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			log := logrus.New()
+			log.Out = out
+			log.Formatter = &logrus.JSONFormatter{}
+
+			e := echo.New()
+			req := test.NewRequest(echo.POST, "/graphql", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, tc.contentType)
+			if tc.requestID != "" {
+				req.Header.Set(echo.HeaderXRequestID, tc.requestID)
+			}
+			res := test.NewRecorder()
+			c := e.NewContext(req, res)
+			m := middleware.InitMiddleware()
+			m.Logger = log
+
+			body := ""
+			h := m.RequestLogger(func(c echo.Context) error {
+				b, err := ioutil.ReadAll(c.Request().Body)
+				body = string(b)
+				logging.FromContext(c.Request().Context()).Warn("from the handler")
+				if err != nil {
+					return err
+				}
+				return c.NoContent(http.StatusCreated)
+			})
+
+			require.NoError(t, h(c))
+			assert.Equal(t, tc.body, body)
+			id := res.Header().Get(echo.HeaderXRequestID)
+			if tc.generated {
+				assert.Len(t, id, 32)
+			} else {
+				assert.Equal(t, tc.requestID, id)
+			}
+
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			require.Len(t, lines, 2)
+			handler := map[string]interface{}{}
+			access := map[string]interface{}{}
+			require.NoError(t, json.Unmarshal([]byte(lines[0]), &handler))
+			require.NoError(t, json.Unmarshal([]byte(lines[1]), &access))
+			assert.Equal(t, id, handler["request_id"])
+			assert.Equal(t, id, access["request_id"])
+			assert.Equal(t, "POST", access["method"])
+			assert.Equal(t, "/graphql", access["path"])
+			assert.Equal(t, float64(http.StatusCreated), access["status"])
+			assert.Contains(t, access, "latency_ms")
+			if tc.operation != "" {
+				assert.Equal(t, tc.operation, access["operation"])
+			} else {
+				assert.NotContains(t, access, "operation")
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	addCart := `{"query":"mutation { AddCart(sku: \"120P90\", quantity: 1) { id } }"}`
+	renamed := `{"query":"mutation Innocent { a: AddCart(sku: \"120P90\", quantity: 1) { id } }"}`
+	items := `{"query":"{ Items { next_cursor } }"}`
+	type call struct {
+		body       string
+		apiKey     string
+		ip         string
+		forwarded  string
+		status     int
+		retryAfter string
+	}
+	tests := []struct {
+		name  string
+		calls []call
+	}{
+		{name: "operation-limit", calls: []call{
+			{body: addCart, ip: "10.0.0.1", status: http.StatusOK},
+			{body: addCart, ip: "10.0.0.1", status: http.StatusTooManyRequests, retryAfter: "10"},
+			{body: items, ip: "10.0.0.1", status: http.StatusOK},
+		}},
+		{name: "renamed-operation", calls: []call{
+			{body: addCart, ip: "10.0.0.1", status: http.StatusOK},
+			{body: renamed, ip: "10.0.0.1", status: http.StatusTooManyRequests, retryAfter: "10"},
+		}},
+		{name: "default-limit", calls: []call{
+			{body: items, ip: "10.0.0.1", status: http.StatusOK},
+			{body: items, ip: "10.0.0.1", status: http.StatusOK},
+			{body: items, ip: "10.0.0.1", status: http.StatusOK},
+			{body: items, ip: "10.0.0.1", status: http.StatusTooManyRequests, retryAfter: "1"},
+			{body: items, ip: "10.0.0.2", status: http.StatusOK},
+		}},
+		{name: "api-key-over-ip", calls: []call{
+			{body: addCart, ip: "10.0.0.1", apiKey: "partner-a", status: http.StatusOK},
+			{body: addCart, ip: "10.0.0.1", apiKey: "partner-b", status: http.StatusOK},
+			{body: addCart, ip: "10.0.0.1", status: http.StatusOK},
+			{body: addCart, ip: "10.0.0.1", apiKey: "partner-a", status: http.StatusTooManyRequests, retryAfter: "10"},
+		}},
+		{name: "unknown-api-keys", calls: []call{
+			{body: addCart, ip: "10.0.0.1", apiKey: "random-1", status: http.StatusOK},
+			{body: addCart, ip: "10.0.0.1", apiKey: "random-2", status: http.StatusTooManyRequests, retryAfter: "10"},
+			{body: addCart, ip: "10.0.0.1", apiKey: "random-3", status: http.StatusTooManyRequests, retryAfter: "10"},
+		}},
+		{name: "spoofed-forwarded-for", calls: []call{
+			{body: addCart, ip: "10.0.0.1", forwarded: "203.0.113.1", status: http.StatusOK},
+			{body: addCart, ip: "10.0.0.1", forwarded: "203.0.113.2", status: http.StatusTooManyRequests, retryAfter: "10"},
+		}},
+		{name: "trusted-proxy", calls: []call{
+			{body: addCart, ip: "192.168.1.10", forwarded: "203.0.113.1", status: http.StatusOK},
+			{body: addCart, ip: "192.168.1.10", forwarded: "203.0.113.2", status: http.StatusOK},
+			// the client cannot hide behind a hop it added itself
+			{body: addCart, ip: "192.168.1.10", forwarded: "198.51.100.7, 203.0.113.1", status: http.StatusTooManyRequests, retryAfter: "10"},
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			store := memory.NewMemoryStore()
+			now := time.Date(2022, 4, 26, 10, 0, 0, 0, time.UTC)
+			store.Now = func() time.Time { return now }
+			m := middleware.InitMiddleware()
+			proxies, err := middleware.ParseTrustedProxies([]string{"192.168.1.0/24"})
+			require.NoError(t, err)
+			m.TrustedProxies = proxies
+			limit := m.RateLimit(store, middleware.RateLimitConfig{
+				Default:    ratelimit.Limit{Rate: 2, Burst: 3},
+				Operations: map[string]ratelimit.Limit{"addcart": {Rate: 0.1, Burst: 1}},
+				APIKeys:    []string{"partner-a", "partner-b"},
+			})
+
+			for i, call := range tc.calls {
+				req := test.NewRequest(echo.POST, "/graphql", strings.NewReader(call.body))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				req.RemoteAddr = call.ip + ":43210"
+				if call.forwarded != "" {
+					req.Header.Set(echo.HeaderXForwardedFor, call.forwarded)
+				}
+				if call.apiKey != "" {
+					req.Header.Set(middleware.HeaderAPIKey, call.apiKey)
+				}
+				res := test.NewRecorder()
+				c := e.NewContext(req, res)
+
+				h := limit(func(c echo.Context) error {
+					return c.NoContent(http.StatusOK)
+				})
+
+				require.NoError(t, h(c))
+				assert.Equal(t, call.status, res.Code, "call %d", i)
+				assert.Equal(t, call.retryAfter, res.Header().Get("Retry-After"), "call %d", i)
+			}
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
 	e := echo.New()
-	req := test.NewRequest(echo.GET, "/", nil)
+	m := middleware.InitMiddleware()
+	e.Use(m.Metrics)
+	e.GET("/orders/:id/invoice", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	e.POST("/graphql", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, m.GraphQLMetrics([]string{"AddCart", "Items"}))
+
+	requests := []*http.Request{
+		test.NewRequest(echo.GET, "/orders/1/invoice", nil),
+		test.NewRequest(echo.GET, "/orders/2/invoice", nil),
+		test.NewRequest(echo.GET, "/unknown/3", nil),
+		test.NewRequest(echo.POST, "/graphql", strings.NewReader(`{"query":"mutation { a: AddCart(sku: \"120P90\", quantity: 1) { id } b: AddCart(sku: \"120P90\", quantity: 1) { id } Secret { id } }"}`)),
+	}
+	for _, req := range requests {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		e.ServeHTTP(test.NewRecorder(), req)
+	}
+
 	res := test.NewRecorder()
-	c := e.NewContext(req, res)
+	metrics.Handler().ServeHTTP(res, test.NewRequest(echo.GET, "/metrics", nil))
+	out := res.Body.String()
+	assert.Contains(t, out, `kuncie_cart_http_requests_total{method="GET",route="/orders/:id/invoice",status="200"} 2`)
+	assert.Contains(t, out, `kuncie_cart_http_requests_total{method="GET",route="",status="404"} 1`)
+	assert.Contains(t, out, `kuncie_cart_http_request_duration_seconds_count{method="POST",route="/graphql"} 1`)
+	assert.Contains(t, out, `kuncie_cart_graphql_operation_duration_seconds_count{operation="AddCart"} 1`)
+	assert.Contains(t, out, `kuncie_cart_graphql_operation_duration_seconds_count{operation="other"} 1`)
+	assert.NotContains(t, out, `operation="Secret"`)
+}
+
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := tracing.Setup(exporter, 1)
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	e := echo.New()
 	m := middleware.InitMiddleware()
+	e.Use(m.Tracing)
+	var child trace.SpanContext
+	e.GET("/orders/:id/invoice", func(c echo.Context) error {
+		_, span := tracing.Start(c.Request().Context(), "orderUsecase.GetOrder")
+		child = span.SpanContext()
+		span.End()
+		return c.NoContent(http.StatusNotFound)
+	})
 
-	h := m.CORS(echo.HandlerFunc(func(c echo.Context) error {
-		return c.NoContent(http.StatusOK)
-	}))
+	req := test.NewRequest(echo.GET, "/orders/1/invoice", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	e.ServeHTTP(test.NewRecorder(), req)
 
-	err := h(c)
-	require.NoError(t, err)
-	assert.Equal(t, "*", res.Header().Get("Access-Control-Allow-Origin"))
+	require.NoError(t, tp.ForceFlush(context.Background()))
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "orderUsecase.GetOrder", spans[0].Name)
+	assert.Equal(t, "HTTP GET /orders/:id/invoice", spans[1].Name)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[1].SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[1].Parent.SpanID().String())
+	assert.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+	assert.Equal(t, child.SpanID(), spans[0].SpanContext.SpanID())
+	assert.Contains(t, spans[1].Attributes, attribute.Int("http.status_code", http.StatusNotFound))
+	assert.Contains(t, spans[1].Attributes, attribute.String("http.route", "/orders/:id/invoice"))
 }
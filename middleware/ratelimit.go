@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo"
+
+	"github.com/williamchand/kuncie-cart/auth"
+	"github.com/williamchand/kuncie-cart/logging"
+	"github.com/williamchand/kuncie-cart/ratelimit"
+)
+
+// HeaderAPIKey identifies the API clients, it is only used to tell them apart for rate limiting and only
+// when the key is one of RateLimitConfig.APIKeys
+const HeaderAPIKey = "X-API-Key"
+
+// RateLimitConfig represent the limits of every client
+type RateLimitConfig struct {
+	// Default is the limit of all the requests of a client, none when its burst is zero
+	Default ratelimit.Limit
+	// Operations are the limits of the GraphQL root fields, such as AddCart, on top of the default one.
+	// Names are compared without case.
+	Operations map[string]ratelimit.Limit
+	// APIKeys are the keys issued to the API clients, a request with another key is limited as if it had none
+	APIKeys []string
+}
+
+// RateLimit refuses with 429 the requests of a client over its limits, the client being the known API key of
+// the request, else the signed in user and else the IP address given by RealIP. Store errors let the
+// requests through.
+func (m *GoMiddleware) RateLimit(store ratelimit.Store, cfg RateLimitConfig) echo.MiddlewareFunc {
+	operations := make(map[string]ratelimit.Limit, len(cfg.Operations))
+	for name, limit := range cfg.Operations {
+		operations[strings.ToLower(name)] = limit
+	}
+	apiKeys := make(map[string]bool, len(cfg.APIKeys))
+	for _, key := range cfg.APIKeys {
+		apiKeys[hashAPIKey(key)] = true
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			client := m.rateLimitClient(c, apiKeys)
+
+			type take struct {
+				key   string
+				limit ratelimit.Limit
+			}
+			takes := make([]take, 0, 1)
+			if cfg.Default.Burst > 0 {
+				takes = append(takes, take{client, cfg.Default})
+			}
+			if len(operations) > 0 {
+				for _, field := range GraphQLFields(c) {
+					name := strings.ToLower(field)
+					if limit, ok := operations[name]; ok {
+						takes = append(takes, take{client + "|" + name, limit})
+					}
+				}
+			}
+
+			for _, t := range takes {
+				ok, retryAfter, err := store.Take(ctx, t.key, t.limit)
+				if err != nil {
+					logging.FromContext(ctx).Error(err)
+					continue
+				}
+				if !ok {
+					seconds := math.Max(1, math.Ceil(retryAfter.Seconds()))
+					c.Response().Header().Set("Retry-After", strconv.FormatFloat(seconds, 'f', 0, 64))
+					return c.JSON(http.StatusTooManyRequests, ResponseError{Message: "rate limit exceeded"})
+				}
+			}
+			return next(c)
+		}
+	}
+}
+
+// rateLimitClient returns the key of the client of the request. API keys are hashed so they are not kept,
+// unknown ones are ignored so a client cannot get a new bucket by making up a key.
+func (m *GoMiddleware) rateLimitClient(c echo.Context, apiKeys map[string]bool) string {
+	if key := c.Request().Header.Get(HeaderAPIKey); key != "" {
+		if hash := hashAPIKey(key); apiKeys[hash] {
+			return "key:" + hash
+		}
+	}
+	if p, ok := auth.FromContext(c.Request().Context()); ok {
+		return "user:" + p.Subject
+	}
+	return "ip:" + m.RealIP(c)
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:16])
+}
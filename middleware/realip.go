@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net"
+	"strings"
+
+	"github.com/labstack/echo"
+)
+
+// ParseTrustedProxies reads the addresses of the proxies in front of the service, as CIDR blocks such as
+// 10.0.0.0/8 or single IP addresses
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	res := make([]*net.IPNet, 0, len(proxies))
+	for _, p := range proxies {
+		if !strings.Contains(p, "/") {
+			ip := net.ParseIP(p)
+			if ip == nil {
+				return nil, &net.ParseError{Type: "IP address", Text: p}
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			res = append(res, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, block, err := net.ParseCIDR(p)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, block)
+	}
+	return res, nil
+}
+
+// RealIP returns the IP address of the client of the request. The X-Forwarded-For and X-Real-IP headers are
+// only believed when the request comes from one of the TrustedProxies, unlike echo's RealIP which believes
+// anyone: X-Forwarded-For is read from the nearest hop and the first address that is not a trusted proxy
+// is the client.
+func (m *GoMiddleware) RealIP(c echo.Context) string {
+	req := c.Request()
+	ip, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		ip = req.RemoteAddr
+	}
+	if !m.trustedProxy(ip) {
+		return ip
+	}
+
+	forwarded := req.Header.Get(echo.HeaderXForwardedFor)
+	if forwarded == "" {
+		if real := strings.TrimSpace(req.Header.Get(echo.HeaderXRealIP)); net.ParseIP(real) != nil {
+			return real
+		}
+		return ip
+	}
+	hops := strings.Split(forwarded, ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			// a malformed hop cannot be trusted, the request is then told apart by the proxy that forwarded it
+			return ip
+		}
+		ip = hop
+		if !m.trustedProxy(hop) {
+			return hop
+		}
+	}
+	return ip
+}
+
+func (m *GoMiddleware) trustedProxy(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, block := range m.TrustedProxies {
+		if block.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
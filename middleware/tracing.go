@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"github.com/labstack/echo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+
+	"github.com/williamchand/kuncie-cart/logging"
+	"github.com/williamchand/kuncie-cart/tracing"
+)
+
+// Tracing starts the span of the request, continuing the trace of the traceparent header when it has a valid one,
+// and adds its trace_id to the logger of the request
+func (m *GoMiddleware) Tracing(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+
+		ctx, span := tracing.Start(ctx, "HTTP "+req.Method+" "+c.Path())
+		defer span.End()
+		span.SetAttributes(attribute.String("http.method", req.Method), attribute.String("http.route", c.Path()))
+		if operation := GraphQLOperation(c); operation != "" {
+			span.SetAttributes(attribute.String("graphql.operation", operation))
+		}
+
+		if sc := span.SpanContext(); sc.HasTraceID() {
+			ctx = logging.NewContext(ctx, logging.FromContext(ctx).WithField("trace_id", sc.TraceID().String()))
+		}
+		c.SetRequest(req.WithContext(ctx))
+
+		err := next(c)
+		if err != nil {
+			c.Error(err)
+			tracing.SetError(span, err)
+		}
+		span.SetAttributes(attribute.Int("http.status_code", c.Response().Status))
+		return nil
+	}
+}
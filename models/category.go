@@ -0,0 +1,38 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Category represent a node of the category tree.
+// Path lists the ids from the root down to the category itself, such as /1/4/9/.
+type Category struct {
+	ID        int64     `json:"id"`
+	ParentID  int64     `json:"parent_id"`
+	Name      string    `json:"name" validate:"required,max=100"`
+	Path      string    `json:"path"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Lineage returns the id of the category followed by the ids of its ancestors up to the root
+func (c *Category) Lineage() []int64 {
+	parts := strings.Split(strings.Trim(c.Path, "/"), "/")
+	res := make([]int64, 0, len(parts))
+	for i := len(parts) - 1; i >= 0; i-- {
+		id, err := strconv.ParseInt(parts[i], 10, 64)
+		if err != nil {
+			continue
+		}
+		res = append(res, id)
+	}
+	return res
+}
+
+// ItemFilter selects items by category, subcategories included, and by tag. Zero values select every item.
+type ItemFilter struct {
+	CategoryID int64  `json:"category_id"`
+	Tag        string `json:"tag"`
+}
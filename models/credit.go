@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	// CreditAccountGiftCard identifies ledger entries moving the balance of a gift card
+	CreditAccountGiftCard = "gift_card"
+	// CreditAccountStoreCredit identifies ledger entries moving the store credit of a customer
+	CreditAccountStoreCredit = "store_credit"
+)
+
+// GiftCard represent a prepaid code that can pay for orders until its balance runs out
+type GiftCard struct {
+	ID             int64     `json:"id"`
+	Code           string    `json:"code"`
+	InitialBalance float64   `json:"initial_balance" validate:"gt=0"`
+	Balance        float64   `json:"balance"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// StoreCredit represent the wallet of a customer
+type StoreCredit struct {
+	CustomerID int64          `json:"customer_id"`
+	Balance    float64        `json:"balance"`
+	Entries    []*CreditEntry `json:"entries"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// CreditEntry represent a movement of a gift card or store credit balance.
+// Debits are negative and reference the order they paid for, credits given back on a refund also reference the refund.
+type CreditEntry struct {
+	ID           int64     `json:"id"`
+	Account      string    `json:"account"`
+	AccountID    int64     `json:"account_id"`
+	OrderID      int64     `json:"order_id"`
+	RefundID     int64     `json:"refund_id"`
+	Amount       float64   `json:"amount"`
+	BalanceAfter float64   `json:"balance_after"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Tender represent a gift card or store credit used to pay for an order at checkout.
+// When Amount is zero as much as possible of the balance is used. Store credit is always the one of the signed in
+// customer, a CustomerID naming anyone else is refused.
+type Tender struct {
+	Account    string  `json:"account" validate:"required,oneof=gift_card store_credit"`
+	Code       string  `json:"code"`
+	CustomerID int64   `json:"customer_id"`
+	Amount     float64 `json:"amount" validate:"gte=0"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	// AddressKindShipping is the kind of the addresses orders are delivered to
+	AddressKindShipping = "shipping"
+	// AddressKindBilling is the kind of the addresses orders are invoiced to
+	AddressKindBilling = "billing"
+)
+
+// Customer represent a shopper with an account and its address book
+type Customer struct {
+	ID        int64      `json:"id"`
+	Email     string     `json:"email" validate:"required,email,max=255"`
+	Name      string     `json:"name" validate:"required,max=100"`
+	Phone     string     `json:"phone" validate:"max=20"`
+	Addresses []*Address `json:"addresses"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Address represent a saved shipping or billing address of a customer.
+// A customer has at most one default address of each kind.
+type Address struct {
+	ID         int64     `json:"id"`
+	CustomerID int64     `json:"customer_id"`
+	Kind       string    `json:"kind" validate:"required,oneof=shipping billing"`
+	Recipient  string    `json:"recipient" validate:"required,max=100"`
+	Line1      string    `json:"line1" validate:"required,max=255"`
+	Line2      string    `json:"line2" validate:"max=255"`
+	City       string    `json:"city" validate:"required,max=100"`
+	PostalCode string    `json:"postal_code" validate:"required,max=20"`
+	Country    string    `json:"country" validate:"required,len=2"`
+	Phone      string    `json:"phone" validate:"max=20"`
+	IsDefault  bool      `json:"is_default"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// OrderPage represent a page of the order history of a customer, NextCursor is empty on the last page
+type OrderPage struct {
+	Orders     []*Order `json:"orders"`
+	NextCursor string   `json:"next_cursor"`
+}
@@ -11,4 +11,14 @@ var (
 	ErrConflict = errors.New("Your Item already exist")
 	// ErrBadParamInput will throw if the given request-body or params is not valid
 	ErrBadParamInput = errors.New("Given Param is not valid")
+	// ErrPaymentDeclined will throw if the payment provider refuses the payment
+	ErrPaymentDeclined = errors.New("Your payment was declined")
+	// ErrInsufficientBalance will throw if a gift card or store credit cannot cover the requested amount
+	ErrInsufficientBalance = errors.New("Insufficient balance")
+	// ErrOutOfStock will throw if there are not enough items in stock for the requested quantity
+	ErrOutOfStock = errors.New("Not enough items in stock")
+	// ErrUnauthorized will throw if the action needs an authenticated caller
+	ErrUnauthorized = errors.New("Authentication required")
+	// ErrForbidden will throw if the authenticated caller is not allowed to perform the action
+	ErrForbidden = errors.New("You are not allowed to perform this action")
 )
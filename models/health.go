@@ -0,0 +1,26 @@
+package models
+
+const (
+	// HealthStatusOK is the status of a service ready to take traffic
+	HealthStatusOK = "ok"
+	// HealthStatusUnavailable is the status of a service which cannot take traffic
+	HealthStatusUnavailable = "unavailable"
+)
+
+// HealthCheck represent the result of one of the checks of the readiness, such as the database ping
+type HealthCheck struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// Readiness represent whether the service can take traffic and the checks it is based on
+type Readiness struct {
+	Status string         `json:"status"`
+	Checks []*HealthCheck `json:"checks"`
+}
+
+// Ready tells whether every check passed
+func (r *Readiness) Ready() bool {
+	return r.Status == HealthStatusOK
+}
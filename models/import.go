@@ -0,0 +1,30 @@
+package models
+
+const (
+	// ImportRowCreated is the status of a row that added an item
+	ImportRowCreated = "created"
+	// ImportRowUpdated is the status of a row that changed an existing item
+	ImportRowUpdated = "updated"
+	// ImportRowRejected is the status of a row that was not imported
+	ImportRowRejected = "rejected"
+	// ImportRowSkipped is the status of a row of an archived item, which an import does not bring back
+	ImportRowSkipped = "skipped"
+)
+
+// ImportRow represent the outcome of one line of a catalog import
+type ImportRow struct {
+	Line   int    `json:"line"`
+	SKU    string `json:"sku"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ImportReport represent the outcome of a catalog import, nothing is saved by a dry run
+type ImportReport struct {
+	DryRun   bool         `json:"dry_run"`
+	Created  int          `json:"created"`
+	Updated  int          `json:"updated"`
+	Rejected int          `json:"rejected"`
+	Skipped  int          `json:"skipped"`
+	Rows     []*ImportRow `json:"rows"`
+}
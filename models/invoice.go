@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+// Invoice represent the invoice model issued for an order
+type Invoice struct {
+	ID        int64           `json:"id"`
+	OrderID   int64           `json:"order_id" validate:"required"`
+	Number    string          `json:"number"`
+	Order     *Order          `json:"order"`
+	Details   []*OrderDetails `json:"details"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	CreatedAt time.Time       `json:"created_at"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+)
+
+// CatalogItem represent an item as shown to shoppers, with its stock, the promotion it is sold with and its tax rule.
+// Both are the most specific active ones: the item's own, then its product's, then the one of its
+// closest category and last the one of one of its tags.
+// A kit lists its components and its stock is the number of kits their stock allows to sell.
+type CatalogItem struct {
+	ID                int64           `json:"id"`
+	ProductID         int64           `json:"product_id"`
+	SKU               string          `json:"sku"`
+	Name              string          `json:"name"`
+	Price             float64         `json:"price"`
+	InventoryQuantity int64           `json:"inventory_quantity"`
+	Attributes        []*Attribute    `json:"attributes"`
+	Categories        []*Category     `json:"categories"`
+	Tags              []string        `json:"tags"`
+	Components        []*KitComponent `json:"components"`
+	Available         bool            `json:"available"`
+	Promotion         *Promotions     `json:"promotion"`
+	TaxRule           *TaxRule        `json:"tax_rule"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+	CreatedAt         time.Time       `json:"created_at"`
+}
+
+// ItemPage represent a page of the catalog, NextCursor is empty on the last page
+type ItemPage struct {
+	Items      []*CatalogItem `json:"items"`
+	NextCursor string         `json:"next_cursor"`
+}
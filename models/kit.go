@@ -0,0 +1,33 @@
+package models
+
+// KitComponent represent an item sold as part of a kit, every kit holds Quantity units of it.
+// SKU, Name and InventoryQuantity are those of the component item.
+type KitComponent struct {
+	KitID             int64  `json:"kit_id"`
+	ItemsID           int64  `json:"items_id" validate:"required"`
+	SKU               string `json:"sku"`
+	Name              string `json:"name"`
+	Quantity          int64  `json:"quantity" validate:"required,min=1"`
+	InventoryQuantity int64  `json:"inventory_quantity"`
+}
+
+// KitStock returns the number of kits the stock of their components allows to sell
+func KitStock(components []*KitComponent) int64 {
+	res := int64(-1)
+	for _, c := range components {
+		if c.Quantity <= 0 {
+			continue
+		}
+		n := c.InventoryQuantity / c.Quantity
+		if n < 0 {
+			n = 0
+		}
+		if res < 0 || n < res {
+			res = n
+		}
+	}
+	if res < 0 {
+		return 0
+	}
+	return res
+}
@@ -1,53 +1,157 @@
 package models
 
 import (
+	"math"
+	"strconv"
 	"time"
 )
 
-// Order represent the order model
+// OrderDetails represent an order line. The component lines of a kit are priced at zero and point to
+// the kit line with ParentID. ListPrice is the catalog price of the units of the line when the order was placed,
+// nil for the lines placed before it was recorded, Price what was charged for them before tax and TaxAmount the
+// tax charged on Price.
 type OrderDetails struct {
 	ID        int64     `json:"id"`
 	OrderID   int64     `json:"order_id" validate:"required"`
+	ParentID  int64     `json:"parent_id"`
+	ItemsID   int64     `json:"items_id"`
 	SKU       string    `json:"sku" validate:"required"`
 	Name      string    `json:"name" validate:"required"`
 	Price     float64   `json:"price" validate:"required"`
+	ListPrice *float64  `json:"list_price"`
+	TaxAmount float64   `json:"tax_amount"`
 	Quantity  int64     `json:"quantity" validate:"required"`
 	PromoType string    `json:"promo_type"`
 	UpdatedAt time.Time `json:"updated_at"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
-// Order represent the order model
+const (
+	// OrderStatusPending is the status of an order waiting for payment
+	OrderStatusPending = "pending"
+	// OrderStatusPaid is the status of an order whose payment was captured
+	OrderStatusPaid = "paid"
+)
+
+// Order represent the order model, an order placed without an account has no CustomerID.
+// TotalPrice includes TaxAmount, the tax of all its lines. Details are only loaded with the order history of a customer.
 type Order struct {
-	ID         int64     `json:"id"`
-	TotalPrice float64   `json:"total_price" validate:"required"`
-	UpdatedAt  time.Time `json:"updated_at"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID                int64           `json:"id"`
+	CustomerID        int64           `json:"customer_id"`
+	ShippingAddressID int64           `json:"shipping_address_id"`
+	BillingAddressID  int64           `json:"billing_address_id"`
+	TotalPrice        float64         `json:"total_price" validate:"required"`
+	TaxAmount         float64         `json:"tax_amount"`
+	CreditAmount      float64         `json:"credit_amount"`
+	Status            string          `json:"status"`
+	Details           []*OrderDetails `json:"details"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+	CreatedAt         time.Time       `json:"created_at"`
+}
+
+// Paid tells whether the payment of the order was captured
+func (o *Order) Paid() bool {
+	return o.Status == OrderStatusPaid
+}
+
+// AmountDue is the part of the total left to pay once gift cards and store credit have been applied
+func (o *Order) AmountDue() float64 {
+	return math.Round((o.TotalPrice-o.CreditAmount)*100) / 100
 }
 
-// Promotions represent the promotion model
+// Promotions represent the promotion model, a disabled promotion is no longer applied.
+// A promotion targets exactly one of a single item (ItemsID), every variant of a product (ProductID),
+// every item of a category and its subcategories (CategoryID) or every item with a tag (Tag).
 type Promotions struct {
 	ID                  int64  `json:"id"`
-	ItemsID             int64  `json:"items_id" validate:"required"`
-	PromoType           string `json:"promo_type" validate:"required"`
-	Promo               string `json:"promo" validate:"required"`
+	ItemsID             int64  `json:"items_id"`
+	ProductID           int64  `json:"product_id"`
+	CategoryID          int64  `json:"category_id"`
+	Tag                 string `json:"tag"`
+	PromoType           string `json:"promo_type" validate:"required,oneof=free_items bonus_price discount_items"`
+	Promo               string `json:"promo" validate:"required,numeric"`
 	QuantityRequirement int64  `json:"quantity_requirement" validate:"required"`
+	Active              bool   `json:"active"`
+}
+
+// Target returns what the promotion applies to
+func (p *Promotions) Target() Target {
+	return Target{ItemsID: p.ItemsID, ProductID: p.ProductID, CategoryID: p.CategoryID, Tag: p.Tag}
+}
+
+// LinePrice returns the price of quantity units of an item sold at unit price with the promotion applied
+func (p *Promotions) LinePrice(price float64, quantity int64) float64 {
+	if p == nil || p.QuantityRequirement <= 0 {
+		return price * float64(quantity)
+	}
+	promoValue, _ := strconv.ParseFloat(p.Promo, 64)
+	switch p.PromoType {
+	case "bonus_price":
+		return price*float64(quantity%p.QuantityRequirement) + promoValue*math.Floor(float64(quantity)/float64(p.QuantityRequirement))
+	case "discount_items":
+		if p.QuantityRequirement >= quantity {
+			return price * float64(quantity) * promoValue
+		}
+	}
+	return price * float64(quantity)
+}
+
+// FreeQuantity returns the number of free units earned by buying quantity units
+func (p *Promotions) FreeQuantity(quantity int64) int64 {
+	if p == nil || p.PromoType != "free_items" || p.QuantityRequirement <= 0 {
+		return 0
+	}
+	return int64(math.Floor(float64(quantity) / float64(p.QuantityRequirement)))
 }
 
+// Items represent a sellable SKU, either standalone or a variant of a product when ProductID is set
 type Items struct {
-	ID                int64     `json:"id"`
-	SKU               string    `json:"sku" validate:"required"`
-	Name              string    `json:"name" validate:"required"`
-	Price             float64   `json:"price" validate:"required"`
-	InventoryQuantity int64     `json:"inventory_quantity" validate:"required"`
-	UpdatedAt         time.Time `json:"updated_at"`
-	CreatedAt         time.Time `json:"created_at"`
+	ID                int64        `json:"id"`
+	ProductID         int64        `json:"product_id"`
+	SKU               string       `json:"sku" validate:"required,max=10"`
+	Name              string       `json:"name" validate:"required"`
+	Price             float64      `json:"price" validate:"required"`
+	InventoryQuantity int64        `json:"inventory_quantity" validate:"required"`
+	Attributes        []*Attribute `json:"attributes" validate:"dive"`
+	Archived          bool         `json:"archived"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+	CreatedAt         time.Time    `json:"created_at"`
 }
 
+// Cart represent a line of the cart of a customer, or of an anonymous session when CustomerID is zero
 type Cart struct {
-	ID        int64     `json:"id"`
-	ItemsID   int64     `json:"items_id" validate:"required"`
-	Quantity  int64     `json:"quantity" validate:"required"`
-	UpdatedAt time.Time `json:"updated_at"`
-	CreatedAt time.Time `json:"created_at"`
+	ID           int64     `json:"id"`
+	CustomerID   int64     `json:"customer_id"`
+	SessionToken string    `json:"-"`
+	ItemsID      int64     `json:"items_id" validate:"required"`
+	Quantity     int64     `json:"quantity" validate:"required"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// CartOwner represent whose cart it is, the customer when signed in and else the anonymous session
+type CartOwner struct {
+	CustomerID   int64
+	SessionToken string
+}
+
+// CartMergeResult represent the cart of a customer after the guest cart of their session was merged into it
+type CartMergeResult struct {
+	Carts   []*Cart        `json:"carts"`
+	Skipped []*SkippedLine `json:"skipped"`
+}
+
+// ReorderResult represent the outcome of copying a past order into the cart
+type ReorderResult struct {
+	OrderID int64          `json:"order_id"`
+	Carts   []*Cart        `json:"carts"`
+	Skipped []*SkippedLine `json:"skipped"`
+}
+
+// SkippedLine represent an order or guest cart line that could not be copied into the cart
+type SkippedLine struct {
+	SKU      string `json:"sku"`
+	Name     string `json:"name"`
+	Quantity int64  `json:"quantity"`
+	Reason   string `json:"reason"`
 }
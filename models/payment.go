@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	// PaymentStatusPending is the status of an intent that was not sent to the provider yet
+	PaymentStatusPending = "pending"
+	// PaymentStatusProcessing is the status of an intent claimed by a payment attempt talking to the provider
+	PaymentStatusProcessing = "processing"
+	// PaymentStatusAuthorized is the status of an intent whose amount is held by the provider
+	PaymentStatusAuthorized = "authorized"
+	// PaymentStatusCaptured is the status of an intent whose amount was collected
+	PaymentStatusCaptured = "captured"
+	// PaymentStatusDeclined is the status of an intent refused by the provider
+	PaymentStatusDeclined = "declined"
+	// PaymentStatusFailed is the status of an intent that could not reach the provider or be captured
+	PaymentStatusFailed = "failed"
+	// PaymentStatusVoided is the status of an authorization released without capture
+	PaymentStatusVoided = "voided"
+	// PaymentStatusRefunded is the status of an intent whose captured amount was fully given back
+	PaymentStatusRefunded = "refunded"
+)
+
+// PaymentIntent represent the payment of an order with a provider
+type PaymentIntent struct {
+	ID             int64     `json:"id"`
+	OrderID        int64     `json:"order_id" validate:"required"`
+	Provider       string    `json:"provider" validate:"required"`
+	ProviderRef    string    `json:"provider_ref"`
+	Amount         float64   `json:"amount"`
+	RefundedAmount float64   `json:"refunded_amount"`
+	Status         string    `json:"status"`
+	LastError      string    `json:"last_error"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+const (
+	// PaymentRefundStatusPending is the status of a refund sent to the provider without an answer yet
+	PaymentRefundStatusPending = "pending"
+	// PaymentRefundStatusSucceeded is the status of a refund the provider gave back
+	PaymentRefundStatusSucceeded = "succeeded"
+	// PaymentRefundStatusFailed is the status of a refund the provider refused
+	PaymentRefundStatusFailed = "failed"
+)
+
+// PaymentRefund represent an amount given back through the provider on a payment intent.
+// ProviderRefundID is the id the provider knows the refund by, the notifications of the refund carry it.
+// Key is the idempotency key the refund was sent with, if any.
+type PaymentRefund struct {
+	ID               int64     `json:"id"`
+	IntentID         int64     `json:"intent_id"`
+	Key              string    `json:"key"`
+	ProviderRefundID string    `json:"provider_refund_id"`
+	Amount           float64   `json:"amount"`
+	Status           string    `json:"status"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+const (
+	// PaymentEventAuthorized notifies that the provider holds the amount
+	PaymentEventAuthorized = "payment.authorized"
+	// PaymentEventCaptured notifies that the amount was collected
+	PaymentEventCaptured = "payment.captured"
+	// PaymentEventDeclined notifies that the payment was refused
+	PaymentEventDeclined = "payment.declined"
+	// PaymentEventFailed notifies that the payment could not be processed
+	PaymentEventFailed = "payment.failed"
+	// PaymentEventVoided notifies that the authorization was released
+	PaymentEventVoided = "payment.voided"
+	// PaymentEventRefunded notifies that part or all of the captured amount was given back by the refund RefundID
+	PaymentEventRefunded = "payment.refunded"
+)
+
+// PaymentEvent represent an asynchronous notification sent by a payment provider
+type PaymentEvent struct {
+	ID          int64     `json:"-"`
+	Provider    string    `json:"-"`
+	EventID     string    `json:"id" validate:"required"`
+	Type        string    `json:"type" validate:"required"`
+	ProviderRef string    `json:"provider_ref" validate:"required"`
+	RefundID    string    `json:"refund_id"`
+	Amount      float64   `json:"amount"`
+	Reason      string    `json:"reason"`
+	CreatedAt   time.Time `json:"-"`
+}
@@ -0,0 +1,11 @@
+package models
+
+// Principal represent the authenticated caller of a request
+type Principal struct {
+	// Subject is the sub claim of the token
+	Subject string
+	// CustomerID is the customer the token was issued to, zero when the subject is not a customer
+	CustomerID int64
+	// Roles are taken from the roles claim, they grant the permissions of the auth package
+	Roles []string
+}
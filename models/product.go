@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// Product represent a parent product, the items sold under it are its variants
+type Product struct {
+	ID          int64          `json:"id"`
+	Name        string         `json:"name" validate:"required,max=100"`
+	Description string         `json:"description"`
+	Promotion   *Promotions    `json:"promotion"`
+	Variants    []*CatalogItem `json:"variants"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// Attribute represent what tells the variants of a product apart, such as their size or color
+type Attribute struct {
+	Name  string `json:"name" validate:"required"`
+	Value string `json:"value" validate:"required"`
+}
+
+// ProductPage represent a page of products, NextCursor is empty on the last page
+type ProductPage struct {
+	Products   []*Product `json:"products"`
+	NextCursor string     `json:"next_cursor"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+)
+
+// SKUSales represent the units and revenue of a SKU on a day
+type SKUSales struct {
+	Day     time.Time `json:"day"`
+	SKU     string    `json:"sku"`
+	Name    string    `json:"name"`
+	Units   int64     `json:"units"`
+	Revenue float64   `json:"revenue"`
+}
+
+// PromotionDiscount represent the discount given by a promotion type over the order lines sold with it, the
+// paid and gift lines of free_items alike. Discount is valued at the catalog price of the items when the
+// orders were placed.
+type PromotionDiscount struct {
+	PromoType string  `json:"promo_type"`
+	Lines     int64   `json:"lines"`
+	Units     int64   `json:"units"`
+	Discount  float64 `json:"discount"`
+}
+
+// OrderSummary represent the order count and value over a period, Revenue includes Tax
+type OrderSummary struct {
+	Orders            int64   `json:"orders"`
+	Revenue           float64 `json:"revenue"`
+	Tax               float64 `json:"tax"`
+	AverageOrderValue float64 `json:"average_order_value"`
+}
+
+// TopItem represent an item ranked by units sold
+type TopItem struct {
+	SKU     string  `json:"sku"`
+	Name    string  `json:"name"`
+	Units   int64   `json:"units"`
+	Revenue float64 `json:"revenue"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	// ReturnStatusRequested is the status of a return waiting for review
+	ReturnStatusRequested = "requested"
+	// ReturnStatusApproved is the status of a return that has been restocked and refunded
+	ReturnStatusApproved = "approved"
+	// ReturnStatusRejected is the status of a return that has been declined
+	ReturnStatusRejected = "rejected"
+)
+
+const (
+	// RefundStatusPending is the status of a refund whose payment part was not given back by the provider yet
+	RefundStatusPending = "pending"
+	// RefundStatusCompleted is the status of a refund given back in full
+	RefundStatusCompleted = "completed"
+	// RefundStatusFailed is the status of a refund whose payment part the provider refused, it can be sent again
+	RefundStatusFailed = "failed"
+)
+
+// ReturnRequest represent the return merchandise authorization model
+type ReturnRequest struct {
+	ID           int64            `json:"id"`
+	OrderID      int64            `json:"order_id" validate:"required"`
+	Status       string           `json:"status"`
+	Reason       string           `json:"reason"`
+	RefundAmount float64          `json:"refund_amount"`
+	Details      []*ReturnDetails `json:"details"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
+
+// ReturnDetails represent a returned order line
+type ReturnDetails struct {
+	ID             int64     `json:"id"`
+	ReturnID       int64     `json:"return_id"`
+	OrderDetailsID int64     `json:"order_details_id" validate:"required"`
+	SKU            string    `json:"sku"`
+	Quantity       int64     `json:"quantity" validate:"required"`
+	RefundAmount   float64   `json:"refund_amount"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Refund represent the money given back for an approved return.
+// CreditAmount is the part of Amount restored to the gift cards and store credit that paid for the order,
+// as recorded in the credit ledger, PaymentAmount the rest refunded by the payment provider.
+// Status tells whether the provider gave PaymentAmount back yet.
+type Refund struct {
+	ID            int64     `json:"id"`
+	OrderID       int64     `json:"order_id" validate:"required"`
+	ReturnID      int64     `json:"return_id" validate:"required"`
+	Amount        float64   `json:"amount"`
+	CreditAmount  float64   `json:"credit_amount"`
+	PaymentAmount float64   `json:"payment_amount"`
+	Status        string    `json:"status"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+// SearchDocument represent what an item can be found by
+type SearchDocument struct {
+	ItemsID int64    `json:"items_id"`
+	SKU     string   `json:"sku"`
+	Name    string   `json:"name"`
+	Tags    []string `json:"tags"`
+}
+
+// SearchHit represent an item matching a search, a higher score is more relevant
+type SearchHit struct {
+	ItemsID int64   `json:"items_id"`
+	Score   float64 `json:"score"`
+}
@@ -0,0 +1,65 @@
+package models
+
+// Target is what a promotion or a tax rule applies to, exactly one of its fields is set:
+// a single item, every variant of a product, every item of a category and its subcategories or every item with a tag.
+type Target struct {
+	ItemsID    int64
+	ProductID  int64
+	CategoryID int64
+	Tag        string
+}
+
+// rank orders the targets matching an item from the most specific one down: the item itself, then its product,
+// then its categories, the closest ones first, and last its tags. depth gives the depth of the categories.
+func (t Target) rank(depth map[int64]int) int {
+	switch {
+	case t.ItemsID != 0:
+		return 3 << 16
+	case t.ProductID != 0:
+		return 2 << 16
+	case t.CategoryID != 0:
+		return 1<<16 + depth[t.CategoryID]
+	}
+	return 0
+}
+
+// precedes tells whether a target applies before another one, ties go to the oldest, of lowest id
+func (t Target) precedes(id int64, other Target, otherID int64, depth map[int64]int) bool {
+	r, o := t.rank(depth), other.rank(depth)
+	return r > o || (r == o && id < otherID)
+}
+
+// CategoryDepths returns the depth in the category tree of the given categories and of their ancestors,
+// 1 for a root category. A category promotion or tax rule is closer to an item the deeper its category is.
+func CategoryDepths(categories []*Category) map[int64]int {
+	res := make(map[int64]int)
+	for _, c := range categories {
+		lineage := c.Lineage()
+		for i, id := range lineage {
+			res[id] = len(lineage) - i
+		}
+	}
+	return res
+}
+
+// SelectPromotion returns among the promotions matching an item the one checkout applies, nil when there is none
+func SelectPromotion(matching []*Promotions, depth map[int64]int) *Promotions {
+	var res *Promotions
+	for _, p := range matching {
+		if res == nil || p.Target().precedes(p.ID, res.Target(), res.ID, depth) {
+			res = p
+		}
+	}
+	return res
+}
+
+// SelectTaxRule returns among the tax rules matching an item the one applied to it, nil when there is none
+func SelectTaxRule(matching []*TaxRule, depth map[int64]int) *TaxRule {
+	var res *TaxRule
+	for _, r := range matching {
+		if res == nil || r.Target().precedes(r.ID, res.Target(), res.ID, depth) {
+			res = r
+		}
+	}
+	return res
+}
@@ -0,0 +1,21 @@
+package models
+
+// TaxRule represent the tax rate of the items it targets, a disabled tax rule is no longer applied.
+// Like a promotion a tax rule targets exactly one of a single item (ItemsID), every variant of a product
+// (ProductID), every item of a category and its subcategories (CategoryID) or every item with a tag (Tag),
+// and the most specific one matching an item applies. Rate is a fraction of the price, 0.11 for 11%.
+type TaxRule struct {
+	ID         int64   `json:"id"`
+	Name       string  `json:"name" validate:"required,max=100"`
+	Rate       float64 `json:"rate" validate:"gte=0,lte=1"`
+	ItemsID    int64   `json:"items_id"`
+	ProductID  int64   `json:"product_id"`
+	CategoryID int64   `json:"category_id"`
+	Tag        string  `json:"tag"`
+	Active     bool    `json:"active"`
+}
+
+// Target returns what the tax rule applies to
+func (r *TaxRule) Target() Target {
+	return Target{ItemsID: r.ItemsID, ProductID: r.ProductID, CategoryID: r.CategoryID, Tag: r.Tag}
+}
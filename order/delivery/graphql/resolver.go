@@ -1,13 +1,13 @@
 package graphql
 
 import (
-	"context"
 	"fmt"
 	"math"
-	"strconv"
 	"time"
 
 	"github.com/graphql-go/graphql"
+	"github.com/williamchand/kuncie-cart/auth"
+	"github.com/williamchand/kuncie-cart/metrics"
 	"github.com/williamchand/kuncie-cart/models"
 	"github.com/williamchand/kuncie-cart/order"
 )
@@ -16,6 +16,9 @@ type Resolver interface {
 	Placeholder(params graphql.ResolveParams) (interface{}, error)
 	AddCart(params graphql.ResolveParams) (interface{}, error)
 	ConfirmOrder(params graphql.ResolveParams) (interface{}, error)
+	Reorder(params graphql.ResolveParams) (interface{}, error)
+	MyOrders(params graphql.ResolveParams) (interface{}, error)
+	MergeCart(params graphql.ResolveParams) (interface{}, error)
 }
 
 type resolver struct {
@@ -26,9 +29,7 @@ func (r resolver) Placeholder(params graphql.ResolveParams) (interface{}, error)
 	return "", nil
 }
 func (r resolver) ConfirmOrder(params graphql.ResolveParams) (interface{}, error) {
-	var ()
-
-	ctx := context.Background()
+	ctx := params.Context
 	carts, err := r.orderService.GetCart(ctx)
 	if len(carts) == 0 {
 		return nil, fmt.Errorf("cart is empty")
@@ -64,34 +65,35 @@ func (r resolver) ConfirmOrder(params graphql.ResolveParams) (interface{}, error
 				}
 			}
 			price := item_detail[0].Price * float64(carts[i].Quantity)
+			// the paid line is tagged with the promotion that adds its gift line
+			promo_type := ""
+			if promotion_quantity > 0 {
+				promo_type = promotion.PromoType
+			}
 			order = append(order, &models.OrderDetails{
+				ItemsID:   item_detail[0].ID,
 				SKU:       item_detail[0].SKU,
 				Name:      item_detail[0].Name,
 				Price:     price,
+				ListPrice: &price,
 				Quantity:  carts[i].Quantity,
-				PromoType: "",
+				PromoType: promo_type,
 				CreatedAt: time.Now(),
 				UpdatedAt: time.Now(),
 			})
 		} else {
-			promoValue, _ := strconv.ParseFloat(promotion.Promo, 64)
-			price := 0.0
-			if promotion.PromoType == "bonus_price" {
-				price = item_detail[0].Price*float64(carts[i].Quantity%promotion.QuantityRequirement) + promoValue*math.Floor(float64(carts[i].Quantity)/float64(promotion.QuantityRequirement))
-			} else {
-				price = item_detail[0].Price * float64(carts[i].Quantity)
-				if promotion.PromoType == "discount_items" && promotion.QuantityRequirement >= carts[i].Quantity {
-					price = price * promoValue
-				}
-			}
+			price := promotion.LinePrice(item_detail[0].Price, carts[i].Quantity)
+			listPrice := item_detail[0].Price * float64(carts[i].Quantity)
 			promo_type := ""
 			if promotion.QuantityRequirement <= carts[i].Quantity {
 				promo_type = promotion.PromoType
 			}
 			order = append(order, &models.OrderDetails{
+				ItemsID:   item_detail[0].ID,
 				SKU:       item_detail[0].SKU,
 				Name:      item_detail[0].Name,
 				Price:     price,
+				ListPrice: &listPrice,
 				Quantity:  carts[i].Quantity,
 				PromoType: promo_type,
 				CreatedAt: time.Now(),
@@ -105,10 +107,13 @@ func (r resolver) ConfirmOrder(params graphql.ResolveParams) (interface{}, error
 		if err != nil {
 			return nil, err
 		}
+		listPrice := item_detail[0].Price * float64(promotion_carts[i].Quantity)
 		order = append(order, &models.OrderDetails{
+			ItemsID:   item_detail[0].ID,
 			SKU:       item_detail[0].SKU,
 			Name:      item_detail[0].Name,
 			Price:     0.0,
+			ListPrice: &listPrice,
 			Quantity:  promotion_carts[i].Quantity,
 			PromoType: "free_items",
 			CreatedAt: time.Now(),
@@ -118,38 +123,33 @@ func (r resolver) ConfirmOrder(params graphql.ResolveParams) (interface{}, error
 	if err != nil {
 		return nil, err
 	}
+	tenders, err := parseTenders(params.Args["payments"])
+	if err != nil {
+		return nil, err
+	}
+	// guests check out without a customer, the address book is only reachable when signed in
+	customerID := int64(0)
+	if p, ok := auth.FromContext(ctx); ok {
+		customerID = p.CustomerID
+	}
+	shippingAddressID, _ := params.Args["shipping_address_id"].(int)
+	billingAddressID, _ := params.Args["billing_address_id"].(int)
 	createOrder := &models.Order{
-		TotalPrice: 0.0,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
+		CustomerID:        customerID,
+		ShippingAddressID: int64(shippingAddressID),
+		BillingAddressID:  int64(billingAddressID),
+		TotalPrice:        0.0,
+		Status:            models.OrderStatusPending,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
 	}
 	for i := range order {
 		createOrder.TotalPrice += order[i].Price
 	}
 
-	if err := r.orderService.CreateOrder(ctx, createOrder); err != nil {
+	if err := r.orderService.PlaceOrder(ctx, createOrder, order, tenders); err != nil {
 		return nil, err
 	}
-
-	for i := range order {
-		order[i].OrderID = createOrder.ID
-		if err := r.orderService.CreateOrderDetails(ctx, order[i]); err != nil {
-			return nil, err
-		}
-	}
-	if err := r.orderService.DeleteCart(ctx); err != nil {
-		return nil, err
-	}
-	for i := range order {
-		item_update := &models.Items{
-			SKU:               order[i].SKU,
-			InventoryQuantity: order[i].Quantity,
-			UpdatedAt:         time.Now(),
-		}
-		if err := r.orderService.UpdateItems(ctx, item_update); err != nil {
-			return nil, err
-		}
-	}
 	return *createOrder, nil
 }
 
@@ -160,7 +160,7 @@ func (r resolver) AddCart(params graphql.ResolveParams) (interface{}, error) {
 		ok       bool
 	)
 
-	ctx := context.Background()
+	ctx := params.Context
 
 	if sku, ok = params.Args["sku"].(string); !ok || sku == "" {
 		return nil, fmt.Errorf("sku is empty or not string")
@@ -241,6 +241,7 @@ func (r resolver) AddCart(params graphql.ResolveParams) (interface{}, error) {
 		for j := range carts {
 			if items_availability[i].ID == carts[j].ItemsID {
 				if items_availability[i].InventoryQuantity < carts[j].Quantity {
+					metrics.OutOfStockRejections.WithLabelValues("AddCart").Inc()
 					return nil, fmt.Errorf("cannot add the items")
 				}
 				break
@@ -267,6 +268,73 @@ func (r resolver) AddCart(params graphql.ResolveParams) (interface{}, error) {
 	return *cartsAns, nil
 }
 
+func (r resolver) Reorder(params graphql.ResolveParams) (interface{}, error) {
+	orderID, ok := params.Args["order_id"].(int)
+	if !ok || orderID == 0 {
+		return nil, fmt.Errorf("order_id is not integer or zero")
+	}
+
+	res, err := r.orderService.Reorder(params.Context, int64(orderID))
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func (r resolver) MyOrders(params graphql.ResolveParams) (interface{}, error) {
+	customerID, err := auth.CustomerID(params.Context)
+	if err != nil {
+		return nil, err
+	}
+	cursor, _ := params.Args["cursor"].(string)
+	limit, _ := params.Args["limit"].(int)
+
+	res, err := r.orderService.MyOrders(params.Context, customerID, cursor, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func (r resolver) MergeCart(params graphql.ResolveParams) (interface{}, error) {
+	res, err := r.orderService.MergeCart(params.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func parseTenders(arg interface{}) ([]*models.Tender, error) {
+	payments, _ := arg.([]interface{})
+	tenders := make([]*models.Tender, 0, len(payments))
+	for i := range payments {
+		payment, ok := payments[i].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("payment %d is not valid", i)
+		}
+		t := &models.Tender{}
+		t.Account, _ = payment["account"].(string)
+		t.Code, _ = payment["code"].(string)
+		if customerID, ok := payment["customer_id"].(int); ok {
+			t.CustomerID = int64(customerID)
+		}
+		t.Amount, _ = payment["amount"].(float64)
+		switch {
+		case t.Account == models.CreditAccountGiftCard && t.Code == "":
+			return nil, fmt.Errorf("payment %d needs the gift card code", i)
+		case t.Account != models.CreditAccountGiftCard && t.Account != models.CreditAccountStoreCredit:
+			return nil, fmt.Errorf("payment %d account is not gift_card or store_credit", i)
+		case t.Amount < 0:
+			return nil, fmt.Errorf("payment %d amount is negative", i)
+		}
+		tenders = append(tenders, t)
+	}
+	return tenders, nil
+}
+
 func NewResolver(orderService order.Usecase) Resolver {
 	return &resolver{
 		orderService: orderService,
@@ -2,6 +2,42 @@ package graphql
 
 import "github.com/graphql-go/graphql"
 
+// OrderDetailsGraphQL holds order line information with graphql object
+var OrderDetailsGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "OrderDetails",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"parent_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"items_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"sku": &graphql.Field{
+				Type: graphql.String,
+			},
+			"name": &graphql.Field{
+				Type: graphql.String,
+			},
+			"price": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"tax_amount": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"quantity": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"promo_type": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+	},
+)
+
 // OrderGraphQL holds order information with graphql object
 var OrderGraphQL = graphql.NewObject(
 	graphql.ObjectConfig{
@@ -10,9 +46,30 @@ var OrderGraphQL = graphql.NewObject(
 			"id": &graphql.Field{
 				Type: graphql.Int,
 			},
+			"customer_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"shipping_address_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"billing_address_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"details": &graphql.Field{
+				Type: graphql.NewList(OrderDetailsGraphQL),
+			},
 			"total_price": &graphql.Field{
 				Type: graphql.Float,
 			},
+			"tax_amount": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"credit_amount": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"status": &graphql.Field{
+				Type: graphql.String,
+			},
 			"updated_at": &graphql.Field{
 				Type: graphql.DateTime,
 			},
@@ -23,6 +80,45 @@ var OrderGraphQL = graphql.NewObject(
 	},
 )
 
+// OrderPageGraphQL holds a page of the order history of a customer with graphql object
+var OrderPageGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "OrderPage",
+		Fields: graphql.Fields{
+			"orders": &graphql.Field{
+				Type: graphql.NewList(OrderGraphQL),
+			},
+			"next_cursor": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+	},
+)
+
+// TenderInput holds a gift card or store credit used to pay at checkout
+var TenderInput = graphql.NewInputObject(
+	graphql.InputObjectConfig{
+		Name: "TenderInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"account": &graphql.InputObjectFieldConfig{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "gift_card or store_credit",
+			},
+			"code": &graphql.InputObjectFieldConfig{
+				Type: graphql.String,
+			},
+			"customer_id": &graphql.InputObjectFieldConfig{
+				Type:        graphql.Int,
+				Description: "deprecated, store credit is always the one of the signed in customer",
+			},
+			"amount": &graphql.InputObjectFieldConfig{
+				Type:        graphql.Float,
+				Description: "as much of the balance as needed when omitted",
+			},
+		},
+	},
+)
+
 // CartGraphQL holds order information with graphql object
 var CartGraphQL = graphql.NewObject(
 	graphql.ObjectConfig{
@@ -47,6 +143,60 @@ var CartGraphQL = graphql.NewObject(
 	},
 )
 
+// SkippedLineGraphQL holds order line that could not be copied into the cart with graphql object
+var SkippedLineGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "SkippedLine",
+		Fields: graphql.Fields{
+			"sku": &graphql.Field{
+				Type: graphql.String,
+			},
+			"name": &graphql.Field{
+				Type: graphql.String,
+			},
+			"quantity": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"reason": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+	},
+)
+
+// ReorderGraphQL holds reorder result information with graphql object
+var ReorderGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Reorder",
+		Fields: graphql.Fields{
+			"order_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"carts": &graphql.Field{
+				Type: graphql.NewList(CartGraphQL),
+			},
+			"skipped": &graphql.Field{
+				Type: graphql.NewList(SkippedLineGraphQL),
+			},
+		},
+	},
+)
+
+// CartMergeGraphQL holds the cart of a customer after merging the guest cart with graphql object
+var CartMergeGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "CartMerge",
+		Fields: graphql.Fields{
+			"carts": &graphql.Field{
+				Type: graphql.NewList(CartGraphQL),
+			},
+			"skipped": &graphql.Field{
+				Type: graphql.NewList(SkippedLineGraphQL),
+			},
+		},
+	},
+)
+
 // Schema is struct which has method for Query and Mutation. Please init this struct using constructor function.
 type Schema struct {
 	orderResolver Resolver
@@ -63,6 +213,20 @@ func (s Schema) Query() *graphql.Object {
 				Args:        graphql.FieldConfigArgument{},
 				Resolve:     s.orderResolver.Placeholder,
 			},
+			"MyOrders": &graphql.Field{
+				Type:        OrderPageGraphQL,
+				Description: "Get the order history of the signed in customer, newest first. Pass the next_cursor of a page to get the following one",
+				Args: graphql.FieldConfigArgument{
+					"cursor": &graphql.ArgumentConfig{
+						Type: graphql.String,
+					},
+					"limit": &graphql.ArgumentConfig{
+						Type:         graphql.Int,
+						DefaultValue: 10,
+					},
+				},
+				Resolve: s.orderResolver.MyOrders,
+			},
 		},
 	}
 
@@ -81,6 +245,15 @@ func (s Schema) Mutation() *graphql.Object {
 					"placeholder": &graphql.ArgumentConfig{
 						Type: graphql.String,
 					},
+					"payments": &graphql.ArgumentConfig{
+						Type: graphql.NewList(TenderInput),
+					},
+					"shipping_address_id": &graphql.ArgumentConfig{
+						Type: graphql.Int,
+					},
+					"billing_address_id": &graphql.ArgumentConfig{
+						Type: graphql.Int,
+					},
 				},
 				Resolve: s.orderResolver.ConfirmOrder,
 			},
@@ -97,6 +270,21 @@ func (s Schema) Mutation() *graphql.Object {
 				},
 				Resolve: s.orderResolver.AddCart,
 			},
+			"Reorder": &graphql.Field{
+				Type:        ReorderGraphQL,
+				Description: "Copy the items of a past order into the cart",
+				Args: graphql.FieldConfigArgument{
+					"order_id": &graphql.ArgumentConfig{
+						Type: graphql.Int,
+					},
+				},
+				Resolve: s.orderResolver.Reorder,
+			},
+			"MergeCart": &graphql.Field{
+				Type:        CartMergeGraphQL,
+				Description: "Merge the guest cart of the session into the cart of the signed in customer, to call right after signing in",
+				Resolve:     s.orderResolver.MergeCart,
+			},
 		},
 	}
 
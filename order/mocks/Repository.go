@@ -13,13 +13,41 @@ type Repository struct {
 	mock.Mock
 }
 
-// Delete provides a mock function with given fields: ctx, id
-func (_m *Repository) Delete(ctx context.Context, id int64) error {
-	ret := _m.Called(ctx, id)
+// CreateCart provides a mock function with given fields: ctx, a
+func (_m *Repository) CreateCart(ctx context.Context, a *models.Cart) error {
+	ret := _m.Called(ctx, a)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Cart) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateOrder provides a mock function with given fields: ctx, a
+func (_m *Repository) CreateOrder(ctx context.Context, a *models.Order) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Order) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateOrderDetails provides a mock function with given fields: ctx, a
+func (_m *Repository) CreateOrderDetails(ctx context.Context, a *models.OrderDetails) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.OrderDetails) error); ok {
+		r0 = rf(ctx, a)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -27,13 +55,27 @@ func (_m *Repository) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, num
-func (_m *Repository) Fetch(ctx context.Context, cursor string, num int64) ([]*models.Order, string, error) {
-	ret := _m.Called(ctx, cursor, num)
+// DeleteCart provides a mock function with given fields: ctx, owner
+func (_m *Repository) DeleteCart(ctx context.Context, owner models.CartOwner) error {
+	ret := _m.Called(ctx, owner)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.CartOwner) error); ok {
+		r0 = rf(ctx, owner)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchOrders provides a mock function with given fields: ctx, customerID, cursor, num
+func (_m *Repository) FetchOrders(ctx context.Context, customerID int64, cursor string, num int64) ([]*models.Order, string, error) {
+	ret := _m.Called(ctx, customerID, cursor, num)
 
 	var r0 []*models.Order
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []*models.Order); ok {
-		r0 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, int64) []*models.Order); ok {
+		r0 = rf(ctx, customerID, cursor, num)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*models.Order)
@@ -41,15 +83,15 @@ func (_m *Repository) Fetch(ctx context.Context, cursor string, num int64) ([]*m
 	}
 
 	var r1 string
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
-		r1 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, int64) string); ok {
+		r1 = rf(ctx, customerID, cursor, num)
 	} else {
 		r1 = ret.Get(1).(string)
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
-		r2 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(2).(func(context.Context, int64, string, int64) error); ok {
+		r2 = rf(ctx, customerID, cursor, num)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -57,8 +99,100 @@ func (_m *Repository) Fetch(ctx context.Context, cursor string, num int64) ([]*m
 	return r0, r1, r2
 }
 
-// GetByID provides a mock function with given fields: ctx, id
-func (_m *Repository) GetByID(ctx context.Context, id int64) (*models.Order, error) {
+// GetCart provides a mock function with given fields: ctx, owner
+func (_m *Repository) GetCart(ctx context.Context, owner models.CartOwner) ([]*models.Cart, error) {
+	ret := _m.Called(ctx, owner)
+
+	var r0 []*models.Cart
+	if rf, ok := ret.Get(0).(func(context.Context, models.CartOwner) []*models.Cart); ok {
+		r0 = rf(ctx, owner)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Cart)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, models.CartOwner) error); ok {
+		r1 = rf(ctx, owner)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetItems provides a mock function with given fields: ctx, sku
+func (_m *Repository) GetItems(ctx context.Context, sku []string) ([]*models.Items, error) {
+	ret := _m.Called(ctx, sku)
+
+	var r0 []*models.Items
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*models.Items); ok {
+		r0 = rf(ctx, sku)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Items)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, sku)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetItemsById provides a mock function with given fields: ctx, id
+func (_m *Repository) GetItemsById(ctx context.Context, id []int64) ([]*models.Items, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []*models.Items
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []*models.Items); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Items)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetKitComponents provides a mock function with given fields: ctx, kitID
+func (_m *Repository) GetKitComponents(ctx context.Context, kitID []int64) ([]*models.KitComponent, error) {
+	ret := _m.Called(ctx, kitID)
+
+	var r0 []*models.KitComponent
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []*models.KitComponent); ok {
+		r0 = rf(ctx, kitID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.KitComponent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, kitID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrder provides a mock function with given fields: ctx, id
+func (_m *Repository) GetOrder(ctx context.Context, id int64) (*models.Order, error) {
 	ret := _m.Called(ctx, id)
 
 	var r0 *models.Order
@@ -80,13 +214,82 @@ func (_m *Repository) GetByID(ctx context.Context, id int64) (*models.Order, err
 	return r0, r1
 }
 
-// GetByTitle provides a mock function with given fields: ctx, title
-func (_m *Repository) GetByTitle(ctx context.Context, title string) (*models.Order, error) {
-	ret := _m.Called(ctx, title)
+// GetOrderDetails provides a mock function with given fields: ctx, orderID
+func (_m *Repository) GetOrderDetails(ctx context.Context, orderID int64) ([]*models.OrderDetails, error) {
+	ret := _m.Called(ctx, orderID)
+
+	var r0 []*models.OrderDetails
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*models.OrderDetails); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.OrderDetails)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPromotions provides a mock function with given fields: ctx, id
+func (_m *Repository) GetPromotions(ctx context.Context, id int64) (*models.Promotions, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.Promotions
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.Promotions); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Promotions)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTaxRule provides a mock function with given fields: ctx, id
+func (_m *Repository) GetTaxRule(ctx context.Context, id int64) (*models.TaxRule, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.TaxRule
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.TaxRule); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TaxRule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockOrder provides a mock function with given fields: ctx, id
+func (_m *Repository) LockOrder(ctx context.Context, id int64) (*models.Order, error) {
+	ret := _m.Called(ctx, id)
 
 	var r0 *models.Order
-	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Order); ok {
-		r0 = rf(ctx, title)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.Order); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.Order)
@@ -94,8 +297,8 @@ func (_m *Repository) GetByTitle(ctx context.Context, title string) (*models.Ord
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, title)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -103,12 +306,26 @@ func (_m *Repository) GetByTitle(ctx context.Context, title string) (*models.Ord
 	return r0, r1
 }
 
-// Store provides a mock function with given fields: ctx, a
-func (_m *Repository) Store(ctx context.Context, a *models.Order) error {
+// UpdateCart provides a mock function with given fields: ctx, a
+func (_m *Repository) UpdateCart(ctx context.Context, a *models.Cart) error {
 	ret := _m.Called(ctx, a)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.Order) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Cart) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateItems provides a mock function with given fields: ctx, a
+func (_m *Repository) UpdateItems(ctx context.Context, a *models.Items) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Items) error); ok {
 		r0 = rf(ctx, a)
 	} else {
 		r0 = ret.Error(0)
@@ -117,13 +334,13 @@ func (_m *Repository) Store(ctx context.Context, a *models.Order) error {
 	return r0
 }
 
-// Update provides a mock function with given fields: ctx, ar
-func (_m *Repository) Update(ctx context.Context, ar *models.Order) error {
-	ret := _m.Called(ctx, ar)
+// UpdateOrderStatus provides a mock function with given fields: ctx, a
+func (_m *Repository) UpdateOrderStatus(ctx context.Context, a *models.Order) error {
+	ret := _m.Called(ctx, a)
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(context.Context, *models.Order) error); ok {
-		r0 = rf(ctx, ar)
+		r0 = rf(ctx, a)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -13,13 +13,55 @@ type Usecase struct {
 	mock.Mock
 }
 
-// Delete provides a mock function with given fields: ctx, id
-func (_m *Usecase) Delete(ctx context.Context, id int64) error {
-	ret := _m.Called(ctx, id)
+// CreateCart provides a mock function with given fields: ctx, a
+func (_m *Usecase) CreateCart(ctx context.Context, a *models.Cart) error {
+	ret := _m.Called(ctx, a)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Cart) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateOrder provides a mock function with given fields: ctx, a
+func (_m *Usecase) CreateOrder(ctx context.Context, a *models.Order) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Order) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateOrderDetails provides a mock function with given fields: ctx, a
+func (_m *Usecase) CreateOrderDetails(ctx context.Context, a *models.OrderDetails) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.OrderDetails) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteCart provides a mock function with given fields: ctx
+func (_m *Usecase) DeleteCart(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -27,38 +69,77 @@ func (_m *Usecase) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, num
-func (_m *Usecase) Fetch(ctx context.Context, cursor string, num int64) ([]*models.Order, string, error) {
-	ret := _m.Called(ctx, cursor, num)
+// GetCart provides a mock function with given fields: ctx
+func (_m *Usecase) GetCart(ctx context.Context) ([]*models.Cart, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*models.Cart
+	if rf, ok := ret.Get(0).(func(context.Context) []*models.Cart); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Cart)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetItems provides a mock function with given fields: ctx, sku
+func (_m *Usecase) GetItems(ctx context.Context, sku []string) ([]*models.Items, error) {
+	ret := _m.Called(ctx, sku)
 
-	var r0 []*models.Order
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []*models.Order); ok {
-		r0 = rf(ctx, cursor, num)
+	var r0 []*models.Items
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*models.Items); ok {
+		r0 = rf(ctx, sku)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Order)
+			r0 = ret.Get(0).([]*models.Items)
 		}
 	}
 
-	var r1 string
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
-		r1 = rf(ctx, cursor, num)
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, sku)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetItemsById provides a mock function with given fields: ctx, id
+func (_m *Usecase) GetItemsById(ctx context.Context, id []int64) ([]*models.Items, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []*models.Items
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []*models.Items); ok {
+		r0 = rf(ctx, id)
 	} else {
-		r1 = ret.Get(1).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Items)
+		}
 	}
 
-	var r2 error
-	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
-		r2 = rf(ctx, cursor, num)
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, id)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: ctx, id
-func (_m *Usecase) GetByID(ctx context.Context, id int64) (*models.Order, error) {
+// GetOrder provides a mock function with given fields: ctx, id
+func (_m *Usecase) GetOrder(ctx context.Context, id int64) (*models.Order, error) {
 	ret := _m.Called(ctx, id)
 
 	var r0 *models.Order
@@ -80,22 +161,91 @@ func (_m *Usecase) GetByID(ctx context.Context, id int64) (*models.Order, error)
 	return r0, r1
 }
 
-// GetByTitle provides a mock function with given fields: ctx, title
-func (_m *Usecase) GetByTitle(ctx context.Context, title string) (*models.Order, error) {
-	ret := _m.Called(ctx, title)
+// GetOrderDetails provides a mock function with given fields: ctx, orderID
+func (_m *Usecase) GetOrderDetails(ctx context.Context, orderID int64) ([]*models.OrderDetails, error) {
+	ret := _m.Called(ctx, orderID)
 
-	var r0 *models.Order
-	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Order); ok {
-		r0 = rf(ctx, title)
+	var r0 []*models.OrderDetails
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*models.OrderDetails); ok {
+		r0 = rf(ctx, orderID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.Order)
+			r0 = ret.Get(0).([]*models.OrderDetails)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPromotions provides a mock function with given fields: ctx, id
+func (_m *Usecase) GetPromotions(ctx context.Context, id int64) (*models.Promotions, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.Promotions
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.Promotions); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Promotions)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MergeCart provides a mock function with given fields: ctx
+func (_m *Usecase) MergeCart(ctx context.Context) (*models.CartMergeResult, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *models.CartMergeResult
+	if rf, ok := ret.Get(0).(func(context.Context) *models.CartMergeResult); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.CartMergeResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MyOrders provides a mock function with given fields: ctx, customerID, cursor, num
+func (_m *Usecase) MyOrders(ctx context.Context, customerID int64, cursor string, num int64) (*models.OrderPage, error) {
+	ret := _m.Called(ctx, customerID, cursor, num)
+
+	var r0 *models.OrderPage
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, int64) *models.OrderPage); ok {
+		r0 = rf(ctx, customerID, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.OrderPage)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, title)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, int64) error); ok {
+		r1 = rf(ctx, customerID, cursor, num)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -103,13 +253,64 @@ func (_m *Usecase) GetByTitle(ctx context.Context, title string) (*models.Order,
 	return r0, r1
 }
 
-// Store provides a mock function with given fields: _a0, _a1
-func (_m *Usecase) Store(_a0 context.Context, _a1 *models.Order) error {
-	ret := _m.Called(_a0, _a1)
+// PlaceOrder provides a mock function with given fields: ctx, o, details, tenders
+func (_m *Usecase) PlaceOrder(ctx context.Context, o *models.Order, details []*models.OrderDetails, tenders []*models.Tender) error {
+	ret := _m.Called(ctx, o, details, tenders)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.Order) error); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Order, []*models.OrderDetails, []*models.Tender) error); ok {
+		r0 = rf(ctx, o, details, tenders)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Reorder provides a mock function with given fields: ctx, orderID
+func (_m *Usecase) Reorder(ctx context.Context, orderID int64) (*models.ReorderResult, error) {
+	ret := _m.Called(ctx, orderID)
+
+	var r0 *models.ReorderResult
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.ReorderResult); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ReorderResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateCart provides a mock function with given fields: ctx, a
+func (_m *Usecase) UpdateCart(ctx context.Context, a *models.Cart) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Cart) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateItems provides a mock function with given fields: ctx, a
+func (_m *Usecase) UpdateItems(ctx context.Context, a *models.Items) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Items) error); ok {
+		r0 = rf(ctx, a)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -117,13 +318,13 @@ func (_m *Usecase) Store(_a0 context.Context, _a1 *models.Order) error {
 	return r0
 }
 
-// Update provides a mock function with given fields: ctx, ar
-func (_m *Usecase) Update(ctx context.Context, ar *models.Order) error {
-	ret := _m.Called(ctx, ar)
+// UpdateOrderStatus provides a mock function with given fields: ctx, a
+func (_m *Usecase) UpdateOrderStatus(ctx context.Context, a *models.Order) error {
+	ret := _m.Called(ctx, a)
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(context.Context, *models.Order) error); ok {
-		r0 = rf(ctx, ar)
+		r0 = rf(ctx, a)
 	} else {
 		r0 = ret.Error(0)
 	}
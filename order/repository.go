@@ -10,12 +10,19 @@ import (
 type Repository interface {
 	GetItems(ctx context.Context, sku []string) (res []*models.Items, err error)
 	GetItemsById(ctx context.Context, id []int64) (res []*models.Items, err error)
-	GetCart(ctx context.Context) (res []*models.Cart, err error)
+	GetKitComponents(ctx context.Context, kitID []int64) (res []*models.KitComponent, err error)
+	GetCart(ctx context.Context, owner models.CartOwner) (res []*models.Cart, err error)
 	GetPromotions(ctx context.Context, id int64) (*models.Promotions, error)
+	GetTaxRule(ctx context.Context, id int64) (*models.TaxRule, error)
+	GetOrder(ctx context.Context, id int64) (*models.Order, error)
+	LockOrder(ctx context.Context, id int64) (*models.Order, error)
+	FetchOrders(ctx context.Context, customerID int64, cursor string, num int64) (res []*models.Order, nextCursor string, err error)
+	GetOrderDetails(ctx context.Context, orderID int64) (res []*models.OrderDetails, err error)
 	CreateCart(ctx context.Context, a *models.Cart) error
 	UpdateItems(ctx context.Context, a *models.Items) error
 	UpdateCart(ctx context.Context, a *models.Cart) error
 	CreateOrder(ctx context.Context, a *models.Order) error
+	UpdateOrderStatus(ctx context.Context, a *models.Order) error
 	CreateOrderDetails(ctx context.Context, a *models.OrderDetails) error
-	DeleteCart(ctx context.Context) error
+	DeleteCart(ctx context.Context, owner models.CartOwner) error
 }
@@ -5,19 +5,32 @@ import (
 	"database/sql"
 	"encoding/base64"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/sirupsen/logrus"
+	"github.com/williamchand/kuncie-cart/logging"
+	"github.com/williamchand/kuncie-cart/metrics"
 	"github.com/williamchand/kuncie-cart/order"
 
 	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/tracing"
+	"github.com/williamchand/kuncie-cart/transaction"
 )
 
 const (
 	timeFormat = "2006-01-02T15:04:05.999Z07:00" // reduce precision from RFC3339Nano as date format
 )
 
+// selectItems reads the items with the stock of kits computed from the stock of their components
+const selectItems = `SELECT i.id, i.sku, i.name, i.price, IFNULL(k.stock, i.inventory_quantity), i.updated_at, i.created_at
+  						FROM items i LEFT JOIN (SELECT kc.kit_items_id, MIN(FLOOR(c.inventory_quantity / kc.quantity)) AS stock
+  							FROM kit_components kc JOIN items c ON c.id = kc.items_id GROUP BY kc.kit_items_id) k ON k.kit_items_id = i.id `
+
+// selectOrder reads the orders
+const selectOrder = "SELECT id, customer_id, shipping_address_id, billing_address_id, total_price, tax_amount, credit_amount, status, updated_at, created_at " +
+	"FROM `order` "
+
 type mysqlOrderRepository struct {
 	Conn *sql.DB
 }
@@ -28,22 +41,24 @@ func NewMysqlOrderRepository(Conn *sql.DB) order.Repository {
 }
 
 func (m *mysqlOrderRepository) GetItemsById(ctx context.Context, id []int64) (res []*models.Items, err error) {
+	defer metrics.ObserveQuery("order", "GetItemsById", time.Now())
+	ctx, span := tracing.Start(ctx, "mysqlOrderRepository.GetItemsById")
+	defer tracing.End(span, &err)
 	args := make([]interface{}, len(id))
 	for i, val := range id {
 		args[i] = val
 	}
-	query := `SELECT id,sku,name,price,inventory_quantity, updated_at, created_at
-  						FROM items WHERE id IN (?` + strings.Repeat(",?", len(args)-1) + `)`
-	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	query := selectItems + `WHERE i.id IN (?` + strings.Repeat(",?", len(args)-1) + `)`
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
 	if err != nil {
-		logrus.Error(err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 
 	defer func() {
 		err := rows.Close()
 		if err != nil {
-			logrus.Error(err)
+			logging.FromContext(ctx).Error(err)
 		}
 	}()
 
@@ -61,7 +76,7 @@ func (m *mysqlOrderRepository) GetItemsById(ctx context.Context, id []int64) (re
 		)
 
 		if err != nil {
-			logrus.Error(err)
+			logging.FromContext(ctx).Error(err)
 			return nil, err
 		}
 		result = append(result, t)
@@ -71,22 +86,24 @@ func (m *mysqlOrderRepository) GetItemsById(ctx context.Context, id []int64) (re
 }
 
 func (m *mysqlOrderRepository) GetItems(ctx context.Context, sku []string) (res []*models.Items, err error) {
+	defer metrics.ObserveQuery("order", "GetItems", time.Now())
+	ctx, span := tracing.Start(ctx, "mysqlOrderRepository.GetItems")
+	defer tracing.End(span, &err)
 	args := make([]interface{}, len(sku))
 	for i, skuid := range sku {
 		args[i] = skuid
 	}
-	query := `SELECT id,sku,name,price,inventory_quantity, updated_at, created_at
-  						FROM items WHERE sku IN (?` + strings.Repeat(",?", len(args)-1) + `)`
-	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	query := selectItems + `WHERE i.archived = 0 AND i.sku IN (?` + strings.Repeat(",?", len(args)-1) + `)`
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
 	if err != nil {
-		logrus.Error(err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 
 	defer func() {
 		err := rows.Close()
 		if err != nil {
-			logrus.Error(err)
+			logging.FromContext(ctx).Error(err)
 		}
 	}()
 
@@ -104,7 +121,7 @@ func (m *mysqlOrderRepository) GetItems(ctx context.Context, sku []string) (res
 		)
 
 		if err != nil {
-			logrus.Error(err)
+			logging.FromContext(ctx).Error(err)
 			return nil, err
 		}
 		result = append(result, t)
@@ -113,81 +130,286 @@ func (m *mysqlOrderRepository) GetItems(ctx context.Context, sku []string) (res
 	return result, nil
 }
 
+// GetPromotions returns the promotion checkout applies to the item among the active ones matching it,
+// see models.SelectPromotion. It returns a promotion without type when there is none.
 func (m *mysqlOrderRepository) GetPromotions(ctx context.Context, id int64) (res *models.Promotions, err error) {
-	query := `SELECT id, items_id, promo_type, promo, quantity_requirement
-  						FROM promotions WHERE items_id = ?`
-	rows, err := m.Conn.QueryContext(ctx, query, id)
+	defer metrics.ObserveQuery("order", "GetPromotions", time.Now())
+	ctx, span := tracing.Start(ctx, "mysqlOrderRepository.GetPromotions")
+	defer tracing.End(span, &err)
+	query := `SELECT p.id, p.items_id, p.product_id, p.category_id, p.tag, p.promo_type, p.promo, p.quantity_requirement, p.active,
+  							IFNULL(pc.path, '')
+  						FROM promotions p JOIN items i ON i.id = ?
+  						LEFT JOIN categories pc ON pc.id = p.category_id
+  						WHERE p.active = 1 AND (p.items_id = i.id
+  							OR (i.product_id > 0 AND p.product_id = i.product_id)
+  							OR (p.category_id > 0 AND EXISTS (SELECT 1 FROM item_categories ic JOIN categories c ON c.id = ic.category_id
+  								WHERE ic.items_id = i.id AND c.path LIKE CONCAT(pc.path, '%')))
+  							OR (p.tag <> '' AND EXISTS (SELECT 1 FROM item_tags t WHERE t.items_id = i.id AND t.tag = p.tag)))
+  						ORDER BY p.id`
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, id)
 	if err != nil {
-		logrus.Error(err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 
 	defer func() {
 		err := rows.Close()
 		if err != nil {
-			logrus.Error(err)
+			logging.FromContext(ctx).Error(err)
 		}
 	}()
 
+	result := make([]*models.Promotions, 0)
+	categories := make([]*models.Category, 0)
+	for rows.Next() {
+		t := new(models.Promotions)
+		c := new(models.Category)
+		err = rows.Scan(
+			&t.ID,
+			&t.ItemsID,
+			&t.ProductID,
+			&t.CategoryID,
+			&t.Tag,
+			&t.PromoType,
+			&t.Promo,
+			&t.QuantityRequirement,
+			&t.Active,
+			&c.Path,
+		)
+
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+		if c.Path != "" {
+			categories = append(categories, c)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if p := models.SelectPromotion(result, models.CategoryDepths(categories)); p != nil {
+		return p, nil
+	}
+	return &models.Promotions{ItemsID: id}, nil
+}
+
+// GetTaxRule returns the tax rule applied to the item among the active ones, see models.SelectTaxRule,
+// a rule of zero rate when none applies
+func (m *mysqlOrderRepository) GetTaxRule(ctx context.Context, id int64) (res *models.TaxRule, err error) {
+	defer metrics.ObserveQuery("order", "GetTaxRule", time.Now())
+	ctx, span := tracing.Start(ctx, "mysqlOrderRepository.GetTaxRule")
+	defer tracing.End(span, &err)
+	query := `SELECT r.id, r.name, r.rate, r.items_id, r.product_id, r.category_id, r.tag, r.active, IFNULL(rc.path, '')
+  						FROM tax_rules r JOIN items i ON i.id = ?
+  						LEFT JOIN categories rc ON rc.id = r.category_id
+  						WHERE r.active = 1 AND (r.items_id = i.id
+  							OR (i.product_id > 0 AND r.product_id = i.product_id)
+  							OR (r.category_id > 0 AND EXISTS (SELECT 1 FROM item_categories ic JOIN categories c ON c.id = ic.category_id
+  								WHERE ic.items_id = i.id AND c.path LIKE CONCAT(rc.path, '%')))
+  							OR (r.tag <> '' AND EXISTS (SELECT 1 FROM item_tags t WHERE t.items_id = i.id AND t.tag = r.tag)))
+  						ORDER BY r.id`
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, id)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
 	defer func() {
 		err := rows.Close()
 		if err != nil {
-			logrus.Error(err)
+			logging.FromContext(ctx).Error(err)
 		}
 	}()
 
-	result := make([]*models.Promotions, 0)
+	result := make([]*models.TaxRule, 0)
+	categories := make([]*models.Category, 0)
 	for rows.Next() {
-		t := new(models.Promotions)
+		t := new(models.TaxRule)
+		c := new(models.Category)
 		err = rows.Scan(
 			&t.ID,
+			&t.Name,
+			&t.Rate,
 			&t.ItemsID,
-			&t.PromoType,
-			&t.Promo,
-			&t.QuantityRequirement,
+			&t.ProductID,
+			&t.CategoryID,
+			&t.Tag,
+			&t.Active,
+			&c.Path,
 		)
 
 		if err != nil {
-			logrus.Error(err)
+			logging.FromContext(ctx).Error(err)
 			return nil, err
 		}
 		result = append(result, t)
+		if c.Path != "" {
+			categories = append(categories, c)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
 	}
-	if len(result) > 0 {
-		return result[0], nil
+	if r := models.SelectTaxRule(result, models.CategoryDepths(categories)); r != nil {
+		return r, nil
 	}
+	return &models.TaxRule{ItemsID: id}, nil
+}
+
+func (m *mysqlOrderRepository) GetOrder(ctx context.Context, id int64) (res *models.Order, err error) {
+	defer metrics.ObserveQuery("order", "GetOrder", time.Now())
+	ctx, span := tracing.Start(ctx, "mysqlOrderRepository.GetOrder")
+	defer tracing.End(span, &err)
+
+	return m.getOrder(ctx, selectOrder+"WHERE id = ?", id)
+}
+
+// LockOrder reads an order and locks its row until the end of the transaction of ctx, so the changes made to
+// the order by concurrent transactions are serialized
+func (m *mysqlOrderRepository) LockOrder(ctx context.Context, id int64) (res *models.Order, err error) {
+	defer metrics.ObserveQuery("order", "LockOrder", time.Now())
+	ctx, span := tracing.Start(ctx, "mysqlOrderRepository.LockOrder")
+	defer tracing.End(span, &err)
+
+	return m.getOrder(ctx, selectOrder+"WHERE id = ? FOR UPDATE", id)
+}
+
+func (m *mysqlOrderRepository) getOrder(ctx context.Context, query string, args ...interface{}) (*models.Order, error) {
+	res := new(models.Order)
+	err := transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, query, args...).Scan(
+		&res.ID,
+		&res.CustomerID,
+		&res.ShippingAddressID,
+		&res.BillingAddressID,
+		&res.TotalPrice,
+		&res.TaxAmount,
+		&res.CreditAmount,
+		&res.Status,
+		&res.UpdatedAt,
+		&res.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
 	return res, nil
 }
 
-func (m *mysqlOrderRepository) GetCart(ctx context.Context) (res []*models.Cart, err error) {
-	query := `SELECT id, items_id, quantity, updated_at, created_at
-  						FROM cart`
-	rows, err := m.Conn.QueryContext(ctx, query)
+// FetchOrders pages through the orders of a customer, newest first
+func (m *mysqlOrderRepository) FetchOrders(ctx context.Context, customerID int64, cursor string, num int64) (_ []*models.Order, _ string, err error) {
+	defer metrics.ObserveQuery("order", "FetchOrders", time.Now())
+	ctx, span := tracing.Start(ctx, "mysqlOrderRepository.FetchOrders")
+	defer tracing.End(span, &err)
+	query := "SELECT id, customer_id, shipping_address_id, billing_address_id, total_price, tax_amount, credit_amount, status, updated_at, created_at " +
+		"FROM `order` WHERE customer_id = ?"
+	args := []interface{}{customerID}
+	if cursor != "" {
+		byt, err := base64.StdEncoding.DecodeString(cursor)
+		if err != nil {
+			return nil, "", models.ErrBadParamInput
+		}
+		beforeID, err := strconv.ParseInt(string(byt), 10, 64)
+		if err != nil {
+			return nil, "", models.ErrBadParamInput
+		}
+		query += " AND id < ?"
+		args = append(args, beforeID)
+	}
+	// one more row than asked tells whether there is a next page
+	query += " ORDER BY id DESC LIMIT ?"
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, append(args, num+1)...)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, "", err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	result := make([]*models.Order, 0)
+	for rows.Next() {
+		t := new(models.Order)
+		err = rows.Scan(
+			&t.ID,
+			&t.CustomerID,
+			&t.ShippingAddressID,
+			&t.BillingAddressID,
+			&t.TotalPrice,
+			&t.TaxAmount,
+			&t.CreditAmount,
+			&t.Status,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, "", err
+		}
+		result = append(result, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if int64(len(result)) > num {
+		result = result[:num]
+		nextCursor = base64.StdEncoding.EncodeToString([]byte(strconv.FormatInt(result[len(result)-1].ID, 10)))
+	}
+
+	return result, nextCursor, nil
+}
+
+func (m *mysqlOrderRepository) GetOrderDetails(ctx context.Context, orderID int64) (res []*models.OrderDetails, err error) {
+	defer metrics.ObserveQuery("order", "GetOrderDetails", time.Now())
+	ctx, span := tracing.Start(ctx, "mysqlOrderRepository.GetOrderDetails")
+	defer tracing.End(span, &err)
+	query := `SELECT id, order_id, parent_id, items_id, sku, name, price, list_price, tax_amount, quantity, IFNULL(promo_type, ''), updated_at, created_at
+  						FROM order_details WHERE order_id = ? ORDER BY id`
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, orderID)
 	if err != nil {
-		logrus.Error(err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 
 	defer func() {
 		err := rows.Close()
 		if err != nil {
-			logrus.Error(err)
+			logging.FromContext(ctx).Error(err)
 		}
 	}()
 
-	result := make([]*models.Cart, 0)
+	result := make([]*models.OrderDetails, 0)
 	for rows.Next() {
-		t := new(models.Cart)
+		t := new(models.OrderDetails)
 		err = rows.Scan(
 			&t.ID,
+			&t.OrderID,
+			&t.ParentID,
 			&t.ItemsID,
+			&t.SKU,
+			&t.Name,
+			&t.Price,
+			&t.ListPrice,
+			&t.TaxAmount,
 			&t.Quantity,
+			&t.PromoType,
 			&t.UpdatedAt,
 			&t.CreatedAt,
 		)
 
 		if err != nil {
-			logrus.Error(err)
+			logging.FromContext(ctx).Error(err)
 			return nil, err
 		}
 		result = append(result, t)
@@ -196,34 +418,68 @@ func (m *mysqlOrderRepository) GetCart(ctx context.Context) (res []*models.Cart,
 	return result, nil
 }
 
-func (m *mysqlOrderRepository) CreateCart(ctx context.Context, a *models.Cart) error {
-	query := `INSERT cart SET items_id=?, quantity=?, updated_at=?, created_at=?`
-	stmt, err := m.Conn.PrepareContext(ctx, query)
-	if err != nil {
-		return err
+// cartOf returns the condition selecting the cart lines of owner, a customer or else an anonymous session
+func cartOf(owner models.CartOwner) (string, []interface{}) {
+	if owner.CustomerID != 0 {
+		return `customer_id = ?`, []interface{}{owner.CustomerID}
 	}
+	return `customer_id = 0 AND session_token = ?`, []interface{}{owner.SessionToken}
+}
 
-	res, err := stmt.ExecContext(ctx, a.ItemsID, a.Quantity, a.UpdatedAt, a.CreatedAt)
+func (m *mysqlOrderRepository) GetCart(ctx context.Context, owner models.CartOwner) (res []*models.Cart, err error) {
+	defer metrics.ObserveQuery("order", "GetCart", time.Now())
+	ctx, span := tracing.Start(ctx, "mysqlOrderRepository.GetCart")
+	defer tracing.End(span, &err)
+	where, args := cartOf(owner)
+	query := `SELECT id, customer_id, session_token, items_id, quantity, updated_at, created_at
+  						FROM cart WHERE ` + where + ` ORDER BY id`
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
 	if err != nil {
-		return err
+		logging.FromContext(ctx).Error(err)
+		return nil, err
 	}
 
-	lastID, err := res.LastInsertId()
-	if err != nil {
-		return err
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	result := make([]*models.Cart, 0)
+	for rows.Next() {
+		t := new(models.Cart)
+		err = rows.Scan(
+			&t.ID,
+			&t.CustomerID,
+			&t.SessionToken,
+			&t.ItemsID,
+			&t.Quantity,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		result = append(result, t)
 	}
 
-	a.ID = lastID
-	return nil
+	return result, nil
 }
 
-func (m *mysqlOrderRepository) CreateOrder(ctx context.Context, a *models.Order) error {
-	query := "INSERT `" + "order" + "` SET total_price=?, updated_at=?, created_at=?"
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+func (m *mysqlOrderRepository) CreateCart(ctx context.Context, a *models.Cart) (err error) {
+	defer metrics.ObserveQuery("order", "CreateCart", time.Now())
+	ctx, span := tracing.Start(ctx, "mysqlOrderRepository.CreateCart")
+	defer tracing.End(span, &err)
+	query := `INSERT cart SET customer_id=?, session_token=?, items_id=?, quantity=?, updated_at=?, created_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
 	if err != nil {
 		return err
 	}
-	res, err := stmt.ExecContext(ctx, a.TotalPrice, a.UpdatedAt, a.CreatedAt)
+
+	res, err := stmt.ExecContext(ctx, a.CustomerID, a.SessionToken, a.ItemsID, a.Quantity, a.UpdatedAt, a.CreatedAt)
 	if err != nil {
 		return err
 	}
@@ -237,14 +493,18 @@ func (m *mysqlOrderRepository) CreateOrder(ctx context.Context, a *models.Order)
 	return nil
 }
 
-func (m *mysqlOrderRepository) CreateOrderDetails(ctx context.Context, a *models.OrderDetails) error {
-	query := `INSERT order_details SET order_id=? , sku=?, name=?, price=?, quantity=?, promo_type=?, updated_at=? , created_at=?`
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+func (m *mysqlOrderRepository) CreateOrder(ctx context.Context, a *models.Order) (err error) {
+	defer metrics.ObserveQuery("order", "CreateOrder", time.Now())
+	ctx, span := tracing.Start(ctx, "mysqlOrderRepository.CreateOrder")
+	defer tracing.End(span, &err)
+	query := "INSERT `order` SET customer_id=?, shipping_address_id=?, billing_address_id=?, total_price=?, tax_amount=?, " +
+		"credit_amount=?, status=?, updated_at=?, created_at=?"
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
 	if err != nil {
 		return err
 	}
-
-	res, err := stmt.ExecContext(ctx, a.OrderID, a.SKU, a.Name, a.Price, a.Quantity, a.PromoType, a.UpdatedAt, a.CreatedAt)
+	res, err := stmt.ExecContext(ctx, a.CustomerID, a.ShippingAddressID, a.BillingAddressID, a.TotalPrice, a.TaxAmount,
+		a.CreditAmount, a.Status, a.UpdatedAt, a.CreatedAt)
 	if err != nil {
 		return err
 	}
@@ -257,15 +517,19 @@ func (m *mysqlOrderRepository) CreateOrderDetails(ctx context.Context, a *models
 	a.ID = lastID
 	return nil
 }
-func (m *mysqlOrderRepository) UpdateCart(ctx context.Context, ar *models.Cart) error {
-	query := `UPDATE cart set items_id=?, quantity=?, updated_at=? WHERE id = ?`
 
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+func (m *mysqlOrderRepository) UpdateOrderStatus(ctx context.Context, ar *models.Order) (err error) {
+	defer metrics.ObserveQuery("order", "UpdateOrderStatus", time.Now())
+	ctx, span := tracing.Start(ctx, "mysqlOrderRepository.UpdateOrderStatus")
+	defer tracing.End(span, &err)
+	query := "UPDATE `" + "order" + "` set status=?, credit_amount=?, updated_at=? WHERE id = ?"
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	res, err := stmt.ExecContext(ctx, ar.ItemsID, ar.Quantity, ar.UpdatedAt, ar.ID)
+	res, err := stmt.ExecContext(ctx, ar.Status, ar.CreditAmount, ar.UpdatedAt, ar.ID)
 	if err != nil {
 		return err
 	}
@@ -282,15 +546,42 @@ func (m *mysqlOrderRepository) UpdateCart(ctx context.Context, ar *models.Cart)
 	return nil
 }
 
-func (m *mysqlOrderRepository) UpdateItems(ctx context.Context, ar *models.Items) error {
-	query := `UPDATE items set inventory_quantity= inventory_quantity - ?, updated_at=? WHERE sku = ?`
+func (m *mysqlOrderRepository) CreateOrderDetails(ctx context.Context, a *models.OrderDetails) (err error) {
+	defer metrics.ObserveQuery("order", "CreateOrderDetails", time.Now())
+	ctx, span := tracing.Start(ctx, "mysqlOrderRepository.CreateOrderDetails")
+	defer tracing.End(span, &err)
+	query := `INSERT order_details SET order_id=? , parent_id=?, items_id=?, sku=?, name=?, price=?, list_price=?, tax_amount=?, quantity=?, promo_type=?, updated_at=? , created_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, a.OrderID, a.ParentID, a.ItemsID, a.SKU, a.Name, a.Price, a.ListPrice, a.TaxAmount, a.Quantity, a.PromoType, a.UpdatedAt, a.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
 
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	a.ID = lastID
+	return nil
+}
+func (m *mysqlOrderRepository) UpdateCart(ctx context.Context, ar *models.Cart) (err error) {
+	defer metrics.ObserveQuery("order", "UpdateCart", time.Now())
+	ctx, span := tracing.Start(ctx, "mysqlOrderRepository.UpdateCart")
+	defer tracing.End(span, &err)
+	where, args := cartOf(models.CartOwner{CustomerID: ar.CustomerID, SessionToken: ar.SessionToken})
+	query := `UPDATE cart set items_id=?, quantity=?, updated_at=? WHERE id = ? AND ` + where
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
 	if err != nil {
 		return nil
 	}
 
-	res, err := stmt.ExecContext(ctx, ar.InventoryQuantity, ar.UpdatedAt, ar.SKU)
+	res, err := stmt.ExecContext(ctx, append([]interface{}{ar.ItemsID, ar.Quantity, ar.UpdatedAt, ar.ID}, args...)...)
 	if err != nil {
 		return err
 	}
@@ -306,33 +597,100 @@ func (m *mysqlOrderRepository) UpdateItems(ctx context.Context, ar *models.Items
 
 	return nil
 }
-func (m *mysqlOrderRepository) DeleteCart(ctx context.Context) error {
-	query := "DELETE FROM cart"
 
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+// GetKitComponents returns the components of the given kits, items that are not kits have none
+func (m *mysqlOrderRepository) GetKitComponents(ctx context.Context, kitID []int64) (res []*models.KitComponent, err error) {
+	defer metrics.ObserveQuery("order", "GetKitComponents", time.Now())
+	ctx, span := tracing.Start(ctx, "mysqlOrderRepository.GetKitComponents")
+	defer tracing.End(span, &err)
+	result := make([]*models.KitComponent, 0)
+	if len(kitID) == 0 {
+		return result, nil
+	}
+	args := make([]interface{}, len(kitID))
+	for i, val := range kitID {
+		args[i] = val
+	}
+	query := `SELECT k.kit_items_id, k.items_id, i.sku, i.name, k.quantity, i.inventory_quantity
+  						FROM kit_components k JOIN items i ON i.id = k.items_id
+  						WHERE k.kit_items_id IN (?` + strings.Repeat(",?", len(args)-1) + `) ORDER BY k.kit_items_id, k.items_id`
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
 	if err != nil {
-		return err
+		logging.FromContext(ctx).Error(err)
+		return nil, err
 	}
 
-	res, err := stmt.ExecContext(ctx)
-	if err != nil {
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
 
-		return err
+	for rows.Next() {
+		t := new(models.KitComponent)
+		err = rows.Scan(
+			&t.KitID,
+			&t.ItemsID,
+			&t.SKU,
+			&t.Name,
+			&t.Quantity,
+			&t.InventoryQuantity,
+		)
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		result = append(result, t)
 	}
 
-	rowsAfected, err := res.RowsAffected()
+	return result, rows.Err()
+}
+
+// UpdateItems takes InventoryQuantity out of the stock of the item, the stock is never taken below zero:
+// an item without enough left returns models.ErrOutOfStock and is left as it is
+func (m *mysqlOrderRepository) UpdateItems(ctx context.Context, ar *models.Items) (err error) {
+	defer metrics.ObserveQuery("order", "UpdateItems", time.Now())
+	ctx, span := tracing.Start(ctx, "mysqlOrderRepository.UpdateItems")
+	defer tracing.End(span, &err)
+	query := `UPDATE items set inventory_quantity= inventory_quantity - ?, updated_at=? WHERE sku = ? AND inventory_quantity >= ?`
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
 	if err != nil {
 		return err
 	}
 
-	if rowsAfected != 1 {
-		err = fmt.Errorf("Weird  Behaviour. Total Affected: %d", rowsAfected)
+	res, err := stmt.ExecContext(ctx, ar.InventoryQuantity, ar.UpdatedAt, ar.SKU, ar.InventoryQuantity)
+	if err != nil {
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
 		return err
 	}
+	if affect == 0 {
+		return models.ErrOutOfStock
+	}
 
 	return nil
 }
 
+func (m *mysqlOrderRepository) DeleteCart(ctx context.Context, owner models.CartOwner) (err error) {
+	defer metrics.ObserveQuery("order", "DeleteCart", time.Now())
+	ctx, span := tracing.Start(ctx, "mysqlOrderRepository.DeleteCart")
+	defer tracing.End(span, &err)
+	where, args := cartOf(owner)
+	query := "DELETE FROM cart WHERE " + where
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx, args...)
+	return err
+}
+
 // DecodeCursor will decode cursor from user for mysql
 func DecodeCursor(encodedTime string) (time.Time, error) {
 	byt, err := base64.StdEncoding.DecodeString(encodedTime)
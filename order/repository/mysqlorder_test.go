@@ -12,153 +12,231 @@ import (
 	orderRepo "github.com/williamchand/kuncie-cart/order/repository"
 )
 
-func TestFetch(t *testing.T) {
+var orderColumns = []string{"id", "customer_id", "shipping_address_id", "billing_address_id", "total_price", "tax_amount", "credit_amount", "status", "updated_at", "created_at"}
+
+func TestGetOrder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	now := time.Now()
+	rows := sqlmock.NewRows(orderColumns).AddRow(7, 5, 1, 2, 149.97, 0, 100, models.OrderStatusPending, now, now)
+
+	query := "SELECT id, customer_id, shipping_address_id, billing_address_id, total_price, tax_amount, credit_amount, status, updated_at, created_at FROM `order` WHERE id = \\?"
+	mock.ExpectQuery(query).WithArgs(int64(7)).WillReturnRows(rows)
+
+	a := orderRepo.NewMysqlOrderRepository(db)
+	o, err := a.GetOrder(context.TODO(), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), o.CustomerID)
+	assert.Equal(t, 49.97, o.AmountDue())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOrderNotFound(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	mockOrders := []models.Order{
-		models.Order{
-			ID: 1, Title: "title 1", Content: "content 1",
-			Author: models.Author{ID: 1}, UpdatedAt: time.Now(), CreatedAt: time.Now(),
-		},
-		models.Order{
-			ID: 2, Title: "title 2", Content: "content 2",
-			Author: models.Author{ID: 1}, UpdatedAt: time.Now(), CreatedAt: time.Now(),
-		},
+	query := "SELECT (.+) FROM `order` WHERE id = \\?"
+	mock.ExpectQuery(query).WithArgs(int64(7)).WillReturnRows(sqlmock.NewRows(orderColumns))
+
+	a := orderRepo.NewMysqlOrderRepository(db)
+	o, err := a.GetOrder(context.TODO(), 7)
+	assert.Equal(t, models.ErrNotFound, err)
+	assert.Nil(t, o)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOrderDetails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at"}).
-		AddRow(mockOrders[0].ID, mockOrders[0].Title, mockOrders[0].Content,
-			mockOrders[0].Author.ID, mockOrders[0].UpdatedAt, mockOrders[0].CreatedAt).
-		AddRow(mockOrders[1].ID, mockOrders[1].Title, mockOrders[1].Content,
-			mockOrders[1].Author.ID, mockOrders[1].UpdatedAt, mockOrders[1].CreatedAt)
+	now := time.Now()
+	columns := []string{"id", "order_id", "parent_id", "items_id", "sku", "name", "price", "list_price", "tax_amount",
+		"quantity", "promo_type", "updated_at", "created_at"}
+	// the second line was placed before list prices were recorded
+	rows := sqlmock.NewRows(columns).
+		AddRow(1, 7, 0, 4, "120P90", "Google Home", 99.98, 149.97, 11, 3, "free_items", now, now).
+		AddRow(2, 7, 0, 2, "43N23P", "Macbook Pro", 5399.99, nil, 0, 1, "", now, now)
 
-	query := "SELECT id,title,content, author_id, updated_at, created_at FROM order WHERE created_at > \\? ORDER BY created_at LIMIT \\?"
+	query := "SELECT id, order_id, parent_id, items_id, sku, name, price, list_price, tax_amount, quantity, (.+) FROM order_details WHERE order_id = \\?"
+	mock.ExpectQuery(query).WithArgs(int64(7)).WillReturnRows(rows)
 
-	mock.ExpectQuery(query).WillReturnRows(rows)
 	a := orderRepo.NewMysqlOrderRepository(db)
-	cursor := orderRepo.EncodeCursor(mockOrders[1].CreatedAt)
-	num := int64(2)
-	list, nextCursor, err := a.Fetch(context.TODO(), cursor, num)
-	assert.NotEmpty(t, nextCursor)
+	details, err := a.GetOrderDetails(context.TODO(), 7)
 	assert.NoError(t, err)
-	assert.Len(t, list, 2)
+	assert.Len(t, details, 2)
+	if assert.NotNil(t, details[0].ListPrice) {
+		assert.Equal(t, 149.97, *details[0].ListPrice)
+	}
+	assert.Equal(t, 11.0, details[0].TaxAmount)
+	assert.Nil(t, details[1].ListPrice)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestGetByID(t *testing.T) {
+func TestFetchOrders(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at"}).
-		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now())
+	now := time.Now()
+	rows := sqlmock.NewRows(orderColumns).
+		AddRow(9, 5, 0, 0, 30, 0, 0, models.OrderStatusPaid, now, now).
+		AddRow(8, 5, 0, 0, 20, 0, 0, models.OrderStatusPaid, now, now).
+		AddRow(7, 5, 0, 0, 10, 0, 0, models.OrderStatusPending, now, now)
 
-	query := "SELECT id,title,content, author_id, updated_at, created_at FROM order WHERE ID = \\?"
+	query := "SELECT (.+) FROM `order` WHERE customer_id = \\? ORDER BY id DESC LIMIT \\?"
+	mock.ExpectQuery(query).WithArgs(int64(5), int64(3)).WillReturnRows(rows)
 
-	mock.ExpectQuery(query).WillReturnRows(rows)
 	a := orderRepo.NewMysqlOrderRepository(db)
-
-	num := int64(5)
-	anOrder, err := a.GetByID(context.TODO(), num)
+	list, nextCursor, err := a.FetchOrders(context.TODO(), 5, "", 2)
 	assert.NoError(t, err)
-	assert.NotNil(t, anOrder)
+	assert.Len(t, list, 2)
+	assert.NotEmpty(t, nextCursor)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestStore(t *testing.T) {
+func TestCreateOrder(t *testing.T) {
 	now := time.Now()
-	ar := &models.Order{
-		Title:     "Judul",
-		Content:   "Content",
-		CreatedAt: now,
-		UpdatedAt: now,
-		Author: models.Author{
-			ID:   1,
-			Name: "Iman Tumorang",
-		},
-	}
+	o := &models.Order{CustomerID: 5, TotalPrice: 149.97, TaxAmount: 14.86, Status: models.OrderStatusPending, CreatedAt: now, UpdatedAt: now}
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	query := "INSERT  order SET title=\\? , content=\\? , author_id=\\?, updated_at=\\? , created_at=\\?"
+	query := "INSERT `order` SET customer_id=\\?, shipping_address_id=\\?, billing_address_id=\\?, total_price=\\?, tax_amount=\\?, credit_amount=\\?, status=\\?, updated_at=\\?, created_at=\\?"
 	prep := mock.ExpectPrepare(query)
-	prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Author.ID, ar.CreatedAt, ar.UpdatedAt).WillReturnResult(sqlmock.NewResult(12, 1))
+	prep.ExpectExec().WithArgs(o.CustomerID, o.ShippingAddressID, o.BillingAddressID, o.TotalPrice, o.TaxAmount, o.CreditAmount, o.Status, o.UpdatedAt, o.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(12, 1))
 
 	a := orderRepo.NewMysqlOrderRepository(db)
-
-	err = a.Store(context.TODO(), ar)
+	err = a.CreateOrder(context.TODO(), o)
 	assert.NoError(t, err)
-	assert.Equal(t, int64(12), ar.ID)
+	assert.Equal(t, int64(12), o.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestGetByTitle(t *testing.T) {
+func TestUpdateOrderStatus(t *testing.T) {
+	o := &models.Order{ID: 12, Status: models.OrderStatusPaid, CreditAmount: 20, UpdatedAt: time.Now()}
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at"}).
-		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now())
-
-	query := "SELECT id,title,content, author_id, updated_at, created_at FROM order WHERE title = \\?"
+	query := "UPDATE `order` set status=\\?, credit_amount=\\?, updated_at=\\? WHERE id = \\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(o.Status, o.CreditAmount, o.UpdatedAt, o.ID).WillReturnResult(sqlmock.NewResult(12, 1))
 
-	mock.ExpectQuery(query).WillReturnRows(rows)
 	a := orderRepo.NewMysqlOrderRepository(db)
-
-	title := "title 1"
-	anOrder, err := a.GetByTitle(context.TODO(), title)
+	err = a.UpdateOrderStatus(context.TODO(), o)
 	assert.NoError(t, err)
-	assert.NotNil(t, anOrder)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestDelete(t *testing.T) {
+func TestDeleteCart(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	query := "DELETE FROM order WHERE id = \\?"
-
+	query := "DELETE FROM cart WHERE customer_id = 0 AND session_token = \\?"
 	prep := mock.ExpectPrepare(query)
-	prep.ExpectExec().WithArgs(12).WillReturnResult(sqlmock.NewResult(12, 1))
+	prep.ExpectExec().WithArgs("guest-session-token").WillReturnResult(sqlmock.NewResult(0, 2))
 
 	a := orderRepo.NewMysqlOrderRepository(db)
+	err = a.DeleteCart(context.TODO(), models.CartOwner{SessionToken: "guest-session-token"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateItems(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	it := &models.Items{SKU: "120P90", InventoryQuantity: 3, UpdatedAt: time.Now()}
+	query := "UPDATE items set inventory_quantity= inventory_quantity - \\?, updated_at=\\? WHERE sku = \\? AND inventory_quantity >= \\?"
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(it.InventoryQuantity, it.UpdatedAt, it.SKU, it.InventoryQuantity).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// only two units left
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(it.InventoryQuantity, it.UpdatedAt, it.SKU, it.InventoryQuantity).
+		WillReturnResult(sqlmock.NewResult(0, 0))
 
-	num := int64(12)
-	err = a.Delete(context.TODO(), num)
+	a := orderRepo.NewMysqlOrderRepository(db)
+	err = a.UpdateItems(context.TODO(), it)
 	assert.NoError(t, err)
+
+	err = a.UpdateItems(context.TODO(), it)
+	assert.Equal(t, models.ErrOutOfStock, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestUpdate(t *testing.T) {
-	now := time.Now()
-	ar := &models.Order{
-		ID:        12,
-		Title:     "Judul",
-		Content:   "Content",
-		CreatedAt: now,
-		UpdatedAt: now,
-		Author: models.Author{
-			ID:   1,
-			Name: "Iman Tumorang",
-		},
+func TestGetPromotions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
+	columns := []string{"id", "items_id", "product_id", "category_id", "tag", "promo_type", "promo", "quantity_requirement", "active", "path"}
+	rows := sqlmock.NewRows(columns).
+		AddRow(1, 0, 0, 0, "speaker", "discount_items", "0.9", 1, true, "").
+		AddRow(2, 0, 0, 1, "", "discount_items", "0.8", 1, true, "/1/").
+		AddRow(3, 0, 0, 4, "", "discount_items", "0.85", 1, true, "/1/4/")
+
+	query := "SELECT (.+) FROM promotions p JOIN items i ON i.id = \\? (.+) ORDER BY p.id"
+	mock.ExpectQuery(query).WithArgs(int64(7)).WillReturnRows(rows)
+
+	a := orderRepo.NewMysqlOrderRepository(db)
+	p, err := a.GetPromotions(context.TODO(), 7)
+	assert.NoError(t, err)
+	// the closest category wins over its parent and over the tag
+	assert.Equal(t, int64(3), p.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTaxRule(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	query := "UPDATE order set title=\\?, content=\\?, author_id=\\?, updated_at=\\? WHERE ID = \\?"
+	columns := []string{"id", "name", "rate", "items_id", "product_id", "category_id", "tag", "active", "path"}
+	rows := sqlmock.NewRows(columns).
+		AddRow(1, "VAT", 0.11, 0, 0, 4, "", true, "/1/4/").
+		AddRow(2, "Reduced", 0.05, 7, 0, 0, "", true, "")
 
-	prep := mock.ExpectPrepare(query)
-	prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Author.ID, ar.UpdatedAt, ar.ID).WillReturnResult(sqlmock.NewResult(12, 1))
+	query := "SELECT (.+) FROM tax_rules r JOIN items i ON i.id = \\? (.+) ORDER BY r.id"
+	mock.ExpectQuery(query).WithArgs(int64(7)).WillReturnRows(rows)
 
 	a := orderRepo.NewMysqlOrderRepository(db)
+	r, err := a.GetTaxRule(context.TODO(), 7)
+	assert.NoError(t, err)
+	// the rule of the item wins over the one of its category
+	assert.Equal(t, int64(2), r.ID)
+	assert.Equal(t, 0.05, r.Rate)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTaxRuleNone(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
 
-	err = a.Update(context.TODO(), ar)
+	columns := []string{"id", "name", "rate", "items_id", "product_id", "category_id", "tag", "active", "path"}
+	query := "SELECT (.+) FROM tax_rules r JOIN items i ON i.id = \\? (.+) ORDER BY r.id"
+	mock.ExpectQuery(query).WithArgs(int64(7)).WillReturnRows(sqlmock.NewRows(columns))
+
+	a := orderRepo.NewMysqlOrderRepository(db)
+	r, err := a.GetTaxRule(context.TODO(), 7)
 	assert.NoError(t, err)
+	assert.Equal(t, 0.0, r.Rate)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
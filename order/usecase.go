@@ -12,10 +12,17 @@ type Usecase interface {
 	GetItemsById(ctx context.Context, id []int64) (res []*models.Items, err error)
 	GetCart(ctx context.Context) (res []*models.Cart, err error)
 	GetPromotions(ctx context.Context, id int64) (*models.Promotions, error)
+	GetOrder(ctx context.Context, id int64) (*models.Order, error)
+	MyOrders(ctx context.Context, customerID int64, cursor string, num int64) (*models.OrderPage, error)
+	GetOrderDetails(ctx context.Context, orderID int64) (res []*models.OrderDetails, err error)
 	CreateCart(ctx context.Context, a *models.Cart) error
 	UpdateItems(ctx context.Context, a *models.Items) error
 	UpdateCart(ctx context.Context, a *models.Cart) error
 	CreateOrder(ctx context.Context, a *models.Order) error
+	UpdateOrderStatus(ctx context.Context, a *models.Order) error
 	CreateOrderDetails(ctx context.Context, a *models.OrderDetails) error
 	DeleteCart(ctx context.Context) error
+	Reorder(ctx context.Context, orderID int64) (*models.ReorderResult, error)
+	MergeCart(ctx context.Context) (*models.CartMergeResult, error)
+	PlaceOrder(ctx context.Context, o *models.Order, details []*models.OrderDetails, tenders []*models.Tender) error
 }
@@ -2,23 +2,42 @@ package usecase
 
 import (
 	"context"
+	"math"
+	"sort"
 	"time"
 
+	"github.com/williamchand/kuncie-cart/auth"
+	"github.com/williamchand/kuncie-cart/credit"
+	"github.com/williamchand/kuncie-cart/customer"
 	"github.com/williamchand/kuncie-cart/order"
+	"github.com/williamchand/kuncie-cart/tracing"
+	"github.com/williamchand/kuncie-cart/transaction"
 
+	"github.com/williamchand/kuncie-cart/metrics"
 	"github.com/williamchand/kuncie-cart/models"
 )
 
+const (
+	defaultPageSize = 10
+	maxPageSize     = 100
+)
+
 type orderUsecase struct {
-	orderRepo      order.Repository
-	contextTimeout time.Duration
+	orderRepo       order.Repository
+	creditService   credit.Usecase
+	customerService customer.Usecase
+	txManager       transaction.Manager
+	contextTimeout  time.Duration
 }
 
 // NewOrderUsecase will create new an orderUsecase object representation of order.Usecase interface
-func NewOrderUsecase(a order.Repository, timeout time.Duration) order.Usecase {
+func NewOrderUsecase(a order.Repository, c credit.Usecase, cs customer.Usecase, tx transaction.Manager, timeout time.Duration) order.Usecase {
 	return &orderUsecase{
-		orderRepo:      a,
-		contextTimeout: timeout,
+		orderRepo:       a,
+		creditService:   c,
+		customerService: cs,
+		txManager:       tx,
+		contextTimeout:  timeout,
 	}
 }
 
@@ -26,6 +45,8 @@ func (a *orderUsecase) GetItems(c context.Context, sku []string) (result []*mode
 
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
+	ctx, span := tracing.Start(ctx, "orderUsecase.GetItems")
+	defer tracing.End(span, &err)
 	res, err := a.orderRepo.GetItems(ctx, sku)
 	if err != nil {
 		return nil, err
@@ -38,6 +59,8 @@ func (a *orderUsecase) GetItemsById(c context.Context, id []int64) (result []*mo
 
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
+	ctx, span := tracing.Start(ctx, "orderUsecase.GetItemsById")
+	defer tracing.End(span, &err)
 	res, err := a.orderRepo.GetItemsById(ctx, id)
 	if err != nil {
 		return nil, err
@@ -49,7 +72,13 @@ func (a *orderUsecase) GetCart(c context.Context) (result []*models.Cart, err er
 
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
-	res, err := a.orderRepo.GetCart(ctx)
+	ctx, span := tracing.Start(ctx, "orderUsecase.GetCart")
+	defer tracing.End(span, &err)
+	owner, err := cartOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := a.orderRepo.GetCart(ctx, owner)
 	if err != nil {
 		return nil, err
 	}
@@ -57,10 +86,29 @@ func (a *orderUsecase) GetCart(c context.Context) (result []*models.Cart, err er
 	return res, nil
 }
 
-func (a *orderUsecase) GetPromotions(c context.Context, id int64) (*models.Promotions, error) {
+// cartOwner returns whose cart the request works on, the signed in customer or else the anonymous session
+func cartOwner(ctx context.Context) (models.CartOwner, error) {
+	if p, ok := auth.FromContext(ctx); ok && p.CustomerID != 0 {
+		return models.CartOwner{CustomerID: p.CustomerID}, nil
+	}
+	if token := auth.SessionFromContext(ctx); token != "" {
+		return models.CartOwner{SessionToken: token}, nil
+	}
+	return models.CartOwner{}, models.ErrUnauthorized
+}
+
+// own makes the cart line one of the cart of owner
+func own(m *models.Cart, owner models.CartOwner) {
+	m.CustomerID = owner.CustomerID
+	m.SessionToken = owner.SessionToken
+}
+
+func (a *orderUsecase) GetPromotions(c context.Context, id int64) (_ *models.Promotions, err error) {
 
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
+	ctx, span := tracing.Start(ctx, "orderUsecase.GetPromotions")
+	defer tracing.End(span, &err)
 	res, err := a.orderRepo.GetPromotions(ctx, id)
 	if err != nil {
 		return nil, err
@@ -69,59 +117,603 @@ func (a *orderUsecase) GetPromotions(c context.Context, id int64) (*models.Promo
 	return res, nil
 }
 
-func (a *orderUsecase) CreateCart(c context.Context, m *models.Cart) error {
+func (a *orderUsecase) GetOrder(c context.Context, id int64) (_ *models.Order, err error) {
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "orderUsecase.GetOrder")
+	defer tracing.End(span, &err)
+	res, err := a.orderRepo.GetOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// MyOrders pages through the order history of a customer with the lines of every order, newest first
+func (a *orderUsecase) MyOrders(c context.Context, customerID int64, cursor string, num int64) (_ *models.OrderPage, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "orderUsecase.MyOrders")
+	defer tracing.End(span, &err)
+
+	if num <= 0 {
+		num = defaultPageSize
+	}
+	if num > maxPageSize {
+		num = maxPageSize
+	}
+	if _, err := a.customerService.GetByID(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	orders, nextCursor, err := a.orderRepo.FetchOrders(ctx, customerID, cursor, num)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range orders {
+		if o.Details, err = a.orderRepo.GetOrderDetails(ctx, o.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.OrderPage{Orders: orders, NextCursor: nextCursor}, nil
+}
+
+func (a *orderUsecase) GetOrderDetails(c context.Context, orderID int64) (result []*models.OrderDetails, err error) {
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "orderUsecase.GetOrderDetails")
+	defer tracing.End(span, &err)
+	res, err := a.orderRepo.GetOrderDetails(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (a *orderUsecase) CreateCart(c context.Context, m *models.Cart) (err error) {
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
+	ctx, span := tracing.Start(ctx, "orderUsecase.CreateCart")
+	defer tracing.End(span, &err)
 
-	err := a.orderRepo.CreateCart(ctx, m)
+	owner, err := cartOwner(ctx)
+	if err != nil {
+		return err
+	}
+	own(m, owner)
+	err = a.orderRepo.CreateCart(ctx, m)
 	if err != nil {
 		return err
 	}
+	metrics.CartsCreated.Inc()
 	return nil
 }
 
-func (a *orderUsecase) CreateOrder(c context.Context, m *models.Order) error {
+func (a *orderUsecase) CreateOrder(c context.Context, m *models.Order) (err error) {
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
+	ctx, span := tracing.Start(ctx, "orderUsecase.CreateOrder")
+	defer tracing.End(span, &err)
 
-	err := a.orderRepo.CreateOrder(ctx, m)
+	err = a.orderRepo.CreateOrder(ctx, m)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (a *orderUsecase) CreateOrderDetails(c context.Context, m *models.OrderDetails) error {
+func (a *orderUsecase) UpdateOrderStatus(c context.Context, ar *models.Order) (err error) {
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "orderUsecase.UpdateOrderStatus")
+	defer tracing.End(span, &err)
+
+	ar.UpdatedAt = time.Now()
+	return a.orderRepo.UpdateOrderStatus(ctx, ar)
+}
+
+func (a *orderUsecase) CreateOrderDetails(c context.Context, m *models.OrderDetails) (err error) {
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
+	ctx, span := tracing.Start(ctx, "orderUsecase.CreateOrderDetails")
+	defer tracing.End(span, &err)
 
-	err := a.orderRepo.CreateOrderDetails(ctx, m)
+	err = a.orderRepo.CreateOrderDetails(ctx, m)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (a *orderUsecase) UpdateItems(c context.Context, ar *models.Items) error {
+func (a *orderUsecase) UpdateItems(c context.Context, ar *models.Items) (err error) {
 
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
+	ctx, span := tracing.Start(ctx, "orderUsecase.UpdateItems")
+	defer tracing.End(span, &err)
 
 	ar.UpdatedAt = time.Now()
 	return a.orderRepo.UpdateItems(ctx, ar)
 }
 
-func (a *orderUsecase) UpdateCart(c context.Context, ar *models.Cart) error {
+func (a *orderUsecase) UpdateCart(c context.Context, ar *models.Cart) (err error) {
 
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
+	ctx, span := tracing.Start(ctx, "orderUsecase.UpdateCart")
+	defer tracing.End(span, &err)
 
+	owner, err := cartOwner(ctx)
+	if err != nil {
+		return err
+	}
+	own(ar, owner)
 	ar.UpdatedAt = time.Now()
 	return a.orderRepo.UpdateCart(ctx, ar)
 }
 
-func (a *orderUsecase) DeleteCart(c context.Context) error {
+func (a *orderUsecase) DeleteCart(c context.Context) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "orderUsecase.DeleteCart")
+	defer tracing.End(span, &err)
+
+	owner, err := cartOwner(ctx)
+	if err != nil {
+		return err
+	}
+	return a.orderRepo.DeleteCart(ctx, owner)
+}
+
+func (a *orderUsecase) Reorder(c context.Context, orderID int64) (_ *models.ReorderResult, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "orderUsecase.Reorder")
+	defer tracing.End(span, &err)
+
+	owner, err := cartOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	o, err := a.orderRepo.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err = auth.AuthorizeOrder(ctx, o); err != nil {
+		return nil, err
+	}
+	details, err := a.orderRepo.GetOrderDetails(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// gift lines generated by free_items promotions are granted again by the promotion itself and the
+	// component lines of a kit come with the kit
+	skus := make([]string, 0)
+	names := make(map[string]string)
+	quantities := make(map[string]int64)
+	for _, d := range details {
+		if (d.PromoType == "free_items" && d.Price == 0) || d.ParentID != 0 {
+			continue
+		}
+		if _, ok := quantities[d.SKU]; !ok {
+			skus = append(skus, d.SKU)
+			names[d.SKU] = d.Name
+		}
+		quantities[d.SKU] += d.Quantity
+	}
+
+	res := &models.ReorderResult{
+		OrderID: orderID,
+		Carts:   make([]*models.Cart, 0),
+		Skipped: make([]*models.SkippedLine, 0),
+	}
+	if len(skus) == 0 {
+		return res, nil
+	}
+
+	items, err := a.orderRepo.GetItems(ctx, skus)
+	if err != nil {
+		return nil, err
+	}
+	itemsBySKU := make(map[string]*models.Items, len(items))
+	for _, it := range items {
+		itemsBySKU[it.SKU] = it
+	}
+	carts, err := a.orderRepo.GetCart(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	cartsByItem := make(map[int64]*models.Cart, len(carts))
+	for _, c := range carts {
+		cartsByItem[c.ItemsID] = c
+	}
+
+	for _, sku := range skus {
+		item, ok := itemsBySKU[sku]
+		if !ok {
+			res.Skipped = append(res.Skipped, &models.SkippedLine{
+				SKU:      sku,
+				Name:     names[sku],
+				Quantity: quantities[sku],
+				Reason:   "item no longer exists",
+			})
+			continue
+		}
+
+		promotion, err := a.orderRepo.GetPromotions(ctx, item.ID)
+		if err != nil {
+			return nil, err
+		}
+		cart, found := cartsByItem[item.ID]
+		quantity := quantities[sku]
+		if found {
+			quantity += cart.Quantity
+		}
+		if item.InventoryQuantity < quantity+promotion.FreeQuantity(quantity) {
+			res.Skipped = append(res.Skipped, &models.SkippedLine{
+				SKU:      sku,
+				Name:     item.Name,
+				Quantity: quantities[sku],
+				Reason:   "insufficient stock",
+			})
+			metrics.OutOfStockRejections.WithLabelValues("Reorder").Inc()
+			continue
+		}
+
+		now := time.Now()
+		if found {
+			cart.Quantity = quantity
+			cart.UpdatedAt = now
+			err = a.orderRepo.UpdateCart(ctx, cart)
+		} else {
+			cart = &models.Cart{
+				ItemsID:   item.ID,
+				Quantity:  quantity,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			own(cart, owner)
+			err = a.orderRepo.CreateCart(ctx, cart)
+			if err == nil {
+				metrics.CartsCreated.Inc()
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		res.Carts = append(res.Carts, cart)
+	}
+
+	return res, nil
+}
+
+// MergeCart moves the guest cart of the session into the cart of the signed in customer in a single transaction.
+// Quantities of the same item are summed, lines of items that are gone or lack the stock for the sum, gifts of
+// free_items promotions included, are left out and reported. The guest cart is emptied either way.
+func (a *orderUsecase) MergeCart(c context.Context) (_ *models.CartMergeResult, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "orderUsecase.MergeCart")
+	defer tracing.End(span, &err)
+
+	customerID, err := auth.CustomerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mine := models.CartOwner{CustomerID: customerID}
+	guest := models.CartOwner{SessionToken: auth.SessionFromContext(ctx)}
+	if guest.SessionToken == "" {
+		return nil, models.ErrBadParamInput
+	}
+
+	res := &models.CartMergeResult{Skipped: make([]*models.SkippedLine, 0)}
+	err = a.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		guestCarts, err := a.orderRepo.GetCart(ctx, guest)
+		if err != nil {
+			return err
+		}
+		carts, err := a.orderRepo.GetCart(ctx, mine)
+		if err != nil {
+			return err
+		}
+		if len(guestCarts) == 0 {
+			res.Carts = carts
+			return nil
+		}
+
+		items, err := a.cartItems(ctx, guestCarts)
+		if err != nil {
+			return err
+		}
+		cartsByItem := make(map[int64]*models.Cart, len(carts))
+		for _, c := range carts {
+			cartsByItem[c.ItemsID] = c
+		}
+
+		now := time.Now()
+		for _, g := range guestCarts {
+			item, ok := items[g.ItemsID]
+			if !ok {
+				res.Skipped = append(res.Skipped, &models.SkippedLine{Quantity: g.Quantity, Reason: "item no longer exists"})
+				continue
+			}
+			promotion, err := a.orderRepo.GetPromotions(ctx, item.ID)
+			if err != nil {
+				return err
+			}
+			cart, found := cartsByItem[item.ID]
+			quantity := g.Quantity
+			if found {
+				quantity += cart.Quantity
+			}
+			if item.InventoryQuantity < quantity+promotion.FreeQuantity(quantity) {
+				res.Skipped = append(res.Skipped, &models.SkippedLine{
+					SKU:      item.SKU,
+					Name:     item.Name,
+					Quantity: g.Quantity,
+					Reason:   "insufficient stock",
+				})
+				metrics.OutOfStockRejections.WithLabelValues("MergeCart").Inc()
+				continue
+			}
+
+			if found {
+				cart.Quantity = quantity
+				cart.UpdatedAt = now
+				err = a.orderRepo.UpdateCart(ctx, cart)
+			} else {
+				cart = &models.Cart{ItemsID: item.ID, Quantity: quantity, CreatedAt: now, UpdatedAt: now}
+				own(cart, mine)
+				err = a.orderRepo.CreateCart(ctx, cart)
+				carts = append(carts, cart)
+				cartsByItem[item.ID] = cart
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		res.Carts = carts
+		return a.orderRepo.DeleteCart(ctx, guest)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// cartItems returns the items of the cart lines still in the catalog by id
+func (a *orderUsecase) cartItems(ctx context.Context, carts []*models.Cart) (_ map[int64]*models.Items, err error) {
+	ctx, span := tracing.Start(ctx, "orderUsecase.cartItems")
+	defer tracing.End(span, &err)
+	ids := make([]int64, len(carts))
+	for i, c := range carts {
+		ids[i] = c.ItemsID
+	}
+	list, err := a.orderRepo.GetItemsById(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return map[int64]*models.Items{}, nil
+	}
+
+	// archived items are left out by the lookup by SKU
+	skus := make([]string, len(list))
+	for i, it := range list {
+		skus[i] = it.SKU
+	}
+	list, err = a.orderRepo.GetItems(ctx, skus)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[int64]*models.Items, len(list))
+	for _, it := range list {
+		res[it.ID] = it
+	}
+	return res, nil
+}
+
+// PlaceOrder stores the order with its lines, pays what it can with the tenders, empties the cart and takes
+// the items out of the stock in a single transaction. Every line is taxed at the rate of the tax rule applied
+// to its item and the tax is added to the total. An order fully paid by the tenders is created paid.
+// A kit line is followed by one zero priced line per component and the components leave the stock, not the kit.
+// The addresses of an order placed by a customer must be in the customer's address book.
+func (a *orderUsecase) PlaceOrder(c context.Context, o *models.Order, details []*models.OrderDetails, tenders []*models.Tender) (err error) {
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
-	return a.orderRepo.DeleteCart(ctx)
+	ctx, span := tracing.Start(ctx, "orderUsecase.PlaceOrder")
+	defer tracing.End(span, &err)
+
+	owner, err := cartOwner(ctx)
+	if err != nil {
+		return err
+	}
+	if err := a.checkCustomer(ctx, o); err != nil {
+		return err
+	}
+
+	err = a.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		components, err := a.kitComponents(ctx, details)
+		if err != nil {
+			return err
+		}
+		if err := a.applyTax(ctx, o, details); err != nil {
+			return err
+		}
+		if err := a.orderRepo.CreateOrder(ctx, o); err != nil {
+			return err
+		}
+		// the stock taken by the order summed per SKU, so a SKU ordered on its own and within kits is checked against its total
+		demand := make(map[string]int64)
+		for _, d := range details {
+			d.OrderID = o.ID
+			if err := a.orderRepo.CreateOrderDetails(ctx, d); err != nil {
+				return err
+			}
+			if len(components[d.ItemsID]) == 0 {
+				demand[d.SKU] += d.Quantity
+				continue
+			}
+			for _, k := range components[d.ItemsID] {
+				line := &models.OrderDetails{
+					OrderID:   o.ID,
+					ParentID:  d.ID,
+					ItemsID:   k.ItemsID,
+					SKU:       k.SKU,
+					Name:      k.Name,
+					Price:     0,
+					Quantity:  d.Quantity * k.Quantity,
+					CreatedAt: d.CreatedAt,
+					UpdatedAt: d.UpdatedAt,
+				}
+				if err := a.orderRepo.CreateOrderDetails(ctx, line); err != nil {
+					return err
+				}
+				demand[line.SKU] += line.Quantity
+			}
+		}
+
+		if len(tenders) > 0 {
+			paid, err := a.creditService.Redeem(ctx, o.ID, tenders, o.TotalPrice)
+			if err != nil {
+				return err
+			}
+			o.CreditAmount = paid
+			if o.AmountDue() <= 0 {
+				o.Status = models.OrderStatusPaid
+			}
+			if err := a.orderRepo.UpdateOrderStatus(ctx, o); err != nil {
+				return err
+			}
+		}
+
+		if err := a.orderRepo.DeleteCart(ctx, owner); err != nil {
+			return err
+		}
+		// the items are taken in the same order by every checkout, so concurrent ones wait for each other's rows
+		// instead of deadlocking, and the order is rolled back when one of them runs out
+		skus := make([]string, 0, len(demand))
+		for sku := range demand {
+			skus = append(skus, sku)
+		}
+		sort.Strings(skus)
+		for _, sku := range skus {
+			err := a.orderRepo.UpdateItems(ctx, &models.Items{
+				SKU:               sku,
+				InventoryQuantity: demand[sku],
+				UpdatedAt:         time.Now(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == models.ErrOutOfStock {
+		metrics.OutOfStockRejections.WithLabelValues("PlaceOrder").Inc()
+	}
+	if err != nil {
+		return err
+	}
+
+	metrics.OrdersCreated.Inc()
+	metrics.OrderRevenue.Add(o.TotalPrice)
+	return nil
+}
+
+// checkCustomer makes sure the customer of the order exists and owns its addresses, which must be of the right kind
+func (a *orderUsecase) checkCustomer(ctx context.Context, o *models.Order) (err error) {
+	ctx, span := tracing.Start(ctx, "orderUsecase.checkCustomer")
+	defer tracing.End(span, &err)
+	if o.CustomerID == 0 {
+		if o.ShippingAddressID != 0 || o.BillingAddressID != 0 {
+			return models.ErrBadParamInput
+		}
+		return nil
+	}
+	if _, err := a.customerService.GetByID(ctx, o.CustomerID); err != nil {
+		return err
+	}
+	addresses := []struct {
+		id   int64
+		kind string
+	}{
+		{o.ShippingAddressID, models.AddressKindShipping},
+		{o.BillingAddressID, models.AddressKindBilling},
+	}
+	for _, v := range addresses {
+		if v.id == 0 {
+			continue
+		}
+		address, err := a.customerService.GetAddress(ctx, o.CustomerID, v.id)
+		if err == models.ErrNotFound || (err == nil && address.Kind != v.kind) {
+			return models.ErrBadParamInput
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyTax sets the tax of every line from the tax rule of its item, see models.SelectTaxRule, and adds the
+// tax of the order to its total. Zero priced lines, gifts, carry no tax.
+func (a *orderUsecase) applyTax(ctx context.Context, o *models.Order, details []*models.OrderDetails) (err error) {
+	ctx, span := tracing.Start(ctx, "orderUsecase.applyTax")
+	defer tracing.End(span, &err)
+	rates := make(map[int64]float64)
+	o.TaxAmount = 0
+	for _, d := range details {
+		d.TaxAmount = 0
+		if d.Price <= 0 {
+			continue
+		}
+		rate, ok := rates[d.ItemsID]
+		if !ok {
+			rule, err := a.orderRepo.GetTaxRule(ctx, d.ItemsID)
+			if err != nil {
+				return err
+			}
+			rate = rule.Rate
+			rates[d.ItemsID] = rate
+		}
+		d.TaxAmount = math.Round(d.Price*rate*100) / 100
+		o.TaxAmount += d.TaxAmount
+	}
+	o.TaxAmount = math.Round(o.TaxAmount*100) / 100
+	o.TotalPrice = math.Round((o.TotalPrice+o.TaxAmount)*100) / 100
+	return nil
+}
+
+// kitComponents returns the components of the kits among the ordered items by kit id
+func (a *orderUsecase) kitComponents(ctx context.Context, details []*models.OrderDetails) (_ map[int64][]*models.KitComponent, err error) {
+	ctx, span := tracing.Start(ctx, "orderUsecase.kitComponents")
+	defer tracing.End(span, &err)
+	ids := make([]int64, 0, len(details))
+	seen := make(map[int64]bool, len(details))
+	for _, d := range details {
+		if d.ItemsID != 0 && !seen[d.ItemsID] {
+			seen[d.ItemsID] = true
+			ids = append(ids, d.ItemsID)
+		}
+	}
+	list, err := a.orderRepo.GetKitComponents(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[int64][]*models.KitComponent)
+	for _, k := range list {
+		res[k.KitID] = append(res[k.KitID], k)
+	}
+	return res, nil
 }
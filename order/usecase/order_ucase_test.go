@@ -9,201 +9,354 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/williamchand/kuncie-cart/auth"
+	_creditMocks "github.com/williamchand/kuncie-cart/credit/mocks"
+	_customerMocks "github.com/williamchand/kuncie-cart/customer/mocks"
 	"github.com/williamchand/kuncie-cart/models"
 	"github.com/williamchand/kuncie-cart/order/mocks"
 	ucase "github.com/williamchand/kuncie-cart/order/usecase"
+	_txMocks "github.com/williamchand/kuncie-cart/transaction/mocks"
 )
 
-func TestFetch(t *testing.T) {
-	mockOrderRepo := new(mocks.Repository)
-	mockOrder := &models.Items{
-		SKU:               "Hello",
-		Name:              "Content",
-		Price:             3222223.0,
-		InventoryQuantity: 4,
-	}
-
-	mockListOrder := make([]*models.Items, 0)
-	mockListOrder = append(mockListOrder, mockOrder)
+// guestSession is the session token of the anonymous shopper of the tests
+const guestSession = "0123456789abcdef0123"
 
+func TestGetOrder(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
-		mockOrderRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"),
-			mock.AnythingOfType("int64")).Return(mockListArtilce, "next-cursor", nil).Once()
-		mockAuthor := &models.Author{
-			ID:   1,
-			Name: "Iman Tumorang",
-		}
-		u := ucase.NewOrderUsecase(mockOrderRepo, time.Second*2)
-		num := int64(1)
-		cursor := "12"
-		list, nextCursor, err := u.Fetch(context.TODO(), cursor, num)
-		cursorExpected := "next-cursor"
-		assert.Equal(t, cursorExpected, nextCursor)
-		assert.NotEmpty(t, nextCursor)
-		assert.NoError(t, err)
-		assert.Len(t, list, len(mockListArtilce))
+		mockOrderRepo := new(mocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).Return(&models.Order{ID: 7, CustomerID: 5}, nil).Once()
 
+		u := ucase.NewOrderUsecase(mockOrderRepo, new(_creditMocks.Usecase), new(_customerMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		o, err := u.GetOrder(context.TODO(), 7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), o.ID)
 		mockOrderRepo.AssertExpectations(t)
-		mockAuthorrepo.AssertExpectations(t)
 	})
 
 	t.Run("error-failed", func(t *testing.T) {
-		mockOrderRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"),
-			mock.AnythingOfType("int64")).Return(nil, "", errors.New("Unexpexted Error")).Once()
+		mockOrderRepo := new(mocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).Return(nil, errors.New("Unexpected")).Once()
 
-		u := ucase.NewOrderUsecase(mockOrderRepo, time.Second*2)
-		num := int64(1)
-		cursor := "12"
-		list, nextCursor, err := u.Fetch(context.TODO(), cursor, num)
+		u := ucase.NewOrderUsecase(mockOrderRepo, new(_creditMocks.Usecase), new(_customerMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		o, err := u.GetOrder(context.TODO(), 7)
 
-		assert.Empty(t, nextCursor)
 		assert.Error(t, err)
-		assert.Len(t, list, 0)
+		assert.Nil(t, o)
 		mockOrderRepo.AssertExpectations(t)
-		mockAuthorrepo.AssertExpectations(t)
 	})
-
 }
 
-func TestGetByID(t *testing.T) {
-	mockOrderRepo := new(mocks.Repository)
-	mockOrder := models.Order{
-		Title:   "Hello",
-		Content: "Content",
-	}
-	mockAuthor := &models.Author{
-		ID:   1,
-		Name: "Iman Tumorang",
-	}
-
-	t.Run("success", func(t *testing.T) {
-		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(&mockOrder, nil).Once()
-		mockAuthorrepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockAuthor, nil)
-		u := ucase.NewOrderUsecase(mockOrderRepo, time.Second*2)
+func TestGetCart(t *testing.T) {
+	t.Run("customer", func(t *testing.T) {
+		mockOrderRepo := new(mocks.Repository)
+		mockOrderRepo.On("GetCart", mock.Anything, models.CartOwner{CustomerID: 5}).
+			Return([]*models.Cart{{ID: 1, CustomerID: 5, ItemsID: 2, Quantity: 1}}, nil).Once()
 
-		a, err := u.GetByID(context.TODO(), mockOrder.ID)
+		u := ucase.NewOrderUsecase(mockOrderRepo, new(_creditMocks.Usecase), new(_customerMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		list, err := u.GetCart(auth.NewCustomerContext(context.TODO(), 5))
 
 		assert.NoError(t, err)
-		assert.NotNil(t, a)
-
+		assert.Len(t, list, 1)
 		mockOrderRepo.AssertExpectations(t)
-		mockAuthorrepo.AssertExpectations(t)
 	})
-	t.Run("error-failed", func(t *testing.T) {
-		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(nil, errors.New("Unexpected")).Once()
 
-		u := ucase.NewOrderUsecase(mockOrderRepo, time.Second*2)
+	t.Run("guest", func(t *testing.T) {
+		mockOrderRepo := new(mocks.Repository)
+		mockOrderRepo.On("GetCart", mock.Anything, models.CartOwner{SessionToken: guestSession}).Return([]*models.Cart{}, nil).Once()
 
-		a, err := u.GetByID(context.TODO(), mockOrder.ID)
-
-		assert.Error(t, err)
-		assert.Nil(t, a)
+		u := ucase.NewOrderUsecase(mockOrderRepo, new(_creditMocks.Usecase), new(_customerMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		list, err := u.GetCart(auth.NewSessionContext(context.TODO(), guestSession))
 
+		assert.NoError(t, err)
+		assert.Empty(t, list)
 		mockOrderRepo.AssertExpectations(t)
-		mockAuthorrepo.AssertExpectations(t)
 	})
 
+	t.Run("anonymous", func(t *testing.T) {
+		mockOrderRepo := new(mocks.Repository)
+
+		u := ucase.NewOrderUsecase(mockOrderRepo, new(_creditMocks.Usecase), new(_customerMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		list, err := u.GetCart(context.TODO())
+
+		assert.Equal(t, models.ErrUnauthorized, err)
+		assert.Nil(t, list)
+		mockOrderRepo.AssertNotCalled(t, "GetCart", mock.Anything, mock.Anything)
+	})
 }
 
-func TestStore(t *testing.T) {
-	mockOrderRepo := new(mocks.Repository)
-	mockOrder := models.Order{
-		Title:   "Hello",
-		Content: "Content",
+func TestReorder(t *testing.T) {
+	details := []*models.OrderDetails{
+		{ID: 1, OrderID: 7, SKU: "120P90", Name: "Google Home", Price: 99.98, Quantity: 3, PromoType: "bonus_price"},
+		{ID: 2, OrderID: 7, SKU: "43N23P", Name: "Macbook Pro", Price: 5399.99, Quantity: 1},
+		{ID: 3, OrderID: 7, SKU: "43N23P", Name: "Macbook Pro", Price: 0, Quantity: 1, PromoType: "free_items"},
+		{ID: 4, OrderID: 7, SKU: "OLD001", Name: "Discontinued", Price: 10, Quantity: 1},
 	}
 
 	t.Run("success", func(t *testing.T) {
-		tempMockOrder := mockOrder
-		tempMockOrder.ID = 0
-		mockOrderRepo.On("GetByTitle", mock.Anything, mock.AnythingOfType("string")).Return(nil, models.ErrNotFound).Once()
-		mockOrderRepo.On("Store", mock.Anything, mock.AnythingOfType("*models.Order")).Return(nil).Once()
-
-		u := ucase.NewOrderUsecase(mockOrderRepo, time.Second*2)
-
-		err := u.Store(context.TODO(), &tempMockOrder)
+		mockOrderRepo := new(mocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).Return(&models.Order{ID: 7, CustomerID: 5}, nil).Once()
+		mockOrderRepo.On("GetOrderDetails", mock.Anything, int64(7)).Return(details, nil).Once()
+		mockOrderRepo.On("GetItems", mock.Anything, []string{"120P90", "43N23P", "OLD001"}).Return([]*models.Items{
+			{ID: 1, SKU: "120P90", Name: "Google Home", Price: 49.99, InventoryQuantity: 10},
+			{ID: 2, SKU: "43N23P", Name: "Macbook Pro", Price: 5399.99, InventoryQuantity: 1},
+		}, nil).Once()
+		mockOrderRepo.On("GetCart", mock.Anything, models.CartOwner{CustomerID: 5}).Return([]*models.Cart{{ID: 5, ItemsID: 1, Quantity: 2}}, nil).Once()
+		mockOrderRepo.On("GetPromotions", mock.Anything, int64(1)).
+			Return(&models.Promotions{PromoType: "bonus_price", Promo: "99.98", QuantityRequirement: 3}, nil).Once()
+		mockOrderRepo.On("GetPromotions", mock.Anything, int64(2)).
+			Return(&models.Promotions{PromoType: "free_items", Promo: "4", QuantityRequirement: 1}, nil).Once()
+		mockOrderRepo.On("UpdateCart", mock.Anything, mock.AnythingOfType("*models.Cart")).Return(nil).Once()
+
+		u := ucase.NewOrderUsecase(mockOrderRepo, new(_creditMocks.Usecase), new(_customerMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		res, err := u.Reorder(auth.NewCustomerContext(context.TODO(), 5), 7)
 
 		assert.NoError(t, err)
-		assert.Equal(t, mockOrder.Title, tempMockOrder.Title)
+		assert.Len(t, res.Carts, 1)
+		assert.Equal(t, int64(5), res.Carts[0].Quantity)
+		assert.Len(t, res.Skipped, 2)
+		assert.Equal(t, "insufficient stock", res.Skipped[0].Reason)
+		assert.Equal(t, "item no longer exists", res.Skipped[1].Reason)
 		mockOrderRepo.AssertExpectations(t)
 	})
-	t.Run("existing-title", func(t *testing.T) {
-		existingOrder := mockOrder
-		mockOrderRepo.On("GetByTitle", mock.Anything, mock.AnythingOfType("string")).Return(&existingOrder, nil).Once()
-		mockAuthor := &models.Author{
-			ID:   1,
-			Name: "Iman Tumorang",
-		}
-		u := ucase.NewOrderUsecase(mockOrderRepo, time.Second*2)
 
-		err := u.Store(context.TODO(), &mockOrder)
+	t.Run("order-is-not-exist", func(t *testing.T) {
+		mockOrderRepo := new(mocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).Return(nil, models.ErrNotFound).Once()
 
-		assert.Error(t, err)
+		u := ucase.NewOrderUsecase(mockOrderRepo, new(_creditMocks.Usecase), new(_customerMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		res, err := u.Reorder(auth.NewCustomerContext(context.TODO(), 5), 7)
+
+		assert.Equal(t, models.ErrNotFound, err)
+		assert.Nil(t, res)
 		mockOrderRepo.AssertExpectations(t)
-		mockAuthorrepo.AssertExpectations(t)
 	})
-
 }
 
-func TestDelete(t *testing.T) {
-	mockOrderRepo := new(mocks.Repository)
-	mockOrder := models.Order{
-		Title:   "Hello",
-		Content: "Content",
+func TestPlaceOrder(t *testing.T) {
+	newDetails := func() []*models.OrderDetails {
+		return []*models.OrderDetails{
+			{SKU: "120P90", Name: "Google Home", Price: 99.98, Quantity: 2},
+			{SKU: "A304SD", Name: "Alexa Speaker", Price: 50.00, Quantity: 1},
+		}
 	}
 
-	t.Run("success", func(t *testing.T) {
-		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(&mockOrder, nil).Once()
-
-		mockOrderRepo.On("Delete", mock.Anything, mock.AnythingOfType("int64")).Return(nil).Once()
+	t.Run("partly-paid-by-gift-card", func(t *testing.T) {
+		mockOrderRepo := new(mocks.Repository)
+		mockOrderRepo.On("GetKitComponents", mock.Anything, []int64{}).Return([]*models.KitComponent{}, nil).Once()
+		mockOrderRepo.On("GetTaxRule", mock.Anything, mock.Anything).Return(&models.TaxRule{}, nil)
+		mockCredit := new(_creditMocks.Usecase)
+		o := &models.Order{TotalPrice: 149.98, Status: models.OrderStatusPending}
+		tenders := []*models.Tender{{Account: models.CreditAccountGiftCard, Code: "ABCD-EFGH-JKLM-NPQR"}}
+		mockOrderRepo.On("CreateOrder", mock.Anything, o).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.Order).ID = 12
+		}).Once()
+		mockOrderRepo.On("CreateOrderDetails", mock.Anything, mock.MatchedBy(func(d *models.OrderDetails) bool {
+			return d.OrderID == 12
+		})).Return(nil).Twice()
+		mockCredit.On("Redeem", mock.Anything, int64(12), tenders, 149.98).Return(100.0, nil).Once()
+		mockOrderRepo.On("UpdateOrderStatus", mock.Anything, o).Return(nil).Once()
+		mockOrderRepo.On("DeleteCart", mock.Anything, models.CartOwner{SessionToken: guestSession}).Return(nil).Once()
+		mockOrderRepo.On("UpdateItems", mock.Anything, mock.AnythingOfType("*models.Items")).Return(nil).Twice()
+
+		u := ucase.NewOrderUsecase(mockOrderRepo, mockCredit, new(_customerMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.PlaceOrder(auth.NewSessionContext(context.TODO(), guestSession), o, newDetails(), tenders)
 
-		u := ucase.NewOrderUsecase(mockOrderRepo, time.Second*2)
+		assert.NoError(t, err)
+		assert.Equal(t, 100.0, o.CreditAmount)
+		assert.Equal(t, 49.98, o.AmountDue())
+		assert.Equal(t, models.OrderStatusPending, o.Status)
+		mockOrderRepo.AssertExpectations(t)
+		mockCredit.AssertExpectations(t)
+	})
 
-		err := u.Delete(context.TODO(), mockOrder.ID)
+	t.Run("fully-paid-by-store-credit", func(t *testing.T) {
+		mockOrderRepo := new(mocks.Repository)
+		mockOrderRepo.On("GetKitComponents", mock.Anything, []int64{}).Return([]*models.KitComponent{}, nil).Once()
+		mockOrderRepo.On("GetTaxRule", mock.Anything, mock.Anything).Return(&models.TaxRule{}, nil)
+		mockCredit := new(_creditMocks.Usecase)
+		o := &models.Order{TotalPrice: 149.98, Status: models.OrderStatusPending}
+		tenders := []*models.Tender{{Account: models.CreditAccountStoreCredit, CustomerID: 5}}
+		mockOrderRepo.On("CreateOrder", mock.Anything, o).Return(nil).Once()
+		mockOrderRepo.On("CreateOrderDetails", mock.Anything, mock.AnythingOfType("*models.OrderDetails")).Return(nil).Twice()
+		mockCredit.On("Redeem", mock.Anything, mock.Anything, tenders, 149.98).Return(149.98, nil).Once()
+		mockOrderRepo.On("UpdateOrderStatus", mock.Anything, o).Return(nil).Once()
+		mockOrderRepo.On("DeleteCart", mock.Anything, models.CartOwner{SessionToken: guestSession}).Return(nil).Once()
+		mockOrderRepo.On("UpdateItems", mock.Anything, mock.AnythingOfType("*models.Items")).Return(nil).Twice()
+
+		u := ucase.NewOrderUsecase(mockOrderRepo, mockCredit, new(_customerMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.PlaceOrder(auth.NewSessionContext(context.TODO(), guestSession), o, newDetails(), tenders)
 
 		assert.NoError(t, err)
+		assert.Equal(t, models.OrderStatusPaid, o.Status)
 		mockOrderRepo.AssertExpectations(t)
-		mockAuthorrepo.AssertExpectations(t)
 	})
-	t.Run("order-is-not-exist", func(t *testing.T) {
-		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(nil, nil).Once()
 
-		u := ucase.NewOrderUsecase(mockOrderRepo, time.Second*2)
+	t.Run("kit-and-component-stock-summed", func(t *testing.T) {
+		mockOrderRepo := new(mocks.Repository)
+		// a kit of two 120P90 is ordered along with one 120P90 on its own
+		mockOrderRepo.On("GetKitComponents", mock.Anything, []int64{3, 1}).Return([]*models.KitComponent{
+			{KitID: 3, ItemsID: 1, SKU: "120P90", Quantity: 2},
+		}, nil).Once()
+		mockOrderRepo.On("GetTaxRule", mock.Anything, mock.Anything).Return(&models.TaxRule{}, nil)
+		o := &models.Order{TotalPrice: 149.98, Status: models.OrderStatusPending}
+		mockOrderRepo.On("CreateOrder", mock.Anything, o).Return(nil).Once()
+		mockOrderRepo.On("CreateOrderDetails", mock.Anything, mock.AnythingOfType("*models.OrderDetails")).Return(nil).Times(3)
+		mockOrderRepo.On("DeleteCart", mock.Anything, models.CartOwner{SessionToken: guestSession}).Return(nil).Once()
+		mockOrderRepo.On("UpdateItems", mock.Anything, mock.MatchedBy(func(i *models.Items) bool {
+			return i.SKU == "120P90" && i.InventoryQuantity == 3
+		})).Return(nil).Once()
+
+		u := ucase.NewOrderUsecase(mockOrderRepo, new(_creditMocks.Usecase), new(_customerMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		details := []*models.OrderDetails{
+			{ItemsID: 3, SKU: "KIT001", Name: "Speaker Kit", Price: 99.99, Quantity: 1},
+			{ItemsID: 1, SKU: "120P90", Name: "Google Home", Price: 49.99, Quantity: 1},
+		}
+		err := u.PlaceOrder(auth.NewSessionContext(context.TODO(), guestSession), o, details, nil)
 
-		err := u.Delete(context.TODO(), mockOrder.ID)
+		assert.NoError(t, err)
+		mockOrderRepo.AssertExpectations(t)
+	})
 
-		assert.Error(t, err)
+	t.Run("out-of-stock", func(t *testing.T) {
+		mockOrderRepo := new(mocks.Repository)
+		mockOrderRepo.On("GetKitComponents", mock.Anything, []int64{}).Return([]*models.KitComponent{}, nil).Once()
+		mockOrderRepo.On("GetTaxRule", mock.Anything, mock.Anything).Return(&models.TaxRule{}, nil)
+		o := &models.Order{TotalPrice: 149.98, Status: models.OrderStatusPending}
+		mockOrderRepo.On("CreateOrder", mock.Anything, o).Return(nil).Once()
+		mockOrderRepo.On("CreateOrderDetails", mock.Anything, mock.AnythingOfType("*models.OrderDetails")).Return(nil).Twice()
+		mockOrderRepo.On("DeleteCart", mock.Anything, models.CartOwner{SessionToken: guestSession}).Return(nil).Once()
+		// the items are taken in SKU order, the last unit of 120P90 went to another checkout
+		mockOrderRepo.On("UpdateItems", mock.Anything, mock.MatchedBy(func(i *models.Items) bool {
+			return i.SKU == "120P90"
+		})).Return(models.ErrOutOfStock).Once()
+
+		u := ucase.NewOrderUsecase(mockOrderRepo, new(_creditMocks.Usecase), new(_customerMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.PlaceOrder(auth.NewSessionContext(context.TODO(), guestSession), o, newDetails(), nil)
+
+		assert.Equal(t, models.ErrOutOfStock, err)
 		mockOrderRepo.AssertExpectations(t)
-		mockAuthorrepo.AssertExpectations(t)
+		mockOrderRepo.AssertNumberOfCalls(t, "UpdateItems", 1)
 	})
-	t.Run("error-happens-in-db", func(t *testing.T) {
-		mockOrderRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(nil, errors.New("Unexpected Error")).Once()
 
-		u := ucase.NewOrderUsecase(mockOrderRepo, mockAuthorrepo, time.Second*2)
+	t.Run("insufficient-balance", func(t *testing.T) {
+		mockOrderRepo := new(mocks.Repository)
+		mockOrderRepo.On("GetKitComponents", mock.Anything, []int64{}).Return([]*models.KitComponent{}, nil).Once()
+		mockOrderRepo.On("GetTaxRule", mock.Anything, mock.Anything).Return(&models.TaxRule{}, nil)
+		mockCredit := new(_creditMocks.Usecase)
+		o := &models.Order{TotalPrice: 149.98, Status: models.OrderStatusPending}
+		tenders := []*models.Tender{{Account: models.CreditAccountGiftCard, Code: "ABCD-EFGH-JKLM-NPQR", Amount: 120}}
+		mockOrderRepo.On("CreateOrder", mock.Anything, o).Return(nil).Once()
+		mockOrderRepo.On("CreateOrderDetails", mock.Anything, mock.AnythingOfType("*models.OrderDetails")).Return(nil).Twice()
+		mockCredit.On("Redeem", mock.Anything, mock.Anything, tenders, 149.98).Return(0.0, models.ErrInsufficientBalance).Once()
+
+		u := ucase.NewOrderUsecase(mockOrderRepo, mockCredit, new(_customerMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.PlaceOrder(auth.NewSessionContext(context.TODO(), guestSession), o, newDetails(), tenders)
+
+		assert.Equal(t, models.ErrInsufficientBalance, err)
+		mockOrderRepo.AssertNotCalled(t, "DeleteCart", mock.Anything, mock.Anything)
+		mockOrderRepo.AssertNotCalled(t, "UpdateItems", mock.Anything, mock.Anything)
+	})
 
-		err := u.Delete(context.TODO(), mockOrder.ID)
+	t.Run("taxed-by-the-most-specific-rule", func(t *testing.T) {
+		mockOrderRepo := new(mocks.Repository)
+		mockOrderRepo.On("GetKitComponents", mock.Anything, []int64{1, 2}).Return([]*models.KitComponent{}, nil).Once()
+		// 120P90 has a rule of its own, A304SD only the one of its category, see models.SelectTaxRule
+		mockOrderRepo.On("GetTaxRule", mock.Anything, int64(1)).Return(&models.TaxRule{ID: 2, Rate: 0.05, ItemsID: 1}, nil).Once()
+		mockOrderRepo.On("GetTaxRule", mock.Anything, int64(2)).Return(&models.TaxRule{ID: 1, Rate: 0.11, CategoryID: 4}, nil).Once()
+		o := &models.Order{TotalPrice: 149.98, Status: models.OrderStatusPending}
+		mockOrderRepo.On("CreateOrder", mock.Anything, mock.MatchedBy(func(o *models.Order) bool {
+			return o.TaxAmount == 10.5 && o.TotalPrice == 160.48
+		})).Return(nil).Once()
+		mockOrderRepo.On("CreateOrderDetails", mock.Anything, mock.AnythingOfType("*models.OrderDetails")).Return(nil).Times(3)
+		mockOrderRepo.On("DeleteCart", mock.Anything, models.CartOwner{SessionToken: guestSession}).Return(nil).Once()
+		mockOrderRepo.On("UpdateItems", mock.Anything, mock.AnythingOfType("*models.Items")).Return(nil).Twice()
+
+		u := ucase.NewOrderUsecase(mockOrderRepo, new(_creditMocks.Usecase), new(_customerMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		details := []*models.OrderDetails{
+			{ItemsID: 1, SKU: "120P90", Name: "Google Home", Price: 99.98, Quantity: 2},
+			{ItemsID: 2, SKU: "A304SD", Name: "Alexa Speaker", Price: 50.00, Quantity: 1},
+			// a gift of free_items is not taxed
+			{ItemsID: 1, SKU: "120P90", Name: "Google Home", Price: 0, Quantity: 1, PromoType: "free_items"},
+		}
+		err := u.PlaceOrder(auth.NewSessionContext(context.TODO(), guestSession), o, details, nil)
 
-		assert.Error(t, err)
+		assert.NoError(t, err)
+		assert.Equal(t, 5.0, details[0].TaxAmount)
+		assert.Equal(t, 5.5, details[1].TaxAmount)
+		assert.Equal(t, 0.0, details[2].TaxAmount)
+		assert.Equal(t, 10.5, o.TaxAmount)
+		assert.Equal(t, 160.48, o.TotalPrice)
 		mockOrderRepo.AssertExpectations(t)
-		mockAuthorrepo.AssertExpectations(t)
 	})
-
 }
 
-func TestUpdate(t *testing.T) {
-	mockOrderRepo := new(mocks.Repository)
-	mockOrder := models.Order{
-		Title:   "Hello",
-		Content: "Content",
-		ID:      23,
-	}
+func TestMergeCart(t *testing.T) {
+	ctx := auth.NewSessionContext(auth.NewCustomerContext(context.TODO(), 5), guestSession)
+	mine := models.CartOwner{CustomerID: 5}
+	guest := models.CartOwner{SessionToken: guestSession}
 
 	t.Run("success", func(t *testing.T) {
-		mockOrderRepo.On("Update", mock.Anything, &mockOrder).Once().Return(nil)
+		mockOrderRepo := new(mocks.Repository)
+		mockOrderRepo.On("GetCart", mock.Anything, guest).Return([]*models.Cart{
+			{ID: 1, SessionToken: guestSession, ItemsID: 1, Quantity: 2},
+			{ID: 2, SessionToken: guestSession, ItemsID: 2, Quantity: 3},
+			{ID: 3, SessionToken: guestSession, ItemsID: 3, Quantity: 1},
+		}, nil).Once()
+		mockOrderRepo.On("GetCart", mock.Anything, mine).Return([]*models.Cart{{ID: 4, CustomerID: 5, ItemsID: 1, Quantity: 1}}, nil).Once()
+		items := []*models.Items{
+			{ID: 1, SKU: "120P90", Name: "Google Home", Price: 49.99, InventoryQuantity: 10},
+			{ID: 2, SKU: "43N23P", Name: "Macbook Pro", Price: 5399.99, InventoryQuantity: 2},
+			{ID: 3, SKU: "A304SD", Name: "Alexa Speaker", Price: 109.50, InventoryQuantity: 10},
+		}
+		mockOrderRepo.On("GetItemsById", mock.Anything, []int64{1, 2, 3}).Return(items, nil).Once()
+		mockOrderRepo.On("GetItems", mock.Anything, []string{"120P90", "43N23P", "A304SD"}).Return(items, nil).Once()
+		mockOrderRepo.On("GetPromotions", mock.Anything, mock.AnythingOfType("int64")).Return(&models.Promotions{}, nil).Times(3)
+		mockOrderRepo.On("UpdateCart", mock.Anything, mock.MatchedBy(func(c *models.Cart) bool {
+			return c.ID == 4 && c.Quantity == 3
+		})).Return(nil).Once()
+		mockOrderRepo.On("CreateCart", mock.Anything, mock.MatchedBy(func(c *models.Cart) bool {
+			return c.ItemsID == 3 && c.Quantity == 1 && c.CustomerID == 5 && c.SessionToken == ""
+		})).Return(nil).Once()
+		mockOrderRepo.On("DeleteCart", mock.Anything, guest).Return(nil).Once()
+
+		u := ucase.NewOrderUsecase(mockOrderRepo, new(_creditMocks.Usecase), new(_customerMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		res, err := u.MergeCart(ctx)
 
-		u := ucase.NewOrderUsecase(mockOrderRepo, time.Second*2)
+		assert.NoError(t, err)
+		assert.Len(t, res.Carts, 2)
+		assert.Len(t, res.Skipped, 1)
+		assert.Equal(t, "43N23P", res.Skipped[0].SKU)
+		assert.Equal(t, "insufficient stock", res.Skipped[0].Reason)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("nothing-left-to-merge", func(t *testing.T) {
+		mockOrderRepo := new(mocks.Repository)
+		mockOrderRepo.On("GetCart", mock.Anything, guest).Return([]*models.Cart{{ID: 1, SessionToken: guestSession, ItemsID: 2, Quantity: 3}}, nil).Once()
+		mockOrderRepo.On("GetCart", mock.Anything, mine).Return([]*models.Cart{}, nil).Once()
+		mockOrderRepo.On("GetItemsById", mock.Anything, []int64{2}).Return([]*models.Items{}, nil).Once()
+		mockOrderRepo.On("DeleteCart", mock.Anything, guest).Return(nil).Once()
+
+		u := ucase.NewOrderUsecase(mockOrderRepo, new(_creditMocks.Usecase), new(_customerMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		res, err := u.MergeCart(ctx)
 
-		err := u.Update(context.TODO(), &mockOrder)
 		assert.NoError(t, err)
+		assert.Empty(t, res.Carts)
+		assert.Len(t, res.Skipped, 1)
+		assert.Equal(t, "item no longer exists", res.Skipped[0].Reason)
 		mockOrderRepo.AssertExpectations(t)
+		mockOrderRepo.AssertNotCalled(t, "CreateCart", mock.Anything, mock.Anything)
+	})
+
+	t.Run("without-session", func(t *testing.T) {
+		mockOrderRepo := new(mocks.Repository)
+
+		u := ucase.NewOrderUsecase(mockOrderRepo, new(_creditMocks.Usecase), new(_customerMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		res, err := u.MergeCart(auth.NewCustomerContext(context.TODO(), 5))
+
+		assert.Equal(t, models.ErrBadParamInput, err)
+		assert.Nil(t, res)
+		mockOrderRepo.AssertNotCalled(t, "DeleteCart", mock.Anything, mock.Anything)
 	})
 }
@@ -0,0 +1,51 @@
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/williamchand/kuncie-cart/payment"
+)
+
+type Resolver interface {
+	PaymentIntent(params graphql.ResolveParams) (interface{}, error)
+	PayOrder(params graphql.ResolveParams) (interface{}, error)
+}
+
+type resolver struct {
+	paymentService payment.Usecase
+}
+
+func (r resolver) PaymentIntent(params graphql.ResolveParams) (interface{}, error) {
+	orderID, ok := params.Args["order_id"].(int)
+	if !ok || orderID == 0 {
+		return nil, fmt.Errorf("order_id is not integer or zero")
+	}
+
+	res, err := r.paymentService.GetByOrder(params.Context, int64(orderID))
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func (r resolver) PayOrder(params graphql.ResolveParams) (interface{}, error) {
+	orderID, ok := params.Args["order_id"].(int)
+	if !ok || orderID == 0 {
+		return nil, fmt.Errorf("order_id is not integer or zero")
+	}
+
+	res, err := r.paymentService.PayOrder(params.Context, int64(orderID))
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func NewResolver(paymentService payment.Usecase) Resolver {
+	return &resolver{
+		paymentService: paymentService,
+	}
+}
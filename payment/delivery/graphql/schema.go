@@ -0,0 +1,83 @@
+package graphql
+
+import "github.com/graphql-go/graphql"
+
+// PaymentIntentGraphQL holds payment intent information with graphql object
+var PaymentIntentGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "PaymentIntent",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"order_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"provider": &graphql.Field{
+				Type: graphql.String,
+			},
+			"amount": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"refunded_amount": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"status": &graphql.Field{
+				Type: graphql.String,
+			},
+			"last_error": &graphql.Field{
+				Type: graphql.String,
+			},
+			"updated_at": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+			"created_at": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+		},
+	},
+)
+
+// Schema is struct which has method for Query and Mutation fields. Please init this struct using constructor function.
+type Schema struct {
+	paymentResolver Resolver
+}
+
+// QueryFields initializes the payment fields of the graphql query.
+func (s Schema) QueryFields() graphql.Fields {
+	return graphql.Fields{
+		"PaymentIntent": &graphql.Field{
+			Type:        PaymentIntentGraphQL,
+			Description: "Get the payment of an order",
+			Args: graphql.FieldConfigArgument{
+				"order_id": &graphql.ArgumentConfig{
+					Type: graphql.Int,
+				},
+			},
+			Resolve: s.paymentResolver.PaymentIntent,
+		},
+	}
+}
+
+// MutationFields initializes the payment fields of the graphql mutation.
+func (s Schema) MutationFields() graphql.Fields {
+	return graphql.Fields{
+		"PayOrder": &graphql.Field{
+			Type:        PaymentIntentGraphQL,
+			Description: "Pay a pending order with the configured payment provider",
+			Args: graphql.FieldConfigArgument{
+				"order_id": &graphql.ArgumentConfig{
+					Type: graphql.Int,
+				},
+			},
+			Resolve: s.paymentResolver.PayOrder,
+		},
+	}
+}
+
+// NewSchema initializes Schema struct which takes resolver as the argument.
+func NewSchema(paymentResolver Resolver) Schema {
+	return Schema{
+		paymentResolver: paymentResolver,
+	}
+}
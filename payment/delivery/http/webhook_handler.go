@@ -0,0 +1,132 @@
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/payment"
+)
+
+const (
+	// HeaderSignature carries the hex encoded HMAC-SHA256 of the timestamp, a dot and the body
+	HeaderSignature = "X-Webhook-Signature"
+	// HeaderTimestamp carries the unix time at which the provider signed the notification
+	HeaderTimestamp = "X-Webhook-Timestamp"
+)
+
+// ResponseError represent the reseponse error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// ResponseStatus represent the acknowledgement sent back to the provider
+type ResponseStatus struct {
+	Status string `json:"status"`
+}
+
+// WebhookHandler  represent the httphandler for payment notifications
+type WebhookHandler struct {
+	PUsecase payment.Usecase
+	// Secret is shared with the provider to sign the notifications
+	Secret string
+	// Tolerance is how old a signature may be before the notification is refused as a replay
+	Tolerance time.Duration
+	Now       func() time.Time
+}
+
+// NewWebhookHandler will initialize the payments/webhook resources endpoint
+func NewWebhookHandler(e *echo.Echo, us payment.Usecase, secret string, tolerance time.Duration) {
+	handler := &WebhookHandler{
+		PUsecase:  us,
+		Secret:    secret,
+		Tolerance: tolerance,
+		Now:       time.Now,
+	}
+	e.POST("/payments/webhook", handler.Receive)
+}
+
+// Sign computes the signature of a notification body sent at timestamp
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Receive verifies a payment notification and applies it to the payment intent
+func (h *WebhookHandler) Receive(c echo.Context) error {
+	body, err := ioutil.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+	}
+
+	timestamp := c.Request().Header.Get(HeaderTimestamp)
+	signature, err := hex.DecodeString(c.Request().Header.Get(HeaderSignature))
+	if err != nil || h.Secret == "" {
+		return c.JSON(http.StatusUnauthorized, ResponseError{Message: "invalid signature"})
+	}
+	expected, _ := hex.DecodeString(Sign(h.Secret, timestamp, body))
+	if !hmac.Equal(signature, expected) {
+		return c.JSON(http.StatusUnauthorized, ResponseError{Message: "invalid signature"})
+	}
+
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: "invalid timestamp"})
+	}
+	if age := h.Now().Sub(time.Unix(sent, 0)); math.Abs(float64(age)) > float64(h.Tolerance) {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: "timestamp outside of the tolerance"})
+	}
+
+	var event models.PaymentEvent
+	if err = json.Unmarshal(body, &event); err != nil {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+	}
+	if err = validator.New().Struct(event); err != nil {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	err = h.PUsecase.HandleEvent(ctx, &event)
+	if err == models.ErrConflict {
+		return c.JSON(http.StatusOK, ResponseStatus{Status: "duplicate"})
+	}
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, ResponseStatus{Status: "processed"})
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case models.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case models.ErrNotFound:
+		return http.StatusNotFound
+	case models.ErrConflict:
+		return http.StatusConflict
+	case models.ErrBadParamInput:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
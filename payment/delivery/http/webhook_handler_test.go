@@ -0,0 +1,93 @@
+package http_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/williamchand/kuncie-cart/models"
+	paymentHttp "github.com/williamchand/kuncie-cart/payment/delivery/http"
+	"github.com/williamchand/kuncie-cart/payment/mocks"
+)
+
+const secret = "whsec_test"
+
+var now = time.Date(2022, 2, 22, 9, 0, 0, 0, time.UTC)
+
+func newRequest(t *testing.T, payload string, sentAt time.Time, sign func(timestamp string, body []byte) string) *http.Request {
+	body, err := ioutil.ReadFile(filepath.Join("testdata", payload))
+	require.NoError(t, err)
+
+	timestamp := strconv.FormatInt(sentAt.Unix(), 10)
+	req := httptest.NewRequest(echo.POST, "/payments/webhook", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(paymentHttp.HeaderTimestamp, timestamp)
+	req.Header.Set(paymentHttp.HeaderSignature, sign(timestamp, body))
+	return req
+}
+
+func validSignature(timestamp string, body []byte) string {
+	return paymentHttp.Sign(secret, timestamp, body)
+}
+
+func TestReceive(t *testing.T) {
+	tests := []struct {
+		name      string
+		payload   string
+		sentAt    time.Time
+		sign      func(timestamp string, body []byte) string
+		handleErr error
+		handled   bool
+		status    int
+	}{
+		{name: "captured", payload: "captured.json", sentAt: now.Add(-time.Minute), sign: validSignature, handled: true, status: http.StatusOK},
+		{name: "declined", payload: "declined.json", sentAt: now, sign: validSignature, handled: true, status: http.StatusOK},
+		{name: "refunded", payload: "refunded.json", sentAt: now, sign: validSignature, handled: true, status: http.StatusOK},
+		{name: "duplicate", payload: "captured.json", sentAt: now, sign: validSignature, handleErr: models.ErrConflict, handled: true, status: http.StatusOK},
+		{name: "unknown-intent", payload: "captured.json", sentAt: now, sign: validSignature, handleErr: models.ErrNotFound, handled: true, status: http.StatusNotFound},
+		{name: "wrong-secret", payload: "captured.json", sentAt: now, status: http.StatusUnauthorized,
+			sign: func(timestamp string, body []byte) string { return paymentHttp.Sign("other", timestamp, body) }},
+		{name: "tampered-body", payload: "captured.json", sentAt: now, status: http.StatusUnauthorized,
+			sign: func(timestamp string, body []byte) string { return validSignature(timestamp, append(body, ' ')) }},
+		{name: "replayed", payload: "captured.json", sentAt: now.Add(-time.Hour), sign: validSignature, status: http.StatusBadRequest},
+		{name: "missing-id", payload: "missing_id.json", sentAt: now, sign: validSignature, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUCase := new(mocks.Usecase)
+			if tt.handled {
+				mockUCase.On("HandleEvent", mock.Anything, mock.AnythingOfType("*models.PaymentEvent")).Return(tt.handleErr).Once()
+			}
+
+			e := echo.New()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(newRequest(t, tt.payload, tt.sentAt, tt.sign), rec)
+			handler := paymentHttp.WebhookHandler{
+				PUsecase:  mockUCase,
+				Secret:    secret,
+				Tolerance: 5 * time.Minute,
+				Now:       func() time.Time { return now },
+			}
+
+			err := handler.Receive(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.status, rec.Code)
+			if !tt.handled {
+				mockUCase.AssertNotCalled(t, "HandleEvent", mock.Anything, mock.Anything)
+			}
+			mockUCase.AssertExpectations(t)
+		})
+	}
+}
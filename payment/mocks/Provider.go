@@ -0,0 +1,98 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	models "github.com/williamchand/kuncie-cart/models"
+)
+
+// Provider is an autogenerated mock type for the Provider type
+type Provider struct {
+	mock.Mock
+}
+
+// Authorize provides a mock function with given fields: ctx, intent
+func (_m *Provider) Authorize(ctx context.Context, intent *models.PaymentIntent) (string, error) {
+	ret := _m.Called(ctx, intent)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PaymentIntent) string); ok {
+		r0 = rf(ctx, intent)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *models.PaymentIntent) error); ok {
+		r1 = rf(ctx, intent)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Capture provides a mock function with given fields: ctx, ref, amount
+func (_m *Provider) Capture(ctx context.Context, ref string, amount float64) error {
+	ret := _m.Called(ctx, ref, amount)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, float64) error); ok {
+		r0 = rf(ctx, ref, amount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Name provides a mock function with given fields:
+func (_m *Provider) Name() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// Refund provides a mock function with given fields: ctx, ref, amount, key
+func (_m *Provider) Refund(ctx context.Context, ref string, amount float64, key string) (string, error) {
+	ret := _m.Called(ctx, ref, amount, key)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string, float64, string) string); ok {
+		r0 = rf(ctx, ref, amount, key)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, float64, string) error); ok {
+		r1 = rf(ctx, ref, amount, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Void provides a mock function with given fields: ctx, ref
+func (_m *Provider) Void(ctx context.Context, ref string) error {
+	ret := _m.Called(ctx, ref)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, ref)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,210 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+	models "github.com/williamchand/kuncie-cart/models"
+)
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+// AddRefunded provides a mock function with given fields: ctx, id, amount
+func (_m *Repository) AddRefunded(ctx context.Context, id int64, amount float64) error {
+	ret := _m.Called(ctx, id, amount)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, float64) error); ok {
+		r0 = rf(ctx, id, amount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Claim provides a mock function with given fields: ctx, a, from
+func (_m *Repository) Claim(ctx context.Context, a *models.PaymentIntent, from ...string) error {
+	_va := make([]interface{}, len(from))
+	for _i := range from {
+		_va[_i] = from[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, a)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PaymentIntent, ...string) error); ok {
+		r0 = rf(ctx, a, from...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClaimStale provides a mock function with given fields: ctx, a, before, from
+func (_m *Repository) ClaimStale(ctx context.Context, a *models.PaymentIntent, before time.Time, from ...string) error {
+	_va := make([]interface{}, len(from))
+	for _i := range from {
+		_va[_i] = from[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, a, before)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PaymentIntent, time.Time, ...string) error); ok {
+		r0 = rf(ctx, a, before, from...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchRefunds provides a mock function with given fields: ctx, intentID
+func (_m *Repository) FetchRefunds(ctx context.Context, intentID int64) ([]*models.PaymentRefund, error) {
+	ret := _m.Called(ctx, intentID)
+
+	var r0 []*models.PaymentRefund
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*models.PaymentRefund); ok {
+		r0 = rf(ctx, intentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.PaymentRefund)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, intentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByOrder provides a mock function with given fields: ctx, orderID
+func (_m *Repository) GetByOrder(ctx context.Context, orderID int64) (*models.PaymentIntent, error) {
+	ret := _m.Called(ctx, orderID)
+
+	var r0 *models.PaymentIntent
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.PaymentIntent); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PaymentIntent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByProviderRef provides a mock function with given fields: ctx, provider, ref
+func (_m *Repository) GetByProviderRef(ctx context.Context, provider string, ref string) (*models.PaymentIntent, error) {
+	ret := _m.Called(ctx, provider, ref)
+
+	var r0 *models.PaymentIntent
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.PaymentIntent); ok {
+		r0 = rf(ctx, provider, ref)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PaymentIntent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, provider, ref)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store provides a mock function with given fields: ctx, a
+func (_m *Repository) Store(ctx context.Context, a *models.PaymentIntent) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PaymentIntent) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreEvent provides a mock function with given fields: ctx, a
+func (_m *Repository) StoreEvent(ctx context.Context, a *models.PaymentEvent) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PaymentEvent) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreRefund provides a mock function with given fields: ctx, a
+func (_m *Repository) StoreRefund(ctx context.Context, a *models.PaymentRefund) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PaymentRefund) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, a
+func (_m *Repository) Update(ctx context.Context, a *models.PaymentIntent) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PaymentIntent) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateRefund provides a mock function with given fields: ctx, a
+func (_m *Repository) UpdateRefund(ctx context.Context, a *models.PaymentRefund) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PaymentRefund) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,97 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	models "github.com/williamchand/kuncie-cart/models"
+)
+
+// Usecase is an autogenerated mock type for the Usecase type
+type Usecase struct {
+	mock.Mock
+}
+
+// GetByOrder provides a mock function with given fields: ctx, orderID
+func (_m *Usecase) GetByOrder(ctx context.Context, orderID int64) (*models.PaymentIntent, error) {
+	ret := _m.Called(ctx, orderID)
+
+	var r0 *models.PaymentIntent
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.PaymentIntent); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PaymentIntent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HandleEvent provides a mock function with given fields: ctx, event
+func (_m *Usecase) HandleEvent(ctx context.Context, event *models.PaymentEvent) error {
+	ret := _m.Called(ctx, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PaymentEvent) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PayOrder provides a mock function with given fields: ctx, orderID
+func (_m *Usecase) PayOrder(ctx context.Context, orderID int64) (*models.PaymentIntent, error) {
+	ret := _m.Called(ctx, orderID)
+
+	var r0 *models.PaymentIntent
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.PaymentIntent); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PaymentIntent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Refund provides a mock function with given fields: ctx, orderID, amount, key
+func (_m *Usecase) Refund(ctx context.Context, orderID int64, amount float64, key string) (*models.PaymentIntent, error) {
+	ret := _m.Called(ctx, orderID, amount, key)
+
+	var r0 *models.PaymentIntent
+	if rf, ok := ret.Get(0).(func(context.Context, int64, float64, string) *models.PaymentIntent); ok {
+		r0 = rf(ctx, orderID, amount, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PaymentIntent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, float64, string) error); ok {
+		r1 = rf(ctx, orderID, amount, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
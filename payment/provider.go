@@ -0,0 +1,22 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// Provider represent the contract of a payment gateway.
+// Authorize holds the amount of the intent and returns the reference the provider knows the payment by,
+// a refused payment is reported with models.ErrPaymentDeclined. Payment attempts can be sent again after a crash,
+// so authorizing an intent that still holds an authorization returns its reference without holding the amount
+// twice, and capturing the amount already captured on a reference succeeds without collecting it twice.
+// Refund returns the id the provider knows the refund by, its notifications carry it. A refund sent again with
+// the key of an earlier one returns the id of that refund without giving the amount back twice.
+type Provider interface {
+	Name() string
+	Authorize(ctx context.Context, intent *models.PaymentIntent) (string, error)
+	Capture(ctx context.Context, ref string, amount float64) error
+	Void(ctx context.Context, ref string) error
+	Refund(ctx context.Context, ref string, amount float64, key string) (string, error)
+}
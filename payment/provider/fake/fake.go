@@ -0,0 +1,145 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/payment"
+)
+
+// Mode selects how the fake provider answers
+type Mode string
+
+const (
+	// Succeed accepts every operation
+	Succeed Mode = "succeed"
+	// Decline refuses every authorization
+	Decline Mode = "decline"
+	// Timeout never answers and waits for the context to be done
+	Timeout Mode = "timeout"
+)
+
+// Name is the provider name recorded on the payment intents
+const Name = "fake"
+
+type charge struct {
+	authorized float64
+	captured   float64
+	refunded   float64
+	voided     bool
+}
+
+type fakeProvider struct {
+	mode    Mode
+	counter int64
+
+	mu      sync.Mutex
+	charges map[string]*charge
+	intents map[int64]string
+	refunds map[string]string
+}
+
+// NewFakeProvider will create an in-process object that represent the payment.Provider interface,
+// keeping its charges in memory. It is meant for tests and local development.
+func NewFakeProvider(mode Mode) payment.Provider {
+	return &fakeProvider{
+		mode:    mode,
+		charges: make(map[string]*charge),
+		intents: make(map[int64]string),
+		refunds: make(map[string]string),
+	}
+}
+
+func (p *fakeProvider) Name() string {
+	return Name
+}
+
+func (p *fakeProvider) answer(ctx context.Context) error {
+	if p.mode == Timeout {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return ctx.Err()
+}
+
+func (p *fakeProvider) Authorize(ctx context.Context, intent *models.PaymentIntent) (string, error) {
+	if err := p.answer(ctx); err != nil {
+		return "", err
+	}
+	if p.mode == Decline {
+		return "", models.ErrPaymentDeclined
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if ref, ok := p.intents[intent.ID]; ok && intent.ID != 0 && !p.charges[ref].voided {
+		return ref, nil
+	}
+	ref := fmt.Sprintf("fake_%d_%d", intent.OrderID, atomic.AddInt64(&p.counter, 1))
+	p.charges[ref] = &charge{authorized: intent.Amount}
+	p.intents[intent.ID] = ref
+
+	return ref, nil
+}
+
+func (p *fakeProvider) Capture(ctx context.Context, ref string, amount float64) error {
+	if err := p.answer(ctx); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c, ok := p.charges[ref]
+	if ok && c.captured > 0 && math.Abs(c.captured-amount) < 0.005 {
+		return nil
+	}
+	if !ok || c.voided || c.captured+amount > c.authorized+0.005 {
+		return models.ErrBadParamInput
+	}
+	c.captured += amount
+
+	return nil
+}
+
+func (p *fakeProvider) Void(ctx context.Context, ref string) error {
+	if err := p.answer(ctx); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c, ok := p.charges[ref]
+	if !ok || c.captured > 0 {
+		return models.ErrBadParamInput
+	}
+	c.voided = true
+
+	return nil
+}
+
+func (p *fakeProvider) Refund(ctx context.Context, ref string, amount float64, key string) (string, error) {
+	if err := p.answer(ctx); err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if id, ok := p.refunds[ref+"/"+key]; ok && key != "" {
+		return id, nil
+	}
+	c, ok := p.charges[ref]
+	if !ok || c.refunded+amount > c.captured+0.005 {
+		return "", models.ErrBadParamInput
+	}
+	c.refunded += amount
+	id := fmt.Sprintf("fake_refund_%d", atomic.AddInt64(&p.counter, 1))
+	if key != "" {
+		p.refunds[ref+"/"+key] = id
+	}
+
+	return id, nil
+}
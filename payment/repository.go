@@ -0,0 +1,23 @@
+package payment
+
+import (
+	"context"
+	"time"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// Repository represent the payment's repository contract
+type Repository interface {
+	GetByOrder(ctx context.Context, orderID int64) (*models.PaymentIntent, error)
+	GetByProviderRef(ctx context.Context, provider string, ref string) (*models.PaymentIntent, error)
+	Store(ctx context.Context, a *models.PaymentIntent) error
+	Update(ctx context.Context, a *models.PaymentIntent) error
+	Claim(ctx context.Context, a *models.PaymentIntent, from ...string) error
+	ClaimStale(ctx context.Context, a *models.PaymentIntent, before time.Time, from ...string) error
+	AddRefunded(ctx context.Context, id int64, amount float64) error
+	StoreEvent(ctx context.Context, a *models.PaymentEvent) error
+	FetchRefunds(ctx context.Context, intentID int64) (res []*models.PaymentRefund, err error)
+	StoreRefund(ctx context.Context, a *models.PaymentRefund) error
+	UpdateRefund(ctx context.Context, a *models.PaymentRefund) error
+}
@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/williamchand/kuncie-cart/logging"
+	"github.com/williamchand/kuncie-cart/metrics"
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/payment"
+	"github.com/williamchand/kuncie-cart/transaction"
+)
+
+// errDuplicateEntry is the mysql error number of a unique key violation
+const errDuplicateEntry = 1062
+
+type mysqlPaymentRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlPaymentRepository will create an object that represent the payment.Repository interface
+func NewMysqlPaymentRepository(Conn *sql.DB) payment.Repository {
+	return &mysqlPaymentRepository{Conn}
+}
+
+func (m *mysqlPaymentRepository) getOne(ctx context.Context, query string, args ...interface{}) (*models.PaymentIntent, error) {
+	res := new(models.PaymentIntent)
+	err := transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, query, args...).Scan(
+		&res.ID,
+		&res.OrderID,
+		&res.Provider,
+		&res.ProviderRef,
+		&res.Amount,
+		&res.RefundedAmount,
+		&res.Status,
+		&res.LastError,
+		&res.UpdatedAt,
+		&res.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (m *mysqlPaymentRepository) GetByOrder(ctx context.Context, orderID int64) (*models.PaymentIntent, error) {
+	defer metrics.ObserveQuery("payment", "GetByOrder", time.Now())
+	query := `SELECT id, order_id, provider, provider_ref, amount, refunded_amount, status, last_error, updated_at, created_at
+  						FROM payment_intents WHERE order_id = ?`
+
+	return m.getOne(ctx, query, orderID)
+}
+
+func (m *mysqlPaymentRepository) GetByProviderRef(ctx context.Context, provider string, ref string) (*models.PaymentIntent, error) {
+	defer metrics.ObserveQuery("payment", "GetByProviderRef", time.Now())
+	query := `SELECT id, order_id, provider, provider_ref, amount, refunded_amount, status, last_error, updated_at, created_at
+  						FROM payment_intents WHERE provider = ? AND provider_ref = ?`
+
+	return m.getOne(ctx, query, provider, ref)
+}
+
+func (m *mysqlPaymentRepository) Store(ctx context.Context, a *models.PaymentIntent) error {
+	defer metrics.ObserveQuery("payment", "Store", time.Now())
+	query := `INSERT payment_intents SET order_id=?, provider=?, provider_ref=?, amount=?, refunded_amount=?, status=?, last_error=?, updated_at=?, created_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, a.OrderID, a.Provider, a.ProviderRef, a.Amount, a.RefundedAmount, a.Status, a.LastError, a.UpdatedAt, a.CreatedAt)
+	if err != nil {
+		if me, ok := err.(*mysql.MySQLError); ok && me.Number == errDuplicateEntry {
+			return models.ErrConflict
+		}
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	a.ID = lastID
+	return nil
+}
+
+func (m *mysqlPaymentRepository) Update(ctx context.Context, a *models.PaymentIntent) error {
+	defer metrics.ObserveQuery("payment", "Update", time.Now())
+	query := `UPDATE payment_intents set provider_ref=?, amount=?, refunded_amount=?, status=?, last_error=?, updated_at=? WHERE id = ?`
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, a.ProviderRef, a.Amount, a.RefundedAmount, a.Status, a.LastError, a.UpdatedAt, a.ID)
+	if err != nil {
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect != 1 {
+		err = fmt.Errorf("Weird  Behaviour. Total Affected: %d", affect)
+
+		return err
+	}
+
+	return nil
+}
+
+// Claim updates the intent only while its status is one of from, in a single statement, so only one of the
+// concurrent callers succeeds. The others get models.ErrConflict.
+func (m *mysqlPaymentRepository) Claim(ctx context.Context, a *models.PaymentIntent, from ...string) error {
+	defer metrics.ObserveQuery("payment", "Claim", time.Now())
+	if len(from) == 0 {
+		return models.ErrConflict
+	}
+	query := `UPDATE payment_intents set provider_ref=?, amount=?, refunded_amount=?, status=?, last_error=?, updated_at=?
+  						WHERE id = ? AND status IN (?` + strings.Repeat(`,?`, len(from)-1) + `)`
+	args := []interface{}{a.ProviderRef, a.Amount, a.RefundedAmount, a.Status, a.LastError, a.UpdatedAt, a.ID}
+	for _, status := range from {
+		args = append(args, status)
+	}
+
+	res, err := transaction.Conn(ctx, m.Conn).ExecContext(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect != 1 {
+		return models.ErrConflict
+	}
+
+	return nil
+}
+
+// ClaimStale works as Claim for an intent left in one of the from statuses since before by an attempt that is
+// over, the condition on updated_at lets a single one of the concurrent callers take it over.
+func (m *mysqlPaymentRepository) ClaimStale(ctx context.Context, a *models.PaymentIntent, before time.Time, from ...string) error {
+	defer metrics.ObserveQuery("payment", "ClaimStale", time.Now())
+	if len(from) == 0 {
+		return models.ErrConflict
+	}
+	query := `UPDATE payment_intents set provider_ref=?, amount=?, refunded_amount=?, status=?, last_error=?, updated_at=?
+  						WHERE id = ? AND updated_at < ? AND status IN (?` + strings.Repeat(`,?`, len(from)-1) + `)`
+	args := []interface{}{a.ProviderRef, a.Amount, a.RefundedAmount, a.Status, a.LastError, a.UpdatedAt, a.ID, before}
+	for _, status := range from {
+		args = append(args, status)
+	}
+
+	res, err := transaction.Conn(ctx, m.Conn).ExecContext(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect != 1 {
+		return models.ErrConflict
+	}
+
+	return nil
+}
+
+// AddRefunded adds amount to the refunded amount of a captured intent in a single statement, so concurrent refunds
+// cannot give back more than was captured together. The intent is refunded once nothing is left to give back and
+// a negative amount cancels a refund. It returns models.ErrConflict when the intent was not captured or the
+// amount does not fit.
+func (m *mysqlPaymentRepository) AddRefunded(ctx context.Context, id int64, amount float64) error {
+	defer metrics.ObserveQuery("payment", "AddRefunded", time.Now())
+	query := `UPDATE payment_intents
+  						SET status = IF(refunded_amount + ? >= amount - 0.005, ?, ?), refunded_amount = ROUND(refunded_amount + ?, 2), updated_at = ?
+  						WHERE id = ? AND status IN (?, ?) AND refunded_amount + ? BETWEEN -0.005 AND amount + 0.005`
+
+	res, err := transaction.Conn(ctx, m.Conn).ExecContext(ctx, query, amount, models.PaymentStatusRefunded, models.PaymentStatusCaptured,
+		amount, time.Now(), id, models.PaymentStatusCaptured, models.PaymentStatusRefunded, amount)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect != 1 {
+		return models.ErrConflict
+	}
+
+	return nil
+}
+
+func (m *mysqlPaymentRepository) StoreEvent(ctx context.Context, a *models.PaymentEvent) error {
+	defer metrics.ObserveQuery("payment", "StoreEvent", time.Now())
+	query := `INSERT payment_events SET provider=?, event_id=?, type=?, provider_ref=?, refund_id=?, amount=?, created_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, a.Provider, a.EventID, a.Type, a.ProviderRef, a.RefundID, a.Amount, a.CreatedAt)
+	if err != nil {
+		if me, ok := err.(*mysql.MySQLError); ok && me.Number == errDuplicateEntry {
+			return models.ErrConflict
+		}
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	a.ID = lastID
+	return nil
+}
+
+func (m *mysqlPaymentRepository) FetchRefunds(ctx context.Context, intentID int64) ([]*models.PaymentRefund, error) {
+	defer metrics.ObserveQuery("payment", "FetchRefunds", time.Now())
+	query := `SELECT id, intent_id, IFNULL(refund_key, ''), provider_refund_id, amount, status, updated_at, created_at
+  						FROM payment_refunds WHERE intent_id = ? ORDER BY id`
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, intentID)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	result := make([]*models.PaymentRefund, 0)
+	for rows.Next() {
+		t := new(models.PaymentRefund)
+		err = rows.Scan(
+			&t.ID,
+			&t.IntentID,
+			&t.Key,
+			&t.ProviderRefundID,
+			&t.Amount,
+			&t.Status,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+func (m *mysqlPaymentRepository) StoreRefund(ctx context.Context, a *models.PaymentRefund) error {
+	defer metrics.ObserveQuery("payment", "StoreRefund", time.Now())
+	// refunds sent without a key are stored with a NULL one, so they are not unique per intent
+	query := `INSERT payment_refunds SET intent_id=?, refund_key=NULLIF(?, ''), provider_refund_id=?, amount=?, status=?, updated_at=?, created_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, a.IntentID, a.Key, a.ProviderRefundID, a.Amount, a.Status, a.UpdatedAt, a.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	a.ID = lastID
+	return nil
+}
+
+func (m *mysqlPaymentRepository) UpdateRefund(ctx context.Context, a *models.PaymentRefund) error {
+	defer metrics.ObserveQuery("payment", "UpdateRefund", time.Now())
+	query := `UPDATE payment_refunds set provider_refund_id=?, status=?, updated_at=? WHERE id = ?`
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, a.ProviderRefundID, a.Status, a.UpdatedAt, a.ID)
+	if err != nil {
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect != 1 {
+		err = fmt.Errorf("Weird  Behaviour. Total Affected: %d", affect)
+
+		return err
+	}
+
+	return nil
+}
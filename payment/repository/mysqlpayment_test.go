@@ -0,0 +1,86 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/williamchand/kuncie-cart/models"
+	paymentRepo "github.com/williamchand/kuncie-cart/payment/repository"
+)
+
+func TestClaim(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	intent := &models.PaymentIntent{ID: 1, OrderID: 7, Amount: 149.97, Status: models.PaymentStatusProcessing, UpdatedAt: time.Now()}
+	query := "UPDATE payment_intents set provider_ref=\\?, amount=\\?, refunded_amount=\\?, status=\\?, last_error=\\?, updated_at=\\?\\s+WHERE id = \\? AND status IN \\(\\?,\\?\\)"
+	args := []driver.Value{sqlmock.AnyArg(), 149.97, sqlmock.AnyArg(), models.PaymentStatusProcessing, sqlmock.AnyArg(), sqlmock.AnyArg(),
+		int64(1), models.PaymentStatusPending, models.PaymentStatusDeclined}
+	mock.ExpectExec(query).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 1))
+	// the other attempt finds the intent already processing
+	mock.ExpectExec(query).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	p := paymentRepo.NewMysqlPaymentRepository(db)
+	err = p.Claim(context.TODO(), intent, models.PaymentStatusPending, models.PaymentStatusDeclined)
+	assert.NoError(t, err)
+
+	err = p.Claim(context.TODO(), intent, models.PaymentStatusPending, models.PaymentStatusDeclined)
+	assert.Equal(t, models.ErrConflict, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimStale(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	before := time.Now().Add(-time.Minute)
+	intent := &models.PaymentIntent{ID: 1, OrderID: 7, Amount: 149.97, Status: models.PaymentStatusProcessing, UpdatedAt: time.Now()}
+	query := "UPDATE payment_intents set (.+) WHERE id = \\? AND updated_at < \\? AND status IN \\(\\?,\\?\\)"
+	args := []driver.Value{sqlmock.AnyArg(), 149.97, sqlmock.AnyArg(), models.PaymentStatusProcessing, sqlmock.AnyArg(), sqlmock.AnyArg(),
+		int64(1), before, models.PaymentStatusProcessing, models.PaymentStatusAuthorized}
+	mock.ExpectExec(query).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 1))
+	// the other attempt finds the intent taken over a moment ago
+	mock.ExpectExec(query).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	p := paymentRepo.NewMysqlPaymentRepository(db)
+	err = p.ClaimStale(context.TODO(), intent, before, models.PaymentStatusProcessing, models.PaymentStatusAuthorized)
+	assert.NoError(t, err)
+
+	err = p.ClaimStale(context.TODO(), intent, before, models.PaymentStatusProcessing, models.PaymentStatusAuthorized)
+	assert.Equal(t, models.ErrConflict, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddRefunded(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := "UPDATE payment_intents SET (.+) WHERE id = \\? AND status IN \\(\\?, \\?\\) AND refunded_amount \\+ \\? BETWEEN -0.005 AND amount \\+ 0.005"
+	args := []driver.Value{60.0, models.PaymentStatusRefunded, models.PaymentStatusCaptured, 60.0, sqlmock.AnyArg(), int64(1),
+		models.PaymentStatusCaptured, models.PaymentStatusRefunded, 60.0}
+	mock.ExpectExec(query).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 1))
+	// a concurrent refund took what was left
+	mock.ExpectExec(query).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	p := paymentRepo.NewMysqlPaymentRepository(db)
+	err = p.AddRefunded(context.TODO(), 1, 60)
+	assert.NoError(t, err)
+
+	err = p.AddRefunded(context.TODO(), 1, 60)
+	assert.Equal(t, models.ErrConflict, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,15 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// Usecase represent the payment's usecases
+type Usecase interface {
+	GetByOrder(ctx context.Context, orderID int64) (*models.PaymentIntent, error)
+	PayOrder(ctx context.Context, orderID int64) (*models.PaymentIntent, error)
+	Refund(ctx context.Context, orderID int64, amount float64, key string) (*models.PaymentIntent, error)
+	HandleEvent(ctx context.Context, event *models.PaymentEvent) error
+}
@@ -0,0 +1,424 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/williamchand/kuncie-cart/auth"
+	"github.com/williamchand/kuncie-cart/logging"
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/order"
+	"github.com/williamchand/kuncie-cart/payment"
+	"github.com/williamchand/kuncie-cart/transaction"
+)
+
+// retryable are the statuses of the intents a new payment attempt can claim
+var retryable = []string{models.PaymentStatusPending, models.PaymentStatusDeclined, models.PaymentStatusFailed, models.PaymentStatusVoided}
+
+// inFlight are the statuses of the intents an attempt is talking to the provider for, they can only be claimed
+// once the attempt is over
+var inFlight = []string{models.PaymentStatusProcessing, models.PaymentStatusAuthorized}
+
+type paymentUsecase struct {
+	paymentRepo     payment.Repository
+	orderRepo       order.Repository
+	provider        payment.Provider
+	txManager       transaction.Manager
+	contextTimeout  time.Duration
+	providerTimeout time.Duration
+}
+
+// NewPaymentUsecase will create new a paymentUsecase object representation of payment.Usecase interface.
+// Every provider call is bounded by providerTimeout, which should be shorter than timeout so the outcome
+// of a call that timed out can still be recorded.
+func NewPaymentUsecase(p payment.Repository, o order.Repository, provider payment.Provider, tx transaction.Manager, timeout time.Duration, providerTimeout time.Duration) payment.Usecase {
+	return &paymentUsecase{
+		paymentRepo:     p,
+		orderRepo:       o,
+		provider:        provider,
+		txManager:       tx,
+		contextTimeout:  timeout,
+		providerTimeout: providerTimeout,
+	}
+}
+
+func (a *paymentUsecase) GetByOrder(c context.Context, orderID int64) (*models.PaymentIntent, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	o, err := a.orderRepo.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err = auth.AuthorizeOrder(ctx, o); err != nil {
+		return nil, err
+	}
+
+	return a.paymentRepo.GetByOrder(ctx, orderID)
+}
+
+// PayOrder authorizes and captures what is left to pay of a pending order, then marks the order as paid.
+// A declined or failed attempt is recorded on the payment intent and can be retried.
+func (a *paymentUsecase) PayOrder(c context.Context, orderID int64) (*models.PaymentIntent, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	o, err := a.orderRepo.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err = auth.AuthorizeOrder(ctx, o); err != nil {
+		return nil, err
+	}
+	if o.Status != models.OrderStatusPending {
+		return nil, models.ErrConflict
+	}
+
+	// the intent is claimed before the provider is called so concurrent attempts cannot charge twice, the one
+	// losing the claim gets models.ErrConflict
+	intent, err := a.paymentRepo.GetByOrder(ctx, orderID)
+	now := time.Now()
+	switch err {
+	case nil:
+		// an attempt cannot run for longer than contextTimeout, an intent it left in flight for longer is taken
+		// over and sent again. The provider answers with the charge it may already hold for the intent.
+		stale := now.Add(-a.contextTimeout)
+		retry := inStatus(intent, retryable...)
+		if !retry && !(inStatus(intent, inFlight...) && intent.UpdatedAt.Before(stale)) {
+			return nil, models.ErrConflict
+		}
+		intent.Amount = o.AmountDue()
+		intent.Status = models.PaymentStatusProcessing
+		intent.LastError = ""
+		intent.UpdatedAt = now
+		if retry {
+			err = a.paymentRepo.Claim(ctx, intent, retryable...)
+		} else {
+			err = a.paymentRepo.ClaimStale(ctx, intent, stale, inFlight...)
+		}
+		if err != nil {
+			return nil, err
+		}
+	case models.ErrNotFound:
+		intent = &models.PaymentIntent{
+			OrderID:   orderID,
+			Provider:  a.provider.Name(),
+			Amount:    o.AmountDue(),
+			Status:    models.PaymentStatusProcessing,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		// an order has a single intent, a concurrent attempt storing it first makes this one fail with
+		// models.ErrConflict
+		if err = a.paymentRepo.Store(ctx, intent); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	pctx, pcancel := context.WithTimeout(ctx, a.providerTimeout)
+	ref, err := a.provider.Authorize(pctx, intent)
+	pcancel()
+	if err != nil {
+		status := models.PaymentStatusFailed
+		if err == models.ErrPaymentDeclined {
+			status = models.PaymentStatusDeclined
+		}
+		return nil, a.fail(ctx, intent, status, err)
+	}
+	intent.ProviderRef = ref
+	intent.Status = models.PaymentStatusAuthorized
+	intent.UpdatedAt = time.Now()
+	if err = a.paymentRepo.Update(ctx, intent); err != nil {
+		return nil, err
+	}
+
+	pctx, pcancel = context.WithTimeout(ctx, a.providerTimeout)
+	err = a.provider.Capture(pctx, ref, intent.Amount)
+	pcancel()
+	if err != nil {
+		vctx, vcancel := context.WithTimeout(ctx, a.providerTimeout)
+		if vErr := a.provider.Void(vctx, ref); vErr != nil {
+			logging.FromContext(ctx).Error(vErr)
+		}
+		vcancel()
+		return nil, a.fail(ctx, intent, models.PaymentStatusFailed, err)
+	}
+
+	err = a.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		intent.Status = models.PaymentStatusCaptured
+		intent.UpdatedAt = time.Now()
+		if err := a.paymentRepo.Update(ctx, intent); err != nil {
+			return err
+		}
+
+		o.Status = models.OrderStatusPaid
+		o.UpdatedAt = intent.UpdatedAt
+		return a.orderRepo.UpdateOrderStatus(ctx, o)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return intent, nil
+}
+
+// fail records an unsuccessful provider call on the intent and returns the cause
+func (a *paymentUsecase) fail(ctx context.Context, intent *models.PaymentIntent, status string, cause error) error {
+	intent.Status = status
+	intent.LastError = cause.Error()
+	intent.UpdatedAt = time.Now()
+	if err := a.paymentRepo.Update(ctx, intent); err != nil {
+		logging.FromContext(ctx).Error(err)
+	}
+
+	return cause
+}
+
+// Refund gives back part or all of the captured amount of an order.
+// The refund is counted on the intent and recorded as pending before the provider is called, so concurrent refunds
+// cannot give back more than was captured together and its notification can be matched even when it comes before
+// the answer. A refund the provider refuses is taken off the intent again, one that timed out stays pending.
+// A refund sent with a key is given back once: sending the key again after a failure or a crash sends the same
+// refund to the provider instead of a new one, and returns the intent alone once the refund succeeded.
+func (a *paymentUsecase) Refund(c context.Context, orderID int64, amount float64, key string) (*models.PaymentIntent, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	intent, err := a.paymentRepo.GetByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if intent.Status != models.PaymentStatusCaptured && intent.Status != models.PaymentStatusRefunded {
+		return nil, models.ErrConflict
+	}
+
+	var refund *models.PaymentRefund
+	if key != "" {
+		refunds, err := a.paymentRepo.FetchRefunds(ctx, intent.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range refunds {
+			if r.Key == key {
+				refund = r
+				break
+			}
+		}
+	}
+	if refund != nil && refund.Status == models.PaymentRefundStatusSucceeded {
+		return intent, nil
+	}
+	if refund != nil && math.Abs(refund.Amount-amount) >= 0.005 {
+		return nil, models.ErrBadParamInput
+	}
+	// a refund left pending was counted already, only one that failed or was never sent is counted again
+	if refund == nil || refund.Status == models.PaymentRefundStatusFailed {
+		if intent.Status != models.PaymentStatusCaptured {
+			return nil, models.ErrConflict
+		}
+		if amount <= 0 || intent.RefundedAmount+amount > intent.Amount+0.005 {
+			return nil, models.ErrBadParamInput
+		}
+
+		now := time.Now()
+		if refund == nil {
+			refund = &models.PaymentRefund{IntentID: intent.ID, Key: key, Amount: amount, CreatedAt: now}
+		}
+		refund.Status = models.PaymentRefundStatusPending
+		refund.UpdatedAt = now
+		err = a.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := a.paymentRepo.AddRefunded(ctx, intent.ID, amount); err != nil {
+				return err
+			}
+			if refund.ID != 0 {
+				return a.paymentRepo.UpdateRefund(ctx, refund)
+			}
+			return a.paymentRepo.StoreRefund(ctx, refund)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pctx, pcancel := context.WithTimeout(ctx, a.providerTimeout)
+	refundID, err := a.provider.Refund(pctx, intent.ProviderRef, amount, key)
+	pcancel()
+	if err == context.DeadlineExceeded || err == context.Canceled {
+		return nil, err
+	}
+	if err != nil {
+		refund.Status = models.PaymentRefundStatusFailed
+		refund.UpdatedAt = time.Now()
+		tErr := a.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := a.paymentRepo.AddRefunded(ctx, intent.ID, -amount); err != nil {
+				return err
+			}
+			return a.paymentRepo.UpdateRefund(ctx, refund)
+		})
+		if tErr != nil {
+			logging.FromContext(ctx).Error(tErr)
+		}
+		return nil, err
+	}
+
+	refund.ProviderRefundID = refundID
+	refund.Status = models.PaymentRefundStatusSucceeded
+	refund.UpdatedAt = time.Now()
+	if err = a.paymentRepo.UpdateRefund(ctx, refund); err != nil {
+		return nil, err
+	}
+
+	return a.paymentRepo.GetByOrder(ctx, orderID)
+}
+
+// HandleEvent applies a provider notification to the payment intent it refers to.
+// Every event is recorded once, a redelivered event returns models.ErrConflict without side effects.
+// Events that do not fit the current state of the intent, such as a failure received after the capture,
+// are recorded and otherwise ignored.
+func (a *paymentUsecase) HandleEvent(c context.Context, event *models.PaymentEvent) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	event.Provider = a.provider.Name()
+	event.CreatedAt = time.Now()
+
+	return a.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := a.paymentRepo.StoreEvent(ctx, event); err != nil {
+			return err
+		}
+
+		intent, err := a.paymentRepo.GetByProviderRef(ctx, event.Provider, event.ProviderRef)
+		if err != nil {
+			return err
+		}
+
+		paid := false
+		switch event.Type {
+		case models.PaymentEventAuthorized:
+			if !inStatus(intent, models.PaymentStatusPending, models.PaymentStatusProcessing, models.PaymentStatusFailed) {
+				return nil
+			}
+			intent.Status = models.PaymentStatusAuthorized
+		case models.PaymentEventCaptured:
+			if !inStatus(intent, models.PaymentStatusPending, models.PaymentStatusProcessing, models.PaymentStatusAuthorized, models.PaymentStatusFailed) {
+				return nil
+			}
+			intent.Status = models.PaymentStatusCaptured
+			intent.LastError = ""
+			paid = true
+		case models.PaymentEventDeclined, models.PaymentEventFailed:
+			if !inStatus(intent, models.PaymentStatusPending, models.PaymentStatusProcessing, models.PaymentStatusAuthorized) {
+				return nil
+			}
+			intent.Status = models.PaymentStatusFailed
+			if event.Type == models.PaymentEventDeclined {
+				intent.Status = models.PaymentStatusDeclined
+			}
+			intent.LastError = event.Reason
+		case models.PaymentEventVoided:
+			if !inStatus(intent, models.PaymentStatusAuthorized) {
+				return nil
+			}
+			intent.Status = models.PaymentStatusVoided
+		case models.PaymentEventRefunded:
+			return a.applyRefund(ctx, intent, event)
+		default:
+			return nil
+		}
+
+		intent.UpdatedAt = time.Now()
+		if err := a.paymentRepo.Update(ctx, intent); err != nil {
+			return err
+		}
+		if !paid {
+			return nil
+		}
+
+		o, err := a.orderRepo.GetOrder(ctx, intent.OrderID)
+		if err != nil {
+			return err
+		}
+		if o.Status != models.OrderStatusPending {
+			return nil
+		}
+		o.Status = models.OrderStatusPaid
+		o.UpdatedAt = intent.UpdatedAt
+		return a.orderRepo.UpdateOrderStatus(ctx, o)
+	})
+}
+
+// applyRefund matches a refund notification with the refund it is about by the provider refund id.
+// A refund sent by Refund was counted on the intent already and is only marked as given back, one made elsewhere,
+// such as in the dashboard of the provider, is recorded and counted. A refund that does not fit the intent is
+// ignored.
+func (a *paymentUsecase) applyRefund(ctx context.Context, intent *models.PaymentIntent, event *models.PaymentEvent) error {
+	if event.RefundID == "" || event.Amount <= 0 {
+		return nil
+	}
+	refunds, err := a.paymentRepo.FetchRefunds(ctx, intent.ID)
+	if err != nil {
+		return err
+	}
+
+	var match *models.PaymentRefund
+	for _, r := range refunds {
+		if r.ProviderRefundID == event.RefundID {
+			match = r
+			break
+		}
+	}
+	if match == nil {
+		// the notification may come before the answer of the provider to Refund was recorded
+		for _, r := range refunds {
+			if r.Status == models.PaymentRefundStatusPending && r.ProviderRefundID == "" && math.Abs(r.Amount-event.Amount) < 0.005 {
+				match = r
+				break
+			}
+		}
+	}
+
+	now := time.Now()
+	if match == nil {
+		err = a.paymentRepo.AddRefunded(ctx, intent.ID, event.Amount)
+		if err == models.ErrConflict {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return a.paymentRepo.StoreRefund(ctx, &models.PaymentRefund{
+			IntentID:         intent.ID,
+			ProviderRefundID: event.RefundID,
+			Amount:           event.Amount,
+			Status:           models.PaymentRefundStatusSucceeded,
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		})
+	}
+
+	switch match.Status {
+	case models.PaymentRefundStatusSucceeded:
+		return nil
+	case models.PaymentRefundStatusFailed:
+		// the provider gave the refund back after all, it was taken off the intent when the call failed
+		if err = a.paymentRepo.AddRefunded(ctx, intent.ID, match.Amount); err != nil && err != models.ErrConflict {
+			return err
+		}
+	}
+	match.ProviderRefundID = event.RefundID
+	match.Status = models.PaymentRefundStatusSucceeded
+	match.UpdatedAt = now
+	return a.paymentRepo.UpdateRefund(ctx, match)
+}
+
+func inStatus(intent *models.PaymentIntent, statuses ...string) bool {
+	for _, s := range statuses {
+		if intent.Status == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,502 @@
+package usecase_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/williamchand/kuncie-cart/auth"
+	"github.com/williamchand/kuncie-cart/models"
+	_orderMocks "github.com/williamchand/kuncie-cart/order/mocks"
+	"github.com/williamchand/kuncie-cart/payment"
+	"github.com/williamchand/kuncie-cart/payment/mocks"
+	"github.com/williamchand/kuncie-cart/payment/provider/fake"
+	ucase "github.com/williamchand/kuncie-cart/payment/usecase"
+	_txMocks "github.com/williamchand/kuncie-cart/transaction/mocks"
+)
+
+// countingProvider counts the authorizations sent to the provider
+type countingProvider struct {
+	payment.Provider
+	authorized int64
+}
+
+func (p *countingProvider) Authorize(ctx context.Context, intent *models.PaymentIntent) (string, error) {
+	atomic.AddInt64(&p.authorized, 1)
+	return p.Provider.Authorize(ctx, intent)
+}
+
+func TestGetByOrder(t *testing.T) {
+	t.Run("owner", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).Return(&models.Order{ID: 7, CustomerID: 5}, nil).Once()
+		mockPaymentRepo.On("GetByOrder", mock.Anything, int64(7)).
+			Return(&models.PaymentIntent{ID: 1, OrderID: 7, Status: models.PaymentStatusCaptured}, nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderRepo, fake.NewFakeProvider(fake.Succeed), _txMocks.NewPassthroughManager(), time.Second*2, time.Second)
+		intent, err := u.GetByOrder(auth.NewCustomerContext(context.TODO(), 5), 7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), intent.ID)
+		mockPaymentRepo.AssertExpectations(t)
+	})
+
+	t.Run("order-of-another-customer", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).Return(&models.Order{ID: 7, CustomerID: 5}, nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderRepo, fake.NewFakeProvider(fake.Succeed), _txMocks.NewPassthroughManager(), time.Second*2, time.Second)
+		intent, err := u.GetByOrder(auth.NewCustomerContext(context.TODO(), 6), 7)
+
+		assert.Equal(t, models.ErrForbidden, err)
+		assert.Nil(t, intent)
+		mockPaymentRepo.AssertNotCalled(t, "GetByOrder", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPayOrder(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).
+			Return(&models.Order{ID: 7, CustomerID: 5, TotalPrice: 149.97, Status: models.OrderStatusPending}, nil).Once()
+		mockPaymentRepo.On("GetByOrder", mock.Anything, int64(7)).Return(nil, models.ErrNotFound).Once()
+		mockPaymentRepo.On("Store", mock.Anything, mock.MatchedBy(func(i *models.PaymentIntent) bool {
+			return i.Status == models.PaymentStatusProcessing
+		})).Return(nil).Once()
+		mockPaymentRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.PaymentIntent")).Return(nil).Twice()
+		mockOrderRepo.On("UpdateOrderStatus", mock.Anything, mock.MatchedBy(func(o *models.Order) bool {
+			return o.Status == models.OrderStatusPaid
+		})).Return(nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderRepo, fake.NewFakeProvider(fake.Succeed), _txMocks.NewPassthroughManager(), time.Second*2, time.Second)
+		intent, err := u.PayOrder(auth.NewCustomerContext(context.TODO(), 5), 7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.PaymentStatusCaptured, intent.Status)
+		assert.Equal(t, 149.97, intent.Amount)
+		assert.NotEmpty(t, intent.ProviderRef)
+		mockPaymentRepo.AssertExpectations(t)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("declined", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).
+			Return(&models.Order{ID: 7, CustomerID: 5, TotalPrice: 149.97, Status: models.OrderStatusPending}, nil).Once()
+		mockPaymentRepo.On("GetByOrder", mock.Anything, int64(7)).Return(nil, models.ErrNotFound).Once()
+		mockPaymentRepo.On("Store", mock.Anything, mock.AnythingOfType("*models.PaymentIntent")).Return(nil).Once()
+		mockPaymentRepo.On("Update", mock.Anything, mock.MatchedBy(func(i *models.PaymentIntent) bool {
+			return i.Status == models.PaymentStatusDeclined
+		})).Return(nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderRepo, fake.NewFakeProvider(fake.Decline), _txMocks.NewPassthroughManager(), time.Second*2, time.Second)
+		intent, err := u.PayOrder(auth.NewCustomerContext(context.TODO(), 5), 7)
+
+		assert.Equal(t, models.ErrPaymentDeclined, err)
+		assert.Nil(t, intent)
+		mockPaymentRepo.AssertExpectations(t)
+		mockOrderRepo.AssertNotCalled(t, "UpdateOrderStatus", mock.Anything, mock.Anything)
+	})
+
+	t.Run("provider-timeout", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).
+			Return(&models.Order{ID: 7, CustomerID: 5, TotalPrice: 149.97, Status: models.OrderStatusPending}, nil).Once()
+		mockPaymentRepo.On("GetByOrder", mock.Anything, int64(7)).
+			Return(&models.PaymentIntent{ID: 1, OrderID: 7, Status: models.PaymentStatusDeclined}, nil).Once()
+		mockPaymentRepo.On("Claim", mock.Anything, mock.MatchedBy(func(i *models.PaymentIntent) bool {
+			return i.Status == models.PaymentStatusProcessing
+		}), models.PaymentStatusPending, models.PaymentStatusDeclined, models.PaymentStatusFailed, models.PaymentStatusVoided).
+			Return(nil).Once()
+		mockPaymentRepo.On("Update", mock.Anything, mock.MatchedBy(func(i *models.PaymentIntent) bool {
+			return i.Status == models.PaymentStatusFailed && i.LastError != ""
+		})).Return(nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderRepo, fake.NewFakeProvider(fake.Timeout), _txMocks.NewPassthroughManager(), time.Second*2, time.Millisecond*10)
+		intent, err := u.PayOrder(auth.NewCustomerContext(context.TODO(), 5), 7)
+
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.Nil(t, intent)
+		mockPaymentRepo.AssertExpectations(t)
+	})
+
+	t.Run("already-paid", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).
+			Return(&models.Order{ID: 7, CustomerID: 5, TotalPrice: 149.97, Status: models.OrderStatusPaid}, nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderRepo, fake.NewFakeProvider(fake.Succeed), _txMocks.NewPassthroughManager(), time.Second*2, time.Second)
+		intent, err := u.PayOrder(auth.NewCustomerContext(context.TODO(), 5), 7)
+
+		assert.Equal(t, models.ErrConflict, err)
+		assert.Nil(t, intent)
+		mockPaymentRepo.AssertExpectations(t)
+	})
+	t.Run("concurrent-attempts", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).
+			Return(func(ctx context.Context, id int64) *models.Order {
+				return &models.Order{ID: 7, CustomerID: 5, TotalPrice: 149.97, Status: models.OrderStatusPending}
+			}, nil).Twice()
+		mockPaymentRepo.On("GetByOrder", mock.Anything, int64(7)).
+			Return(func(ctx context.Context, id int64) *models.PaymentIntent {
+				return &models.PaymentIntent{ID: 1, OrderID: 7, Status: models.PaymentStatusDeclined}
+			}, nil).Twice()
+		// the database lets a single claim through, as the conditional update does
+		claimed := []interface{}{mock.Anything, mock.AnythingOfType("*models.PaymentIntent"),
+			models.PaymentStatusPending, models.PaymentStatusDeclined, models.PaymentStatusFailed, models.PaymentStatusVoided}
+		mockPaymentRepo.On("Claim", claimed...).Return(nil).Once()
+		mockPaymentRepo.On("Claim", claimed...).Return(models.ErrConflict).Once()
+		mockPaymentRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.PaymentIntent")).Return(nil).Twice()
+		mockOrderRepo.On("UpdateOrderStatus", mock.Anything, mock.AnythingOfType("*models.Order")).Return(nil).Once()
+
+		provider := &countingProvider{Provider: fake.NewFakeProvider(fake.Succeed)}
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderRepo, provider, _txMocks.NewPassthroughManager(), time.Second*2, time.Second)
+		errs := make(chan error, 2)
+		var wg sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := u.PayOrder(auth.NewCustomerContext(context.TODO(), 5), 7)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		results := make([]error, 0, 2)
+		for err := range errs {
+			results = append(results, err)
+		}
+		assert.ElementsMatch(t, []error{nil, models.ErrConflict}, results)
+		assert.Equal(t, int64(1), atomic.LoadInt64(&provider.authorized))
+		mockPaymentRepo.AssertExpectations(t)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("in-flight", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).
+			Return(&models.Order{ID: 7, CustomerID: 5, TotalPrice: 149.97, Status: models.OrderStatusPending}, nil).Once()
+		mockPaymentRepo.On("GetByOrder", mock.Anything, int64(7)).
+			Return(&models.PaymentIntent{ID: 1, OrderID: 7, Status: models.PaymentStatusProcessing, UpdatedAt: time.Now()}, nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderRepo, fake.NewFakeProvider(fake.Succeed), _txMocks.NewPassthroughManager(), time.Second*2, time.Second)
+		intent, err := u.PayOrder(auth.NewCustomerContext(context.TODO(), 5), 7)
+
+		assert.Equal(t, models.ErrConflict, err)
+		assert.Nil(t, intent)
+		mockPaymentRepo.AssertNotCalled(t, "ClaimStale", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("left-in-flight-by-a-crash", func(t *testing.T) {
+		provider := &countingProvider{Provider: fake.NewFakeProvider(fake.Succeed)}
+		// the crashed attempt got the authorization but never recorded it
+		ref, err := provider.Provider.Authorize(context.TODO(), &models.PaymentIntent{ID: 1, OrderID: 7, Amount: 149.97})
+		assert.NoError(t, err)
+
+		mockPaymentRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).
+			Return(&models.Order{ID: 7, CustomerID: 5, TotalPrice: 149.97, Status: models.OrderStatusPending}, nil).Once()
+		mockPaymentRepo.On("GetByOrder", mock.Anything, int64(7)).
+			Return(&models.PaymentIntent{ID: 1, OrderID: 7, Status: models.PaymentStatusProcessing, UpdatedAt: time.Now().Add(-time.Hour)}, nil).Once()
+		mockPaymentRepo.On("ClaimStale", mock.Anything, mock.MatchedBy(func(i *models.PaymentIntent) bool {
+			return i.Status == models.PaymentStatusProcessing
+		}), mock.AnythingOfType("time.Time"), models.PaymentStatusProcessing, models.PaymentStatusAuthorized).Return(nil).Once()
+		mockPaymentRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.PaymentIntent")).Return(nil).Twice()
+		mockOrderRepo.On("UpdateOrderStatus", mock.Anything, mock.AnythingOfType("*models.Order")).Return(nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderRepo, provider, _txMocks.NewPassthroughManager(), time.Second*2, time.Second)
+		intent, err := u.PayOrder(auth.NewCustomerContext(context.TODO(), 5), 7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.PaymentStatusCaptured, intent.Status)
+		assert.Equal(t, ref, intent.ProviderRef)
+		mockPaymentRepo.AssertExpectations(t)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("order-of-another-customer", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).
+			Return(&models.Order{ID: 7, CustomerID: 5, TotalPrice: 149.97, Status: models.OrderStatusPending}, nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderRepo, fake.NewFakeProvider(fake.Succeed), _txMocks.NewPassthroughManager(), time.Second*2, time.Second)
+		intent, err := u.PayOrder(auth.NewCustomerContext(context.TODO(), 6), 7)
+
+		assert.Equal(t, models.ErrForbidden, err)
+		assert.Nil(t, intent)
+		mockPaymentRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRefund(t *testing.T) {
+	t.Run("partial-then-full", func(t *testing.T) {
+		provider := new(mocks.Provider)
+		mockPaymentRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		intent := &models.PaymentIntent{ID: 1, OrderID: 7, ProviderRef: "ref", Amount: 100, Status: models.PaymentStatusCaptured}
+		refunded := &models.PaymentIntent{ID: 1, OrderID: 7, ProviderRef: "ref", Amount: 100, RefundedAmount: 60, Status: models.PaymentStatusCaptured}
+		mockPaymentRepo.On("GetByOrder", mock.Anything, int64(7)).Return(intent, nil).Once()
+		mockPaymentRepo.On("AddRefunded", mock.Anything, int64(1), 60.0).Return(nil).Once()
+		mockPaymentRepo.On("StoreRefund", mock.Anything, mock.AnythingOfType("*models.PaymentRefund")).Return(nil).Twice()
+		provider.On("Refund", mock.Anything, "ref", 60.0, "").Return("re_1", nil).Once()
+		mockPaymentRepo.On("UpdateRefund", mock.Anything, mock.MatchedBy(func(r *models.PaymentRefund) bool {
+			return r.Status == models.PaymentRefundStatusSucceeded && r.ProviderRefundID != ""
+		})).Return(nil).Twice()
+		mockPaymentRepo.On("GetByOrder", mock.Anything, int64(7)).Return(refunded, nil).Twice()
+		mockPaymentRepo.On("AddRefunded", mock.Anything, int64(1), 40.0).Return(nil).Once()
+		provider.On("Refund", mock.Anything, "ref", 40.0, "").Return("re_2", nil).Once()
+		mockPaymentRepo.On("GetByOrder", mock.Anything, int64(7)).
+			Return(&models.PaymentIntent{ID: 1, OrderID: 7, Amount: 100, RefundedAmount: 100, Status: models.PaymentStatusRefunded}, nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderRepo, provider, _txMocks.NewPassthroughManager(), time.Second*2, time.Second)
+		res, err := u.Refund(context.TODO(), 7, 60, "")
+		assert.NoError(t, err)
+		assert.Equal(t, models.PaymentStatusCaptured, res.Status)
+
+		res, err = u.Refund(context.TODO(), 7, 40, "")
+		assert.NoError(t, err)
+		assert.Equal(t, models.PaymentStatusRefunded, res.Status)
+		assert.Equal(t, 100.0, res.RefundedAmount)
+		provider.AssertExpectations(t)
+		mockPaymentRepo.AssertExpectations(t)
+	})
+
+	t.Run("concurrent-refunds", func(t *testing.T) {
+		provider := new(mocks.Provider)
+		mockPaymentRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		// the intent is read before a concurrent refund of 50 is counted
+		mockPaymentRepo.On("GetByOrder", mock.Anything, int64(7)).
+			Return(&models.PaymentIntent{ID: 1, OrderID: 7, ProviderRef: "ref", Amount: 100, Status: models.PaymentStatusCaptured}, nil).Once()
+		// and the conditional update finds too little left for this one
+		mockPaymentRepo.On("AddRefunded", mock.Anything, int64(1), 80.0).Return(models.ErrConflict).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderRepo, provider, _txMocks.NewPassthroughManager(), time.Second*2, time.Second)
+		res, err := u.Refund(context.TODO(), 7, 80, "")
+
+		assert.Equal(t, models.ErrConflict, err)
+		assert.Nil(t, res)
+		provider.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockPaymentRepo.AssertNotCalled(t, "StoreRefund", mock.Anything, mock.Anything)
+		mockPaymentRepo.AssertExpectations(t)
+	})
+
+	t.Run("refused-by-provider", func(t *testing.T) {
+		provider := new(mocks.Provider)
+		mockPaymentRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockPaymentRepo.On("GetByOrder", mock.Anything, int64(7)).
+			Return(&models.PaymentIntent{ID: 1, OrderID: 7, ProviderRef: "ref", Amount: 100, Status: models.PaymentStatusCaptured}, nil).Once()
+		mockPaymentRepo.On("AddRefunded", mock.Anything, int64(1), 30.0).Return(nil).Once()
+		mockPaymentRepo.On("StoreRefund", mock.Anything, mock.AnythingOfType("*models.PaymentRefund")).Return(nil).Once()
+		provider.On("Refund", mock.Anything, "ref", 30.0, "").Return("", models.ErrBadParamInput).Once()
+		mockPaymentRepo.On("AddRefunded", mock.Anything, int64(1), -30.0).Return(nil).Once()
+		mockPaymentRepo.On("UpdateRefund", mock.Anything, mock.MatchedBy(func(r *models.PaymentRefund) bool {
+			return r.Status == models.PaymentRefundStatusFailed
+		})).Return(nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderRepo, provider, _txMocks.NewPassthroughManager(), time.Second*2, time.Second)
+		res, err := u.Refund(context.TODO(), 7, 30, "")
+
+		assert.Equal(t, models.ErrBadParamInput, err)
+		assert.Nil(t, res)
+		provider.AssertExpectations(t)
+		mockPaymentRepo.AssertExpectations(t)
+	})
+
+	t.Run("sent-again-with-its-key", func(t *testing.T) {
+		provider := new(mocks.Provider)
+		mockPaymentRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		intent := &models.PaymentIntent{ID: 1, OrderID: 7, ProviderRef: "ref", Amount: 100, RefundedAmount: 30, Status: models.PaymentStatusCaptured}
+		mockPaymentRepo.On("GetByOrder", mock.Anything, int64(7)).Return(intent, nil).Twice()
+		// the answer to the first attempt was lost, the refund is counted already
+		mockPaymentRepo.On("FetchRefunds", mock.Anything, int64(1)).Return([]*models.PaymentRefund{
+			{ID: 3, IntentID: 1, Key: "refund-9", Amount: 30, Status: models.PaymentRefundStatusPending},
+		}, nil).Once()
+		provider.On("Refund", mock.Anything, "ref", 30.0, "refund-9").Return("re_1", nil).Once()
+		mockPaymentRepo.On("UpdateRefund", mock.Anything, mock.MatchedBy(func(r *models.PaymentRefund) bool {
+			return r.ID == 3 && r.ProviderRefundID == "re_1" && r.Status == models.PaymentRefundStatusSucceeded
+		})).Return(nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderRepo, provider, _txMocks.NewPassthroughManager(), time.Second*2, time.Second)
+		_, err := u.Refund(context.TODO(), 7, 30, "refund-9")
+
+		assert.NoError(t, err)
+		mockPaymentRepo.AssertNotCalled(t, "AddRefunded", mock.Anything, mock.Anything, mock.Anything)
+		mockPaymentRepo.AssertNotCalled(t, "StoreRefund", mock.Anything, mock.Anything)
+		provider.AssertExpectations(t)
+		mockPaymentRepo.AssertExpectations(t)
+	})
+
+	t.Run("key-already-refunded", func(t *testing.T) {
+		provider := new(mocks.Provider)
+		mockPaymentRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		intent := &models.PaymentIntent{ID: 1, OrderID: 7, ProviderRef: "ref", Amount: 100, RefundedAmount: 30, Status: models.PaymentStatusCaptured}
+		mockPaymentRepo.On("GetByOrder", mock.Anything, int64(7)).Return(intent, nil).Once()
+		mockPaymentRepo.On("FetchRefunds", mock.Anything, int64(1)).Return([]*models.PaymentRefund{
+			{ID: 3, IntentID: 1, Key: "refund-9", ProviderRefundID: "re_1", Amount: 30, Status: models.PaymentRefundStatusSucceeded},
+		}, nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderRepo, provider, _txMocks.NewPassthroughManager(), time.Second*2, time.Second)
+		res, err := u.Refund(context.TODO(), 7, 30, "refund-9")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 30.0, res.RefundedAmount)
+		provider.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockPaymentRepo.AssertNotCalled(t, "AddRefunded", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestHandleEvent(t *testing.T) {
+	t.Run("captured", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockPaymentRepo.On("StoreEvent", mock.Anything, mock.MatchedBy(func(e *models.PaymentEvent) bool {
+			return e.Provider == fake.Name
+		})).Return(nil).Once()
+		mockPaymentRepo.On("GetByProviderRef", mock.Anything, fake.Name, "fake_1").
+			Return(&models.PaymentIntent{ID: 1, OrderID: 7, ProviderRef: "fake_1", Amount: 149.97, Status: models.PaymentStatusAuthorized}, nil).Once()
+		mockPaymentRepo.On("Update", mock.Anything, mock.MatchedBy(func(i *models.PaymentIntent) bool {
+			return i.Status == models.PaymentStatusCaptured
+		})).Return(nil).Once()
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(7)).
+			Return(&models.Order{ID: 7, TotalPrice: 149.97, Status: models.OrderStatusPending}, nil).Once()
+		mockOrderRepo.On("UpdateOrderStatus", mock.Anything, mock.MatchedBy(func(o *models.Order) bool {
+			return o.Status == models.OrderStatusPaid
+		})).Return(nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderRepo, fake.NewFakeProvider(fake.Succeed), _txMocks.NewPassthroughManager(), time.Second*2, time.Second)
+		err := u.HandleEvent(context.TODO(), &models.PaymentEvent{EventID: "evt_1", Type: models.PaymentEventCaptured, ProviderRef: "fake_1"})
+
+		assert.NoError(t, err)
+		mockPaymentRepo.AssertExpectations(t)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockPaymentRepo.On("StoreEvent", mock.Anything, mock.AnythingOfType("*models.PaymentEvent")).Return(models.ErrConflict).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderRepo, fake.NewFakeProvider(fake.Succeed), _txMocks.NewPassthroughManager(), time.Second*2, time.Second)
+		err := u.HandleEvent(context.TODO(), &models.PaymentEvent{EventID: "evt_1", Type: models.PaymentEventCaptured, ProviderRef: "fake_1"})
+
+		assert.Equal(t, models.ErrConflict, err)
+		mockPaymentRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		mockOrderRepo.AssertNotCalled(t, "UpdateOrderStatus", mock.Anything, mock.Anything)
+	})
+
+	t.Run("out-of-order", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockPaymentRepo.On("StoreEvent", mock.Anything, mock.AnythingOfType("*models.PaymentEvent")).Return(nil).Once()
+		mockPaymentRepo.On("GetByProviderRef", mock.Anything, fake.Name, "fake_1").
+			Return(&models.PaymentIntent{ID: 1, OrderID: 7, ProviderRef: "fake_1", Amount: 149.97, Status: models.PaymentStatusCaptured}, nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderRepo, fake.NewFakeProvider(fake.Succeed), _txMocks.NewPassthroughManager(), time.Second*2, time.Second)
+		err := u.HandleEvent(context.TODO(), &models.PaymentEvent{EventID: "evt_2", Type: models.PaymentEventFailed, ProviderRef: "fake_1"})
+
+		assert.NoError(t, err)
+		mockPaymentRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRefundNotification(t *testing.T) {
+	captured := func() *models.PaymentIntent {
+		return &models.PaymentIntent{ID: 1, OrderID: 7, ProviderRef: "fake_1", Amount: 100, Status: models.PaymentStatusCaptured}
+	}
+
+	t.Run("refund-then-webhook", func(t *testing.T) {
+		provider := new(mocks.Provider)
+		mockPaymentRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		var stored *models.PaymentRefund
+		mockPaymentRepo.On("GetByOrder", mock.Anything, int64(7)).Return(captured(), nil).Twice()
+		mockPaymentRepo.On("AddRefunded", mock.Anything, int64(1), 60.0).Return(nil).Once()
+		mockPaymentRepo.On("StoreRefund", mock.Anything, mock.AnythingOfType("*models.PaymentRefund")).Return(nil).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*models.PaymentRefund)
+			stored.ID = 3
+		}).Once()
+		provider.On("Refund", mock.Anything, "fake_1", 60.0, "").Return("re_1", nil).Once()
+		mockPaymentRepo.On("UpdateRefund", mock.Anything, mock.AnythingOfType("*models.PaymentRefund")).Return(nil).Once()
+		provider.On("Name").Return(fake.Name)
+		mockPaymentRepo.On("StoreEvent", mock.Anything, mock.AnythingOfType("*models.PaymentEvent")).Return(nil).Once()
+		mockPaymentRepo.On("GetByProviderRef", mock.Anything, fake.Name, "fake_1").Return(captured(), nil).Once()
+		mockPaymentRepo.On("FetchRefunds", mock.Anything, int64(1)).Return(func(ctx context.Context, id int64) []*models.PaymentRefund {
+			return []*models.PaymentRefund{stored}
+		}, nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderRepo, provider, _txMocks.NewPassthroughManager(), time.Second*2, time.Second)
+		_, err := u.Refund(context.TODO(), 7, 60, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "re_1", stored.ProviderRefundID)
+
+		err = u.HandleEvent(context.TODO(), &models.PaymentEvent{EventID: "evt_3", Type: models.PaymentEventRefunded, ProviderRef: "fake_1", RefundID: "re_1", Amount: 60})
+		assert.NoError(t, err)
+		// the refund is counted once, when it was sent
+		mockPaymentRepo.AssertNumberOfCalls(t, "AddRefunded", 1)
+		mockPaymentRepo.AssertNumberOfCalls(t, "UpdateRefund", 1)
+		mockPaymentRepo.AssertExpectations(t)
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("webhook-before-the-answer", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockPaymentRepo.On("StoreEvent", mock.Anything, mock.AnythingOfType("*models.PaymentEvent")).Return(nil).Once()
+		mockPaymentRepo.On("GetByProviderRef", mock.Anything, fake.Name, "fake_1").Return(captured(), nil).Once()
+		mockPaymentRepo.On("FetchRefunds", mock.Anything, int64(1)).Return([]*models.PaymentRefund{
+			{ID: 3, IntentID: 1, Amount: 60, Status: models.PaymentRefundStatusPending},
+		}, nil).Once()
+		mockPaymentRepo.On("UpdateRefund", mock.Anything, mock.MatchedBy(func(r *models.PaymentRefund) bool {
+			return r.ID == 3 && r.ProviderRefundID == "re_1" && r.Status == models.PaymentRefundStatusSucceeded
+		})).Return(nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderRepo, fake.NewFakeProvider(fake.Succeed), _txMocks.NewPassthroughManager(), time.Second*2, time.Second)
+		err := u.HandleEvent(context.TODO(), &models.PaymentEvent{EventID: "evt_3", Type: models.PaymentEventRefunded, ProviderRef: "fake_1", RefundID: "re_1", Amount: 60})
+
+		assert.NoError(t, err)
+		mockPaymentRepo.AssertNotCalled(t, "AddRefunded", mock.Anything, mock.Anything, mock.Anything)
+		mockPaymentRepo.AssertExpectations(t)
+	})
+
+	t.Run("refund-made-with-the-provider", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockPaymentRepo.On("StoreEvent", mock.Anything, mock.AnythingOfType("*models.PaymentEvent")).Return(nil).Once()
+		mockPaymentRepo.On("GetByProviderRef", mock.Anything, fake.Name, "fake_1").Return(captured(), nil).Once()
+		mockPaymentRepo.On("FetchRefunds", mock.Anything, int64(1)).Return([]*models.PaymentRefund{
+			{ID: 3, IntentID: 1, ProviderRefundID: "re_1", Amount: 60, Status: models.PaymentRefundStatusSucceeded},
+		}, nil).Once()
+		mockPaymentRepo.On("AddRefunded", mock.Anything, int64(1), 25.0).Return(nil).Once()
+		mockPaymentRepo.On("StoreRefund", mock.Anything, mock.MatchedBy(func(r *models.PaymentRefund) bool {
+			return r.ProviderRefundID == "re_2" && r.Status == models.PaymentRefundStatusSucceeded
+		})).Return(nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderRepo, fake.NewFakeProvider(fake.Succeed), _txMocks.NewPassthroughManager(), time.Second*2, time.Second)
+		err := u.HandleEvent(context.TODO(), &models.PaymentEvent{EventID: "evt_4", Type: models.PaymentEventRefunded, ProviderRef: "fake_1", RefundID: "re_2", Amount: 25})
+
+		assert.NoError(t, err)
+		mockPaymentRepo.AssertExpectations(t)
+	})
+}
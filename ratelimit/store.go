@@ -0,0 +1,19 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit represent a token bucket refilled with Rate tokens per second and holding at most Burst tokens
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Store represent the contract of the place the token buckets are kept, in memory for a single instance or
+// shared between the instances of the service.
+// Take removes a token from the bucket of key, created full, and tells how long until the next token when it is empty.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (ok bool, retryAfter time.Duration, err error)
+}
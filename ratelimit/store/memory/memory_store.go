@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/williamchand/kuncie-cart/ratelimit"
+)
+
+// sweepEvery is how many takes go by between two removals of the buckets that are full again
+const sweepEvery = 1024
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  ratelimit.Limit
+}
+
+// Store is a ratelimit.Store keeping the buckets in memory, the limits only hold for a single instance
+type Store struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	takes   int
+	// Now tells the time the buckets are refilled at
+	Now func() time.Time
+}
+
+// NewMemoryStore will create an empty Store
+func NewMemoryStore() *Store {
+	return &Store{
+		buckets: make(map[string]*bucket),
+		Now:     time.Now,
+	}
+}
+
+// Take removes a token from the bucket of key after refilling it for the time elapsed since the last take
+func (s *Store) Take(ctx context.Context, key string, limit ratelimit.Limit) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.Now()
+	s.takes++
+	if s.takes%sweepEvery == 0 {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		s.buckets[key] = b
+	}
+	b.limit = limit
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+		b.last = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0, nil
+	}
+	if limit.Rate <= 0 {
+		return false, time.Duration(math.MaxInt64), nil
+	}
+	return false, time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second)), nil
+}
+
+// sweep forgets the buckets that had the time to fill up again, they are created full on the next take
+func (s *Store) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if b.limit.Rate <= 0 {
+			continue
+		}
+		full := time.Duration((float64(b.limit.Burst) - b.tokens) / b.limit.Rate * float64(time.Second))
+		if now.Sub(b.last) >= full {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/williamchand/kuncie-cart/ratelimit"
+	"github.com/williamchand/kuncie-cart/ratelimit/store/memory"
+)
+
+func TestTake(t *testing.T) {
+	now := time.Date(2022, 4, 26, 10, 0, 0, 0, time.UTC)
+	s := memory.NewMemoryStore()
+	s.Now = func() time.Time { return now }
+	limit := ratelimit.Limit{Rate: 2, Burst: 3}
+
+	for i := 0; i < 3; i++ {
+		ok, _, err := s.Take(context.TODO(), "ip:10.0.0.1", limit)
+		assert.NoError(t, err)
+		assert.True(t, ok, "burst token %d", i)
+	}
+	ok, retryAfter, err := s.Take(context.TODO(), "ip:10.0.0.1", limit)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	ok, _, _ = s.Take(context.TODO(), "ip:10.0.0.2", limit)
+	assert.True(t, ok, "buckets are kept per key")
+
+	now = now.Add(500 * time.Millisecond)
+	ok, _, _ = s.Take(context.TODO(), "ip:10.0.0.1", limit)
+	assert.True(t, ok, "refilled after retryAfter")
+	ok, _, _ = s.Take(context.TODO(), "ip:10.0.0.1", limit)
+	assert.False(t, ok)
+
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		ok, _, _ = s.Take(context.TODO(), "ip:10.0.0.1", limit)
+		assert.True(t, ok, "refilled up to the burst only, token %d", i)
+	}
+	ok, _, _ = s.Take(context.TODO(), "ip:10.0.0.1", limit)
+	assert.False(t, ok)
+}
@@ -0,0 +1,119 @@
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/refund"
+)
+
+type Resolver interface {
+	Returns(params graphql.ResolveParams) (interface{}, error)
+	RequestReturn(params graphql.ResolveParams) (interface{}, error)
+	ApproveReturn(params graphql.ResolveParams) (interface{}, error)
+	RetryRefund(params graphql.ResolveParams) (interface{}, error)
+	RejectReturn(params graphql.ResolveParams) (interface{}, error)
+}
+
+type resolver struct {
+	refundService refund.Usecase
+}
+
+func (r resolver) Returns(params graphql.ResolveParams) (interface{}, error) {
+	orderID, ok := params.Args["order_id"].(int)
+	if !ok || orderID == 0 {
+		return nil, fmt.Errorf("order_id is not integer or zero")
+	}
+
+	return r.refundService.FetchByOrder(params.Context, int64(orderID))
+}
+
+func (r resolver) RequestReturn(params graphql.ResolveParams) (interface{}, error) {
+	orderID, ok := params.Args["order_id"].(int)
+	if !ok || orderID == 0 {
+		return nil, fmt.Errorf("order_id is not integer or zero")
+	}
+	lines, ok := params.Args["lines"].([]interface{})
+	if !ok || len(lines) == 0 {
+		return nil, fmt.Errorf("lines is empty")
+	}
+	reason, _ := params.Args["reason"].(string)
+
+	req := &models.ReturnRequest{
+		OrderID: int64(orderID),
+		Reason:  reason,
+		Details: make([]*models.ReturnDetails, 0, len(lines)),
+	}
+	for i := range lines {
+		line, ok := lines[i].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("line %d is not valid", i)
+		}
+		detailsID, _ := line["order_details_id"].(int)
+		quantity, _ := line["quantity"].(int)
+		if detailsID == 0 || quantity <= 0 {
+			return nil, fmt.Errorf("line %d needs order_details_id and a positive quantity", i)
+		}
+		req.Details = append(req.Details, &models.ReturnDetails{
+			OrderDetailsID: int64(detailsID),
+			Quantity:       int64(quantity),
+		})
+	}
+
+	if err := r.refundService.Request(params.Context, req); err != nil {
+		return nil, err
+	}
+
+	return *req, nil
+}
+
+func (r resolver) ApproveReturn(params graphql.ResolveParams) (interface{}, error) {
+	id, ok := params.Args["id"].(int)
+	if !ok || id == 0 {
+		return nil, fmt.Errorf("id is not integer or zero")
+	}
+
+	toStoreCredit, _ := params.Args["to_store_credit"].(bool)
+
+	res, err := r.refundService.Approve(params.Context, int64(id), toStoreCredit)
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func (r resolver) RetryRefund(params graphql.ResolveParams) (interface{}, error) {
+	returnID, ok := params.Args["return_id"].(int)
+	if !ok || returnID == 0 {
+		return nil, fmt.Errorf("return_id is not integer or zero")
+	}
+
+	res, err := r.refundService.RetryRefund(params.Context, int64(returnID))
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func (r resolver) RejectReturn(params graphql.ResolveParams) (interface{}, error) {
+	id, ok := params.Args["id"].(int)
+	if !ok || id == 0 {
+		return nil, fmt.Errorf("id is not integer or zero")
+	}
+	reason, _ := params.Args["reason"].(string)
+
+	if err := r.refundService.Reject(params.Context, int64(id), reason); err != nil {
+		return nil, err
+	}
+
+	return r.refundService.GetByID(params.Context, int64(id))
+}
+
+func NewResolver(refundService refund.Usecase) Resolver {
+	return &resolver{
+		refundService: refundService,
+	}
+}
@@ -0,0 +1,199 @@
+package graphql
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/williamchand/kuncie-cart/auth"
+)
+
+// ReturnDetailsGraphQL holds returned order line information with graphql object
+var ReturnDetailsGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "ReturnDetails",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"order_details_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"sku": &graphql.Field{
+				Type: graphql.String,
+			},
+			"quantity": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"refund_amount": &graphql.Field{
+				Type: graphql.Float,
+			},
+		},
+	},
+)
+
+// ReturnGraphQL holds return request information with graphql object
+var ReturnGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Return",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"order_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"status": &graphql.Field{
+				Type: graphql.String,
+			},
+			"reason": &graphql.Field{
+				Type: graphql.String,
+			},
+			"refund_amount": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"details": &graphql.Field{
+				Type: graphql.NewList(ReturnDetailsGraphQL),
+			},
+			"updated_at": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+			"created_at": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+		},
+	},
+)
+
+// RefundGraphQL holds refund information with graphql object
+var RefundGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Refund",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"order_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"return_id": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"amount": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"credit_amount": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"payment_amount": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"status": &graphql.Field{
+				Type: graphql.String,
+			},
+			"created_at": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+		},
+	},
+)
+
+// ReturnLineInput holds a returned order line argument
+var ReturnLineInput = graphql.NewInputObject(
+	graphql.InputObjectConfig{
+		Name: "ReturnLineInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"order_details_id": &graphql.InputObjectFieldConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+			"quantity": &graphql.InputObjectFieldConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+		},
+	},
+)
+
+// Schema is struct which has method for Query and Mutation fields. Please init this struct using constructor function.
+type Schema struct {
+	refundResolver Resolver
+}
+
+// QueryFields initializes the refund fields of the graphql query.
+func (s Schema) QueryFields() graphql.Fields {
+	return graphql.Fields{
+		"Returns": &graphql.Field{
+			Type:        graphql.NewList(ReturnGraphQL),
+			Description: "List the return requests of an order",
+			Args: graphql.FieldConfigArgument{
+				"order_id": &graphql.ArgumentConfig{
+					Type: graphql.Int,
+				},
+			},
+			Resolve: s.refundResolver.Returns,
+		},
+	}
+}
+
+// MutationFields initializes the refund fields of the graphql mutation.
+func (s Schema) MutationFields() graphql.Fields {
+	return graphql.Fields{
+		"RequestReturn": &graphql.Field{
+			Type:        ReturnGraphQL,
+			Description: "Request to return order lines",
+			Args: graphql.FieldConfigArgument{
+				"order_id": &graphql.ArgumentConfig{
+					Type: graphql.Int,
+				},
+				"reason": &graphql.ArgumentConfig{
+					Type: graphql.String,
+				},
+				"lines": &graphql.ArgumentConfig{
+					Type: graphql.NewList(ReturnLineInput),
+				},
+			},
+			Resolve: s.refundResolver.RequestReturn,
+		},
+		"ApproveReturn": &graphql.Field{
+			Type:        RefundGraphQL,
+			Description: "Approve a return, restock its items and record the refund",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type: graphql.Int,
+				},
+				"to_store_credit": &graphql.ArgumentConfig{
+					Type:        graphql.Boolean,
+					Description: "Credit the store credit of the customer instead of refunding the payment",
+				},
+			},
+			Resolve: auth.Require(auth.PermManageOrders, s.refundResolver.ApproveReturn),
+		},
+		"RetryRefund": &graphql.Field{
+			Type:        RefundGraphQL,
+			Description: "Send the refund of an approved return to the payment provider again",
+			Args: graphql.FieldConfigArgument{
+				"return_id": &graphql.ArgumentConfig{
+					Type: graphql.Int,
+				},
+			},
+			Resolve: auth.Require(auth.PermManageOrders, s.refundResolver.RetryRefund),
+		},
+		"RejectReturn": &graphql.Field{
+			Type:        ReturnGraphQL,
+			Description: "Reject a return request",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type: graphql.Int,
+				},
+				"reason": &graphql.ArgumentConfig{
+					Type: graphql.String,
+				},
+			},
+			Resolve: auth.Require(auth.PermManageOrders, s.refundResolver.RejectReturn),
+		},
+	}
+}
+
+// NewSchema initializes Schema struct which takes resolver as the argument.
+func NewSchema(refundResolver Resolver) Schema {
+	return Schema{
+		refundResolver: refundResolver,
+	}
+}
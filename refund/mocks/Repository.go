@@ -0,0 +1,153 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	models "github.com/williamchand/kuncie-cart/models"
+)
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+// FetchByOrder provides a mock function with given fields: ctx, orderID
+func (_m *Repository) FetchByOrder(ctx context.Context, orderID int64) ([]*models.ReturnRequest, error) {
+	ret := _m.Called(ctx, orderID)
+
+	var r0 []*models.ReturnRequest
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*models.ReturnRequest); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.ReturnRequest)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *Repository) GetByID(ctx context.Context, id int64) (*models.ReturnRequest, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.ReturnRequest
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.ReturnRequest); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ReturnRequest)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRefundByReturn provides a mock function with given fields: ctx, returnID
+func (_m *Repository) GetRefundByReturn(ctx context.Context, returnID int64) (*models.Refund, error) {
+	ret := _m.Called(ctx, returnID)
+
+	var r0 *models.Refund
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.Refund); ok {
+		r0 = rf(ctx, returnID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Refund)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, returnID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestockItems provides a mock function with given fields: ctx, sku, quantity
+func (_m *Repository) RestockItems(ctx context.Context, sku string, quantity int64) error {
+	ret := _m.Called(ctx, sku, quantity)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) error); ok {
+		r0 = rf(ctx, sku, quantity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Store provides a mock function with given fields: ctx, a
+func (_m *Repository) Store(ctx context.Context, a *models.ReturnRequest) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ReturnRequest) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreRefund provides a mock function with given fields: ctx, a
+func (_m *Repository) StoreRefund(ctx context.Context, a *models.Refund) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Refund) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateRefund provides a mock function with given fields: ctx, a
+func (_m *Repository) UpdateRefund(ctx context.Context, a *models.Refund) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Refund) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateStatus provides a mock function with given fields: ctx, a, from
+func (_m *Repository) UpdateStatus(ctx context.Context, a *models.ReturnRequest, from string) error {
+	ret := _m.Called(ctx, a, from)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ReturnRequest, string) error); ok {
+		r0 = rf(ctx, a, from)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,134 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	models "github.com/williamchand/kuncie-cart/models"
+)
+
+// Usecase is an autogenerated mock type for the Usecase type
+type Usecase struct {
+	mock.Mock
+}
+
+// Approve provides a mock function with given fields: ctx, id, toStoreCredit
+func (_m *Usecase) Approve(ctx context.Context, id int64, toStoreCredit bool) (*models.Refund, error) {
+	ret := _m.Called(ctx, id, toStoreCredit)
+
+	var r0 *models.Refund
+	if rf, ok := ret.Get(0).(func(context.Context, int64, bool) *models.Refund); ok {
+		r0 = rf(ctx, id, toStoreCredit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Refund)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, bool) error); ok {
+		r1 = rf(ctx, id, toStoreCredit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByOrder provides a mock function with given fields: ctx, orderID
+func (_m *Usecase) FetchByOrder(ctx context.Context, orderID int64) ([]*models.ReturnRequest, error) {
+	ret := _m.Called(ctx, orderID)
+
+	var r0 []*models.ReturnRequest
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*models.ReturnRequest); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.ReturnRequest)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *Usecase) GetByID(ctx context.Context, id int64) (*models.ReturnRequest, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.ReturnRequest
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.ReturnRequest); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ReturnRequest)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reject provides a mock function with given fields: ctx, id, reason
+func (_m *Usecase) Reject(ctx context.Context, id int64, reason string) error {
+	ret := _m.Called(ctx, id, reason)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, id, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Request provides a mock function with given fields: ctx, a
+func (_m *Usecase) Request(ctx context.Context, a *models.ReturnRequest) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ReturnRequest) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RetryRefund provides a mock function with given fields: ctx, returnID
+func (_m *Usecase) RetryRefund(ctx context.Context, returnID int64) (*models.Refund, error) {
+	ret := _m.Called(ctx, returnID)
+
+	var r0 *models.Refund
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.Refund); ok {
+		r0 = rf(ctx, returnID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Refund)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, returnID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,19 @@
+package refund
+
+import (
+	"context"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// Repository represent the refund's repository contract
+type Repository interface {
+	GetByID(ctx context.Context, id int64) (*models.ReturnRequest, error)
+	FetchByOrder(ctx context.Context, orderID int64) (res []*models.ReturnRequest, err error)
+	Store(ctx context.Context, a *models.ReturnRequest) error
+	UpdateStatus(ctx context.Context, a *models.ReturnRequest, from string) error
+	RestockItems(ctx context.Context, sku string, quantity int64) error
+	GetRefundByReturn(ctx context.Context, returnID int64) (*models.Refund, error)
+	StoreRefund(ctx context.Context, a *models.Refund) error
+	UpdateRefund(ctx context.Context, a *models.Refund) error
+}
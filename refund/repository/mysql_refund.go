@@ -0,0 +1,303 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/williamchand/kuncie-cart/logging"
+	"github.com/williamchand/kuncie-cart/metrics"
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/refund"
+	"github.com/williamchand/kuncie-cart/transaction"
+)
+
+type mysqlRefundRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlRefundRepository will create an object that represent the refund.Repository interface
+func NewMysqlRefundRepository(Conn *sql.DB) refund.Repository {
+	return &mysqlRefundRepository{Conn}
+}
+
+func (m *mysqlRefundRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]*models.ReturnRequest, error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	result := make([]*models.ReturnRequest, 0)
+	for rows.Next() {
+		t := new(models.ReturnRequest)
+		err = rows.Scan(
+			&t.ID,
+			&t.OrderID,
+			&t.Status,
+			&t.Reason,
+			&t.RefundAmount,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, t := range result {
+		t.Details, err = m.fetchDetails(ctx, t.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (m *mysqlRefundRepository) fetchDetails(ctx context.Context, returnID int64) ([]*models.ReturnDetails, error) {
+	query := `SELECT rd.id, rd.return_id, rd.order_details_id, od.sku, rd.quantity, rd.refund_amount, rd.updated_at, rd.created_at
+  						FROM return_details rd JOIN order_details od ON od.id = rd.order_details_id
+  						WHERE rd.return_id = ? ORDER BY rd.id`
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, returnID)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	result := make([]*models.ReturnDetails, 0)
+	for rows.Next() {
+		t := new(models.ReturnDetails)
+		err = rows.Scan(
+			&t.ID,
+			&t.ReturnID,
+			&t.OrderDetailsID,
+			&t.SKU,
+			&t.Quantity,
+			&t.RefundAmount,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+func (m *mysqlRefundRepository) GetByID(ctx context.Context, id int64) (*models.ReturnRequest, error) {
+	defer metrics.ObserveQuery("refund", "GetByID", time.Now())
+	query := `SELECT id, order_id, status, reason, refund_amount, updated_at, created_at
+  						FROM returns WHERE id = ?`
+	list, err := m.fetch(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, models.ErrNotFound
+	}
+
+	return list[0], nil
+}
+
+func (m *mysqlRefundRepository) FetchByOrder(ctx context.Context, orderID int64) ([]*models.ReturnRequest, error) {
+	defer metrics.ObserveQuery("refund", "FetchByOrder", time.Now())
+	query := `SELECT id, order_id, status, reason, refund_amount, updated_at, created_at
+  						FROM returns WHERE order_id = ? ORDER BY id`
+
+	return m.fetch(ctx, query, orderID)
+}
+
+func (m *mysqlRefundRepository) Store(ctx context.Context, a *models.ReturnRequest) error {
+	defer metrics.ObserveQuery("refund", "Store", time.Now())
+	conn := transaction.Conn(ctx, m.Conn)
+	query := `INSERT returns SET order_id=?, status=?, reason=?, refund_amount=?, updated_at=?, created_at=?`
+	stmt, err := conn.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, a.OrderID, a.Status, a.Reason, a.RefundAmount, a.UpdatedAt, a.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	a.ID = lastID
+
+	query = `INSERT return_details SET return_id=?, order_details_id=?, quantity=?, refund_amount=?, updated_at=?, created_at=?`
+	stmt, err = conn.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range a.Details {
+		d.ReturnID = a.ID
+		res, err = stmt.ExecContext(ctx, d.ReturnID, d.OrderDetailsID, d.Quantity, d.RefundAmount, d.UpdatedAt, d.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		lastID, err = res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		d.ID = lastID
+	}
+
+	return nil
+}
+
+// UpdateStatus moves the return to its new status only while it is still in the status from,
+// a return another request moved in the meantime returns models.ErrConflict
+func (m *mysqlRefundRepository) UpdateStatus(ctx context.Context, a *models.ReturnRequest, from string) error {
+	defer metrics.ObserveQuery("refund", "UpdateStatus", time.Now())
+	query := `UPDATE returns set status=?, reason=?, updated_at=? WHERE id = ? AND status = ?`
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, a.Status, a.Reason, a.UpdatedAt, a.ID, from)
+	if err != nil {
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect == 0 {
+		return models.ErrConflict
+	}
+
+	return nil
+}
+
+func (m *mysqlRefundRepository) RestockItems(ctx context.Context, sku string, quantity int64) error {
+	defer metrics.ObserveQuery("refund", "RestockItems", time.Now())
+	query := `UPDATE items set inventory_quantity = inventory_quantity + ?, updated_at=? WHERE sku = ?`
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, quantity, time.Now(), sku)
+	if err != nil {
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+func (m *mysqlRefundRepository) GetRefundByReturn(ctx context.Context, returnID int64) (*models.Refund, error) {
+	defer metrics.ObserveQuery("refund", "GetRefundByReturn", time.Now())
+	query := `SELECT id, order_id, return_id, amount, credit_amount, payment_amount, status, updated_at, created_at
+  						FROM refunds WHERE return_id = ?`
+
+	t := new(models.Refund)
+	err := transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, query, returnID).Scan(
+		&t.ID,
+		&t.OrderID,
+		&t.ReturnID,
+		&t.Amount,
+		&t.CreditAmount,
+		&t.PaymentAmount,
+		&t.Status,
+		&t.UpdatedAt,
+		&t.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (m *mysqlRefundRepository) StoreRefund(ctx context.Context, a *models.Refund) error {
+	defer metrics.ObserveQuery("refund", "StoreRefund", time.Now())
+	query := `INSERT refunds SET order_id=?, return_id=?, amount=?, credit_amount=?, payment_amount=?, status=?, updated_at=?, created_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, a.OrderID, a.ReturnID, a.Amount, a.CreditAmount, a.PaymentAmount, a.Status, a.UpdatedAt, a.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	a.ID = lastID
+	return nil
+}
+
+func (m *mysqlRefundRepository) UpdateRefund(ctx context.Context, a *models.Refund) error {
+	defer metrics.ObserveQuery("refund", "UpdateRefund", time.Now())
+	query := `UPDATE refunds set credit_amount=?, payment_amount=?, status=?, updated_at=? WHERE id = ?`
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, a.CreditAmount, a.PaymentAmount, a.Status, a.UpdatedAt, a.ID)
+	if err != nil {
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect != 1 {
+		err = fmt.Errorf("Weird  Behaviour. Total Affected: %d", affect)
+
+		return err
+	}
+
+	return nil
+}
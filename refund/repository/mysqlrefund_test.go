@@ -0,0 +1,60 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/williamchand/kuncie-cart/models"
+	refundRepo "github.com/williamchand/kuncie-cart/refund/repository"
+)
+
+func TestUpdateStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := &models.ReturnRequest{ID: 3, Status: models.ReturnStatusApproved, UpdatedAt: time.Now()}
+	query := "UPDATE returns set status=\\?, reason=\\?, updated_at=\\? WHERE id = \\? AND status = \\?"
+	mock.ExpectPrepare(query).ExpectExec().
+		WithArgs(r.Status, r.Reason, r.UpdatedAt, r.ID, models.ReturnStatusRequested).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// the return was rejected in the meantime
+	mock.ExpectPrepare(query).ExpectExec().
+		WithArgs(r.Status, r.Reason, r.UpdatedAt, r.ID, models.ReturnStatusRequested).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	a := refundRepo.NewMysqlRefundRepository(db)
+	err = a.UpdateStatus(context.TODO(), r, models.ReturnStatusRequested)
+	assert.NoError(t, err)
+
+	err = a.UpdateStatus(context.TODO(), r, models.ReturnStatusRequested)
+	assert.Equal(t, models.ErrConflict, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreRefund(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	r := &models.Refund{OrderID: 1, ReturnID: 3, Amount: 49.99, CreditAmount: 20, PaymentAmount: 29.99, Status: models.RefundStatusPending, UpdatedAt: now, CreatedAt: now}
+	query := "INSERT refunds SET order_id=\\?, return_id=\\?, amount=\\?, credit_amount=\\?, payment_amount=\\?, status=\\?, updated_at=\\?, created_at=\\?"
+	mock.ExpectPrepare(query).ExpectExec().
+		WithArgs(r.OrderID, r.ReturnID, r.Amount, r.CreditAmount, r.PaymentAmount, r.Status, r.UpdatedAt, r.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(9, 1))
+
+	a := refundRepo.NewMysqlRefundRepository(db)
+	err = a.StoreRefund(context.TODO(), r)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9), r.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,17 @@
+package refund
+
+import (
+	"context"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// Usecase represent the refund's usecases
+type Usecase interface {
+	GetByID(ctx context.Context, id int64) (*models.ReturnRequest, error)
+	FetchByOrder(ctx context.Context, orderID int64) (res []*models.ReturnRequest, err error)
+	Request(ctx context.Context, a *models.ReturnRequest) error
+	Approve(ctx context.Context, id int64, toStoreCredit bool) (*models.Refund, error)
+	RetryRefund(ctx context.Context, returnID int64) (*models.Refund, error)
+	Reject(ctx context.Context, id int64, reason string) error
+}
@@ -0,0 +1,394 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/williamchand/kuncie-cart/auth"
+	"github.com/williamchand/kuncie-cart/credit"
+	"github.com/williamchand/kuncie-cart/logging"
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/order"
+	"github.com/williamchand/kuncie-cart/payment"
+	"github.com/williamchand/kuncie-cart/refund"
+	"github.com/williamchand/kuncie-cart/transaction"
+)
+
+type refundUsecase struct {
+	refundRepo     refund.Repository
+	orderRepo      order.Repository
+	creditService  credit.Usecase
+	paymentService payment.Usecase
+	txManager      transaction.Manager
+	contextTimeout time.Duration
+}
+
+// NewRefundUsecase will create new a refundUsecase object representation of refund.Usecase interface
+func NewRefundUsecase(r refund.Repository, o order.Repository, c credit.Usecase, p payment.Usecase, tx transaction.Manager, timeout time.Duration) refund.Usecase {
+	return &refundUsecase{
+		refundRepo:     r,
+		orderRepo:      o,
+		creditService:  c,
+		paymentService: p,
+		txManager:      tx,
+		contextTimeout: timeout,
+	}
+}
+
+func (a *refundUsecase) GetByID(c context.Context, id int64) (*models.ReturnRequest, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.refundRepo.GetByID(ctx, id)
+}
+
+func (a *refundUsecase) FetchByOrder(c context.Context, orderID int64) ([]*models.ReturnRequest, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	o, err := a.orderRepo.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err = auth.AuthorizeOrder(ctx, o); err != nil {
+		return nil, err
+	}
+
+	return a.refundRepo.FetchByOrder(ctx, orderID)
+}
+
+func (a *refundUsecase) Request(c context.Context, m *models.ReturnRequest) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if len(m.Details) == 0 {
+		return models.ErrBadParamInput
+	}
+	o, err := a.orderRepo.GetOrder(ctx, m.OrderID)
+	if err != nil {
+		return err
+	}
+	if err = auth.AuthorizeOrder(ctx, o); err != nil {
+		return err
+	}
+	// only what was paid for can be returned
+	if o.Status == models.OrderStatusPending {
+		return models.ErrConflict
+	}
+
+	// the order row is locked before the returned quantities are read, so concurrent requests on the same
+	// order are checked one after the other and cannot return the same units twice
+	return a.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := a.orderRepo.LockOrder(ctx, m.OrderID); err != nil {
+			return err
+		}
+		details, err := a.orderRepo.GetOrderDetails(ctx, m.OrderID)
+		if err != nil {
+			return err
+		}
+		previous, err := a.refundRepo.FetchByOrder(ctx, m.OrderID)
+		if err != nil {
+			return err
+		}
+
+		returned := make(map[int64]int64)
+		for _, r := range previous {
+			if r.Status == models.ReturnStatusRejected {
+				continue
+			}
+			for _, d := range r.Details {
+				returned[d.OrderDetailsID] += d.Quantity
+			}
+		}
+
+		byID := make(map[int64]*models.OrderDetails, len(details))
+		for _, d := range details {
+			byID[d.ID] = d
+		}
+		requested := make(map[int64]int64)
+		for _, d := range m.Details {
+			line, ok := byID[d.OrderDetailsID]
+			// the components of a kit are returned with the kit line
+			if !ok || d.Quantity <= 0 || line.ParentID != 0 {
+				return models.ErrBadParamInput
+			}
+			requested[d.OrderDetailsID] += d.Quantity
+			if returned[line.ID]+requested[line.ID] > line.Quantity {
+				return models.ErrBadParamInput
+			}
+			d.SKU = line.SKU
+		}
+
+		amounts := refundAmounts(details, returned, requested)
+
+		now := time.Now()
+		m.Status = models.ReturnStatusRequested
+		m.RefundAmount = 0
+		m.CreatedAt = now
+		m.UpdatedAt = now
+		for _, d := range m.Details {
+			d.RefundAmount = 0
+			if requested[d.OrderDetailsID] > 0 {
+				share := float64(d.Quantity) / float64(requested[d.OrderDetailsID])
+				d.RefundAmount = round(amounts[d.OrderDetailsID] * share)
+			}
+			d.CreatedAt = now
+			d.UpdatedAt = now
+			m.RefundAmount += d.RefundAmount
+		}
+		m.RefundAmount = round(m.RefundAmount)
+
+		return a.refundRepo.Store(ctx, m)
+	})
+}
+
+// refundAmounts computes the money owed back for every requested order line from what was recorded at checkout,
+// a price or promotion changed in the catalog since then does not change the refund.
+//
+// Order lines are grouped by SKU: checkout stores one paid line per item and, for free_items
+// promotions, a zero priced gift line of the same SKU. What was charged for the paid line is spread over
+// every unit of the group, gifts included, so a customer who keeps the gifts keeps paying for them.
+// The group is refunded the share of the charge of the units it has left before the return minus the
+// share of those it keeps after, which adds up to the charge once every unit is returned. The zero priced
+// component lines of a kit are left out, a kit is refunded by its own line.
+func refundAmounts(details []*models.OrderDetails, returned, requested map[int64]int64) map[int64]float64 {
+	type group struct {
+		paid *models.OrderDetails
+		gift *models.OrderDetails
+	}
+	groups := make(map[string]*group)
+	skus := make([]string, 0)
+	for _, d := range details {
+		if _, ok := groups[d.SKU]; !ok && requested[d.ID] > 0 {
+			groups[d.SKU] = &group{}
+			skus = append(skus, d.SKU)
+		}
+	}
+	for _, d := range details {
+		g, ok := groups[d.SKU]
+		if !ok || d.ParentID != 0 {
+			continue
+		}
+		if d.PromoType == "free_items" && d.Price == 0 {
+			g.gift = d
+		} else {
+			g.paid = d
+		}
+	}
+
+	res := make(map[int64]float64)
+	for _, sku := range skus {
+		g := groups[sku]
+		if g.paid == nil {
+			// gifts without the line that paid for them have nothing to give back
+			continue
+		}
+
+		var total, before, requestedPaid, requestedGift int64
+		for _, d := range []*models.OrderDetails{g.paid, g.gift} {
+			if d == nil {
+				continue
+			}
+			total += d.Quantity
+			before += d.Quantity - returned[d.ID]
+		}
+		requestedPaid = requested[g.paid.ID]
+		if g.gift != nil {
+			requestedGift = requested[g.gift.ID]
+		}
+		after := before - requestedPaid - requestedGift
+		if total <= 0 || requestedPaid+requestedGift <= 0 {
+			continue
+		}
+
+		amount := round(g.paid.Price*float64(before)/float64(total)) - round(g.paid.Price*float64(after)/float64(total))
+		amount = math.Max(round(amount), 0)
+		paidShare := round(amount * float64(requestedPaid) / float64(requestedPaid+requestedGift))
+		if requestedPaid > 0 {
+			res[g.paid.ID] = paidShare
+		}
+		if requestedGift > 0 {
+			res[g.gift.ID] = round(amount - paidShare)
+		}
+	}
+
+	return res
+}
+
+// restock puts the returned items back in stock, the components of a returned kit instead of the kit
+func (a *refundUsecase) restock(ctx context.Context, m *models.ReturnRequest) error {
+	details, err := a.orderRepo.GetOrderDetails(ctx, m.OrderID)
+	if err != nil {
+		return err
+	}
+	byID := make(map[int64]*models.OrderDetails, len(details))
+	components := make(map[int64][]*models.OrderDetails)
+	for _, d := range details {
+		byID[d.ID] = d
+		if d.ParentID != 0 {
+			components[d.ParentID] = append(components[d.ParentID], d)
+		}
+	}
+
+	for _, d := range m.Details {
+		line, ok := byID[d.OrderDetailsID]
+		if !ok || len(components[line.ID]) == 0 {
+			if err := a.refundRepo.RestockItems(ctx, d.SKU, d.Quantity); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, c := range components[line.ID] {
+			if err := a.refundRepo.RestockItems(ctx, c.SKU, c.Quantity/line.Quantity*d.Quantity); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Approve restocks the returned items and records the refund in a single transaction, gift cards and store credit
+// are given back within it. The transaction is committed with the payment part of the refund pending before the
+// payment provider is called, so a rollback cannot undo the record of money the provider gave back already.
+// The outcome of the provider is recorded on the refund, one left pending or failed is sent again by RetryRefund.
+// With toStoreCredit the payment part is credited to the store credit of the customer instead, within the transaction.
+func (a *refundUsecase) Approve(c context.Context, id int64, toStoreCredit bool) (*models.Refund, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	var res *models.Refund
+	err := a.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		m, err := a.refundRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if m.Status != models.ReturnStatusRequested {
+			return models.ErrConflict
+		}
+
+		// the status is moved first, a concurrent approval or rejection waits for the row and then finds nothing to move
+		now := time.Now()
+		m.Status = models.ReturnStatusApproved
+		m.UpdatedAt = now
+		if err := a.refundRepo.UpdateStatus(ctx, m, models.ReturnStatusRequested); err != nil {
+			return err
+		}
+
+		if err := a.restock(ctx, m); err != nil {
+			return err
+		}
+
+		res = &models.Refund{
+			OrderID:   m.OrderID,
+			ReturnID:  m.ID,
+			Amount:    m.RefundAmount,
+			Status:    models.RefundStatusPending,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := a.refundRepo.StoreRefund(ctx, res); err != nil {
+			return err
+		}
+		// gift cards and store credit are given back first, only the rest goes back to the payment provider
+		credited, err := a.creditService.Restore(ctx, m.OrderID, res.ID, res.Amount)
+		if err != nil {
+			return err
+		}
+		if rest := round(res.Amount - credited); toStoreCredit && rest > 0 {
+			o, err := a.orderRepo.GetOrder(ctx, m.OrderID)
+			if err != nil {
+				return err
+			}
+			// an order placed without an account has no wallet to credit
+			if o.CustomerID == 0 {
+				return models.ErrBadParamInput
+			}
+			if _, err := a.creditService.IssueStoreCredit(ctx, o.CustomerID, m.OrderID, res.ID, rest); err != nil {
+				return err
+			}
+			credited = round(credited + rest)
+		}
+		res.CreditAmount = credited
+		res.PaymentAmount = math.Max(round(res.Amount-credited), 0)
+		if res.PaymentAmount == 0 {
+			res.Status = models.RefundStatusCompleted
+		}
+
+		return a.refundRepo.UpdateRefund(ctx, res)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Status == models.RefundStatusCompleted {
+		return res, nil
+	}
+	return a.sendRefund(ctx, res)
+}
+
+// RetryRefund sends the payment part of the refund of an approved return to the provider again
+// when it was refused or its answer was lost, a completed refund is returned as it is
+func (a *refundUsecase) RetryRefund(c context.Context, returnID int64) (*models.Refund, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, err := a.refundRepo.GetRefundByReturn(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+	if res.Status == models.RefundStatusCompleted {
+		return res, nil
+	}
+
+	return a.sendRefund(ctx, res)
+}
+
+// sendRefund gives the payment part of the refund back through the payment provider and records the outcome.
+// The refund is sent with a key derived from its id, so sending it again cannot give the money back twice.
+// A refused refund is recorded as failed and returned without an error, the return stays approved.
+func (a *refundUsecase) sendRefund(ctx context.Context, res *models.Refund) (*models.Refund, error) {
+	_, err := a.paymentService.Refund(ctx, res.OrderID, res.PaymentAmount, fmt.Sprintf("refund-%d", res.ID))
+	if err == context.DeadlineExceeded || err == context.Canceled {
+		// the provider may have given the money back, the refund stays pending
+		return nil, err
+	}
+
+	res.Status = models.RefundStatusCompleted
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		res.Status = models.RefundStatusFailed
+	}
+	res.UpdatedAt = time.Now()
+	if err := a.refundRepo.UpdateRefund(ctx, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (a *refundUsecase) Reject(c context.Context, id int64, reason string) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	m, err := a.refundRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if m.Status != models.ReturnStatusRequested {
+		return models.ErrConflict
+	}
+
+	m.Status = models.ReturnStatusRejected
+	if reason != "" {
+		m.Reason = reason
+	}
+	m.UpdatedAt = time.Now()
+	// a return approved meanwhile is not rejected over
+	return a.refundRepo.UpdateStatus(ctx, m, models.ReturnStatusRequested)
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,601 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/williamchand/kuncie-cart/auth"
+	_creditMocks "github.com/williamchand/kuncie-cart/credit/mocks"
+	"github.com/williamchand/kuncie-cart/models"
+	_orderMocks "github.com/williamchand/kuncie-cart/order/mocks"
+	_paymentMocks "github.com/williamchand/kuncie-cart/payment/mocks"
+	"github.com/williamchand/kuncie-cart/refund/mocks"
+	ucase "github.com/williamchand/kuncie-cart/refund/usecase"
+	_txMocks "github.com/williamchand/kuncie-cart/transaction/mocks"
+)
+
+// txKey marks the contexts of the transactions run by the test managers
+type txKey struct{}
+
+func TestFetchByOrder(t *testing.T) {
+	order := &models.Order{ID: 1, CustomerID: 5}
+
+	t.Run("owner", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(1)).Return(order, nil).Once()
+		mockRefundRepo.On("FetchByOrder", mock.Anything, int64(1)).Return([]*models.ReturnRequest{{ID: 3, OrderID: 1}}, nil).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockOrderRepo, new(_creditMocks.Usecase), new(_paymentMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		res, err := u.FetchByOrder(auth.NewCustomerContext(context.TODO(), 5), 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+		mockRefundRepo.AssertExpectations(t)
+	})
+
+	t.Run("order-of-another-customer", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(1)).Return(order, nil).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockOrderRepo, new(_creditMocks.Usecase), new(_paymentMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		res, err := u.FetchByOrder(auth.NewCustomerContext(context.TODO(), 6), 1)
+
+		assert.Equal(t, models.ErrForbidden, err)
+		assert.Nil(t, res)
+		mockRefundRepo.AssertNotCalled(t, "FetchByOrder", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRequest(t *testing.T) {
+	order := &models.Order{ID: 1, CustomerID: 5, TotalPrice: 199.96, Status: models.OrderStatusPaid}
+	googleHomePrice, macbookPrice := 49.99, 5399.99
+	details := []*models.OrderDetails{
+		{ID: 10, OrderID: 1, SKU: "120P90", Name: "Google Home", Price: 149.97, ListPrice: &googleHomePrice, Quantity: 4, PromoType: "bonus_price"},
+		{ID: 11, OrderID: 1, SKU: "43N23P", Name: "Macbook Pro", Price: 10799.98, ListPrice: &macbookPrice, Quantity: 2, PromoType: "free_items"},
+		{ID: 12, OrderID: 1, SKU: "43N23P", Name: "Macbook Pro", Price: 0, ListPrice: &macbookPrice, Quantity: 2, PromoType: "free_items"},
+	}
+
+	t.Run("bonus-price-prorated", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(1)).Return(order, nil).Once()
+		mockOrderRepo.On("LockOrder", mock.Anything, int64(1)).Return(order, nil).Once()
+		mockOrderRepo.On("GetOrderDetails", mock.Anything, int64(1)).Return(details, nil).Once()
+		mockRefundRepo.On("FetchByOrder", mock.Anything, int64(1)).Return([]*models.ReturnRequest{}, nil).Once()
+		mockRefundRepo.On("Store", mock.Anything, mock.AnythingOfType("*models.ReturnRequest")).Return(nil).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockOrderRepo, new(_creditMocks.Usecase), new(_paymentMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		req := &models.ReturnRequest{
+			OrderID: 1,
+			Details: []*models.ReturnDetails{{OrderDetailsID: 10, Quantity: 2}},
+		}
+		err := u.Request(auth.NewCustomerContext(context.TODO(), 5), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.ReturnStatusRequested, req.Status)
+		// half of the 149.97 paid for 4 units, the odd cent stays with the units kept
+		assert.Equal(t, 74.98, req.RefundAmount)
+		mockRefundRepo.AssertExpectations(t)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("free-items-kept", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(1)).Return(order, nil).Once()
+		mockOrderRepo.On("LockOrder", mock.Anything, int64(1)).Return(order, nil).Once()
+		mockOrderRepo.On("GetOrderDetails", mock.Anything, int64(1)).Return(details, nil).Once()
+		mockRefundRepo.On("FetchByOrder", mock.Anything, int64(1)).Return([]*models.ReturnRequest{}, nil).Once()
+		mockRefundRepo.On("Store", mock.Anything, mock.AnythingOfType("*models.ReturnRequest")).Return(nil).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockOrderRepo, new(_creditMocks.Usecase), new(_paymentMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		req := &models.ReturnRequest{
+			OrderID: 1,
+			Details: []*models.ReturnDetails{
+				{OrderDetailsID: 11, Quantity: 1},
+				{OrderDetailsID: 12, Quantity: 1},
+			},
+		}
+		err := u.Request(auth.NewCustomerContext(context.TODO(), 5), req)
+
+		assert.NoError(t, err)
+		// 10799.98 paid for 2 units and 2 gifts, the 4 units are worth 2699.995 each
+		assert.Equal(t, 2700.0, req.Details[0].RefundAmount)
+		assert.Equal(t, 2699.99, req.Details[1].RefundAmount)
+		assert.Equal(t, 5399.99, req.RefundAmount)
+		mockRefundRepo.AssertExpectations(t)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("free-items-gifts-kept", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(1)).Return(order, nil).Once()
+		mockOrderRepo.On("LockOrder", mock.Anything, int64(1)).Return(order, nil).Once()
+		mockOrderRepo.On("GetOrderDetails", mock.Anything, int64(1)).Return(details, nil).Once()
+		mockRefundRepo.On("FetchByOrder", mock.Anything, int64(1)).Return([]*models.ReturnRequest{}, nil).Once()
+		mockRefundRepo.On("Store", mock.Anything, mock.AnythingOfType("*models.ReturnRequest")).Return(nil).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockOrderRepo, new(_creditMocks.Usecase), new(_paymentMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		req := &models.ReturnRequest{
+			OrderID: 1,
+			Details: []*models.ReturnDetails{{OrderDetailsID: 11, Quantity: 2}},
+		}
+		err := u.Request(auth.NewCustomerContext(context.TODO(), 5), req)
+
+		// the 2 gifts kept are paid for by half of the charge
+		assert.NoError(t, err)
+		assert.Equal(t, 5399.99, req.RefundAmount)
+
+		// returning the gifts afterwards gives back the rest
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(1)).Return(order, nil).Once()
+		mockOrderRepo.On("LockOrder", mock.Anything, int64(1)).Return(order, nil).Once()
+		mockOrderRepo.On("GetOrderDetails", mock.Anything, int64(1)).Return(details, nil).Once()
+		mockRefundRepo.On("FetchByOrder", mock.Anything, int64(1)).Return([]*models.ReturnRequest{req}, nil).Once()
+		mockRefundRepo.On("Store", mock.Anything, mock.AnythingOfType("*models.ReturnRequest")).Return(nil).Once()
+		gifts := &models.ReturnRequest{
+			OrderID: 1,
+			Details: []*models.ReturnDetails{{OrderDetailsID: 12, Quantity: 2}},
+		}
+		err = u.Request(auth.NewCustomerContext(context.TODO(), 5), gifts)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5399.99, gifts.RefundAmount)
+		mockRefundRepo.AssertExpectations(t)
+	})
+
+	t.Run("catalog-changed-since-checkout", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(1)).Return(order, nil).Once()
+		mockOrderRepo.On("LockOrder", mock.Anything, int64(1)).Return(order, nil).Once()
+		mockOrderRepo.On("GetOrderDetails", mock.Anything, int64(1)).Return(details, nil).Once()
+		mockRefundRepo.On("FetchByOrder", mock.Anything, int64(1)).Return([]*models.ReturnRequest{}, nil).Once()
+		// the price went up and the promotion changed after the order was placed
+		mockOrderRepo.On("GetItems", mock.Anything, mock.Anything).
+			Return([]*models.Items{{ID: 1, SKU: "120P90", Price: 59.99}}, nil).Maybe()
+		mockOrderRepo.On("GetPromotions", mock.Anything, mock.Anything).
+			Return(&models.Promotions{ItemsID: 1, PromoType: "discount_items", Promo: "0.5", QuantityRequirement: 10}, nil).Maybe()
+		mockRefundRepo.On("Store", mock.Anything, mock.AnythingOfType("*models.ReturnRequest")).Return(nil).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockOrderRepo, new(_creditMocks.Usecase), new(_paymentMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		req := &models.ReturnRequest{
+			OrderID: 1,
+			Details: []*models.ReturnDetails{{OrderDetailsID: 10, Quantity: 4}},
+		}
+		err := u.Request(auth.NewCustomerContext(context.TODO(), 5), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 149.97, req.RefundAmount)
+		mockOrderRepo.AssertNotCalled(t, "GetItems", mock.Anything, mock.Anything)
+		mockOrderRepo.AssertNotCalled(t, "GetPromotions", mock.Anything, mock.Anything)
+	})
+
+	t.Run("order-not-paid", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(1)).
+			Return(&models.Order{ID: 1, CustomerID: 5, Status: models.OrderStatusPending}, nil).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockOrderRepo, new(_creditMocks.Usecase), new(_paymentMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		req := &models.ReturnRequest{
+			OrderID: 1,
+			Details: []*models.ReturnDetails{{OrderDetailsID: 10, Quantity: 2}},
+		}
+		err := u.Request(auth.NewCustomerContext(context.TODO(), 5), req)
+
+		assert.Equal(t, models.ErrConflict, err)
+		mockOrderRepo.AssertNotCalled(t, "LockOrder", mock.Anything, mock.Anything)
+		mockRefundRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("kit-component-line", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(1)).Return(order, nil).Once()
+		mockOrderRepo.On("LockOrder", mock.Anything, int64(1)).Return(order, nil).Once()
+		kitDetails := []*models.OrderDetails{
+			{ID: 20, OrderID: 1, SKU: "KIT001", Price: 99.99, Quantity: 1},
+			{ID: 21, OrderID: 1, ParentID: 20, SKU: "120P90", Quantity: 1},
+		}
+		mockOrderRepo.On("GetOrderDetails", mock.Anything, int64(1)).Return(kitDetails, nil).Once()
+		mockRefundRepo.On("FetchByOrder", mock.Anything, int64(1)).Return([]*models.ReturnRequest{}, nil).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockOrderRepo, new(_creditMocks.Usecase), new(_paymentMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		req := &models.ReturnRequest{
+			OrderID: 1,
+			Details: []*models.ReturnDetails{{OrderDetailsID: 21, Quantity: 1}},
+		}
+		err := u.Request(auth.NewCustomerContext(context.TODO(), 5), req)
+
+		assert.Equal(t, models.ErrBadParamInput, err)
+		mockRefundRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("quantity-already-returned", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(1)).Return(order, nil).Once()
+		mockOrderRepo.On("LockOrder", mock.Anything, int64(1)).Return(order, nil).Once()
+		mockOrderRepo.On("GetOrderDetails", mock.Anything, int64(1)).Return(details, nil).Once()
+		previous := []*models.ReturnRequest{{
+			Status:  models.ReturnStatusApproved,
+			Details: []*models.ReturnDetails{{OrderDetailsID: 10, Quantity: 3}},
+		}}
+		mockRefundRepo.On("FetchByOrder", mock.Anything, int64(1)).Return(previous, nil).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockOrderRepo, new(_creditMocks.Usecase), new(_paymentMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		req := &models.ReturnRequest{
+			OrderID: 1,
+			Details: []*models.ReturnDetails{{OrderDetailsID: 10, Quantity: 2}},
+		}
+		err := u.Request(auth.NewCustomerContext(context.TODO(), 5), req)
+
+		assert.Equal(t, models.ErrBadParamInput, err)
+		mockRefundRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+	t.Run("validated-in-transaction", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockTx := new(_txMocks.Manager)
+		mockTx.On("WithTransaction", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(context.WithValue(ctx, txKey{}, true))
+			}).Once()
+		inTx := mock.MatchedBy(func(ctx context.Context) bool {
+			return ctx.Value(txKey{}) != nil
+		})
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(1)).Return(order, nil).Once()
+		mockOrderRepo.On("LockOrder", inTx, int64(1)).Return(order, nil).Once()
+		mockOrderRepo.On("GetOrderDetails", inTx, int64(1)).Return(details, nil).Once()
+		// another request took the last units while this one waited for the lock
+		previous := []*models.ReturnRequest{{
+			Status:  models.ReturnStatusRequested,
+			Details: []*models.ReturnDetails{{OrderDetailsID: 10, Quantity: 3}},
+		}}
+		mockRefundRepo.On("FetchByOrder", inTx, int64(1)).Return(previous, nil).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockOrderRepo, new(_creditMocks.Usecase), new(_paymentMocks.Usecase), mockTx, time.Second*2)
+		req := &models.ReturnRequest{
+			OrderID: 1,
+			Details: []*models.ReturnDetails{{OrderDetailsID: 10, Quantity: 2}},
+		}
+		err := u.Request(auth.NewCustomerContext(context.TODO(), 5), req)
+
+		assert.Equal(t, models.ErrBadParamInput, err)
+		mockOrderRepo.AssertExpectations(t)
+		mockRefundRepo.AssertExpectations(t)
+		mockRefundRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("order-of-another-customer", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(1)).Return(order, nil).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockOrderRepo, new(_creditMocks.Usecase), new(_paymentMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		req := &models.ReturnRequest{
+			OrderID: 1,
+			Details: []*models.ReturnDetails{{OrderDetailsID: 10, Quantity: 2}},
+		}
+		err := u.Request(auth.NewCustomerContext(context.TODO(), 6), req)
+
+		assert.Equal(t, models.ErrForbidden, err)
+		mockRefundRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
+
+func TestApprove(t *testing.T) {
+	returnRequest := func() *models.ReturnRequest {
+		return &models.ReturnRequest{
+			ID:           3,
+			OrderID:      1,
+			Status:       models.ReturnStatusRequested,
+			RefundAmount: 49.99,
+			Details:      []*models.ReturnDetails{{OrderDetailsID: 10, SKU: "120P90", Quantity: 2}},
+		}
+	}
+	withStatus := func(status string) interface{} {
+		return mock.MatchedBy(func(r *models.Refund) bool {
+			return r.Status == status
+		})
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockTx := new(_txMocks.Manager)
+		committed := false
+		mockTx.On("WithTransaction", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				err := fn(ctx)
+				committed = err == nil
+				return err
+			}).Once()
+		req := returnRequest()
+		mockRefundRepo.On("GetByID", mock.Anything, int64(3)).Return(req, nil).Once()
+		mockRefundRepo.On("UpdateStatus", mock.Anything, req, models.ReturnStatusRequested).Return(nil).Once()
+		mockOrderRepo.On("GetOrderDetails", mock.Anything, int64(1)).
+			Return([]*models.OrderDetails{{ID: 10, OrderID: 1, SKU: "120P90", Quantity: 4}}, nil).Once()
+		mockRefundRepo.On("RestockItems", mock.Anything, "120P90", int64(2)).Return(nil).Once()
+		mockRefundRepo.On("StoreRefund", mock.Anything, withStatus(models.RefundStatusPending)).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.Refund).ID = 9
+		}).Once()
+		mockCredit := new(_creditMocks.Usecase)
+		mockCredit.On("Restore", mock.Anything, int64(1), int64(9), 49.99).Return(20.0, nil).Once()
+		mockRefundRepo.On("UpdateRefund", mock.Anything, withStatus(models.RefundStatusPending)).Return(nil).Once()
+		mockPayment := new(_paymentMocks.Usecase)
+		mockPayment.On("Refund", mock.Anything, int64(1), 29.99, "refund-9").
+			Return(func(ctx context.Context, orderID int64, amount float64, key string) *models.PaymentIntent {
+				// the provider is called once the approval is committed
+				assert.True(t, committed)
+				return &models.PaymentIntent{OrderID: 1, RefundedAmount: 29.99}
+			}, nil).Once()
+		mockRefundRepo.On("UpdateRefund", mock.Anything, withStatus(models.RefundStatusCompleted)).Return(nil).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockOrderRepo, mockCredit, mockPayment, mockTx, time.Second*2)
+		res, err := u.Approve(context.TODO(), 3, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 49.99, res.Amount)
+		assert.Equal(t, 20.0, res.CreditAmount)
+		// the rest goes back to the card
+		assert.Equal(t, 29.99, res.PaymentAmount)
+		assert.Equal(t, models.RefundStatusCompleted, res.Status)
+		mockCredit.AssertExpectations(t)
+		mockPayment.AssertExpectations(t)
+		assert.Equal(t, models.ReturnStatusApproved, req.Status)
+		mockRefundRepo.AssertExpectations(t)
+	})
+
+	t.Run("paid-with-credit-only", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		req := returnRequest()
+		mockRefundRepo.On("GetByID", mock.Anything, int64(3)).Return(req, nil).Once()
+		mockRefundRepo.On("UpdateStatus", mock.Anything, req, models.ReturnStatusRequested).Return(nil).Once()
+		mockOrderRepo.On("GetOrderDetails", mock.Anything, int64(1)).
+			Return([]*models.OrderDetails{{ID: 10, OrderID: 1, SKU: "120P90", Quantity: 4}}, nil).Once()
+		mockRefundRepo.On("RestockItems", mock.Anything, "120P90", int64(2)).Return(nil).Once()
+		mockRefundRepo.On("StoreRefund", mock.Anything, mock.AnythingOfType("*models.Refund")).Return(nil).Once()
+		mockRefundRepo.On("UpdateRefund", mock.Anything, withStatus(models.RefundStatusCompleted)).Return(nil).Once()
+		mockCredit := new(_creditMocks.Usecase)
+		mockCredit.On("Restore", mock.Anything, int64(1), mock.Anything, 49.99).Return(49.99, nil).Once()
+		mockPayment := new(_paymentMocks.Usecase)
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockOrderRepo, mockCredit, mockPayment, _txMocks.NewPassthroughManager(), time.Second*2)
+		res, err := u.Approve(context.TODO(), 3, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0.0, res.PaymentAmount)
+		assert.Equal(t, models.RefundStatusCompleted, res.Status)
+		mockPayment.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockRefundRepo.AssertExpectations(t)
+	})
+
+	t.Run("to-store-credit", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		req := returnRequest()
+		mockRefundRepo.On("GetByID", mock.Anything, int64(3)).Return(req, nil).Once()
+		mockRefundRepo.On("UpdateStatus", mock.Anything, req, models.ReturnStatusRequested).Return(nil).Once()
+		mockOrderRepo.On("GetOrderDetails", mock.Anything, int64(1)).
+			Return([]*models.OrderDetails{{ID: 10, OrderID: 1, SKU: "120P90", Quantity: 4}}, nil).Once()
+		mockRefundRepo.On("RestockItems", mock.Anything, "120P90", int64(2)).Return(nil).Once()
+		mockRefundRepo.On("StoreRefund", mock.Anything, mock.AnythingOfType("*models.Refund")).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.Refund).ID = 9
+		}).Once()
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(1)).Return(&models.Order{ID: 1, CustomerID: 5}, nil).Once()
+		mockCredit := new(_creditMocks.Usecase)
+		mockCredit.On("Restore", mock.Anything, int64(1), int64(9), 49.99).Return(20.0, nil).Once()
+		mockCredit.On("IssueStoreCredit", mock.Anything, int64(5), int64(1), int64(9), 29.99).
+			Return(&models.StoreCredit{CustomerID: 5, Balance: 29.99}, nil).Once()
+		mockRefundRepo.On("UpdateRefund", mock.Anything, withStatus(models.RefundStatusCompleted)).Return(nil).Once()
+		mockPayment := new(_paymentMocks.Usecase)
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockOrderRepo, mockCredit, mockPayment, _txMocks.NewPassthroughManager(), time.Second*2)
+		res, err := u.Approve(context.TODO(), 3, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 49.99, res.CreditAmount)
+		assert.Equal(t, 0.0, res.PaymentAmount)
+		assert.Equal(t, models.RefundStatusCompleted, res.Status)
+		mockCredit.AssertExpectations(t)
+		mockPayment.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockRefundRepo.AssertExpectations(t)
+	})
+
+	t.Run("to-store-credit-of-a-guest", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		req := returnRequest()
+		mockRefundRepo.On("GetByID", mock.Anything, int64(3)).Return(req, nil).Once()
+		mockRefundRepo.On("UpdateStatus", mock.Anything, req, models.ReturnStatusRequested).Return(nil).Once()
+		mockOrderRepo.On("GetOrderDetails", mock.Anything, int64(1)).
+			Return([]*models.OrderDetails{{ID: 10, OrderID: 1, SKU: "120P90", Quantity: 4}}, nil).Once()
+		mockRefundRepo.On("RestockItems", mock.Anything, "120P90", int64(2)).Return(nil).Once()
+		mockRefundRepo.On("StoreRefund", mock.Anything, mock.AnythingOfType("*models.Refund")).Return(nil).Once()
+		mockOrderRepo.On("GetOrder", mock.Anything, int64(1)).Return(&models.Order{ID: 1}, nil).Once()
+		mockCredit := new(_creditMocks.Usecase)
+		mockCredit.On("Restore", mock.Anything, int64(1), mock.Anything, 49.99).Return(0.0, nil).Once()
+		mockPayment := new(_paymentMocks.Usecase)
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockOrderRepo, mockCredit, mockPayment, _txMocks.NewPassthroughManager(), time.Second*2)
+		res, err := u.Approve(context.TODO(), 3, true)
+
+		assert.Equal(t, models.ErrBadParamInput, err)
+		assert.Nil(t, res)
+		mockCredit.AssertNotCalled(t, "IssueStoreCredit", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockPayment.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("provider-refund-fails", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		req := returnRequest()
+		mockRefundRepo.On("GetByID", mock.Anything, int64(3)).Return(req, nil).Once()
+		mockRefundRepo.On("UpdateStatus", mock.Anything, req, models.ReturnStatusRequested).Return(nil).Once()
+		mockOrderRepo.On("GetOrderDetails", mock.Anything, int64(1)).
+			Return([]*models.OrderDetails{{ID: 10, OrderID: 1, SKU: "120P90", Quantity: 4}}, nil).Once()
+		mockRefundRepo.On("RestockItems", mock.Anything, "120P90", int64(2)).Return(nil).Once()
+		mockRefundRepo.On("StoreRefund", mock.Anything, mock.AnythingOfType("*models.Refund")).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.Refund).ID = 9
+		}).Once()
+		mockRefundRepo.On("UpdateRefund", mock.Anything, withStatus(models.RefundStatusPending)).Return(nil).Once()
+		mockCredit := new(_creditMocks.Usecase)
+		mockCredit.On("Restore", mock.Anything, int64(1), int64(9), 49.99).Return(0.0, nil).Once()
+		mockPayment := new(_paymentMocks.Usecase)
+		mockPayment.On("Refund", mock.Anything, int64(1), 49.99, "refund-9").Return(nil, models.ErrConflict).Once()
+		mockRefundRepo.On("UpdateRefund", mock.Anything, withStatus(models.RefundStatusFailed)).Return(nil).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockOrderRepo, mockCredit, mockPayment, _txMocks.NewPassthroughManager(), time.Second*2)
+		res, err := u.Approve(context.TODO(), 3, false)
+
+		// the return stays approved, the refund is left to be sent again
+		assert.NoError(t, err)
+		assert.Equal(t, models.RefundStatusFailed, res.Status)
+		assert.Equal(t, models.ReturnStatusApproved, req.Status)
+		mockPayment.AssertExpectations(t)
+		mockRefundRepo.AssertExpectations(t)
+	})
+
+	t.Run("kit-restocks-components", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		req := &models.ReturnRequest{
+			ID:           3,
+			OrderID:      1,
+			Status:       models.ReturnStatusRequested,
+			RefundAmount: 99.99,
+			Details:      []*models.ReturnDetails{{OrderDetailsID: 10, SKU: "KIT001", Quantity: 1}},
+		}
+		details := []*models.OrderDetails{
+			{ID: 10, OrderID: 1, SKU: "KIT001", Price: 199.98, Quantity: 2},
+			{ID: 11, OrderID: 1, ParentID: 10, SKU: "120P90", Quantity: 2},
+			{ID: 12, OrderID: 1, ParentID: 10, SKU: "234234", Quantity: 4},
+		}
+		mockRefundRepo.On("GetByID", mock.Anything, int64(3)).Return(req, nil).Once()
+		mockRefundRepo.On("UpdateStatus", mock.Anything, req, models.ReturnStatusRequested).Return(nil).Once()
+		mockOrderRepo.On("GetOrderDetails", mock.Anything, int64(1)).Return(details, nil).Once()
+		mockRefundRepo.On("RestockItems", mock.Anything, "120P90", int64(1)).Return(nil).Once()
+		mockRefundRepo.On("RestockItems", mock.Anything, "234234", int64(2)).Return(nil).Once()
+		mockRefundRepo.On("StoreRefund", mock.Anything, mock.AnythingOfType("*models.Refund")).Return(nil).Once()
+		mockRefundRepo.On("UpdateRefund", mock.Anything, mock.AnythingOfType("*models.Refund")).Return(nil).Twice()
+		mockCredit := new(_creditMocks.Usecase)
+		mockCredit.On("Restore", mock.Anything, int64(1), mock.Anything, 99.99).Return(0.0, nil).Once()
+		mockPayment := new(_paymentMocks.Usecase)
+		mockPayment.On("Refund", mock.Anything, int64(1), 99.99, mock.Anything).Return(&models.PaymentIntent{OrderID: 1}, nil).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockOrderRepo, mockCredit, mockPayment, _txMocks.NewPassthroughManager(), time.Second*2)
+		_, err := u.Approve(context.TODO(), 3, false)
+
+		assert.NoError(t, err)
+		mockRefundRepo.AssertExpectations(t)
+		mockRefundRepo.AssertNotCalled(t, "RestockItems", mock.Anything, "KIT001", mock.Anything)
+	})
+
+	t.Run("already-rejected", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		mockRefundRepo.On("GetByID", mock.Anything, int64(3)).
+			Return(&models.ReturnRequest{ID: 3, Status: models.ReturnStatusRejected}, nil).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockOrderRepo, new(_creditMocks.Usecase), new(_paymentMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		res, err := u.Approve(context.TODO(), 3, false)
+
+		assert.Equal(t, models.ErrConflict, err)
+		assert.Nil(t, res)
+		mockRefundRepo.AssertExpectations(t)
+	})
+
+	t.Run("approved-concurrently", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		// the return was read as requested, another approval moved it before this one got the row
+		req := returnRequest()
+		mockRefundRepo.On("GetByID", mock.Anything, int64(3)).Return(req, nil).Once()
+		mockRefundRepo.On("UpdateStatus", mock.Anything, req, models.ReturnStatusRequested).Return(models.ErrConflict).Once()
+		mockPayment := new(_paymentMocks.Usecase)
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockOrderRepo, new(_creditMocks.Usecase), mockPayment, _txMocks.NewPassthroughManager(), time.Second*2)
+		res, err := u.Approve(context.TODO(), 3, false)
+
+		assert.Equal(t, models.ErrConflict, err)
+		assert.Nil(t, res)
+		mockRefundRepo.AssertNotCalled(t, "RestockItems", mock.Anything, mock.Anything, mock.Anything)
+		mockRefundRepo.AssertNotCalled(t, "StoreRefund", mock.Anything, mock.Anything)
+		mockPayment.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRetryRefund(t *testing.T) {
+	t.Run("failed", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		failed := &models.Refund{ID: 9, OrderID: 1, ReturnID: 3, Amount: 49.99, PaymentAmount: 49.99, Status: models.RefundStatusFailed}
+		mockRefundRepo.On("GetRefundByReturn", mock.Anything, int64(3)).Return(failed, nil).Once()
+		mockPayment := new(_paymentMocks.Usecase)
+		// sent with the key of the first attempt
+		mockPayment.On("Refund", mock.Anything, int64(1), 49.99, "refund-9").Return(&models.PaymentIntent{OrderID: 1}, nil).Once()
+		mockRefundRepo.On("UpdateRefund", mock.Anything, failed).Return(nil).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, new(_orderMocks.Repository), new(_creditMocks.Usecase), mockPayment, _txMocks.NewPassthroughManager(), time.Second*2)
+		res, err := u.RetryRefund(context.TODO(), 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.RefundStatusCompleted, res.Status)
+		mockPayment.AssertExpectations(t)
+		mockRefundRepo.AssertExpectations(t)
+	})
+
+	t.Run("completed", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockRefundRepo.On("GetRefundByReturn", mock.Anything, int64(3)).
+			Return(&models.Refund{ID: 9, OrderID: 1, ReturnID: 3, PaymentAmount: 49.99, Status: models.RefundStatusCompleted}, nil).Once()
+		mockPayment := new(_paymentMocks.Usecase)
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, new(_orderMocks.Repository), new(_creditMocks.Usecase), mockPayment, _txMocks.NewPassthroughManager(), time.Second*2)
+		res, err := u.RetryRefund(context.TODO(), 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.RefundStatusCompleted, res.Status)
+		mockPayment.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestReject(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		req := &models.ReturnRequest{ID: 3, Status: models.ReturnStatusRequested}
+		mockRefundRepo.On("GetByID", mock.Anything, int64(3)).Return(req, nil).Once()
+		mockRefundRepo.On("UpdateStatus", mock.Anything, req, models.ReturnStatusRequested).Return(nil).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockOrderRepo, new(_creditMocks.Usecase), new(_paymentMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.Reject(context.TODO(), 3, "damaged by customer")
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.ReturnStatusRejected, req.Status)
+		assert.Equal(t, "damaged by customer", req.Reason)
+		mockRefundRepo.AssertExpectations(t)
+	})
+
+	t.Run("approved-concurrently", func(t *testing.T) {
+		mockRefundRepo := new(mocks.Repository)
+		mockOrderRepo := new(_orderMocks.Repository)
+		req := &models.ReturnRequest{ID: 3, Status: models.ReturnStatusRequested}
+		mockRefundRepo.On("GetByID", mock.Anything, int64(3)).Return(req, nil).Once()
+		mockRefundRepo.On("UpdateStatus", mock.Anything, req, models.ReturnStatusRequested).Return(models.ErrConflict).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockOrderRepo, new(_creditMocks.Usecase), new(_paymentMocks.Usecase), _txMocks.NewPassthroughManager(), time.Second*2)
+		err := u.Reject(context.TODO(), 3, "damaged by customer")
+
+		assert.Equal(t, models.ErrConflict, err)
+		mockRefundRepo.AssertExpectations(t)
+	})
+}
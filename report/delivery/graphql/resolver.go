@@ -0,0 +1,82 @@
+package graphql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/williamchand/kuncie-cart/report"
+)
+
+type Resolver interface {
+	SalesBySKU(params graphql.ResolveParams) (interface{}, error)
+	PromotionDiscounts(params graphql.ResolveParams) (interface{}, error)
+	OrderSummary(params graphql.ResolveParams) (interface{}, error)
+	TopItems(params graphql.ResolveParams) (interface{}, error)
+}
+
+type resolver struct {
+	reportService report.Usecase
+}
+
+// dateRange reads the from (inclusive) and to (exclusive) arguments
+func dateRange(params graphql.ResolveParams) (time.Time, time.Time, error) {
+	from, ok := params.Args["from"].(time.Time)
+	if !ok {
+		return time.Time{}, time.Time{}, fmt.Errorf("from is not a RFC3339 date time")
+	}
+	to, ok := params.Args["to"].(time.Time)
+	if !ok {
+		return time.Time{}, time.Time{}, fmt.Errorf("to is not a RFC3339 date time")
+	}
+
+	return from, to, nil
+}
+
+func (r resolver) SalesBySKU(params graphql.ResolveParams) (interface{}, error) {
+	from, to, err := dateRange(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.reportService.SalesBySKU(params.Context, from, to)
+}
+
+func (r resolver) PromotionDiscounts(params graphql.ResolveParams) (interface{}, error) {
+	from, to, err := dateRange(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.reportService.PromotionDiscounts(params.Context, from, to)
+}
+
+func (r resolver) OrderSummary(params graphql.ResolveParams) (interface{}, error) {
+	from, to, err := dateRange(params)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.reportService.OrderSummary(params.Context, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func (r resolver) TopItems(params graphql.ResolveParams) (interface{}, error) {
+	from, to, err := dateRange(params)
+	if err != nil {
+		return nil, err
+	}
+	limit, _ := params.Args["limit"].(int)
+
+	return r.reportService.TopItems(params.Context, from, to, int64(limit))
+}
+
+func NewResolver(reportService report.Usecase) Resolver {
+	return &resolver{
+		reportService: reportService,
+	}
+}
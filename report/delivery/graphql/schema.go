@@ -0,0 +1,154 @@
+package graphql
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/williamchand/kuncie-cart/auth"
+)
+
+// SKUSalesGraphQL holds daily sales of a SKU with graphql object
+var SKUSalesGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "SKUSales",
+		Fields: graphql.Fields{
+			"day": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+			"sku": &graphql.Field{
+				Type: graphql.String,
+			},
+			"name": &graphql.Field{
+				Type: graphql.String,
+			},
+			"units": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"revenue": &graphql.Field{
+				Type: graphql.Float,
+			},
+		},
+	},
+)
+
+// PromotionDiscountGraphQL holds the discount given by a promotion type with graphql object
+var PromotionDiscountGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "PromotionDiscount",
+		Fields: graphql.Fields{
+			"promo_type": &graphql.Field{
+				Type: graphql.String,
+			},
+			"lines": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"units": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"discount": &graphql.Field{
+				Type: graphql.Float,
+			},
+		},
+	},
+)
+
+// OrderSummaryGraphQL holds the order count and value with graphql object
+var OrderSummaryGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "OrderSummary",
+		Fields: graphql.Fields{
+			"orders": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"revenue": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"tax": &graphql.Field{
+				Type: graphql.Float,
+			},
+			"average_order_value": &graphql.Field{
+				Type: graphql.Float,
+			},
+		},
+	},
+)
+
+// TopItemGraphQL holds an item ranked by units sold with graphql object
+var TopItemGraphQL = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "TopItem",
+		Fields: graphql.Fields{
+			"sku": &graphql.Field{
+				Type: graphql.String,
+			},
+			"name": &graphql.Field{
+				Type: graphql.String,
+			},
+			"units": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"revenue": &graphql.Field{
+				Type: graphql.Float,
+			},
+		},
+	},
+)
+
+// rangeArgs are the arguments shared by every report, from is inclusive and to is exclusive
+func rangeArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"from": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.DateTime),
+		},
+		"to": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.DateTime),
+		},
+	}
+}
+
+// Schema is struct which has method for Query fields. Please init this struct using constructor function.
+type Schema struct {
+	reportResolver Resolver
+}
+
+// QueryFields initializes the admin report fields of the graphql query.
+func (s Schema) QueryFields() graphql.Fields {
+	topItemsArgs := rangeArgs()
+	topItemsArgs["limit"] = &graphql.ArgumentConfig{
+		Type:         graphql.Int,
+		DefaultValue: 10,
+	}
+
+	return graphql.Fields{
+		"SalesBySKU": &graphql.Field{
+			Type:        graphql.NewList(SKUSalesGraphQL),
+			Description: "Units sold and revenue by SKU and day",
+			Args:        rangeArgs(),
+			Resolve:     auth.Require(auth.PermReadReports, s.reportResolver.SalesBySKU),
+		},
+		"PromotionDiscounts": &graphql.Field{
+			Type:        graphql.NewList(PromotionDiscountGraphQL),
+			Description: "Discount given per promotion type, valued at the current item price",
+			Args:        rangeArgs(),
+			Resolve:     auth.Require(auth.PermReadReports, s.reportResolver.PromotionDiscounts),
+		},
+		"OrderSummary": &graphql.Field{
+			Type:        OrderSummaryGraphQL,
+			Description: "Number of orders, revenue and average order value",
+			Args:        rangeArgs(),
+			Resolve:     auth.Require(auth.PermReadReports, s.reportResolver.OrderSummary),
+		},
+		"TopItems": &graphql.Field{
+			Type:        graphql.NewList(TopItemGraphQL),
+			Description: "Best selling items by units sold",
+			Args:        topItemsArgs,
+			Resolve:     auth.Require(auth.PermReadReports, s.reportResolver.TopItems),
+		},
+	}
+}
+
+// NewSchema initializes Schema struct which takes resolver as the argument.
+func NewSchema(reportResolver Resolver) Schema {
+	return Schema{
+		reportResolver: reportResolver,
+	}
+}
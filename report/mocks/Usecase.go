@@ -0,0 +1,107 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+	models "github.com/williamchand/kuncie-cart/models"
+)
+
+// Usecase is an autogenerated mock type for the Usecase type
+type Usecase struct {
+	mock.Mock
+}
+
+// OrderSummary provides a mock function with given fields: ctx, from, to
+func (_m *Usecase) OrderSummary(ctx context.Context, from time.Time, to time.Time) (*models.OrderSummary, error) {
+	ret := _m.Called(ctx, from, to)
+
+	var r0 *models.OrderSummary
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) *models.OrderSummary); ok {
+		r0 = rf(ctx, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.OrderSummary)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PromotionDiscounts provides a mock function with given fields: ctx, from, to
+func (_m *Usecase) PromotionDiscounts(ctx context.Context, from time.Time, to time.Time) ([]*models.PromotionDiscount, error) {
+	ret := _m.Called(ctx, from, to)
+
+	var r0 []*models.PromotionDiscount
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []*models.PromotionDiscount); ok {
+		r0 = rf(ctx, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.PromotionDiscount)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SalesBySKU provides a mock function with given fields: ctx, from, to
+func (_m *Usecase) SalesBySKU(ctx context.Context, from time.Time, to time.Time) ([]*models.SKUSales, error) {
+	ret := _m.Called(ctx, from, to)
+
+	var r0 []*models.SKUSales
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []*models.SKUSales); ok {
+		r0 = rf(ctx, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.SKUSales)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TopItems provides a mock function with given fields: ctx, from, to, num
+func (_m *Usecase) TopItems(ctx context.Context, from time.Time, to time.Time, num int64) ([]*models.TopItem, error) {
+	ret := _m.Called(ctx, from, to, num)
+
+	var r0 []*models.TopItem
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, int64) []*models.TopItem); ok {
+		r0 = rf(ctx, from, to, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.TopItem)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, int64) error); ok {
+		r1 = rf(ctx, from, to, num)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,16 @@
+package report
+
+import (
+	"context"
+	"time"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// Repository represent the report's repository contract
+type Repository interface {
+	SalesBySKU(ctx context.Context, from time.Time, to time.Time) (res []*models.SKUSales, err error)
+	PromotionDiscounts(ctx context.Context, from time.Time, to time.Time) (res []*models.PromotionDiscount, err error)
+	OrderSummary(ctx context.Context, from time.Time, to time.Time) (*models.OrderSummary, error)
+	TopItems(ctx context.Context, from time.Time, to time.Time, num int64) (res []*models.TopItem, err error)
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/williamchand/kuncie-cart/logging"
+	"github.com/williamchand/kuncie-cart/metrics"
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/report"
+)
+
+type mysqlReportRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlReportRepository will create an object that represent the report.Repository interface
+func NewMysqlReportRepository(Conn *sql.DB) report.Repository {
+	return &mysqlReportRepository{Conn}
+}
+
+// query runs a report query and calls scan for every row
+func (m *mysqlReportRepository) query(ctx context.Context, scan func(rows *sql.Rows) error, query string, args ...interface{}) error {
+	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	for rows.Next() {
+		if err = scan(rows); err != nil {
+			logging.FromContext(ctx).Error(err)
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (m *mysqlReportRepository) SalesBySKU(ctx context.Context, from time.Time, to time.Time) ([]*models.SKUSales, error) {
+	defer metrics.ObserveQuery("report", "SalesBySKU", time.Now())
+	query := "SELECT DATE(o.created_at) AS day, od.sku, MAX(od.name), SUM(od.quantity), SUM(od.price) " +
+		"FROM `order` o JOIN order_details od ON od.order_id = o.id " +
+		"WHERE o.created_at >= ? AND o.created_at < ? AND od.parent_id = 0 GROUP BY day, od.sku ORDER BY day, od.sku"
+
+	result := make([]*models.SKUSales, 0)
+	err := m.query(ctx, func(rows *sql.Rows) error {
+		t := new(models.SKUSales)
+		if err := rows.Scan(&t.Day, &t.SKU, &t.Name, &t.Units, &t.Revenue); err != nil {
+			return err
+		}
+		result = append(result, t)
+		return nil
+	}, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (m *mysqlReportRepository) PromotionDiscounts(ctx context.Context, from time.Time, to time.Time) ([]*models.PromotionDiscount, error) {
+	defer metrics.ObserveQuery("report", "PromotionDiscounts", time.Now())
+	query := "SELECT od.promo_type, COUNT(*), SUM(od.quantity), SUM(od.list_price - od.price) " +
+		"FROM `order` o JOIN order_details od ON od.order_id = o.id " +
+		"WHERE o.created_at >= ? AND o.created_at < ? AND od.promo_type <> '' AND od.parent_id = 0 " +
+		"AND od.list_price IS NOT NULL GROUP BY od.promo_type ORDER BY od.promo_type"
+
+	result := make([]*models.PromotionDiscount, 0)
+	err := m.query(ctx, func(rows *sql.Rows) error {
+		t := new(models.PromotionDiscount)
+		if err := rows.Scan(&t.PromoType, &t.Lines, &t.Units, &t.Discount); err != nil {
+			return err
+		}
+		result = append(result, t)
+		return nil
+	}, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (m *mysqlReportRepository) OrderSummary(ctx context.Context, from time.Time, to time.Time) (*models.OrderSummary, error) {
+	defer metrics.ObserveQuery("report", "OrderSummary", time.Now())
+	query := "SELECT COUNT(*), IFNULL(SUM(total_price), 0), IFNULL(SUM(tax_amount), 0), IFNULL(AVG(total_price), 0) " +
+		"FROM `order` WHERE created_at >= ? AND created_at < ?"
+
+	res := new(models.OrderSummary)
+	err := m.Conn.QueryRowContext(ctx, query, from, to).Scan(&res.Orders, &res.Revenue, &res.Tax, &res.AverageOrderValue)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (m *mysqlReportRepository) TopItems(ctx context.Context, from time.Time, to time.Time, num int64) ([]*models.TopItem, error) {
+	defer metrics.ObserveQuery("report", "TopItems", time.Now())
+	query := "SELECT od.sku, MAX(od.name), SUM(od.quantity) AS units, SUM(od.price) " +
+		"FROM `order` o JOIN order_details od ON od.order_id = o.id " +
+		"WHERE o.created_at >= ? AND o.created_at < ? AND od.parent_id = 0 GROUP BY od.sku ORDER BY units DESC, od.sku LIMIT ?"
+
+	result := make([]*models.TopItem, 0)
+	err := m.query(ctx, func(rows *sql.Rows) error {
+		t := new(models.TopItem)
+		if err := rows.Scan(&t.SKU, &t.Name, &t.Units, &t.Revenue); err != nil {
+			return err
+		}
+		result = append(result, t)
+		return nil
+	}, query, from, to, num)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
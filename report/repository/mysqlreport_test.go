@@ -0,0 +1,76 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	reportRepo "github.com/williamchand/kuncie-cart/report/repository"
+)
+
+func TestSalesBySKU(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	day := time.Date(2022, 1, 21, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"day", "sku", "name", "units", "revenue"}).
+		AddRow(day, "120P90", "Google Home", 4, 149.97).
+		AddRow(day, "43N23P", "Macbook Pro", 2, 5399.99)
+
+	query := "SELECT DATE\\(o.created_at\\) AS day, od.sku, (.+) GROUP BY day, od.sku ORDER BY day, od.sku"
+	mock.ExpectQuery(query).WillReturnRows(rows)
+
+	a := reportRepo.NewMysqlReportRepository(db)
+	list, err := a.SalesBySKU(context.TODO(), day, day.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, int64(4), list[0].Units)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPromotionDiscounts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"promo_type", "lines", "units", "discount"}).
+		AddRow("bonus_price", 3, 9, 149.97)
+
+	query := "SELECT od.promo_type, COUNT\\(\\*\\), SUM\\(od.quantity\\), SUM\\(od.list_price - od.price\\) (.+) AND od.list_price IS NOT NULL GROUP BY od.promo_type"
+	mock.ExpectQuery(query).WillReturnRows(rows)
+
+	a := reportRepo.NewMysqlReportRepository(db)
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	list, err := a.PromotionDiscounts(context.TODO(), from, from.AddDate(0, 1, 0))
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, 149.97, list[0].Discount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderSummary(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"count", "sum", "tax", "avg"}).AddRow(2, 300.0, 29.73, 150.0)
+
+	query := "SELECT COUNT\\(\\*\\), IFNULL\\(SUM\\(total_price\\), 0\\), IFNULL\\(SUM\\(tax_amount\\), 0\\), IFNULL\\(AVG\\(total_price\\), 0\\) FROM `order`"
+	mock.ExpectQuery(query).WillReturnRows(rows)
+
+	a := reportRepo.NewMysqlReportRepository(db)
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	res, err := a.OrderSummary(context.TODO(), from, from.AddDate(0, 1, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), res.Orders)
+	assert.Equal(t, 29.73, res.Tax)
+	assert.Equal(t, 150.0, res.AverageOrderValue)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
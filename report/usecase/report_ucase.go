@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/report"
+)
+
+const (
+	defaultTopItems = 10
+	maxTopItems     = 100
+)
+
+type reportUsecase struct {
+	reportRepo     report.Repository
+	contextTimeout time.Duration
+}
+
+// NewReportUsecase will create new a reportUsecase object representation of report.Usecase interface
+func NewReportUsecase(a report.Repository, timeout time.Duration) report.Usecase {
+	return &reportUsecase{
+		reportRepo:     a,
+		contextTimeout: timeout,
+	}
+}
+
+func validRange(from time.Time, to time.Time) bool {
+	return !from.IsZero() && from.Before(to)
+}
+
+func (a *reportUsecase) SalesBySKU(c context.Context, from time.Time, to time.Time) ([]*models.SKUSales, error) {
+	if !validRange(from, to) {
+		return nil, models.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	return a.reportRepo.SalesBySKU(ctx, from, to)
+}
+
+func (a *reportUsecase) PromotionDiscounts(c context.Context, from time.Time, to time.Time) ([]*models.PromotionDiscount, error) {
+	if !validRange(from, to) {
+		return nil, models.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	return a.reportRepo.PromotionDiscounts(ctx, from, to)
+}
+
+func (a *reportUsecase) OrderSummary(c context.Context, from time.Time, to time.Time) (*models.OrderSummary, error) {
+	if !validRange(from, to) {
+		return nil, models.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	return a.reportRepo.OrderSummary(ctx, from, to)
+}
+
+func (a *reportUsecase) TopItems(c context.Context, from time.Time, to time.Time, num int64) ([]*models.TopItem, error) {
+	if !validRange(from, to) {
+		return nil, models.ErrBadParamInput
+	}
+	if num <= 0 {
+		num = defaultTopItems
+	}
+	if num > maxTopItems {
+		num = maxTopItems
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	return a.reportRepo.TopItems(ctx, from, to, num)
+}
@@ -0,0 +1,34 @@
+package graphql
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/williamchand/kuncie-cart/search"
+)
+
+type Resolver interface {
+	Search(params graphql.ResolveParams) (interface{}, error)
+}
+
+type resolver struct {
+	searchService search.Usecase
+}
+
+func (r resolver) Search(params graphql.ResolveParams) (interface{}, error) {
+	query, _ := params.Args["query"].(string)
+	cursor, _ := params.Args["cursor"].(string)
+	limit, _ := params.Args["limit"].(int)
+
+	res, err := r.searchService.Search(params.Context, query, cursor, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	return *res, nil
+}
+
+func NewResolver(searchService search.Usecase) Resolver {
+	return &resolver{
+		searchService: searchService,
+	}
+}
@@ -0,0 +1,42 @@
+package graphql
+
+import (
+	"github.com/graphql-go/graphql"
+
+	_itemGraphQL "github.com/williamchand/kuncie-cart/item/delivery/graphql"
+)
+
+// Schema is struct which has method for Query fields. Please init this struct using constructor function.
+type Schema struct {
+	searchResolver Resolver
+}
+
+// QueryFields initializes the search fields of the graphql query.
+func (s Schema) QueryFields() graphql.Fields {
+	return graphql.Fields{
+		"Search": &graphql.Field{
+			Type:        _itemGraphQL.ItemPageGraphQL,
+			Description: "Search the items by name, SKU and tags, most relevant first",
+			Args: graphql.FieldConfigArgument{
+				"query": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"cursor": &graphql.ArgumentConfig{
+					Type: graphql.String,
+				},
+				"limit": &graphql.ArgumentConfig{
+					Type:         graphql.Int,
+					DefaultValue: 10,
+				},
+			},
+			Resolve: s.searchResolver.Search,
+		},
+	}
+}
+
+// NewSchema initializes Schema struct which takes resolver as the argument.
+func NewSchema(searchResolver Resolver) Schema {
+	return Schema{
+		searchResolver: searchResolver,
+	}
+}
@@ -0,0 +1,13 @@
+package search
+
+import (
+	"context"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// Index represent the contract of a full-text index over the name, SKU and tags of the items in the catalog.
+// Search returns the hits of every item matching all the words of the query, most relevant first.
+type Index interface {
+	Search(ctx context.Context, query string, offset int64, num int64) (res []*models.SearchHit, err error)
+}
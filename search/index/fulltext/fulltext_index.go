@@ -0,0 +1,171 @@
+package fulltext
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/williamchand/kuncie-cart/logging"
+	"github.com/williamchand/kuncie-cart/metrics"
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/search"
+)
+
+const (
+	// minTokenSize is the default innodb_ft_min_token_size, shorter words are not in the FULLTEXT indexes
+	minTokenSize = 3
+	// scanLimit bounds the number of items ranked in process when a query needs typo tolerance
+	scanLimit = 10000
+	// documentsTTL is how long the documents ranked in process are kept before they are read again
+	documentsTTL = time.Minute
+)
+
+type fulltextIndex struct {
+	Conn *sql.DB
+
+	// mu guards the documents cached for the in-process ranking, held while they are read so concurrent
+	// misses wait for one query instead of each running it
+	mu       sync.Mutex
+	docs     []*models.SearchDocument
+	loadedAt time.Time
+}
+
+// NewFulltextIndex will create an object that represent the search.Index interface on the MySQL FULLTEXT
+// indexes of the items and their tags
+func NewFulltextIndex(Conn *sql.DB) search.Index {
+	return &fulltextIndex{Conn: Conn}
+}
+
+// booleanQuery requires every word of the query as a word prefix, the words FULLTEXT does not index are left out
+func booleanQuery(terms []string) string {
+	parts := make([]string, 0, len(terms))
+	for _, t := range terms {
+		if len([]rune(t)) >= minTokenSize {
+			parts = append(parts, "+"+t+"*")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Search ranks the items with FULLTEXT. When nothing matches a short query, which may hold a typo or words
+// too short for FULLTEXT, the catalog is ranked in process with the typo tolerance of search.Rank instead. The
+// catalog is read at most once every documentsTTL for that, so misses do not each scan the items.
+func (m *fulltextIndex) Search(ctx context.Context, query string, offset int64, num int64) ([]*models.SearchHit, error) {
+	defer metrics.ObserveQuery("search", "Search", time.Now())
+	terms := search.Terms(query)
+	if len(terms) == 0 {
+		return make([]*models.SearchHit, 0), nil
+	}
+
+	if expr := booleanQuery(terms); expr != "" {
+		res, err := m.match(ctx, expr, offset, num)
+		if err != nil {
+			return nil, err
+		}
+		if len(res) > 0 || !search.Fuzzy(terms) {
+			return res, nil
+		}
+		if offset > 0 {
+			// an empty page past the last match is not a miss
+			first, err := m.match(ctx, expr, 0, 1)
+			if err != nil {
+				return nil, err
+			}
+			if len(first) > 0 {
+				return res, nil
+			}
+		}
+	}
+
+	docs, err := m.cachedDocuments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return search.Rank(docs, query, offset, num), nil
+}
+
+// cachedDocuments returns the documents read less than documentsTTL ago, reading them again when they are older
+func (m *fulltextIndex) cachedDocuments(ctx context.Context) ([]*models.SearchDocument, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.docs != nil && time.Since(m.loadedAt) < documentsTTL {
+		return m.docs, nil
+	}
+	docs, err := m.documents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m.docs, m.loadedAt = docs, time.Now()
+	return docs, nil
+}
+
+func (m *fulltextIndex) match(ctx context.Context, expr string, offset int64, num int64) ([]*models.SearchHit, error) {
+	query := `SELECT i.id, MATCH(i.name, i.sku) AGAINST (? IN BOOLEAN MODE) + IFNULL(t.score, 0) AS score
+  						FROM items i
+  						LEFT JOIN (SELECT items_id, SUM(MATCH(tag) AGAINST (? IN BOOLEAN MODE)) AS score FROM item_tags
+  							WHERE MATCH(tag) AGAINST (? IN BOOLEAN MODE) GROUP BY items_id) t ON t.items_id = i.id
+  						WHERE i.archived = 0 AND (MATCH(i.name, i.sku) AGAINST (? IN BOOLEAN MODE) OR t.items_id IS NOT NULL)
+  						ORDER BY score DESC, i.id LIMIT ? OFFSET ?`
+	rows, err := m.Conn.QueryContext(ctx, query, expr, expr, expr, expr, num, offset)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	result := make([]*models.SearchHit, 0)
+	for rows.Next() {
+		t := new(models.SearchHit)
+		if err = rows.Scan(&t.ItemsID, &t.Score); err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+func (m *fulltextIndex) documents(ctx context.Context) ([]*models.SearchDocument, error) {
+	query := `SELECT i.id, i.sku, i.name, IFNULL(GROUP_CONCAT(t.tag SEPARATOR '\n'), '')
+  						FROM items i LEFT JOIN item_tags t ON t.items_id = i.id
+  						WHERE i.archived = 0 GROUP BY i.id ORDER BY i.id LIMIT ?`
+	rows, err := m.Conn.QueryContext(ctx, query, scanLimit)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+		}
+	}()
+
+	result := make([]*models.SearchDocument, 0)
+	for rows.Next() {
+		t := new(models.SearchDocument)
+		tags := ""
+		if err = rows.Scan(&t.ItemsID, &t.SKU, &t.Name, &tags); err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		if tags != "" {
+			t.Tags = strings.Split(tags, "\n")
+		}
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
@@ -0,0 +1,80 @@
+package fulltext_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/williamchand/kuncie-cart/search/index/fulltext"
+)
+
+func TestSearch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "score"}).AddRow(3, 1.8).AddRow(5, 0.9)
+	mock.ExpectQuery("SELECT i.id, MATCH\\(i.name, i.sku\\) AGAINST \\(\\? IN BOOLEAN MODE\\) (.+) LIMIT \\? OFFSET \\?").
+		WithArgs("+speaker*", "+speaker*", "+speaker*", "+speaker*", int64(10), int64(0)).WillReturnRows(rows)
+
+	idx := fulltext.NewFulltextIndex(db)
+	hits, err := idx.Search(context.TODO(), "Speaker!", 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, hits, 2)
+	assert.Equal(t, int64(3), hits[0].ItemsID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchTypo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectQuery("SELECT i.id, MATCH(.+) LIMIT \\? OFFSET \\?").
+		WithArgs("+makbook*", "+makbook*", "+makbook*", "+makbook*", int64(10), int64(0)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "score"}))
+	docs := sqlmock.NewRows([]string{"id", "sku", "name", "tags"}).
+		AddRow(1, "120P90", "Google Home", "speaker\nsmart home").
+		AddRow(2, "43N23P", "Macbook Pro", "")
+	mock.ExpectQuery("SELECT i.id, i.sku, i.name, (.+) FROM items i LEFT JOIN item_tags t (.+) LIMIT \\?").WillReturnRows(docs)
+
+	idx := fulltext.NewFulltextIndex(db)
+	hits, err := idx.Search(context.TODO(), "makbook", 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, hits, 1)
+	assert.Equal(t, int64(2), hits[0].ItemsID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchTypoReusesDocuments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectQuery("SELECT i.id, MATCH(.+) LIMIT \\? OFFSET \\?").
+		WithArgs("+makbook*", "+makbook*", "+makbook*", "+makbook*", int64(10), int64(0)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "score"}))
+	docs := sqlmock.NewRows([]string{"id", "sku", "name", "tags"}).
+		AddRow(1, "120P90", "Google Home", "speaker\nsmart home").
+		AddRow(2, "43N23P", "Macbook Pro", "")
+	mock.ExpectQuery("SELECT i.id, i.sku, i.name, (.+) FROM items i LEFT JOIN item_tags t (.+) LIMIT \\?").WillReturnRows(docs)
+	// the second miss is ranked against the documents read for the first one
+	mock.ExpectQuery("SELECT i.id, MATCH(.+) LIMIT \\? OFFSET \\?").
+		WithArgs("+gogle*", "+gogle*", "+gogle*", "+gogle*", int64(10), int64(0)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "score"}))
+
+	idx := fulltext.NewFulltextIndex(db)
+	hits, err := idx.Search(context.TODO(), "makbook", 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, hits, 1)
+	hits, err = idx.Search(context.TODO(), "gogle", 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, hits, 1)
+	assert.Equal(t, int64(1), hits[0].ItemsID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
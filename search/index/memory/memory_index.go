@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/search"
+)
+
+// Index is an embedded search.Index holding its documents in memory, meant for tests and small catalogs
+type Index struct {
+	mu   sync.RWMutex
+	docs map[int64]*models.SearchDocument
+}
+
+// NewMemoryIndex will create an Index holding the given documents
+func NewMemoryIndex(docs ...*models.SearchDocument) *Index {
+	idx := &Index{docs: make(map[int64]*models.SearchDocument, len(docs))}
+	for _, doc := range docs {
+		idx.Add(doc)
+	}
+	return idx
+}
+
+// Add indexes a document, replacing the previous one of the same item
+func (idx *Index) Add(doc *models.SearchDocument) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.docs[doc.ItemsID] = doc
+}
+
+// Remove drops the document of an item, such as an archived one
+func (idx *Index) Remove(itemsID int64) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	delete(idx.docs, itemsID)
+}
+
+func (idx *Index) Search(ctx context.Context, query string, offset int64, num int64) ([]*models.SearchHit, error) {
+	idx.mu.RLock()
+	docs := make([]*models.SearchDocument, 0, len(idx.docs))
+	for _, doc := range idx.docs {
+		docs = append(docs, doc)
+	}
+	idx.mu.RUnlock()
+
+	return search.Rank(docs, query, offset, num), nil
+}
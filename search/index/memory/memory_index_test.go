@@ -0,0 +1,72 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/search/index/memory"
+)
+
+func idsOf(hits []*models.SearchHit) []int64 {
+	res := make([]int64, len(hits))
+	for i, h := range hits {
+		res[i] = h.ItemsID
+	}
+	return res
+}
+
+func TestSearch(t *testing.T) {
+	idx := memory.NewMemoryIndex(
+		&models.SearchDocument{ItemsID: 1, SKU: "120P90", Name: "Google Home", Tags: []string{"speaker"}},
+		&models.SearchDocument{ItemsID: 2, SKU: "43N23P", Name: "Macbook Pro"},
+		&models.SearchDocument{ItemsID: 3, SKU: "A304SD", Name: "Alexa Speaker"},
+	)
+
+	tests := []struct {
+		name   string
+		query  string
+		offset int64
+		num    int64
+		ids    []int64
+	}{
+		{name: "relevance", query: "speaker", num: 10, ids: []int64{3, 1}},
+		{name: "typo", query: "makbook", num: 10, ids: []int64{2}},
+		{name: "sku", query: "120p90", num: 10, ids: []int64{1}},
+		{name: "page", query: "speaker", offset: 1, num: 1, ids: []int64{1}},
+		{name: "past-last-page", query: "speaker", offset: 2, num: 1, ids: []int64{}},
+		{name: "no-match", query: "television", num: 10, ids: []int64{}},
+		{name: "no-words", query: "!?", num: 10, ids: []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits, err := idx.Search(context.TODO(), tt.query, tt.offset, tt.num)
+			require.NoError(t, err)
+			assert.Equal(t, tt.ids, idsOf(hits))
+		})
+	}
+}
+
+func TestAddRemove(t *testing.T) {
+	idx := memory.NewMemoryIndex(&models.SearchDocument{ItemsID: 1, SKU: "120P90", Name: "Google Home"})
+
+	// adding a document of the same item replaces it
+	idx.Add(&models.SearchDocument{ItemsID: 1, SKU: "120P90", Name: "Google Nest"})
+	idx.Add(&models.SearchDocument{ItemsID: 2, SKU: "43N23P", Name: "Nest Cam"})
+	hits, err := idx.Search(context.TODO(), "home", 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, hits)
+	hits, err = idx.Search(context.TODO(), "nest", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, idsOf(hits))
+
+	idx.Remove(1)
+	idx.Remove(3)
+	hits, err = idx.Search(context.TODO(), "nest", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2}, idsOf(hits))
+}
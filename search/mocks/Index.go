@@ -0,0 +1,37 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	models "github.com/williamchand/kuncie-cart/models"
+)
+
+// Index is an autogenerated mock type for the Index type
+type Index struct {
+	mock.Mock
+}
+
+// Search provides a mock function with given fields: ctx, query, offset, num
+func (_m *Index) Search(ctx context.Context, query string, offset int64, num int64) ([]*models.SearchHit, error) {
+	ret := _m.Called(ctx, query, offset, num)
+
+	var r0 []*models.SearchHit
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int64) []*models.SearchHit); ok {
+		r0 = rf(ctx, query, offset, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.SearchHit)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, int64) error); ok {
+		r1 = rf(ctx, query, offset, num)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
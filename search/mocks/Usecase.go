@@ -0,0 +1,37 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	models "github.com/williamchand/kuncie-cart/models"
+)
+
+// Usecase is an autogenerated mock type for the Usecase type
+type Usecase struct {
+	mock.Mock
+}
+
+// Search provides a mock function with given fields: ctx, query, cursor, num
+func (_m *Usecase) Search(ctx context.Context, query string, cursor string, num int64) (*models.ItemPage, error) {
+	ret := _m.Called(ctx, query, cursor, num)
+
+	var r0 *models.ItemPage
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) *models.ItemPage); ok {
+		r0 = rf(ctx, query, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ItemPage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64) error); ok {
+		r1 = rf(ctx, query, cursor, num)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,168 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// MaxFuzzyTerms is the number of words up to which a query tolerates typos
+const MaxFuzzyTerms = 3
+
+const (
+	weightSKU  = 2.0
+	weightName = 1.0
+	weightTag  = 0.8
+)
+
+// Terms splits a text into lower case words, anything but letters and digits separates them
+func Terms(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Fuzzy tells whether the query made of terms is short enough to tolerate typos
+func Fuzzy(terms []string) bool {
+	return len(terms) <= MaxFuzzyTerms
+}
+
+// MaxTypos returns the number of typos tolerated in a query word, none below four letters
+func MaxTypos(term string) int {
+	switch n := len([]rune(term)); {
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Distance returns the Levenshtein distance between a and b
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func min(v ...int) int {
+	res := v[0]
+	for _, x := range v[1:] {
+		if x < res {
+			res = x
+		}
+	}
+	return res
+}
+
+// Match tells how well a query term matches a word: 1 when they are equal, 0.75 when the word starts with
+// the term, 0.5 when fuzzy and the word is within the tolerated typos of the term, 0 otherwise
+func Match(term string, word string, fuzzy bool) float64 {
+	switch {
+	case term == word:
+		return 1
+	case strings.HasPrefix(word, term):
+		return 0.75
+	case fuzzy && withinTypos(term, word):
+		return 0.5
+	}
+	return 0
+}
+
+// withinTypos tells whether word is within the tolerated typos of term, the lengths are compared first since
+// each typo changes the length by one at most
+func withinTypos(term string, word string) bool {
+	k := MaxTypos(term)
+	if k == 0 {
+		return false
+	}
+	if d := len([]rune(term)) - len([]rune(word)); d > k || -d > k {
+		return false
+	}
+	return Distance(term, word) <= k
+}
+
+func bestMatch(term string, words []string, fuzzy bool) float64 {
+	best := 0.0
+	for _, w := range words {
+		if m := Match(term, w, fuzzy); m > best {
+			best = m
+		}
+	}
+	return best
+}
+
+// Score returns the relevance of a document for the query terms, zero unless every term is found
+func Score(doc *models.SearchDocument, terms []string, fuzzy bool) float64 {
+	sku := Terms(doc.SKU)
+	name := Terms(doc.Name)
+	tags := make([]string, 0)
+	for _, t := range doc.Tags {
+		tags = append(tags, Terms(t)...)
+	}
+
+	total := 0.0
+	for _, term := range terms {
+		best := bestMatch(term, sku, false) * weightSKU
+		if s := bestMatch(term, name, fuzzy) * weightName; s > best {
+			best = s
+		}
+		if s := bestMatch(term, tags, fuzzy) * weightTag; s > best {
+			best = s
+		}
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+	return total
+}
+
+// Rank scores the documents for a query and returns the page of hits starting at offset, most relevant first
+func Rank(docs []*models.SearchDocument, query string, offset int64, num int64) []*models.SearchHit {
+	terms := Terms(query)
+	res := make([]*models.SearchHit, 0)
+	if len(terms) == 0 {
+		return res
+	}
+
+	fuzzy := Fuzzy(terms)
+	for _, doc := range docs {
+		if score := Score(doc, terms, fuzzy); score > 0 {
+			res = append(res, &models.SearchHit{ItemsID: doc.ItemsID, Score: score})
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score > res[j].Score
+		}
+		return res[i].ItemsID < res[j].ItemsID
+	})
+
+	if offset >= int64(len(res)) {
+		return res[:0]
+	}
+	res = res[offset:]
+	if int64(len(res)) > num {
+		res = res[:num]
+	}
+	return res
+}
@@ -0,0 +1,12 @@
+package search
+
+import (
+	"context"
+
+	"github.com/williamchand/kuncie-cart/models"
+)
+
+// Usecase represent the item search's usecases
+type Usecase interface {
+	Search(ctx context.Context, query string, cursor string, num int64) (*models.ItemPage, error)
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/williamchand/kuncie-cart/item"
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/search"
+)
+
+const (
+	defaultPageSize = 10
+	maxPageSize     = 100
+	maxQueryLength  = 100
+)
+
+type searchUsecase struct {
+	index          search.Index
+	itemService    item.Usecase
+	contextTimeout time.Duration
+}
+
+// NewSearchUsecase will create new a searchUsecase object representation of search.Usecase interface
+func NewSearchUsecase(idx search.Index, it item.Usecase, timeout time.Duration) search.Usecase {
+	return &searchUsecase{
+		index:          idx,
+		itemService:    it,
+		contextTimeout: timeout,
+	}
+}
+
+// Search returns a page of the items matching the query, most relevant first.
+// Relevance is not stable across catalog changes, so the cursor is the offset of the next page.
+func (a *searchUsecase) Search(c context.Context, query string, cursor string, num int64) (*models.ItemPage, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	query = strings.TrimSpace(query)
+	if query == "" || len(query) > maxQueryLength {
+		return nil, models.ErrBadParamInput
+	}
+	if num <= 0 {
+		num = defaultPageSize
+	}
+	if num > maxPageSize {
+		num = maxPageSize
+	}
+	offset, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, models.ErrBadParamInput
+	}
+
+	// one more hit than asked tells whether there is a next page
+	hits, err := a.index.Search(ctx, query, offset, num+1)
+	if err != nil {
+		return nil, err
+	}
+	nextCursor := ""
+	if int64(len(hits)) > num {
+		hits = hits[:num]
+		nextCursor = encodeCursor(offset + num)
+	}
+
+	ids := make([]int64, len(hits))
+	for i, h := range hits {
+		ids[i] = h.ItemsID
+	}
+	items, err := a.itemService.FetchByID(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ItemPage{Items: items, NextCursor: nextCursor}, nil
+}
+
+func decodeCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	byt, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	offset, err := strconv.ParseInt(string(byt), 10, 64)
+	if err != nil || offset < 0 {
+		return 0, models.ErrBadParamInput
+	}
+
+	return offset, nil
+}
+
+func encodeCursor(offset int64) string {
+	return base64.StdEncoding.EncodeToString([]byte(strconv.FormatInt(offset, 10)))
+}
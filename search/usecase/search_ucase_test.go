@@ -0,0 +1,108 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	_itemMocks "github.com/williamchand/kuncie-cart/item/mocks"
+	"github.com/williamchand/kuncie-cart/models"
+	"github.com/williamchand/kuncie-cart/search/index/memory"
+	ucase "github.com/williamchand/kuncie-cart/search/usecase"
+)
+
+func newIndex() *memory.Index {
+	return memory.NewMemoryIndex(
+		&models.SearchDocument{ItemsID: 1, SKU: "120P90", Name: "Google Home", Tags: []string{"speaker", "smart home"}},
+		&models.SearchDocument{ItemsID: 2, SKU: "43N23P", Name: "Macbook Pro"},
+		&models.SearchDocument{ItemsID: 3, SKU: "A304SD", Name: "Alexa Speaker", Tags: []string{"alexa"}},
+		&models.SearchDocument{ItemsID: 4, SKU: "234234", Name: "Raspberry Pi B"},
+		&models.SearchDocument{ItemsID: 5, SKU: "SPK100", Name: "Speaker Stand"},
+	)
+}
+
+// catalogOf returns the catalog items the item usecase would for the given ids
+func catalogOf(ids ...int64) []*models.CatalogItem {
+	res := make([]*models.CatalogItem, len(ids))
+	for i, id := range ids {
+		res[i] = &models.CatalogItem{ID: id}
+	}
+	return res
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		ids   []int64
+	}{
+		// a name match ranks before a tag match, ties are in item order
+		{name: "relevance", query: "speaker", ids: []int64{3, 5, 1}},
+		{name: "prefix", query: "mac", ids: []int64{2}},
+		{name: "typo", query: "makbook", ids: []int64{2}},
+		{name: "sku", query: "120p90", ids: []int64{1}},
+		{name: "every-word", query: "alexa speaker", ids: []int64{3}},
+		{name: "short-words-exact", query: "pi", ids: []int64{4}},
+		{name: "no-match", query: "television", ids: []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockItemUcase := new(_itemMocks.Usecase)
+			mockItemUcase.On("FetchByID", mock.Anything, tt.ids).Return(catalogOf(tt.ids...), nil).Once()
+
+			u := ucase.NewSearchUsecase(newIndex(), mockItemUcase, time.Second*2)
+			page, err := u.Search(context.TODO(), tt.query, "", 10)
+
+			assert.NoError(t, err)
+			assert.Empty(t, page.NextCursor)
+			mockItemUcase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSearchTypoToleranceLongQuery(t *testing.T) {
+	mockItemUcase := new(_itemMocks.Usecase)
+	mockItemUcase.On("FetchByID", mock.Anything, []int64{}).Return(catalogOf(), nil).Once()
+
+	u := ucase.NewSearchUsecase(newIndex(), mockItemUcase, time.Second*2)
+	// typos are only tolerated in short queries
+	page, err := u.Search(context.TODO(), "google home smart speker", "", 10)
+
+	assert.NoError(t, err)
+	assert.Empty(t, page.Items)
+	mockItemUcase.AssertExpectations(t)
+}
+
+func TestSearchPagination(t *testing.T) {
+	mockItemUcase := new(_itemMocks.Usecase)
+	mockItemUcase.On("FetchByID", mock.Anything, []int64{3, 5}).Return(catalogOf(3, 5), nil).Once()
+	mockItemUcase.On("FetchByID", mock.Anything, []int64{1}).Return(catalogOf(1), nil).Once()
+
+	u := ucase.NewSearchUsecase(newIndex(), mockItemUcase, time.Second*2)
+	first, err := u.Search(context.TODO(), "speaker", "", 2)
+	assert.NoError(t, err)
+	assert.Len(t, first.Items, 2)
+	assert.NotEmpty(t, first.NextCursor)
+
+	second, err := u.Search(context.TODO(), "speaker", first.NextCursor, 2)
+	assert.NoError(t, err)
+	assert.Len(t, second.Items, 1)
+	assert.Empty(t, second.NextCursor)
+	mockItemUcase.AssertExpectations(t)
+}
+
+func TestSearchBadParam(t *testing.T) {
+	mockItemUcase := new(_itemMocks.Usecase)
+	u := ucase.NewSearchUsecase(newIndex(), mockItemUcase, time.Second*2)
+
+	_, err := u.Search(context.TODO(), "   ", "", 10)
+	assert.Equal(t, models.ErrBadParamInput, err)
+
+	_, err = u.Search(context.TODO(), "speaker", "not a cursor", 10)
+	assert.Equal(t, models.ErrBadParamInput, err)
+	mockItemUcase.AssertNotCalled(t, "FetchByID", mock.Anything, mock.Anything)
+}
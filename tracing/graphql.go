@@ -0,0 +1,18 @@
+package tracing
+
+import (
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Resolve wraps the resolver of the GraphQL field name in a span recording its error
+func Resolve(name string, fn graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(params graphql.ResolveParams) (res interface{}, err error) {
+		ctx, span := Start(params.Context, "graphql "+name)
+		defer End(span, &err)
+		span.SetAttributes(attribute.String("graphql.field", name))
+
+		params.Context = ctx
+		return fn(params)
+	}
+}
@@ -0,0 +1,54 @@
+// Package tracing records the spans of the requests with OpenTelemetry, from the HTTP middleware down to the
+// repositories, and propagates their context with the W3C traceparent header
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName names the tracer of the service
+const InstrumentationName = "github.com/williamchand/kuncie-cart"
+
+// Setup installs the global tracer provider and the W3C trace context propagator. The sampled spans go to
+// exporter, none when it is nil. sampleRatio is the share of the traces started here which are sampled, traces
+// coming from other services keep their decision. The provider must be shut down to flush the last spans.
+func Setup(exporter sdktrace.SpanExporter, sampleRatio float64) *sdktrace.TracerProvider {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return tp
+}
+
+// Start begins a span as a child of the span of ctx, local or remote, or as the root of a new trace. The returned
+// context carries the new span.
+func Start(ctx context.Context, name string) (context.Context, trace.Span) {
+	return otel.Tracer(InstrumentationName).Start(ctx, name)
+}
+
+// SetError marks the operation of the span as failed, nil errors are ignored
+func SetError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// End marks the span as failed when the error err points to is set, then ends it. It is deferred with the address
+// of the named error result of the traced function, so every return is covered.
+func End(span trace.Span, err *error) {
+	SetError(span, *err)
+	span.End()
+}
@@ -0,0 +1,111 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/williamchand/kuncie-cart/tracing"
+)
+
+// setup installs a provider sampling ratio of the traces, it returns the exported spans and the func restoring
+// the no-op provider
+func setup(t *testing.T, ratio float64) (func() tracetest.SpanStubs, func()) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := tracing.Setup(exporter, ratio)
+	spans := func() tracetest.SpanStubs {
+		require.NoError(t, tp.ForceFlush(context.Background()))
+		return exporter.GetSpans()
+	}
+	return spans, func() {
+		otel.SetTracerProvider(trace.NewNoopTracerProvider())
+		assert.NoError(t, tp.Shutdown(context.Background()))
+	}
+}
+
+func remoteContext(traceparent string) context.Context {
+	header := http.Header{}
+	header.Set("traceparent", traceparent)
+	return otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(header))
+}
+
+func TestEnd(t *testing.T) {
+	spans, done := setup(t, 1)
+	defer done()
+
+	traced := func(ctx context.Context, fail bool) (err error) {
+		_, span := tracing.Start(ctx, "orderUsecase.PlaceOrder")
+		defer tracing.End(span, &err)
+		if fail {
+			return errors.New("out of stock")
+		}
+		return nil
+	}
+	ctx, parent := tracing.Start(remoteContext("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"), "parent")
+	assert.EqualError(t, traced(ctx, true), "out of stock")
+	assert.NoError(t, traced(ctx, false))
+	parent.End()
+
+	got := spans()
+	require.Len(t, got, 3)
+	assert.Equal(t, "orderUsecase.PlaceOrder", got[0].Name)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", got[0].SpanContext.TraceID().String())
+	assert.Equal(t, parent.SpanContext().SpanID(), got[0].Parent.SpanID())
+	assert.Equal(t, codes.Error, got[0].Status.Code)
+	assert.Equal(t, "out of stock", got[0].Status.Description)
+	require.Len(t, got[0].Events, 1)
+	assert.Equal(t, "exception", got[0].Events[0].Name)
+	assert.Equal(t, codes.Unset, got[1].Status.Code)
+	assert.Empty(t, got[1].Events)
+	assert.Equal(t, "00f067aa0ba902b7", got[2].Parent.SpanID().String())
+}
+
+func TestSampling(t *testing.T) {
+	spans, done := setup(t, 0)
+	defer done()
+
+	ctx, root := tracing.Start(context.Background(), "root")
+	_, child := tracing.Start(ctx, "child")
+	child.End()
+	root.End()
+	assert.Empty(t, spans())
+	assert.True(t, root.SpanContext().IsValid())
+
+	// the decision of the caller wins over the local ratio
+	_, span := tracing.Start(remoteContext("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"), "remote")
+	span.End()
+	assert.Len(t, spans(), 1)
+}
+
+func TestResolve(t *testing.T) {
+	spans, done := setup(t, 1)
+	defer done()
+
+	ctx, parent := tracing.Start(context.Background(), "HTTP POST /graphql")
+	var resolver trace.SpanContext
+	resolve := tracing.Resolve("AddCart", func(p graphql.ResolveParams) (interface{}, error) {
+		resolver = trace.SpanContextFromContext(p.Context)
+		return nil, errors.New("cannot add the items")
+	})
+	_, err := resolve(graphql.ResolveParams{Context: ctx})
+	assert.EqualError(t, err, "cannot add the items")
+
+	got := spans()
+	require.Len(t, got, 1)
+	assert.Equal(t, "graphql AddCart", got[0].Name)
+	assert.Equal(t, resolver.SpanID(), got[0].SpanContext.SpanID())
+	assert.Equal(t, parent.SpanContext().SpanID(), got[0].Parent.SpanID())
+	assert.Contains(t, got[0].Attributes, attribute.String("graphql.field", "AddCart"))
+	assert.Equal(t, "cannot add the items", got[0].Status.Description)
+}
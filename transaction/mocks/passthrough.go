@@ -0,0 +1,17 @@
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewPassthroughManager returns a Manager running every function it is given directly with the caller's context
+func NewPassthroughManager() *Manager {
+	m := new(Manager)
+	m.On("WithTransaction", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+	return m
+}
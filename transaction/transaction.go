@@ -0,0 +1,72 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/williamchand/kuncie-cart/logging"
+)
+
+// Manager represent the contract to run several repository calls atomically
+type Manager interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Executor represent the query methods shared by *sql.DB and *sql.Tx
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txKey struct{}
+
+type mysqlManager struct {
+	Conn *sql.DB
+}
+
+// NewMysqlManager will create an object that represent the transaction.Manager interface
+func NewMysqlManager(Conn *sql.DB) Manager {
+	return &mysqlManager{Conn}
+}
+
+// WithTransaction runs fn inside a database transaction carried by the context given to fn.
+// The transaction is committed when fn returns nil and rolled back otherwise.
+// Nested calls join the outer transaction.
+func (m *mysqlManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				logging.FromContext(ctx).Error(rbErr)
+			}
+			panic(p)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			logging.FromContext(ctx).Error(rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Conn returns the transaction bound to the context, or the given connection when there is none
+func Conn(ctx context.Context, db *sql.DB) Executor {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}